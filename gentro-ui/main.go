@@ -1,16 +1,25 @@
 package main
 
 import (
+	"context"
 	"embed"
 	_ "embed"
+	"flag"
 	"log"
 	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
 
 	"github.com/rhythmerc/gentro-ui/services/games"
 	"github.com/rhythmerc/gentro-ui/services/games/models"
+
+	// Game sources register themselves with the games service on import
+	_ "github.com/rhythmerc/gentro-ui/services/games/sources/emulated"
+	_ "github.com/rhythmerc/gentro-ui/services/games/sources/gog"
+	_ "github.com/rhythmerc/gentro-ui/services/games/sources/steam"
 )
 
 // Wails uses Go's `embed` package to embed the frontend files into the binary.
@@ -22,14 +31,27 @@ import (
 var assets embed.FS
 
 func init() {
-	application.RegisterEvent[models.MetadataStatusUpdate]("metadata:status-update")
-	application.RegisterEvent[models.LaunchStatusUpdate]("launchStatusUpdate")
+	// Every event the backend emits must be registered here for the TS bindings to include it
+	application.RegisterEvent[models.MetadataStatusUpdate](models.EventMetadataStatus)
+	application.RegisterEvent[models.LaunchStatusUpdate](models.EventLaunchStatus)
+	application.RegisterEvent[models.LaunchError](models.EventLaunchError)
+	application.RegisterEvent[models.ArtUpdate](models.EventArtUpdated)
+	application.RegisterEvent[models.ArtPrefetchProgress](models.EventArtPrefetch)
+	application.RegisterEvent[models.RefreshResult](models.EventRefreshResult)
+	application.RegisterEvent[models.VerifyProgress](models.EventVerifyProgress)
+	application.RegisterEvent[models.InstanceUpdate](models.EventInstanceUpdate)
+	application.RegisterEvent[models.LibraryUpdate](models.EventLibraryUpdated)
+	application.RegisterEvent[[]models.UnavailableInstanceEmulator](models.EventInstanceEmulatorsUnavailable)
 }
 
 // main function serves as the application's entry point. It initializes the application, creates a window,
 // and starts a goroutine that emits a time-based event every second. It subsequently runs the application and
 // logs any error that might occur.
 func main() {
+	// -headless serves the library over the HTTP API without a window
+	headless := flag.Bool("headless", false, "run without a window, serving the HTTP API from api.address in the config")
+	flag.Parse()
+
 	// Initialize logger
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
@@ -41,6 +63,15 @@ func main() {
 		log.Fatalf("Failed to create GamesService: %v", err)
 	}
 
+	if *headless {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := games.RunHeadless(ctx, gamesService); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// Create a new Wails application by providing the necessary options.
 	// Variables 'Name' and 'Description' are for application metadata.
 	// 'Assets' configures the asset server with the 'FS' variable pointing to the frontend files.
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/BurntSushi/toml"
@@ -18,14 +19,78 @@ type Manager struct {
 
 // Config represents the application configuration
 type Config struct {
+	// Version is the layout of the file on disk, used to migrate older files
+	Version int `toml:"version"`
+
 	// Filters contains user filter preferences
 	Filters FilterConfig `toml:"filters"`
+
+	// Metadata contains external metadata settings
+	Metadata MetadataConfig `toml:"metadata"`
+
+	// Art contains art composition settings
+	Art ArtConfig `toml:"art"`
+
+	// Steam contains Steam source settings
+	Steam SteamConfig `toml:"steam"`
+
+	// Emulated contains emulated ROM source settings
+	Emulated EmulatedConfig `toml:"emulated"`
+
+	// GOG contains GOG source settings
+	GOG GOGConfig `toml:"gog"`
+
+	// Scan contains library refresh settings
+	Scan ScanConfig `toml:"scan"`
+
+	// Metrics contains diagnostics counter settings
+	Metrics MetricsConfig `toml:"metrics"`
+
+	// API contains HTTP JSON API settings for running without the window
+	API APIConfig `toml:"api"`
+
+	// SaveSync contains emulator save syncing settings
+	SaveSync SaveSyncConfig `toml:"saveSync"`
+
+	// Platforms adds to or overrides the built-in emulated platforms, keyed by platform ID
+	Platforms map[string]PlatformConfigOverride `toml:"platforms"`
+}
+
+// PlatformConfigOverride customizes an emulated platform. Unknown platform IDs add a new platform.
+type PlatformConfigOverride struct {
+	// Extensions are ROM file extensions recognized in addition to the built-in ones
+	Extensions []string `toml:"extensions"`
+	// DisplayName replaces the built-in display name when set
+	DisplayName string `toml:"displayName,omitempty"`
+	// MaxDepth limits how many directory levels are scanned: 1 scans only the
+	// platform folder itself, 2 adds its subfolders, and so on. 0 is unlimited.
+	MaxDepth int `toml:"maxDepth,omitempty"`
+	// DiskSets bundles the disks of a multi-disk game, e.g. "Game (Disk 1 of 2).adf",
+	// into one instance. Only the built-in value is used when unset.
+	DiskSets *bool `toml:"diskSets,omitempty"`
+	// Romsets treats ROMs as MAME-style romsets, linking clones to their parent.
+	// Only the built-in value is used when unset.
+	Romsets *bool `toml:"romsets,omitempty"`
 }
 
 // FilterConfig contains filter-related settings
 type FilterConfig struct {
 	// Steam contains Steam-specific filter settings
 	Steam SteamFilterConfig `toml:"steam"`
+
+	// DefaultSort is the library sort used when the UI doesn't request one
+	DefaultSort SortConfig `toml:"defaultSort"`
+
+	// DefaultInstalledOnly hides uninstalled games by default
+	DefaultInstalledOnly bool `toml:"defaultInstalledOnly"`
+}
+
+// SortConfig mirrors models.GameSort for persistence
+type SortConfig struct {
+	// Field is "name", "lastPlayed", "fileSize" or "dateAdded"
+	Field string `toml:"field"`
+	// Order is "asc" or "desc"
+	Order string `toml:"order"`
 }
 
 // SteamFilterConfig contains Steam-specific filter settings
@@ -34,11 +99,149 @@ type SteamFilterConfig struct {
 	ExcludeTools bool `toml:"excludeTools"`
 }
 
+// MetadataConfig contains external metadata settings
+type MetadataConfig struct {
+	// CacheTTLDays is how long cached external metadata is considered fresh.
+	// Older entries are still shown but re-fetched in the background. 0 disables expiry.
+	CacheTTLDays int `toml:"cacheTTLDays"`
+	// GenreAliases maps resolver genre names to library labels, e.g.
+	// "Role-playing (RPG)" = "RPG". Entries override the built-in aliases;
+	// an empty label drops the genre.
+	GenreAliases map[string]string `toml:"genreAliases,omitempty"`
+	// SearchUnmappedPlatforms looks up games on platforms IGDB has no mapping
+	// for by name alone, fetching just the cleaned name and a cover
+	SearchUnmappedPlatforms bool `toml:"searchUnmappedPlatforms"`
+}
+
+// ArtConfig contains art composition settings
+type ArtConfig struct {
+	// LogoAnchor places the logo on composed headers: "center", "bottom-left" or "bottom-center"
+	LogoAnchor string `toml:"logoAnchor"`
+	// LogoMaxWidthPercent is the maximum logo width as a percentage of the header width
+	LogoMaxWidthPercent int `toml:"logoMaxWidthPercent"`
+	// LogoShadow draws a drop shadow behind logos
+	LogoShadow bool `toml:"logoShadow"`
+	// ScrimOpacity is the peak opacity (0-100) of the gradient behind logos. 0 disables it.
+	ScrimOpacity int `toml:"scrimOpacity"`
+	// HeroVideos downloads trailer videos for animated library backgrounds
+	HeroVideos bool `toml:"heroVideos"`
+	// DownloadAttempts is how many times an art download is tried before giving up
+	DownloadAttempts int `toml:"downloadAttempts"`
+	// Fallbacks overrides the art types served, in order, when a requested type
+	// is missing, e.g. hero = ["artwork", "screenshot"]. An empty list disables fallback.
+	Fallbacks map[string][]string `toml:"fallbacks,omitempty"`
+	// NamePlaceholders draws the game's initials on a colour derived from its name
+	// when no art exists, instead of a plain neutral image
+	NamePlaceholders bool `toml:"namePlaceholders"`
+}
+
+// SteamConfig contains Steam source settings
+type SteamConfig struct {
+	// PrefetchArt downloads header, library and hero art for installed games after each refresh
+	PrefetchArt bool `toml:"prefetchArt"`
+	// CDNBase is a Steam CDN or mirror tried before the defaults, e.g. "https://steamcdn-a.akamaihd.net"
+	CDNBase string `toml:"cdnBase"`
+}
+
+// GOGConfig contains GOG source settings
+type GOGConfig struct {
+	// BasePath is a folder of GOG installs scanned in addition to the default
+	// "~/GOG Games" and Heroic folders
+	BasePath string `toml:"basePath,omitempty"`
+}
+
+// EmulatedConfig contains emulated ROM source settings
+type EmulatedConfig struct {
+	// HeaderValidation checks manually added ROMs for their platform's header:
+	// "off", "warn" (log and add anyway) or "strict" (reject). Homebrew and hacks
+	// often lack standard headers, so "warn" is the default.
+	HeaderValidation string `toml:"headerValidation"`
+	// LaunchGraceMs is how long a launched emulator must keep running to count as
+	// started. One that exits sooner is reported as a failed launch with its stderr.
+	LaunchGraceMs int `toml:"launchGraceMs"`
+}
+
+// ScanConfig contains library refresh settings
+type ScanConfig struct {
+	// FailFast stops a refresh at the first source or instance error instead of
+	// skipping it and continuing. A failed source's changes are not saved.
+	FailFast bool `toml:"failFast"`
+}
+
+// MetricsConfig contains diagnostics counter settings
+type MetricsConfig struct {
+	// Persist keeps counters across restarts in the library database. They are never sent anywhere.
+	Persist bool `toml:"persist"`
+}
+
+// APIConfig contains settings for the HTTP JSON API, which lets a web UI drive a
+// headless backend (e.g. a server-mode build)
+type APIConfig struct {
+	// Enabled serves the API under the games service route's /api path
+	Enabled bool `toml:"enabled"`
+	// Address, like "0.0.0.0:8080", also serves the API from its own HTTP listener
+	// so it's reachable without the window. Empty serves it only through the window.
+	// Addresses other than loopback need a Token.
+	Address string `toml:"address,omitempty"`
+	// Token, when set, must be sent as "Authorization: Bearer <token>" with every API
+	// request, or as a token query parameter by clients that can't set headers.
+	// Requests that change state, like launching a game, are refused without one.
+	Token string `toml:"token,omitempty"`
+	// AllowedOrigins are host patterns, like "deck.local:8080", whose pages may open
+	// the event stream or send state-changing requests. Same-origin pages are always
+	// allowed.
+	AllowedOrigins []string `toml:"allowedOrigins,omitempty"`
+}
+
+// SaveSyncConfig contains settings for syncing emulator saves through a folder
+// the user keeps in sync between devices, like a cloud drive
+type SaveSyncConfig struct {
+	// Folder holds the synced saves. Syncing is off when it's empty.
+	Folder string `toml:"folder,omitempty"`
+	// Platforms are the platform IDs whose saves are synced: pulled before a game
+	// launches and pushed after it stops
+	Platforms []string `toml:"platforms,omitempty"`
+}
+
+// Header validation modes for EmulatedConfig.HeaderValidation
+const (
+	HeaderValidationOff    = "off"
+	HeaderValidationWarn   = "warn"
+	HeaderValidationStrict = "strict"
+)
+
+// DefaultMetadataCacheTTLDays is the default freshness window for cached metadata
+const DefaultMetadataCacheTTLDays = 30
+
+// DefaultLaunchGraceMs is the launch grace period used when none is configured
+const DefaultLaunchGraceMs = 500
+
 var defaultConfig = Config{
+	Version: CurrentVersion,
 	Filters: FilterConfig{
 		Steam: SteamFilterConfig{
 			ExcludeTools: true,
 		},
+		DefaultSort: SortConfig{
+			Field: "name",
+			Order: "asc",
+		},
+	},
+	Metadata: MetadataConfig{
+		CacheTTLDays:            DefaultMetadataCacheTTLDays,
+		SearchUnmappedPlatforms: true,
+	},
+	Art: ArtConfig{
+		LogoAnchor:          "center",
+		LogoMaxWidthPercent: 60,
+		LogoShadow:          true,
+		ScrimOpacity:        50,
+		DownloadAttempts:    3,
+		NamePlaceholders:    true,
+	},
+	Emulated: EmulatedConfig{
+		HeaderValidation: HeaderValidationWarn,
+		LaunchGraceMs:    DefaultLaunchGraceMs,
 	},
 }
 
@@ -50,13 +253,16 @@ func NewManager(configPath string) (*Manager, error) {
 		return nil, fmt.Errorf("failed to create config directory: %w", err)
 	}
 
+	// Copy the defaults so managers never write through to the shared template
+	data := defaultConfig
 	manager := &Manager{
 		path: configPath,
-		data: &defaultConfig,
+		data: &data,
 	}
 
 	// Try to load existing config
-	if err := manager.Load(); err != nil {
+	migrated, err := manager.load()
+	if err != nil {
 		// If file doesn't exist, save defaults
 		if os.IsNotExist(err) {
 			if err := manager.Save(); err != nil {
@@ -67,38 +273,89 @@ func NewManager(configPath string) (*Manager, error) {
 		}
 	}
 
+	// Rewrite older files in the current layout
+	if migrated {
+		if err := manager.Save(); err != nil {
+			return nil, fmt.Errorf("failed to save migrated config: %w", err)
+		}
+	}
+
 	return manager, nil
 }
 
-// Load reads configuration from disk
+// Load reads configuration from disk, migrating older layouts in memory
 func (m *Manager) Load() error {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	_, err := m.load()
+	return err
+}
 
-	if _, err := toml.DecodeFile(m.path, m.data); err != nil {
-		return err
+// load reads configuration from disk and reports whether it was migrated
+func (m *Manager) load() (bool, error) {
+	// Decoding writes into m.data, so this needs the write lock
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	raw := make(map[string]any)
+	if _, err := toml.DecodeFile(m.path, &raw); err != nil {
+		return false, err
 	}
 
-	return nil
+	return decodeMigrated(raw, m.data)
 }
 
-// Save writes configuration to disk
+// Save writes configuration to disk. The file is written to a temporary file
+// and renamed over the target, so a crash mid-write leaves the old config intact.
 func (m *Manager) Save() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	file, err := os.Create(m.path)
+	tmpPath, err := m.writeTemp()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, m.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace config file: %w", err)
+	}
+
+	return nil
+}
+
+// writeTemp encodes the config to a new file next to the target and returns its path
+func (m *Manager) writeTemp() (string, error) {
+	file, err := os.CreateTemp(filepath.Dir(m.path), "."+filepath.Base(m.path)+".*.tmp")
 	if err != nil {
-		return fmt.Errorf("failed to create config file: %w", err)
+		return "", fmt.Errorf("failed to create config file: %w", err)
+	}
+
+	// CreateTemp uses 0600; keep the permissions os.Create used to give the config
+	if err := file.Chmod(0644); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to set config file permissions: %w", err)
 	}
-	defer file.Close()
 
 	encoder := toml.NewEncoder(file)
 	if err := encoder.Encode(m.data); err != nil {
-		return fmt.Errorf("failed to encode config: %w", err)
+		file.Close()
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to encode config: %w", err)
 	}
 
-	return nil
+	// Flush to disk before the rename makes the new file visible
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to sync config file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to close config file: %w", err)
+	}
+
+	return file.Name(), nil
 }
 
 // Get returns the current configuration
@@ -117,6 +374,105 @@ func (m *Manager) SetFilters(filters FilterConfig) error {
 	return m.Save()
 }
 
+// SetMetadata updates metadata configuration
+func (m *Manager) SetMetadata(metadata MetadataConfig) error {
+	m.mu.Lock()
+	m.data.Metadata = metadata
+	m.mu.Unlock()
+
+	return m.Save()
+}
+
+// SetArt updates art composition configuration
+func (m *Manager) SetArt(art ArtConfig) error {
+	m.mu.Lock()
+	m.data.Art = art
+	m.mu.Unlock()
+
+	return m.Save()
+}
+
+// SetSteam updates Steam source configuration
+func (m *Manager) SetSteam(steam SteamConfig) error {
+	m.mu.Lock()
+	m.data.Steam = steam
+	m.mu.Unlock()
+
+	return m.Save()
+}
+
+// SetGOG updates GOG source configuration
+func (m *Manager) SetGOG(gog GOGConfig) error {
+	m.mu.Lock()
+	m.data.GOG = gog
+	m.mu.Unlock()
+
+	return m.Save()
+}
+
+// SetEmulated updates emulated ROM source configuration
+func (m *Manager) SetEmulated(emulated EmulatedConfig) error {
+	m.mu.Lock()
+	m.data.Emulated = emulated
+	m.mu.Unlock()
+
+	return m.Save()
+}
+
+// SetScan updates library refresh configuration
+func (m *Manager) SetScan(scan ScanConfig) error {
+	m.mu.Lock()
+	m.data.Scan = scan
+	m.mu.Unlock()
+
+	return m.Save()
+}
+
+// SetMetrics updates diagnostics counter configuration
+func (m *Manager) SetMetrics(metrics MetricsConfig) error {
+	m.mu.Lock()
+	m.data.Metrics = metrics
+	m.mu.Unlock()
+
+	return m.Save()
+}
+
+// SetAPI updates HTTP JSON API configuration
+func (m *Manager) SetAPI(api APIConfig) error {
+	m.mu.Lock()
+	m.data.API = api
+	m.mu.Unlock()
+
+	return m.Save()
+}
+
+// SetSaveSync updates save sync configuration
+func (m *Manager) SetSaveSync(saveSync SaveSyncConfig) error {
+	m.mu.Lock()
+	m.data.SaveSync = saveSync
+	m.mu.Unlock()
+
+	return m.Save()
+}
+
+// NormalizeExtension lowercases a ROM extension and ensures it has a leading dot
+func NormalizeExtension(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
+// SetPlatforms updates emulated platform overrides
+func (m *Manager) SetPlatforms(platforms map[string]PlatformConfigOverride) error {
+	m.mu.Lock()
+	m.data.Platforms = platforms
+	m.mu.Unlock()
+
+	return m.Save()
+}
+
 // DefaultConfigPath returns the default configuration file path
 func DefaultConfigPath() string {
 	homeDir, err := os.UserHomeDir()
@@ -3,7 +3,10 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+
+	"github.com/BurntSushi/toml"
 )
 
 func TestNewManager(t *testing.T) {
@@ -22,6 +25,9 @@ func TestNewManager(t *testing.T) {
 	if !cfg.Filters.Steam.ExcludeTools {
 		t.Error("Expected ExcludeTools to default to true")
 	}
+	if cfg.Metadata.CacheTTLDays != DefaultMetadataCacheTTLDays {
+		t.Errorf("Expected CacheTTLDays to default to %d, got %d", DefaultMetadataCacheTTLDays, cfg.Metadata.CacheTTLDays)
+	}
 
 	// Verify file was created
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
@@ -62,6 +68,164 @@ func TestLoadAndSave(t *testing.T) {
 	}
 }
 
+func TestNewManager_MigratesV0Config(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "test.toml")
+
+	// v0 files have no version field
+	v0 := `[filters.steam]
+excludeTools = false
+
+[metadata]
+cacheTTLDays = 7
+`
+	if err := os.WriteFile(configPath, []byte(v0), 0644); err != nil {
+		t.Fatalf("failed to write v0 config: %v", err)
+	}
+
+	manager, err := NewManager(configPath)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	cfg := manager.Get()
+	if cfg.Version != CurrentVersion {
+		t.Errorf("Expected version %d, got %d", CurrentVersion, cfg.Version)
+	}
+	if cfg.Filters.Steam.ExcludeTools || cfg.Metadata.CacheTTLDays != 7 {
+		t.Errorf("Expected user settings to be preserved, got %+v", cfg)
+	}
+	if cfg.Art.LogoMaxWidthPercent != 60 {
+		t.Errorf("Expected missing sections to keep defaults, got %+v", cfg.Art)
+	}
+
+	// The upgraded file is written back
+	raw := make(map[string]any)
+	if _, err := toml.DecodeFile(configPath, &raw); err != nil {
+		t.Fatalf("Failed to decode migrated file: %v", err)
+	}
+	if fileVersion(raw) != CurrentVersion {
+		t.Errorf("Expected migrated file to record version %d, got %v", CurrentVersion, raw["version"])
+	}
+}
+
+func TestNewManager_RejectsNewerVersion(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "test.toml")
+	if err := os.WriteFile(configPath, []byte("version = 999\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	if _, err := NewManager(configPath); err == nil {
+		t.Error("Expected an error loading a config from a newer version")
+	}
+}
+
+func TestMigrationsCoverCurrentVersion(t *testing.T) {
+	if len(migrations) != CurrentVersion {
+		t.Errorf("Expected %d migrations for version %d, got %d", CurrentVersion, CurrentVersion, len(migrations))
+	}
+}
+
+func TestManager_ConcurrentAccess(t *testing.T) {
+	manager, err := NewManager(filepath.Join(t.TempDir(), "test.toml"))
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(exclude bool) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if err := manager.SetFilters(FilterConfig{Steam: SteamFilterConfig{ExcludeTools: exclude}}); err != nil {
+					t.Errorf("SetFilters failed: %v", err)
+					return
+				}
+			}
+		}(i%2 == 0)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				manager.Get()
+				if err := manager.Load(); err != nil {
+					t.Errorf("Load failed: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestNewManager_DoesNotShareDefaults(t *testing.T) {
+	first, err := NewManager(filepath.Join(t.TempDir(), "first.toml"))
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if err := first.SetFilters(FilterConfig{}); err != nil {
+		t.Fatalf("Failed to set filters: %v", err)
+	}
+
+	second, err := NewManager(filepath.Join(t.TempDir(), "second.toml"))
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if !second.Get().Filters.Steam.ExcludeTools {
+		t.Error("Expected a new manager to start from defaults, not another manager's settings")
+	}
+}
+
+func TestSave_InterruptedWriteKeepsOldConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "test.toml")
+
+	manager, err := NewManager(configPath)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if err := manager.SetFilters(FilterConfig{}); err != nil {
+		t.Fatalf("Failed to set filters: %v", err)
+	}
+
+	// Simulate a crash after the new contents are written but before the rename
+	manager.mu.Lock()
+	manager.data.Filters.Steam.ExcludeTools = true
+	tmpPath, err := manager.writeTemp()
+	manager.mu.Unlock()
+	if err != nil {
+		t.Fatalf("writeTemp failed: %v", err)
+	}
+	if filepath.Dir(tmpPath) != filepath.Dir(configPath) {
+		t.Errorf("Expected temp file next to the config so rename is atomic, got %s", tmpPath)
+	}
+
+	reloaded, err := NewManager(configPath)
+	if err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	if reloaded.Get().Filters.Steam.ExcludeTools {
+		t.Error("Expected the previous config to survive an interrupted write")
+	}
+}
+
+func TestSave_LeavesNoTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	manager, err := NewManager(filepath.Join(dir, "test.toml"))
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if err := manager.SetFilters(FilterConfig{}); err != nil {
+		t.Fatalf("Failed to set filters: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read dir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected only the config file, got %d entries", len(entries))
+	}
+}
+
 func TestDefaultConfigPath(t *testing.T) {
 	path := DefaultConfigPath()
 	if path == "" {
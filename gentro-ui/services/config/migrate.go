@@ -0,0 +1,64 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+// CurrentVersion is the config file layout written by this build
+const CurrentVersion = 1
+
+// migrations upgrade a decoded config file in place; migrations[i] upgrades version i to i+1.
+// They work on the raw TOML tables so renamed or restructured keys can be carried over
+// before the file is decoded into Config, which would otherwise drop them.
+var migrations = []func(raw map[string]any) error{
+	// v0 files predate the version field and already match the v1 layout
+	func(raw map[string]any) error { return nil },
+}
+
+// fileVersion returns the version recorded in a decoded config file, 0 if absent
+func fileVersion(raw map[string]any) int {
+	if v, ok := raw["version"].(int64); ok {
+		return int(v)
+	}
+	return 0
+}
+
+// migrate upgrades raw to CurrentVersion. Returns whether any migration ran.
+func migrate(raw map[string]any) (bool, error) {
+	version := fileVersion(raw)
+	if version > CurrentVersion {
+		return false, fmt.Errorf("config version %d is newer than supported version %d", version, CurrentVersion)
+	}
+
+	for ; version < CurrentVersion; version++ {
+		if err := migrations[version](raw); err != nil {
+			return false, fmt.Errorf("failed to migrate config from version %d: %w", version, err)
+		}
+	}
+
+	migrated := fileVersion(raw) != CurrentVersion
+	raw["version"] = int64(CurrentVersion)
+	return migrated, nil
+}
+
+// decodeMigrated upgrades raw and decodes it over cfg, so keys missing from the
+// file keep the values already in cfg
+func decodeMigrated(raw map[string]any, cfg *Config) (bool, error) {
+	migrated, err := migrate(raw)
+	if err != nil {
+		return false, err
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(raw); err != nil {
+		return false, fmt.Errorf("failed to encode migrated config: %w", err)
+	}
+	if _, err := toml.Decode(buf.String(), cfg); err != nil {
+		return false, fmt.Errorf("failed to decode migrated config: %w", err)
+	}
+
+	return migrated, nil
+}
@@ -0,0 +1,223 @@
+package games
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// apiPrefix is the path under the service route that serves the JSON API
+const apiPrefix = "/api/"
+
+// apiEnabled reports whether the HTTP JSON API is switched on in the config
+func (s *GamesService) apiEnabled() bool {
+	return s.config != nil && s.config.Get().API.Enabled
+}
+
+// serveAPI handles a JSON API request. The API exposes the same operations as the
+// Wails bindings so a web UI can drive the backend without the window. It's off
+// unless enabled in the config, and requires the configured bearer token if any.
+// Requests that change state also need a token configured and an allowed origin.
+func (s *GamesService) serveAPI(w http.ResponseWriter, r *http.Request) {
+	if !s.apiEnabled() {
+		http.NotFound(w, r)
+		return
+	}
+	api := s.config.Get().API
+	if token := api.Token; token != "" {
+		// Browsers can't set headers on WebSocket requests, so the token may be a query parameter
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			got, ok = r.URL.Query().Get("token"), r.URL.Query().Has("token")
+		}
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			writeAPIError(w, http.StatusUnauthorized, errors.New("missing or invalid API token"))
+			return
+		}
+	}
+	// Any page can make a browser send a POST, so changing state needs a token
+	// the page can't know and an origin the user allowed
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		if api.Token == "" {
+			writeAPIError(w, http.StatusForbidden, errors.New("state-changing API requests need a token configured"))
+			return
+		}
+		if !apiOriginAllowed(r, api.AllowedOrigins) {
+			writeAPIError(w, http.StatusForbidden, errors.New("request origin not allowed"))
+			return
+		}
+	}
+
+	s.apiOnce.Do(func() { s.apiMux = s.newAPIMux() })
+	s.apiMux.ServeHTTP(w, r)
+}
+
+// apiOriginAllowed reports whether a request's Origin, if it sent one, is the
+// server's own host or matches one of the allowed host patterns. Clients other
+// than browsers don't send an Origin.
+func apiOriginAllowed(r *http.Request, allowed []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, pattern := range allowed {
+		if ok, err := path.Match(strings.ToLower(pattern), strings.ToLower(u.Host)); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// newAPIMux routes API requests to the service methods backing them
+func (s *GamesService) newAPIMux() *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/games", func(w http.ResponseWriter, r *http.Request) {
+		filter, sort := apiGameQuery(r)
+		result, err := s.GetGames(filter, sort)
+		writeAPIResult(w, result, err)
+	})
+	mux.HandleFunc("GET /api/games/{id}", func(w http.ResponseWriter, r *http.Request) {
+		game, instances, err := s.GetGame(r.PathValue("id"))
+		if errors.Is(err, models.ErrGameNotFound) {
+			writeAPIError(w, http.StatusNotFound, err)
+			return
+		}
+		writeAPIResult(w, struct {
+			Game      *models.Game          `json:"game"`
+			Instances []models.GameInstance `json:"instances"`
+		}{game, instances}, err)
+	})
+	mux.HandleFunc("GET /api/recent", func(w http.ResponseWriter, r *http.Request) {
+		limit, ok := apiIntParam(w, r, "limit", 20)
+		if ok {
+			result, err := s.GetRecentlyAdded(limit)
+			writeAPIResult(w, result, err)
+		}
+	})
+	mux.HandleFunc("GET /api/stats", func(w http.ResponseWriter, r *http.Request) {
+		result, err := s.GetLibraryStats()
+		writeAPIResult(w, result, err)
+	})
+	mux.HandleFunc("GET /api/history/{instanceId}", func(w http.ResponseWriter, r *http.Request) {
+		result, err := s.GetPlayHistory(r.PathValue("instanceId"))
+		writeAPIResult(w, result, err)
+	})
+	mux.HandleFunc("GET /api/playtime", func(w http.ResponseWriter, r *http.Request) {
+		days, ok := apiIntParam(w, r, "days", 30)
+		if ok {
+			result, err := s.GetPlaytimeByDay(days)
+			writeAPIResult(w, result, err)
+		}
+	})
+	mux.HandleFunc("POST /api/launch/{instanceId}", func(w http.ResponseWriter, r *http.Request) {
+		instance, err := s.db.GetInstance(r.PathValue("instanceId"))
+		if err == nil && instance == nil {
+			writeAPIError(w, http.StatusNotFound, errors.New("instance not found"))
+			return
+		}
+		writeAPIResult(w, struct{}{}, s.Launch(r.PathValue("instanceId")))
+	})
+	mux.HandleFunc("POST /api/refresh", func(w http.ResponseWriter, r *http.Request) {
+		result, err := s.RefreshGames()
+		if errors.Is(err, models.ErrRefreshInProgress) {
+			writeAPIError(w, http.StatusConflict, err)
+			return
+		}
+		writeAPIResult(w, result, err)
+	})
+	mux.HandleFunc("POST /api/refresh/cancel", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIResult(w, struct {
+			Cancelled bool `json:"cancelled"`
+		}{s.CancelRefresh()}, nil)
+	})
+	mux.HandleFunc("GET /api/emulators", func(w http.ResponseWriter, r *http.Request) {
+		result, err := s.GetEmulators()
+		writeAPIResult(w, result, err)
+	})
+	mux.HandleFunc("POST /api/emulators/refresh", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIResult(w, struct{}{}, s.RefreshEmulators())
+	})
+	mux.HandleFunc("GET /api/events", s.serveEventStream)
+
+	return mux
+}
+
+// apiGameQuery reads the library filter and sort from query parameters. Without
+// any, nil is returned so GetGames applies the configured defaults.
+func apiGameQuery(r *http.Request) (*models.GameFilter, *models.GameSort) {
+	query := r.URL.Query()
+
+	var filter *models.GameFilter
+	if query.Has("source") || query.Has("platform") || query.Has("search") || query.Has("installedOnly") || query.Has("genre") {
+		filter = &models.GameFilter{
+			Source:        query.Get("source"),
+			Platform:      query.Get("platform"),
+			Search:        query.Get("search"),
+			InstalledOnly: query.Get("installedOnly") == "true",
+			Genres:        query["genre"],
+		}
+	}
+
+	var sort *models.GameSort
+	if query.Has("sort") {
+		sort = &models.GameSort{Field: query.Get("sort"), Order: models.SortOrderAsc}
+		if query.Get("order") == models.SortOrderDesc {
+			sort.Order = models.SortOrderDesc
+		}
+	}
+
+	return filter, sort
+}
+
+// apiIntParam reads a positive integer query parameter, writing a 400 and
+// returning false if it's malformed
+func apiIntParam(w http.ResponseWriter, r *http.Request, name string, fallback int) (int, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return fallback, true
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value <= 0 {
+		writeAPIError(w, http.StatusBadRequest, fmt.Errorf("invalid %s: %q", name, raw))
+		return 0, false
+	}
+	return value, true
+}
+
+// writeAPIResult writes result as JSON, or err as a 500
+func writeAPIResult(w http.ResponseWriter, result any, err error) {
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, result)
+}
+
+// writeAPIError writes an error as a JSON object with the given status
+func writeAPIError(w http.ResponseWriter, status int, err error) {
+	writeAPIJSON(w, status, struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
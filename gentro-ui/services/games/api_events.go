@@ -0,0 +1,76 @@
+package games
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+)
+
+const (
+	// eventStreamBuffer is how many events may queue for a WebSocket client before
+	// it's disconnected as too slow
+	eventStreamBuffer = 64
+	// eventStreamWriteTimeout bounds how long a single event write may take
+	eventStreamWriteTimeout = 5 * time.Second
+)
+
+// streamedEvent is an event as sent over the WebSocket stream, shaped like the
+// events the Wails frontend receives
+type streamedEvent struct {
+	Name string `json:"name"`
+	Data any    `json:"data"`
+}
+
+// serveEventStream upgrades to a WebSocket and forwards every backend event to the
+// client as JSON until it disconnects, so a browser client gets the same real-time
+// updates as the desktop frontend
+func (s *GamesService) serveEventStream(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		OriginPatterns: s.config.Get().API.AllowedOrigins,
+	})
+	if err != nil {
+		// Accept has already written the error response
+		s.logger.Warn("failed to accept event stream", "error", err, "remote", r.RemoteAddr)
+		return
+	}
+	defer conn.CloseNow()
+
+	queue := make(chan streamedEvent, eventStreamBuffer)
+	overflowed := make(chan struct{})
+	var overflowOnce sync.Once
+	unsubscribe := s.events.Subscribe(func(name string, data any) {
+		select {
+		case queue <- streamedEvent{Name: name, Data: data}:
+		default:
+			overflowOnce.Do(func() { close(overflowed) })
+		}
+	})
+	defer unsubscribe()
+
+	s.logger.Info("event stream connected", "remote", r.RemoteAddr)
+	defer s.logger.Info("event stream disconnected", "remote", r.RemoteAddr)
+
+	// Clients only listen; CloseRead handles their close frames and cancels ctx
+	ctx := conn.CloseRead(r.Context())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-overflowed:
+			conn.Close(websocket.StatusPolicyViolation, "client too slow")
+			return
+		case event := <-queue:
+			writeCtx, cancel := context.WithTimeout(ctx, eventStreamWriteTimeout)
+			err := wsjson.Write(writeCtx, conn, event)
+			cancel()
+			if err != nil {
+				s.logger.Debug("failed to write event", "error", err, "event", event.Name)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package games
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// apiReadHeaderTimeout bounds how long the standalone listener waits for request
+// headers. There's no write timeout since event streams stay open.
+const apiReadHeaderTimeout = 10 * time.Second
+
+// headlessShutdownTimeout bounds how long RunHeadless waits for open requests
+// when it stops
+const headlessShutdownTimeout = 10 * time.Second
+
+// startAPIServer serves the service route, including the event stream, from its
+// own HTTP listener when the config gives the API an address, so clients can reach
+// it without the window
+func (s *GamesService) startAPIServer() error {
+	if s.config == nil {
+		return nil
+	}
+	api := s.config.Get().API
+	if !api.Enabled || api.Address == "" {
+		return nil
+	}
+
+	// Without a token anyone who can reach the address could read the library
+	if api.Token == "" && !isLoopbackAddress(api.Address) {
+		return fmt.Errorf("refusing to serve the API on %s without a token; set api.token or listen on a loopback address", api.Address)
+	}
+
+	listener, err := net.Listen("tcp", api.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", api.Address, err)
+	}
+
+	// Mount under the route so art URLs from the API resolve the same as in the window
+	mux := http.NewServeMux()
+	mux.Handle(s.route+"/", http.StripPrefix(s.route, s))
+
+	// Shutdown doesn't track hijacked connections, so event streams end when
+	// their base context is cancelled instead
+	streamCtx, cancelStreams := context.WithCancel(context.Background())
+	server := &http.Server{
+		Addr:              listener.Addr().String(),
+		Handler:           mux,
+		ReadHeaderTimeout: apiReadHeaderTimeout,
+		BaseContext:       func(net.Listener) context.Context { return streamCtx },
+	}
+	server.RegisterOnShutdown(cancelStreams)
+
+	s.apiServerMu.Lock()
+	s.apiServer = server
+	s.apiServerMu.Unlock()
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("API server stopped", "error", err)
+		}
+	}()
+	s.logger.Info("Serving API", "address", listener.Addr().String())
+	return nil
+}
+
+// isLoopbackAddress reports whether a listen address only accepts local
+// connections. An empty host listens on every interface.
+func isLoopbackAddress(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil || host == "" {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// stopAPIServer shuts the standalone listener down, if it's running
+func (s *GamesService) stopAPIServer(ctx context.Context) error {
+	s.apiServerMu.Lock()
+	server := s.apiServer
+	s.apiServer = nil
+	s.apiServerMu.Unlock()
+
+	if server == nil {
+		return nil
+	}
+	return server.Shutdown(ctx)
+}
+
+// RunHeadless starts the service without a Wails application or window and
+// serves the API from its configured address until ctx ends. It fails if the API
+// isn't enabled with an address, since nothing could reach the library otherwise.
+func RunHeadless(ctx context.Context, s *GamesService) error {
+	if err := s.ServiceStartup(ctx, application.ServiceOptions{Route: "/games"}); err != nil {
+		return fmt.Errorf("failed to start games service: %w", err)
+	}
+
+	s.apiServerMu.Lock()
+	serving := s.apiServer != nil
+	s.apiServerMu.Unlock()
+
+	if serving {
+		<-ctx.Done()
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), headlessShutdownTimeout)
+	defer cancel()
+	if err := s.ServiceShutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if !serving {
+		return errors.New("API server isn't running; headless mode needs api.enabled and a free api.address in the config")
+	}
+	return nil
+}
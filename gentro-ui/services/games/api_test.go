@@ -0,0 +1,355 @@
+package games
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+
+	"github.com/rhythmerc/gentro-ui/services/config"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestServeHTTP_API(t *testing.T) {
+	service := newTestService(t)
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "pc", Installed: true},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+
+	request := func(method, target, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		service.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Off unless enabled
+	if rec := request("GET", "/api/games", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 while disabled, got %d", rec.Code)
+	}
+
+	manager, err := config.NewManager(filepath.Join(t.TempDir(), "gentro.toml"))
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	if err := manager.SetAPI(config.APIConfig{Enabled: true, Token: "secret"}); err != nil {
+		t.Fatalf("SetAPI failed: %v", err)
+	}
+	service.config = manager
+
+	if rec := request("GET", "/api/games", "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 for a bad token, got %d", rec.Code)
+	}
+
+	rec := request("GET", "/api/games?source=mock", "secret")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("expected JSON games, got %d %q", rec.Code, rec.Body.String())
+	}
+	var games []models.GameWithInstance
+	if err := json.NewDecoder(rec.Body).Decode(&games); err != nil {
+		t.Fatalf("failed to decode games: %v", err)
+	}
+	if len(games) != 1 || games[0].Instance.ID != "inst1" {
+		t.Errorf("expected inst1, got %+v", games)
+	}
+
+	tests := []struct {
+		method, target string
+		wantCode       int
+	}{
+		{"GET", "/api/games/game1", http.StatusOK},
+		{"GET", "/api/games/missing", http.StatusNotFound},
+		{"GET", "/api/recent?limit=5", http.StatusOK},
+		{"GET", "/api/recent?limit=zero", http.StatusBadRequest},
+		{"POST", "/api/launch/missing", http.StatusNotFound},
+		{"GET", "/api/launch/inst1", http.StatusMethodNotAllowed},
+		{"GET", "/api/nope", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if rec := request(tt.method, tt.target, "secret"); rec.Code != tt.wantCode {
+			t.Errorf("%s %s: expected %d, got %d %q", tt.method, tt.target, tt.wantCode, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestServeHTTP_EventStream(t *testing.T) {
+	service := newTestService(t)
+	manager, err := config.NewManager(filepath.Join(t.TempDir(), "gentro.toml"))
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	if err := manager.SetAPI(config.APIConfig{Enabled: true, Token: "secret"}); err != nil {
+		t.Fatalf("SetAPI failed: %v", err)
+	}
+	service.config = manager
+
+	server := httptest.NewServer(service)
+	t.Cleanup(server.Close)
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/events"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, _, err := websocket.Dial(ctx, url, nil); err == nil {
+		t.Error("expected the stream to require the token")
+	}
+
+	conn, _, err := websocket.Dial(ctx, url+"?token=secret", nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.CloseNow()
+
+	// The server subscribes after the handshake, so emit until the client hears one
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				service.events.EmitGameArtUpdated("inst1", "game1", "cover")
+			}
+		}
+	}()
+
+	var event struct {
+		Name string           `json:"name"`
+		Data models.ArtUpdate `json:"data"`
+	}
+	if err := wsjson.Read(ctx, conn, &event); err != nil {
+		t.Fatalf("failed to read event: %v", err)
+	}
+	if event.Name != models.EventArtUpdated || event.Data.InstanceID != "inst1" || event.Data.ArtType != "cover" {
+		t.Errorf("unexpected event %+v", event)
+	}
+}
+
+func TestServeHTTP_APIStateChanges(t *testing.T) {
+	service := newTestService(t)
+	manager, err := config.NewManager(filepath.Join(t.TempDir(), "gentro.toml"))
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	service.config = manager
+
+	request := func(method, target, token, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		rec := httptest.NewRecorder()
+		service.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Without a token reads still work but nothing can change state
+	if err := manager.SetAPI(config.APIConfig{Enabled: true}); err != nil {
+		t.Fatalf("SetAPI failed: %v", err)
+	}
+	if rec := request("GET", "/api/games", "", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected reads without a token, got %d", rec.Code)
+	}
+	if rec := request("POST", "/api/launch/missing", "", ""); rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for a launch without a token configured, got %d", rec.Code)
+	}
+
+	if err := manager.SetAPI(config.APIConfig{Enabled: true, Token: "secret", AllowedOrigins: []string{"*.local:8080"}}); err != nil {
+		t.Fatalf("SetAPI failed: %v", err)
+	}
+	tests := []struct {
+		name     string
+		origin   string
+		wantCode int
+	}{
+		{"no origin", "", http.StatusNotFound},
+		{"same origin", "http://example.com", http.StatusNotFound},
+		{"allowed origin", "http://deck.local:8080", http.StatusNotFound},
+		{"other origin", "http://evil.example", http.StatusForbidden},
+		{"opaque origin", "null", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := request("POST", "/api/launch/missing", "secret", tt.origin); rec.Code != tt.wantCode {
+				t.Errorf("expected %d, got %d %q", tt.wantCode, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestStartAPIServer(t *testing.T) {
+	service := newTestService(t)
+	service.route = "/games"
+	manager, err := config.NewManager(filepath.Join(t.TempDir(), "gentro.toml"))
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	if err := manager.SetAPI(config.APIConfig{Enabled: true, Address: "127.0.0.1:0", Token: "secret"}); err != nil {
+		t.Fatalf("SetAPI failed: %v", err)
+	}
+	service.config = manager
+
+	if err := service.startAPIServer(); err != nil {
+		t.Fatalf("startAPIServer failed: %v", err)
+	}
+	t.Cleanup(func() { service.stopAPIServer(context.Background()) })
+	base := "http://" + service.apiServer.Addr
+	// Shutdown waits on connections the client dialed but never used, so don't pool any
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	req, _ := http.NewRequest("GET", base+"/games/api/games", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 from the listener, got %d", resp.StatusCode)
+	}
+
+	// Only the service route is mounted
+	resp, err = client.Get(base + "/api/games")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 outside the route, got %d", resp.StatusCode)
+	}
+
+	if err := service.stopAPIServer(context.Background()); err != nil {
+		t.Fatalf("stopAPIServer failed: %v", err)
+	}
+	if _, err := client.Get(base + "/games/api/games"); err == nil {
+		t.Error("expected the listener to be closed")
+	}
+}
+
+func TestStartAPIServer_NeedsTokenOffLoopback(t *testing.T) {
+	service := newTestService(t)
+	service.route = "/games"
+	manager, err := config.NewManager(filepath.Join(t.TempDir(), "gentro.toml"))
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	if err := manager.SetAPI(config.APIConfig{Enabled: true, Address: "0.0.0.0:0"}); err != nil {
+		t.Fatalf("SetAPI failed: %v", err)
+	}
+	service.config = manager
+
+	if err := service.startAPIServer(); err == nil {
+		service.stopAPIServer(context.Background())
+		t.Fatal("expected a listener on every interface to need a token")
+	}
+	if service.apiServer != nil {
+		t.Error("expected no server to be started")
+	}
+}
+
+func TestIsLoopbackAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{"127.0.0.1:8080", true},
+		{"[::1]:8080", true},
+		{"localhost:8080", true},
+		{":8080", false},
+		{"0.0.0.0:8080", false},
+		{"192.168.1.10:8080", false},
+		{"deck.local:8080", false},
+		{"nonsense", false},
+	}
+	for _, tt := range tests {
+		if got := isLoopbackAddress(tt.address); got != tt.want {
+			t.Errorf("isLoopbackAddress(%q): expected %v, got %v", tt.address, tt.want, got)
+		}
+	}
+}
+
+func TestStartAPIServer_EventStream(t *testing.T) {
+	service := newTestService(t)
+	service.route = "/games"
+	manager, err := config.NewManager(filepath.Join(t.TempDir(), "gentro.toml"))
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	if err := manager.SetAPI(config.APIConfig{Enabled: true, Address: "127.0.0.1:0", Token: "secret"}); err != nil {
+		t.Fatalf("SetAPI failed: %v", err)
+	}
+	service.config = manager
+
+	if err := service.startAPIServer(); err != nil {
+		t.Fatalf("startAPIServer failed: %v", err)
+	}
+	t.Cleanup(func() { service.stopAPIServer(context.Background()) })
+	url := "ws://" + service.apiServer.Addr + "/games/api/events?token=secret"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(ctx, url, nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.CloseNow()
+
+	// The server subscribes after the handshake, so emit until the client hears one
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				service.events.EmitGameArtUpdated("inst1", "game1", "cover")
+			}
+		}
+	}()
+
+	var event struct {
+		Name string `json:"name"`
+	}
+	err = wsjson.Read(ctx, conn, &event)
+	close(done)
+	if err != nil {
+		t.Fatalf("failed to read event: %v", err)
+	}
+	if event.Name != models.EventArtUpdated {
+		t.Errorf("expected %s, got %s", models.EventArtUpdated, event.Name)
+	}
+
+	// Stopping the listener ends open streams too
+	if err := service.stopAPIServer(ctx); err != nil {
+		t.Fatalf("stopAPIServer failed: %v", err)
+	}
+	for {
+		if _, _, err := conn.Read(ctx); err != nil {
+			if ctx.Err() != nil {
+				t.Fatal("expected the stream to close on shutdown")
+			}
+			break
+		}
+	}
+}
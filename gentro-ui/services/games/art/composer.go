@@ -2,15 +2,22 @@ package art
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"image"
+	"image/color"
 	"image/draw"
+	_ "image/jpeg" // register JPEG decoder for image.Decode
 	"image/png"
 	"io"
 	"log/slog"
+	"math"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -22,6 +29,51 @@ type Composer struct {
 	cacheDir string
 	logger   *slog.Logger
 	client   *http.Client
+	options  ComposeOptions
+	mu       sync.RWMutex
+
+	// retryAttempts is the total number of tries for a download, with
+	// retryBaseDelay doubling between each
+	retryAttempts  int
+	retryBaseDelay time.Duration
+}
+
+// Default download retry policy
+const (
+	DefaultRetryAttempts  = 3
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+)
+
+// LogoAnchor controls where the logo is placed on a composed header
+type LogoAnchor string
+
+const (
+	LogoAnchorCenter       LogoAnchor = "center"
+	LogoAnchorBottomLeft   LogoAnchor = "bottom-left"
+	LogoAnchorBottomCenter LogoAnchor = "bottom-center"
+)
+
+// ComposeOptions tunes how logos are laid out on composed headers
+type ComposeOptions struct {
+	// LogoAnchor is where the logo is placed
+	LogoAnchor LogoAnchor
+	// LogoMaxWidthPercent is the maximum logo width as a percentage of the header width
+	LogoMaxWidthPercent int
+	// LogoShadow draws a soft drop shadow behind the logo for legibility
+	LogoShadow bool
+	// ScrimOpacity is the peak opacity (0-100) of the gradient painted behind
+	// the logo. The scrim is skipped on already dark backgrounds. 0 disables it.
+	ScrimOpacity int
+}
+
+// DefaultComposeOptions returns the default header layout
+func DefaultComposeOptions() ComposeOptions {
+	return ComposeOptions{
+		LogoAnchor:          LogoAnchorCenter,
+		LogoMaxWidthPercent: 60,
+		LogoShadow:          true,
+		ScrimOpacity:        50,
+	}
 }
 
 // NewComposer creates a new art composer
@@ -34,23 +86,67 @@ func NewComposer(cacheDir string, logger *slog.Logger) *Composer {
 		cacheDir: cacheDir,
 		logger:   logger,
 		client:   &http.Client{Timeout: 30 * time.Second},
+		options:  DefaultComposeOptions(),
+
+		retryAttempts:  DefaultRetryAttempts,
+		retryBaseDelay: DefaultRetryBaseDelay,
+	}
+}
+
+// SetRetry sets how many times a download is attempted and the delay before
+// the first retry. Values below 1 attempt or 0 delay fall back to defaults.
+func (c *Composer) SetRetry(attempts int, baseDelay time.Duration) {
+	if attempts < 1 {
+		attempts = DefaultRetryAttempts
+	}
+	if baseDelay <= 0 {
+		baseDelay = DefaultRetryBaseDelay
+	}
+
+	c.mu.Lock()
+	c.retryAttempts = attempts
+	c.retryBaseDelay = baseDelay
+	c.mu.Unlock()
+}
+
+// SetOptions replaces the header layout options. Invalid values fall back to defaults.
+func (c *Composer) SetOptions(opts ComposeOptions) {
+	defaults := DefaultComposeOptions()
+	switch opts.LogoAnchor {
+	case LogoAnchorCenter, LogoAnchorBottomLeft, LogoAnchorBottomCenter:
+	default:
+		opts.LogoAnchor = defaults.LogoAnchor
+	}
+	if opts.LogoMaxWidthPercent <= 0 || opts.LogoMaxWidthPercent > 100 {
+		opts.LogoMaxWidthPercent = defaults.LogoMaxWidthPercent
 	}
+	if opts.ScrimOpacity < 0 || opts.ScrimOpacity > 100 {
+		opts.ScrimOpacity = defaults.ScrimOpacity
+	}
+
+	c.mu.Lock()
+	c.options = opts
+	c.mu.Unlock()
+}
+
+// Options returns the current header layout options
+func (c *Composer) Options() ComposeOptions {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.options
 }
 
 // ComposeHeader creates a 460x215 header image:
 // - Background: Screenshot (scaled/cropped to fill)
 // - Overlay: Logo (centered, max 50% width, preserve aspect ratio)
 // Falls back to cover art if no logo, or artwork/cover if no screenshot
-func (c *Composer) ComposeHeader(screenshotURL, logoURL, coverURL, artworkURL, gameID string) ([]byte, error) {
-	// Target dimensions (Steam header size)
-	targetWidth, targetHeight := 460, 215
-
+func (c *Composer) ComposeHeader(ctx context.Context, screenshotURL, logoURL, coverURL, artworkURL, gameID string) ([]byte, error) {
 	var backgroundImg image.Image
 	var backgroundSource string
 
 	// Try screenshot first for background
 	if screenshotURL != "" {
-		img, err := c.downloadImage(screenshotURL)
+		img, err := c.downloadImage(ctx, screenshotURL)
 		if err != nil {
 			c.logger.Warn("failed to download screenshot for header", "error", err, "gameID", gameID)
 		} else {
@@ -61,7 +157,7 @@ func (c *Composer) ComposeHeader(screenshotURL, logoURL, coverURL, artworkURL, g
 
 	// Fallback to artwork
 	if backgroundImg == nil && artworkURL != "" {
-		img, err := c.downloadImage(artworkURL)
+		img, err := c.downloadImage(ctx, artworkURL)
 		if err != nil {
 			c.logger.Warn("failed to download artwork for header", "error", err, "gameID", gameID)
 		} else {
@@ -72,7 +168,7 @@ func (c *Composer) ComposeHeader(screenshotURL, logoURL, coverURL, artworkURL, g
 
 	// Final fallback to cover
 	if backgroundImg == nil && coverURL != "" {
-		img, err := c.downloadImage(coverURL)
+		img, err := c.downloadImage(ctx, coverURL)
 		if err != nil {
 			c.logger.Warn("failed to download cover for header", "error", err, "gameID", gameID)
 		} else {
@@ -87,6 +183,71 @@ func (c *Composer) ComposeHeader(screenshotURL, logoURL, coverURL, artworkURL, g
 
 	c.logger.Debug("using background for header", "gameID", gameID, "source", backgroundSource)
 
+	// Try to overlay logo
+	var logoImg image.Image
+	if logoURL != "" {
+		img, err := c.downloadImage(ctx, logoURL)
+		if err != nil {
+			c.logger.Warn("failed to download logo for header", "error", err, "gameID", gameID)
+		} else {
+			logoImg = img
+		}
+	}
+
+	return c.composeHeaderImage(backgroundImg, logoImg)
+}
+
+// RecomposeHeader rebuilds an instance's header from its cached screenshot,
+// artwork or cover and logo using the current options, without downloading
+// anything, and caches the result.
+func (c *Composer) RecomposeHeader(source, instanceID string) error {
+	var backgroundImg image.Image
+	for _, artType := range []string{"screenshot", "artwork", "cover"} {
+		img, err := c.cachedImage(source, instanceID, artType)
+		if err == nil {
+			backgroundImg = img
+			break
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			c.logger.Warn("failed to load cached art for header", "error", err, "instanceID", instanceID, "artType", artType)
+		}
+	}
+	if backgroundImg == nil {
+		return fmt.Errorf("no cached background image available for header composition")
+	}
+
+	logoImg, err := c.cachedImage(source, instanceID, "logo")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		c.logger.Warn("failed to load cached logo for header", "error", err, "instanceID", instanceID)
+	}
+
+	data, err := c.composeHeaderImage(backgroundImg, logoImg)
+	if err != nil {
+		return err
+	}
+	return c.CacheArt(source, instanceID, "header", data)
+}
+
+// cachedImage decodes an instance's cached art of the given type
+func (c *Composer) cachedImage(source, instanceID, artType string) (image.Image, error) {
+	data, err := c.GetCachedArt(source, instanceID, artType)
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cached %s: %w", artType, err)
+	}
+	return applyOrientation(img, readJPEGOrientation(data)), nil
+}
+
+// composeHeaderImage draws the background scaled to cover a 460x215 canvas and
+// overlays the logo, if any, according to the current options
+func (c *Composer) composeHeaderImage(backgroundImg, logoImg image.Image) ([]byte, error) {
+	// Target dimensions (Steam header size)
+	targetWidth, targetHeight := 460, 215
+
 	// Create target canvas
 	canvas := image.NewRGBA(image.Rect(0, 0, targetWidth, targetHeight))
 
@@ -94,40 +255,164 @@ func (c *Composer) ComposeHeader(screenshotURL, logoURL, coverURL, artworkURL, g
 	scaledBg := c.scaleToCover(backgroundImg, targetWidth, targetHeight)
 	draw.Draw(canvas, canvas.Bounds(), scaledBg, image.Point{}, draw.Src)
 
-	// Try to overlay logo
+	if logoImg != nil {
+		c.drawHeaderLogo(canvas, logoImg, c.Options())
+	}
+
+	// Encode as PNG
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, canvas); err != nil {
+		return nil, fmt.Errorf("failed to encode header image: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// drawHeaderLogo scales the logo and draws it onto canvas according to opts
+func (c *Composer) drawHeaderLogo(canvas *image.RGBA, logoImg image.Image, opts ComposeOptions) {
+	width, height := canvas.Bounds().Dx(), canvas.Bounds().Dy()
+
+	// Scale logo to the max width while preserving aspect ratio. Anchored
+	// logos are kept to half the height so they stay clear of the scene.
+	maxLogoWidth := width * opts.LogoMaxWidthPercent / 100
+	maxLogoHeight := height
+	if opts.LogoAnchor != LogoAnchorCenter {
+		maxLogoHeight = height / 2
+	}
+	scaledLogo := c.scalePreserveAspect(logoImg, maxLogoWidth, maxLogoHeight)
+	logoBounds := scaledLogo.Bounds()
+
+	margin := height / 20
+	var pos image.Point
+	switch opts.LogoAnchor {
+	case LogoAnchorBottomLeft:
+		pos = image.Point{X: margin, Y: height - logoBounds.Dy() - margin}
+	case LogoAnchorBottomCenter:
+		pos = image.Point{X: (width - logoBounds.Dx()) / 2, Y: height - logoBounds.Dy() - margin}
+	default:
+		pos = image.Point{X: (width - logoBounds.Dx()) / 2, Y: (height - logoBounds.Dy()) / 2}
+	}
+
+	logoRect := logoBounds.Add(pos)
+	if opts.ScrimOpacity > 0 && averageLuminance(canvas, logoRect) > darkLuminance {
+		c.drawScrim(canvas, logoRect, opts)
+	}
+
+	// Shadow uses the logo's alpha as a mask, offset down and to the right
+	if opts.LogoShadow {
+		shadow := image.NewUniform(color.RGBA{A: 110})
+		offset := image.Point{X: 2, Y: 2}
+		draw.DrawMask(canvas, logoBounds.Add(pos).Add(offset), shadow, image.Point{}, scaledLogo, image.Point{}, draw.Over)
+	}
+
+	// Draw logo with alpha blending
+	draw.Draw(canvas, logoBounds.Add(pos), scaledLogo, image.Point{}, draw.Over)
+}
+
+// darkLuminance is the average luminance (0-1) below which a background
+// already gives logos enough contrast
+const darkLuminance = 0.35
+
+// drawScrim darkens the band behind the logo with a vertical gradient. Bottom
+// anchored logos fade from the bottom edge upwards; centered logos fade out
+// both above and below.
+func (c *Composer) drawScrim(canvas *image.RGBA, logoRect image.Rectangle, opts ComposeOptions) {
+	bounds := canvas.Bounds()
+	padding := logoRect.Dy() / 2
+
+	band := image.Rect(bounds.Min.X, logoRect.Min.Y-padding, bounds.Max.X, logoRect.Max.Y+padding)
+	bottomUp := opts.LogoAnchor != LogoAnchorCenter
+	if bottomUp {
+		band.Max.Y = bounds.Max.Y
+	}
+	band = band.Intersect(bounds)
+	if band.Empty() {
+		return
+	}
+
+	peak := float64(opts.ScrimOpacity) / 100
+	for y := band.Min.Y; y < band.Max.Y; y++ {
+		// t runs from 0 at the faded edge to 1 at the darkest point
+		t := float64(y-band.Min.Y) / float64(band.Dy())
+		if !bottomUp {
+			t = 1 - math.Abs(2*t-1)
+		}
+		alpha := uint8(peak * t * 255)
+		line := image.Rect(band.Min.X, y, band.Max.X, y+1)
+		draw.Draw(canvas, line, image.NewUniform(color.RGBA{A: alpha}), image.Point{}, draw.Over)
+	}
+}
+
+// averageLuminance returns the mean relative luminance (0-1) of img within rect
+func averageLuminance(img image.Image, rect image.Rectangle) float64 {
+	rect = rect.Intersect(img.Bounds())
+	if rect.Empty() {
+		return 0
+	}
+
+	// Sampling every few pixels is plenty for a brightness estimate
+	const step = 4
+	var total float64
+	var samples int
+	for y := rect.Min.Y; y < rect.Max.Y; y += step {
+		for x := rect.Min.X; x < rect.Max.X; x += step {
+			r, g, b, _ := img.At(x, y).RGBA()
+			total += (0.2126*float64(r) + 0.7152*float64(g) + 0.0722*float64(b)) / 0xFFFF
+			samples++
+		}
+	}
+
+	return total / float64(samples)
+}
+
+// ComposePortrait creates a 600x900 portrait "grid" image:
+// - Background: Cover (scaled/cropped to fill)
+// - Overlay: Logo (centered near the bottom, max 80% width, preserve aspect ratio)
+// The logo is skipped if there isn't one or it fails to download.
+func (c *Composer) ComposePortrait(ctx context.Context, coverURL, logoURL string) ([]byte, error) {
+	targetWidth, targetHeight := 600, 900
+
+	if coverURL == "" {
+		return nil, fmt.Errorf("no cover available for portrait composition")
+	}
+
+	coverImg, err := c.downloadImage(ctx, coverURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download cover: %w", err)
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, targetWidth, targetHeight))
+	draw.Draw(canvas, canvas.Bounds(), c.scaleToCover(coverImg, targetWidth, targetHeight), image.Point{}, draw.Src)
+
 	if logoURL != "" {
-		logoImg, err := c.downloadImage(logoURL)
+		logoImg, err := c.downloadImage(ctx, logoURL)
 		if err != nil {
-			c.logger.Warn("failed to download logo for header", "error", err, "gameID", gameID)
+			c.logger.Warn("failed to download logo for portrait, using cover only", "error", err)
 		} else {
-			// Scale logo to max 50% width while preserving aspect ratio
-			maxLogoWidth := int(float32(targetWidth) * .6)
-			scaledLogo := c.scalePreserveAspect(logoImg, maxLogoWidth, targetHeight)
+			// Keep the logo in the bottom quarter so it doesn't hide the cover art
+			maxLogoWidth := int(float32(targetWidth) * .8)
+			maxLogoHeight := targetHeight / 4
+			scaledLogo := c.scalePreserveAspect(logoImg, maxLogoWidth, maxLogoHeight)
 
-			// Center the logo
 			logoBounds := scaledLogo.Bounds()
+			margin := targetHeight / 20
 			x := (targetWidth - logoBounds.Dx()) / 2
-			y := (targetHeight - logoBounds.Dy()) / 2
-			centerPoint := image.Point{X: x, Y: y}
-
-			// Draw logo with alpha blending
-			draw.Draw(canvas, logoBounds.Add(centerPoint), scaledLogo, image.Point{}, draw.Over)
-			c.logger.Debug("composed logo onto header", "gameID", gameID)
+			y := targetHeight - logoBounds.Dy() - margin
+			draw.Draw(canvas, logoBounds.Add(image.Point{X: x, Y: y}), scaledLogo, image.Point{}, draw.Over)
 		}
 	}
 
-	// Encode as PNG
 	var buf bytes.Buffer
 	if err := png.Encode(&buf, canvas); err != nil {
-		return nil, fmt.Errorf("failed to encode header image: %w", err)
+		return nil, fmt.Errorf("failed to encode portrait image: %w", err)
 	}
 
 	return buf.Bytes(), nil
 }
 
 // DownloadArt downloads art from URL and returns the image
-func (c *Composer) DownloadArt(url string) ([]byte, string, error) {
-	return c.downloadImageBytes(url)
+func (c *Composer) DownloadArt(ctx context.Context, url string) ([]byte, string, error) {
+	return c.downloadImageBytes(ctx, url)
 }
 
 // CacheArt saves art to the cache directory
@@ -163,20 +448,19 @@ func (c *Composer) HasCachedArt(source string, instanceID, artType string) bool
 }
 
 // DownloadAllArt downloads all art types concurrently
-func (c *Composer) DownloadAllArt(artURLs map[string]string) map[string][]byte {
+func (c *Composer) DownloadAllArt(ctx context.Context, artURLs map[string]string) map[string][]byte {
 	results := make(map[string][]byte)
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
-	artTypes := []string{"screenshot", "logo", "cover", "artwork"}
 	c.logger.Info(fmt.Sprintf("Downloading artURLs: %#v", artURLs))
 
-	for _, artType := range artTypes {
-		if url, ok := artURLs[artType]; ok && url != "" {
+	for artType, url := range artURLs {
+		if IsDownloadableArtType(artType) && url != "" {
 			wg.Add(1)
 			go func(t, u string) {
 				defer wg.Done()
-				data, _, err := c.downloadImageBytes(u)
+				data, _, err := c.downloadImageBytes(ctx, u)
 				if err != nil {
 					c.logger.Warn("failed to download art", "type", t, "error", err)
 					return
@@ -192,9 +476,29 @@ func (c *Composer) DownloadAllArt(artURLs map[string]string) map[string][]byte {
 	return results
 }
 
+// downloadableArtTypes are the art types fetched as-is from resolver URLs
+var downloadableArtTypes = map[string]bool{
+	"screenshot": true,
+	"logo":       true,
+	"cover":      true,
+	"artwork":    true,
+}
+
+// IsDownloadableArtType reports whether artType is a plain or indexed
+// ("screenshot.2") downloadable art type
+func IsDownloadableArtType(artType string) bool {
+	base, index, indexed := strings.Cut(artType, ".")
+	if indexed {
+		if _, err := strconv.Atoi(index); err != nil {
+			return false
+		}
+	}
+	return downloadableArtTypes[base]
+}
+
 // downloadImage downloads and decodes an image from URL
-func (c *Composer) downloadImage(url string) (image.Image, error) {
-	data, format, err := c.downloadImageBytes(url)
+func (c *Composer) downloadImage(ctx context.Context, url string) (image.Image, error) {
+	data, format, err := c.downloadImageBytes(ctx, url)
 	if err != nil {
 		return nil, err
 	}
@@ -204,14 +508,54 @@ func (c *Composer) downloadImage(url string) (image.Image, error) {
 		return nil, fmt.Errorf("failed to decode %s image: %w", format, err)
 	}
 
-	return img, nil
+	// image.Decode ignores EXIF, so camera-rotated JPEGs would come out sideways
+	return applyOrientation(img, readJPEGOrientation(data)), nil
+}
+
+// downloadImageBytes downloads image bytes and detects format, retrying
+// network errors and server errors with exponential backoff
+func (c *Composer) downloadImageBytes(ctx context.Context, url string) ([]byte, string, error) {
+	c.mu.RLock()
+	attempts, delay := c.retryAttempts, c.retryBaseDelay
+	c.mu.RUnlock()
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var data []byte
+		var format string
+		data, format, err = c.downloadImageOnce(ctx, url)
+		if err == nil {
+			return data, format, nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt >= attempts || ctx.Err() != nil {
+			return nil, "", err
+		}
+
+		c.logger.Debug("retrying image download", "url", url, "attempt", attempt, "error", err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, "", fmt.Errorf("image download cancelled: %w", ctx.Err())
+		}
+		delay *= 2
+	}
+}
+
+// permanentError marks a download failure that retrying won't fix
+type permanentError struct {
+	err error
 }
 
-// downloadImageBytes downloads image bytes and detects format
-func (c *Composer) downloadImageBytes(url string) ([]byte, string, error) {
-	req, err := http.NewRequest("GET", url, nil)
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// downloadImageOnce makes a single download attempt
+func (c *Composer) downloadImageOnce(ctx context.Context, url string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to create request: %w", err)
+		return nil, "", &permanentError{fmt.Errorf("failed to create request: %w", err)}
 	}
 
 	resp, err := c.client.Do(req)
@@ -220,9 +564,13 @@ func (c *Composer) downloadImageBytes(url string) ([]byte, string, error) {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	// Server errors and rate limiting are transient; other statuses, like 404, are not
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
 		return nil, "", fmt.Errorf("image download returned status %d", resp.StatusCode)
 	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", &permanentError{fmt.Errorf("image download returned status %d", resp.StatusCode)}
+	}
 
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
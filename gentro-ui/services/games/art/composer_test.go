@@ -0,0 +1,339 @@
+package art
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestComposer creates a composer that writes to a temporary cache
+func newTestComposer(t *testing.T) *Composer {
+	t.Helper()
+	return NewComposer(t.TempDir(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// encodePNG returns a solid-color PNG of the given size
+func encodePNG(t *testing.T, width, height int, c color.Color) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// serveImages starts a server returning the given bytes for each path
+func serveImages(t *testing.T, images map[string][]byte) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := images[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestComposePortrait(t *testing.T) {
+	cover := encodePNG(t, 264, 352, color.RGBA{R: 255, A: 255})
+	logo := encodePNG(t, 400, 100, color.RGBA{B: 255, A: 255})
+	server := serveImages(t, map[string][]byte{"/cover.png": cover, "/logo.png": logo})
+
+	composer := newTestComposer(t)
+
+	data, err := composer.ComposePortrait(context.Background(), server.URL+"/cover.png", server.URL+"/logo.png")
+	if err != nil {
+		t.Fatalf("ComposePortrait failed: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode portrait: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 600 || b.Dy() != 900 {
+		t.Errorf("expected 600x900 portrait, got %dx%d", b.Dx(), b.Dy())
+	}
+
+	// Top stays cover, bottom center carries the logo
+	if r, _, b, _ := img.At(300, 50).RGBA(); r == 0 || b != 0 {
+		t.Errorf("expected cover at top of portrait")
+	}
+	if r, _, b, _ := img.At(300, 800).RGBA(); r != 0 || b == 0 {
+		t.Errorf("expected logo near bottom of portrait")
+	}
+}
+
+func TestComposePortrait_NoLogo(t *testing.T) {
+	coverImg := image.NewRGBA(image.Rect(0, 0, 264, 352))
+	draw.Draw(coverImg, coverImg.Bounds(), image.NewUniform(color.RGBA{R: 255, A: 255}), image.Point{}, draw.Src)
+	var cover bytes.Buffer
+	if err := jpeg.Encode(&cover, coverImg, nil); err != nil {
+		t.Fatalf("failed to encode jpeg: %v", err)
+	}
+	server := serveImages(t, map[string][]byte{"/cover.jpg": cover.Bytes()})
+
+	composer := newTestComposer(t)
+
+	for _, logoURL := range []string{"", server.URL + "/missing.png"} {
+		data, err := composer.ComposePortrait(context.Background(), server.URL+"/cover.jpg", logoURL)
+		if err != nil {
+			t.Fatalf("ComposePortrait failed: %v", err)
+		}
+
+		// The JPEG cover is still fitted to the portrait and encoded as PNG
+		img, format, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("failed to decode portrait: %v", err)
+		}
+		if format != "png" {
+			t.Errorf("expected png portrait when logo is %q, got %s", logoURL, format)
+		}
+		if b := img.Bounds(); b.Dx() != 600 || b.Dy() != 900 {
+			t.Errorf("expected 600x900 portrait when logo is %q, got %dx%d", logoURL, b.Dx(), b.Dy())
+		}
+	}
+}
+
+func TestComposeHeader_LogoAnchor(t *testing.T) {
+	screenshot := encodePNG(t, 920, 430, color.RGBA{R: 255, A: 255})
+	logo := encodePNG(t, 200, 100, color.RGBA{B: 255, A: 255})
+	server := serveImages(t, map[string][]byte{"/shot.png": screenshot, "/logo.png": logo})
+
+	isLogo := func(img image.Image, x, y int) bool {
+		r, _, b, _ := img.At(x, y).RGBA()
+		return b > 0x8000 && r < 0x8000
+	}
+
+	tests := []struct {
+		anchor   LogoAnchor
+		logoAt   image.Point
+		sceneAt  image.Point
+		maxWidth int
+	}{
+		{LogoAnchorCenter, image.Point{X: 230, Y: 107}, image.Point{X: 20, Y: 200}, 60},
+		{LogoAnchorBottomLeft, image.Point{X: 20, Y: 200}, image.Point{X: 230, Y: 20}, 30},
+		{LogoAnchorBottomCenter, image.Point{X: 230, Y: 200}, image.Point{X: 230, Y: 20}, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.anchor), func(t *testing.T) {
+			composer := newTestComposer(t)
+			composer.SetOptions(ComposeOptions{LogoAnchor: tt.anchor, LogoMaxWidthPercent: tt.maxWidth})
+
+			data, err := composer.ComposeHeader(context.Background(), server.URL+"/shot.png", server.URL+"/logo.png", "", "", "game1")
+			if err != nil {
+				t.Fatalf("ComposeHeader failed: %v", err)
+			}
+			img, err := png.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("failed to decode header: %v", err)
+			}
+
+			if !isLogo(img, tt.logoAt.X, tt.logoAt.Y) {
+				t.Errorf("expected logo at %v", tt.logoAt)
+			}
+			if isLogo(img, tt.sceneAt.X, tt.sceneAt.Y) {
+				t.Errorf("expected screenshot at %v", tt.sceneAt)
+			}
+		})
+	}
+}
+
+func TestRecomposeHeader(t *testing.T) {
+	composer := newTestComposer(t)
+	if err := composer.CacheArt("mock", "inst1", "screenshot", encodePNG(t, 920, 430, color.RGBA{R: 255, A: 255})); err != nil {
+		t.Fatalf("failed to cache screenshot: %v", err)
+	}
+	if err := composer.CacheArt("mock", "inst1", "logo", encodePNG(t, 200, 100, color.RGBA{B: 255, A: 255})); err != nil {
+		t.Fatalf("failed to cache logo: %v", err)
+	}
+
+	logoAt := func(x, y int) bool {
+		t.Helper()
+		data, err := composer.GetCachedArt("mock", "inst1", "header")
+		if err != nil {
+			t.Fatalf("expected a cached header: %v", err)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("failed to decode header: %v", err)
+		}
+		r, _, b, _ := img.At(x, y).RGBA()
+		return b > 0x8000 && r < 0x8000
+	}
+
+	composer.SetOptions(ComposeOptions{LogoAnchor: LogoAnchorCenter, LogoMaxWidthPercent: 30})
+	if err := composer.RecomposeHeader("mock", "inst1"); err != nil {
+		t.Fatalf("RecomposeHeader failed: %v", err)
+	}
+	if !logoAt(230, 107) {
+		t.Error("expected centred logo")
+	}
+
+	composer.SetOptions(ComposeOptions{LogoAnchor: LogoAnchorBottomLeft, LogoMaxWidthPercent: 30})
+	if err := composer.RecomposeHeader("mock", "inst1"); err != nil {
+		t.Fatalf("RecomposeHeader failed: %v", err)
+	}
+	if !logoAt(20, 200) || logoAt(230, 107) {
+		t.Error("expected the header to be rebuilt with the logo bottom-left")
+	}
+
+	if err := composer.RecomposeHeader("mock", "bare"); err == nil {
+		t.Error("expected an error without cached background art")
+	}
+}
+
+func TestSetOptions_InvalidFallsBackToDefaults(t *testing.T) {
+	composer := newTestComposer(t)
+	composer.SetOptions(ComposeOptions{LogoAnchor: "top-right", LogoMaxWidthPercent: 150})
+
+	opts := composer.Options()
+	defaults := DefaultComposeOptions()
+	if opts.LogoAnchor != defaults.LogoAnchor || opts.LogoMaxWidthPercent != defaults.LogoMaxWidthPercent {
+		t.Errorf("expected invalid options to fall back to defaults, got %+v", opts)
+	}
+}
+
+func TestComposeHeader_Scrim(t *testing.T) {
+	// Logo with an opaque border and a transparent middle, so the center pixel
+	// shows whatever is painted behind the logo
+	logoImg := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 200; x++ {
+			if x < 10 || x >= 190 || y < 10 || y >= 90 {
+				logoImg.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+	var logo bytes.Buffer
+	if err := png.Encode(&logo, logoImg); err != nil {
+		t.Fatalf("failed to encode logo: %v", err)
+	}
+
+	server := serveImages(t, map[string][]byte{
+		"/bright.png": encodePNG(t, 460, 215, color.White),
+		"/dark.png":   encodePNG(t, 460, 215, color.Gray{Y: 40}),
+		"/logo.png":   logo.Bytes(),
+	})
+
+	luminanceUnderLogo := func(background string, scrimOpacity int) float64 {
+		composer := newTestComposer(t)
+		composer.SetOptions(ComposeOptions{LogoAnchor: LogoAnchorCenter, LogoMaxWidthPercent: 60, ScrimOpacity: scrimOpacity})
+
+		data, err := composer.ComposeHeader(context.Background(), server.URL+background, server.URL+"/logo.png", "", "", "game1")
+		if err != nil {
+			t.Fatalf("ComposeHeader failed: %v", err)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("failed to decode header: %v", err)
+		}
+		return averageLuminance(img, image.Rect(220, 100, 240, 115))
+	}
+
+	bright, brightScrim := luminanceUnderLogo("/bright.png", 0), luminanceUnderLogo("/bright.png", 50)
+	if brightScrim > bright-0.3 {
+		t.Errorf("expected scrim to darken bright background under logo: %.2f -> %.2f", bright, brightScrim)
+	}
+
+	dark, darkScrim := luminanceUnderLogo("/dark.png", 0), luminanceUnderLogo("/dark.png", 50)
+	if dark != darkScrim {
+		t.Errorf("expected scrim to be skipped on dark background: %.2f -> %.2f", dark, darkScrim)
+	}
+}
+
+func TestDownloadImageBytes_Retry(t *testing.T) {
+	img := encodePNG(t, 4, 4, color.White)
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		switch r.URL.Path {
+		case "/flaky.png":
+			if n <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write(img)
+		case "/down.png":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	composer := newTestComposer(t)
+	composer.SetRetry(3, time.Millisecond)
+
+	t.Run("transient failures are retried", func(t *testing.T) {
+		requests.Store(0)
+		data, _, err := composer.downloadImageBytes(context.Background(), server.URL+"/flaky.png")
+		if err != nil {
+			t.Fatalf("expected success after retries, got %v", err)
+		}
+		if !bytes.Equal(data, img) || requests.Load() != 3 {
+			t.Errorf("expected image after 3 requests, got %d requests", requests.Load())
+		}
+	})
+
+	t.Run("not found is permanent", func(t *testing.T) {
+		requests.Store(0)
+		if _, _, err := composer.downloadImageBytes(context.Background(), server.URL+"/missing.png"); err == nil {
+			t.Fatal("expected an error")
+		}
+		if requests.Load() != 1 {
+			t.Errorf("expected a single request for 404, got %d", requests.Load())
+		}
+	})
+
+	t.Run("gives up after the configured attempts", func(t *testing.T) {
+		requests.Store(0)
+		if _, _, err := composer.downloadImageBytes(context.Background(), server.URL+"/down.png"); err == nil {
+			t.Fatal("expected an error")
+		}
+		if requests.Load() != 3 {
+			t.Errorf("expected 3 attempts, got %d", requests.Load())
+		}
+	})
+
+	t.Run("cancellation stops retries", func(t *testing.T) {
+		requests.Store(0)
+		composer.SetRetry(5, time.Hour)
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		if _, _, err := composer.downloadImageBytes(ctx, server.URL+"/down.png"); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected cancellation, got %v", err)
+		}
+		if requests.Load() != 1 {
+			t.Errorf("expected no retries after cancel, got %d requests", requests.Load())
+		}
+	})
+}
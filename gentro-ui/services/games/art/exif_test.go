@@ -0,0 +1,91 @@
+package art
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// rotatedJPEG builds a 40x20 JPEG, red on the left half and blue on the right,
+// tagged with the given EXIF orientation
+func rotatedJPEG(t *testing.T, orientation uint16) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			if x < 20 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatalf("failed to encode jpeg: %v", err)
+	}
+
+	// Big-endian TIFF block with a single IFD0 entry for orientation
+	var tiff bytes.Buffer
+	tiff.WriteString("MM")
+	binary.Write(&tiff, binary.BigEndian, uint16(42))
+	binary.Write(&tiff, binary.BigEndian, uint32(8))
+	binary.Write(&tiff, binary.BigEndian, uint16(1))
+	binary.Write(&tiff, binary.BigEndian, uint16(exifOrientationTag))
+	binary.Write(&tiff, binary.BigEndian, uint16(3)) // SHORT
+	binary.Write(&tiff, binary.BigEndian, uint32(1))
+	binary.Write(&tiff, binary.BigEndian, orientation)
+	binary.Write(&tiff, binary.BigEndian, uint16(0))
+	binary.Write(&tiff, binary.BigEndian, uint32(0))
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+
+	// Insert the APP1 segment right after the SOI marker
+	var out bytes.Buffer
+	out.Write(encoded.Bytes()[:2])
+	out.Write([]byte{0xFF, 0xE1})
+	binary.Write(&out, binary.BigEndian, uint16(len(payload)+2))
+	out.Write(payload)
+	out.Write(encoded.Bytes()[2:])
+
+	return out.Bytes()
+}
+
+func TestReadJPEGOrientation(t *testing.T) {
+	for orientation := uint16(1); orientation <= 8; orientation++ {
+		if got := readJPEGOrientation(rotatedJPEG(t, orientation)); got != int(orientation) {
+			t.Errorf("expected orientation %d, got %d", orientation, got)
+		}
+	}
+
+	if got := readJPEGOrientation(encodePNG(t, 2, 2, color.White)); got != 1 {
+		t.Errorf("expected orientation 1 for PNG, got %d", got)
+	}
+}
+
+func TestDownloadImage_AppliesOrientation(t *testing.T) {
+	// Orientation 6 means the stored image must be rotated 90 degrees clockwise,
+	// so the red left half ends up on top
+	server := serveImages(t, map[string][]byte{"/rotated.jpg": rotatedJPEG(t, 6)})
+
+	img, err := newTestComposer(t).downloadImage(context.Background(), server.URL+"/rotated.jpg")
+	if err != nil {
+		t.Fatalf("downloadImage failed: %v", err)
+	}
+
+	if b := img.Bounds(); b.Dx() != 20 || b.Dy() != 40 {
+		t.Fatalf("expected 20x40 after rotation, got %dx%d", b.Dx(), b.Dy())
+	}
+	if r, _, b, _ := img.At(10, 5).RGBA(); r < 0x8000 || b > 0x8000 {
+		t.Errorf("expected red at top after rotation")
+	}
+	if r, _, b, _ := img.At(10, 35).RGBA(); r > 0x8000 || b < 0x8000 {
+		t.Errorf("expected blue at bottom after rotation")
+	}
+}
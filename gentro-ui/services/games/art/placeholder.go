@@ -0,0 +1,162 @@
+package art
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+var (
+	placeholderOnce sync.Once
+	placeholderPNG  []byte
+
+	placeholderFontOnce sync.Once
+	placeholderFont     *opentype.Font
+	placeholderFontErr  error
+)
+
+// placeholderSizes are the dimensions generated placeholders are drawn at, per art type
+var placeholderSizes = map[string][2]int{
+	"grid":       {600, 900},
+	"cover":      {600, 900},
+	"header":     {460, 215},
+	"hero":       {1920, 620},
+	"artwork":    {1920, 1080},
+	"screenshot": {1920, 1080},
+	"logo":       {640, 360},
+	"icon":       {256, 256},
+}
+
+// Placeholder returns a neutral 460x215 PNG served when a game has no art
+func Placeholder() []byte {
+	placeholderOnce.Do(func() {
+		img := image.NewRGBA(image.Rect(0, 0, 460, 215))
+		draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{R: 0x2a, G: 0x2d, B: 0x34, A: 0xff}), image.Point{}, draw.Src)
+
+		var buf bytes.Buffer
+		png.Encode(&buf, img)
+		placeholderPNG = buf.Bytes()
+	})
+	return placeholderPNG
+}
+
+// PlaceholderSize returns the dimensions of a generated placeholder for an art type.
+// Unknown types get the header size.
+func PlaceholderSize(artType string) (w, h int) {
+	if size, ok := placeholderSizes[artType]; ok {
+		return size[0], size[1]
+	}
+	return 460, 215
+}
+
+// GeneratePlaceholder draws a w x h PNG showing the initials of name on a
+// background colour derived from a hash of the name, so the same game always
+// gets the same placeholder. Falls back to the neutral Placeholder if drawing fails.
+func GeneratePlaceholder(name string, w, h int) []byte {
+	if w <= 0 || h <= 0 {
+		return Placeholder()
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(placeholderColor(name)), image.Point{}, draw.Src)
+
+	if err := drawCentered(img, placeholderInitials(name)); err != nil {
+		return Placeholder()
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return Placeholder()
+	}
+	return buf.Bytes()
+}
+
+// placeholderInitials returns up to two uppercase initials from the words of name
+func placeholderInitials(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var initials []rune
+	for _, word := range words {
+		initials = append(initials, unicode.ToUpper([]rune(word)[0]))
+		if len(initials) == 2 {
+			break
+		}
+	}
+	if len(initials) == 0 {
+		return "?"
+	}
+	return string(initials)
+}
+
+// placeholderColor picks a muted background colour from a hash of name. Only the
+// hue varies, so white text stays readable on every colour.
+func placeholderColor(name string) color.RGBA {
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+	hue := float64(hash.Sum32()%360) / 60
+
+	// HSL to RGB with fixed saturation and lightness
+	const saturation, lightness = 0.45, 0.35
+	chroma := (1 - math.Abs(2*lightness-1)) * saturation
+	x := chroma * (1 - math.Abs(math.Mod(hue, 2)-1))
+	var r, g, b float64
+	switch int(hue) {
+	case 0:
+		r, g = chroma, x
+	case 1:
+		r, g = x, chroma
+	case 2:
+		g, b = chroma, x
+	case 3:
+		g, b = x, chroma
+	case 4:
+		r, b = x, chroma
+	default:
+		r, b = chroma, x
+	}
+	m := lightness - chroma/2
+	return color.RGBA{R: uint8((r + m) * 255), G: uint8((g + m) * 255), B: uint8((b + m) * 255), A: 0xff}
+}
+
+// drawCentered draws text in white, centred in img and sized to its shorter side
+func drawCentered(img *image.RGBA, text string) error {
+	placeholderFontOnce.Do(func() {
+		placeholderFont, placeholderFontErr = opentype.Parse(gobold.TTF)
+	})
+	if placeholderFontErr != nil {
+		return fmt.Errorf("failed to parse placeholder font: %w", placeholderFontErr)
+	}
+
+	bounds := img.Bounds()
+	size := float64(min(bounds.Dx(), bounds.Dy())) * 0.4
+	face, err := opentype.NewFace(placeholderFont, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return fmt.Errorf("failed to create placeholder font face: %w", err)
+	}
+	defer face.Close()
+
+	drawer := &font.Drawer{Dst: img, Src: image.White, Face: face}
+	metrics := face.Metrics()
+	width := drawer.MeasureString(text)
+	drawer.Dot = fixed.Point26_6{
+		X: (fixed.I(bounds.Dx()) - width) / 2,
+		Y: (fixed.I(bounds.Dy()) + metrics.Ascent - metrics.Descent) / 2,
+	}
+	drawer.DrawString(text)
+	return nil
+}
@@ -0,0 +1,47 @@
+package art
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+func TestPlaceholderInitials(t *testing.T) {
+	tests := map[string]string{
+		"Super Metroid":            "SM",
+		"the legend of zelda":      "TL",
+		"Doom":                     "D",
+		"F-Zero: Maximum Velocity": "FZ",
+		"  ":                       "?",
+		"Ōkami":                    "Ō",
+		"1080° Snowboarding":       "1S",
+	}
+	for name, want := range tests {
+		if got := placeholderInitials(name); got != want {
+			t.Errorf("placeholderInitials(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestGeneratePlaceholder(t *testing.T) {
+	data := GeneratePlaceholder("Super Metroid", 600, 900)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode placeholder: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 600 || b.Dy() != 900 {
+		t.Errorf("expected 600x900, got %dx%d", b.Dx(), b.Dy())
+	}
+
+	// The initials sit in the middle, so the corner shows the background
+	if img.At(0, 0) != placeholderColor("Super Metroid") {
+		t.Errorf("expected background %v, got %v", placeholderColor("Super Metroid"), img.At(0, 0))
+	}
+	if !bytes.Equal(data, GeneratePlaceholder("Super Metroid", 600, 900)) {
+		t.Error("expected the same name to produce the same placeholder")
+	}
+	if placeholderColor("Super Metroid") == placeholderColor("Chrono Trigger") {
+		t.Error("expected different names to get different colours")
+	}
+}
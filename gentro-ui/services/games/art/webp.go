@@ -0,0 +1,67 @@
+package art
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+
+	"github.com/HugoSmits86/nativewebp"
+)
+
+// EncodeWebP transcodes PNG or JPEG data to lossless WebP. EXIF orientation is
+// applied, since WebP carries none.
+func EncodeWebP(data []byte) ([]byte, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	img = applyOrientation(img, readJPEGOrientation(data))
+
+	var buf bytes.Buffer
+	if err := nativewebp.Encode(&buf, img, nil); err != nil {
+		return nil, fmt.Errorf("failed to encode %s as webp: %w", format, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// CachedWebP returns the path of a WebP copy of the art file at srcPath, transcoding
+// it when the copy is missing or older than the file. Copies sit beside the
+// instance's other cached art.
+func (c *Composer) CachedWebP(srcPath, source, instanceID, artType string) (string, error) {
+	srcInfo, err := os.Stat(srcPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat art: %w", err)
+	}
+
+	path := filepath.Join(c.cacheDir, source, instanceID, artType+".webp")
+	if info, err := os.Stat(path); err == nil && !info.ModTime().Before(srcInfo.ModTime()) {
+		return path, nil
+	}
+
+	data, err := os.ReadFile(srcPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read art: %w", err)
+	}
+	encoded, err := EncodeWebP(data)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create art cache directory: %w", err)
+	}
+	// Write then rename so concurrent requests never serve a partial file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, encoded, 0644); err != nil {
+		return "", fmt.Errorf("failed to write webp cache: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("failed to write webp cache: %w", err)
+	}
+
+	c.logger.Debug("cached webp art", "instanceID", instanceID, "artType", artType, "bytes", len(encoded))
+	return path, nil
+}
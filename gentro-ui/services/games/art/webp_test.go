@@ -0,0 +1,83 @@
+package art
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/image/webp"
+)
+
+// writePNG writes a solid-colored PNG and returns its path
+func writePNG(t *testing.T, dir string, c color.Color) string {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode png: %v", err)
+	}
+	path := filepath.Join(dir, "cover.png")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("failed to write png: %v", err)
+	}
+	return path
+}
+
+func TestCachedWebP(t *testing.T) {
+	composer := NewComposer(t.TempDir(), nil)
+	src := writePNG(t, t.TempDir(), color.RGBA{R: 255, A: 255})
+
+	path, err := composer.CachedWebP(src, "mock", "inst1", "cover")
+	if err != nil {
+		t.Fatalf("CachedWebP failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read webp: %v", err)
+	}
+	img, err := webp.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("expected a valid webp: %v", err)
+	}
+	if r, _, _, _ := img.At(0, 0).RGBA(); r>>8 != 255 {
+		t.Errorf("expected the red source pixel, got %v", img.At(0, 0))
+	}
+
+	// A fresh copy is reused as is
+	cached, _ := os.Stat(path)
+	if again, err := composer.CachedWebP(src, "mock", "inst1", "cover"); err != nil || again != path {
+		t.Fatalf("expected the cached copy, got %q (%v)", again, err)
+	}
+	if info, _ := os.Stat(path); !info.ModTime().Equal(cached.ModTime()) {
+		t.Error("expected a fresh copy not to be rewritten")
+	}
+
+	// Replacing the source art invalidates the copy
+	writePNG(t, filepath.Dir(src), color.RGBA{B: 255, A: 255})
+	future := time.Now().Add(time.Hour)
+	if err := os.Chtimes(src, future, future); err != nil {
+		t.Fatalf("failed to touch source: %v", err)
+	}
+	if _, err := composer.CachedWebP(src, "mock", "inst1", "cover"); err != nil {
+		t.Fatalf("CachedWebP failed: %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	img, err = webp.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("expected a valid webp: %v", err)
+	}
+	if _, _, b, _ := img.At(0, 0).RGBA(); b>>8 != 255 {
+		t.Errorf("expected the stale copy to be re-encoded, got %v", img.At(0, 0))
+	}
+}
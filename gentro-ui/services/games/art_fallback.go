@@ -0,0 +1,77 @@
+package games
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// defaultArtFallbacks lists the art types tried, in order, when a requested type
+// doesn't exist, so the UI always gets the closest available image
+var defaultArtFallbacks = map[string][]string{
+	"logo":   {"cover"},
+	"hero":   {"artwork", "screenshot"},
+	"header": {"screenshot", "artwork", "cover"},
+	"grid":   {"cover"},
+}
+
+// artFallbacks returns the fallback chain for an art type. A chain set in the
+// config replaces the default; an empty one disables fallback for that type.
+func (s *GamesService) artFallbacks(artType string) []string {
+	if s.config != nil {
+		if chain, ok := s.config.Get().Art.Fallbacks[artType]; ok {
+			return chain
+		}
+	}
+	return defaultArtFallbacks[artType]
+}
+
+// resolvedArt is art found by getArtWithFallback. Path is set when the source
+// keeps the art on disk; otherwise Data holds it.
+type resolvedArt struct {
+	Path        string
+	Data        []byte
+	ContentType string
+	ArtType     string
+}
+
+// getArtWithFallback fetches artType from the source, walking its fallback chain
+// on ErrArtNotFound. Art files on disk are preferred so they can be streamed.
+func (s *GamesService) getArtWithFallback(ctx context.Context, source GameSource, instance models.GameInstance, artType string) (resolvedArt, error) {
+	candidates := append([]string{artType}, s.artFallbacks(artType)...)
+
+	var err error
+	for _, candidate := range candidates {
+		var found resolvedArt
+		found, err = s.getArt(ctx, source, instance, candidate)
+		if err == nil {
+			return found, nil
+		}
+		if !errors.Is(err, models.ErrArtNotFound) {
+			return resolvedArt{}, err
+		}
+	}
+
+	return resolvedArt{}, err
+}
+
+// getArt fetches a single art type, as a file path when the source supports it
+func (s *GamesService) getArt(ctx context.Context, source GameSource, instance models.GameInstance, artType string) (resolvedArt, error) {
+	if files, ok := source.(ArtFileSource); ok {
+		path, err := files.GameArtPath(ctx, instance, artType)
+		if err == nil {
+			return resolvedArt{Path: path, ArtType: artType}, nil
+		}
+		if errors.Is(err, models.ErrArtNotFound) {
+			return resolvedArt{}, err
+		}
+		s.logger.Debug("art file unavailable, reading art into memory", "error", err, "instanceID", instance.ID, "artType", artType)
+	}
+
+	data, contentType, err := source.GetGameArt(ctx, instance, artType)
+	if err != nil {
+		return resolvedArt{}, err
+	}
+	return resolvedArt{Data: data, ContentType: contentType, ArtType: artType}, nil
+}
@@ -0,0 +1,105 @@
+package games
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// importArtExtensions are the image files ImportArtFolder considers
+var importArtExtensions = map[string]bool{".png": true, ".jpg": true, ".jpeg": true}
+
+// ImportArtFolder caches the images in dir as artType art for the instances whose
+// names match their filenames, ignoring case, punctuation and tags like "(USA)".
+// A file matching instances of more than one game is ambiguous and skipped. Returns
+// how many files were imported.
+func (s *GamesService) ImportArtFolder(dir string, artType string) (int, error) {
+	if !validArtType(artType) {
+		return 0, fmt.Errorf("invalid art type: %q", artType)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read art folder: %w", err)
+	}
+
+	instances, err := s.db.GetInstances(models.GameFilter{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get instances: %w", err)
+	}
+
+	// Index every instance under both its display name and its game's name
+	byName := make(map[string][]models.GameInstance)
+	for _, instance := range instances {
+		names := []string{s.getDisplayName(instance)}
+		if game, err := s.db.GetGame(instance.GameID); err == nil && game != nil {
+			names = append(names, game.Name)
+		}
+
+		keys := make(map[string]bool)
+		for _, name := range names {
+			if key := importArtKey(name); key != "" {
+				keys[key] = true
+			}
+		}
+		for key := range keys {
+			byName[key] = append(byName[key], instance)
+		}
+	}
+
+	imported := 0
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || !importArtExtensions[ext] {
+			continue
+		}
+
+		matches := byName[importArtKey(strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())))]
+		if len(matches) == 0 {
+			s.logger.Debug("no instance matches art file", "file", entry.Name())
+			continue
+		}
+
+		gameIDs := make(map[string]bool)
+		for _, instance := range matches {
+			gameIDs[instance.GameID] = true
+		}
+		if len(gameIDs) > 1 {
+			s.logger.Warn("skipping ambiguous art file", "file", entry.Name(), "games", slices.Sorted(maps.Keys(gameIDs)))
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			s.logger.Warn("failed to read art file", "file", entry.Name(), "error", err)
+			continue
+		}
+
+		cached := false
+		for _, instance := range matches {
+			if err := s.artComposer.CacheArt(instance.Source, instance.ID, artType, data); err != nil {
+				s.logger.Warn("failed to cache imported art", "file", entry.Name(), "instanceID", instance.ID, "error", err)
+				s.metrics.countError(metricErrorArtCache)
+				continue
+			}
+			cached = true
+		}
+		if cached {
+			imported++
+		}
+	}
+
+	s.logger.Info("imported art folder", "dir", dir, "artType", artType, "imported", imported)
+	return imported, nil
+}
+
+// importArtKey reduces a name to its matching key. Spaces are dropped too, so
+// "SuperMetroid.png" still matches "Super Metroid".
+func importArtKey(name string) string {
+	return strings.ReplaceAll(normalizeDuplicateName(name), " ", "")
+}
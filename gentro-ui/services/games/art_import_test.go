@@ -0,0 +1,67 @@
+package games
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/games/art"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestImportArtFolder(t *testing.T) {
+	service := newTestService(t)
+	service.artComposer = art.NewComposer(t.TempDir(), service.logger)
+
+	named := func(id, gameID, name string) models.GameInstance {
+		return models.GameInstance{ID: id, GameID: gameID, Source: "mock", Platform: "snes", SourceData: map[string]any{"displayName": name}}
+	}
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		named("metroid", "super-metroid", "Super Metroid"),
+		named("chrono1", "chrono-trigger", "Chrono Trigger"),
+		named("chrono2", "chrono-trigger", "Chrono Trigger"),
+		named("tetris-gb", "tetris-gb", "Tetris"),
+		named("tetris-nes", "tetris-nes", "Tetris"),
+	}); err != nil {
+		t.Fatalf("failed to seed instances: %v", err)
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"Super Metroid (USA).png", "chrono_trigger.JPG", "Tetris.png", "Unknown Game.png", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatalf("failed to write art: %v", err)
+		}
+	}
+
+	imported, err := service.ImportArtFolder(dir, "cover")
+	if err != nil {
+		t.Fatalf("ImportArtFolder failed: %v", err)
+	}
+	if imported != 2 {
+		t.Errorf("expected 2 imported files, got %d", imported)
+	}
+
+	want := map[string]string{
+		"metroid":    "Super Metroid (USA).png",
+		"chrono1":    "chrono_trigger.JPG",
+		"chrono2":    "chrono_trigger.JPG",
+		"tetris-gb":  "",
+		"tetris-nes": "",
+	}
+	for instanceID, file := range want {
+		data, err := service.artComposer.GetCachedArt("mock", instanceID, "cover")
+		if file == "" {
+			if err == nil {
+				t.Errorf("expected no art for ambiguous %s, got %q", instanceID, data)
+			}
+			continue
+		}
+		if err != nil || string(data) != file {
+			t.Errorf("expected %s to get %q, got %q (%v)", instanceID, file, data, err)
+		}
+	}
+
+	if _, err := service.ImportArtFolder(dir, "../cover"); err == nil {
+		t.Error("expected an invalid art type to be rejected")
+	}
+}
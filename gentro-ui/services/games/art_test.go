@@ -0,0 +1,454 @@
+package games
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/image/webp"
+
+	"github.com/rhythmerc/gentro-ui/services/config"
+	"github.com/rhythmerc/gentro-ui/services/games/art"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestServeHTTP_ArtErrors(t *testing.T) {
+	tests := []struct {
+		name            string
+		artErr          error
+		wantCode        int
+		wantPlaceholder bool
+	}{
+		{"not found serves placeholder", models.ErrArtNotFound, http.StatusOK, true},
+		{"wrapped not found serves placeholder", errors.Join(errors.New("cdn"), models.ErrArtNotFound), http.StatusOK, true},
+		{"other errors are server errors", errors.New("disk on fire"), http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService(t)
+			service.registry.Register(context.Background(), &MockSource{name: "mock", artErr: tt.artErr})
+
+			if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+				{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes"},
+			}); err != nil {
+				t.Fatalf("failed to seed instance: %v", err)
+			}
+
+			rec := httptest.NewRecorder()
+			service.ServeHTTP(rec, httptest.NewRequest("GET", "/art/inst1/cover", nil))
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("expected %d, got %d", tt.wantCode, rec.Code)
+			}
+			if got := rec.Header().Get("X-Art-Placeholder") == "true"; got != tt.wantPlaceholder {
+				t.Errorf("expected placeholder %v, got %v", tt.wantPlaceholder, got)
+			}
+			if tt.wantPlaceholder && rec.Header().Get("Content-Type") != "image/png" {
+				t.Errorf("expected image/png placeholder, got %q", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+// artSource serves a fixed set of art types
+type artSource struct {
+	MockSource
+	art map[string][]byte
+}
+
+func (a *artSource) GetGameArt(ctx context.Context, instance models.GameInstance, artType string) ([]byte, string, error) {
+	if data, ok := a.art[artType]; ok {
+		return data, "image/png", nil
+	}
+	return nil, "", models.ErrArtNotFound
+}
+
+func TestServeHTTP_ArtFallback(t *testing.T) {
+	service := newTestService(t)
+	service.registry.Register(context.Background(), &artSource{
+		MockSource: MockSource{name: "mock"},
+		art:        map[string][]byte{"cover": []byte("cover"), "artwork": []byte("artwork")},
+	})
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+
+	get := func(artType string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		service.ServeHTTP(rec, httptest.NewRequest("GET", "/art/inst1/"+artType, nil))
+		return rec
+	}
+
+	if rec := get("logo"); rec.Body.String() != "cover" || rec.Header().Get("X-Art-Type") != "cover" {
+		t.Errorf("expected logo to fall back to cover, got %q (%s)", rec.Body.String(), rec.Header().Get("X-Art-Type"))
+	}
+	if rec := get("hero"); rec.Body.String() != "artwork" {
+		t.Errorf("expected hero to fall back to artwork, got %q", rec.Body.String())
+	}
+	if rec := get("icon"); rec.Header().Get("X-Art-Placeholder") != "true" {
+		t.Error("expected a type without a chain to serve the placeholder")
+	}
+
+	manager, err := config.NewManager(filepath.Join(t.TempDir(), "gentro.toml"))
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	artConfig := manager.Get().Art
+	artConfig.Fallbacks = map[string][]string{"hero": {}}
+	if err := manager.SetArt(artConfig); err != nil {
+		t.Fatalf("SetArt failed: %v", err)
+	}
+	service.config = manager
+
+	if rec := get("hero"); rec.Header().Get("X-Art-Placeholder") != "true" {
+		t.Error("expected an empty configured chain to disable fallback")
+	}
+}
+
+func TestServeHTTP_GalleryArt(t *testing.T) {
+	service := newTestService(t)
+	service.registry.Register(context.Background(), &artSource{
+		MockSource: MockSource{name: "mock"},
+		art:        map[string][]byte{"screenshot.2": []byte("third screenshot")},
+	})
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+
+	tests := []struct {
+		target   string
+		wantCode int
+	}{
+		{"/art/inst1/screenshot.2", http.StatusOK},
+		{"/art/inst1/screenshot.", http.StatusBadRequest},
+		{"/art/inst1/screenshot.2.png", http.StatusBadRequest},
+		{"/art/inst1/screenshot..", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		service.ServeHTTP(rec, httptest.NewRequest("GET", tt.target, nil))
+		if rec.Code != tt.wantCode {
+			t.Errorf("%s: expected %d, got %d", tt.target, tt.wantCode, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	service.ServeHTTP(rec, httptest.NewRequest("GET", "/art/inst1/screenshot.2", nil))
+	if rec.Body.String() != "third screenshot" {
+		t.Errorf("expected the indexed screenshot, got %q", rec.Body.String())
+	}
+}
+
+func TestGetArtURLs(t *testing.T) {
+	service := newTestService(t)
+	service.route = "/games"
+	service.registry.Register(context.Background(), &artSource{
+		MockSource: MockSource{name: "mock"},
+		art:        map[string][]byte{"cover": []byte("cover"), "header": []byte("header")},
+	})
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+
+	urls, err := service.GetArtURLs("inst1")
+	if err != nil {
+		t.Fatalf("GetArtURLs failed: %v", err)
+	}
+	want := map[string]string{
+		"cover":  "/games/art/inst1/cover",
+		"header": "/games/art/inst1/header",
+	}
+	if !maps.Equal(urls, want) {
+		t.Errorf("expected only the available types without fallbacks, got %v", urls)
+	}
+
+	if _, err := service.GetArtURLs("missing"); err == nil {
+		t.Error("expected an unknown instance to fail")
+	}
+}
+
+// onDemandArtSource downloads art on request, and lists what it has without downloading
+type onDemandArtSource struct {
+	MockSource
+	available map[string]bool
+}
+
+func (o *onDemandArtSource) HasGameArt(instance models.GameInstance, artType string) bool {
+	return o.available[artType]
+}
+
+func TestGetArtURLs_OnDemandSource(t *testing.T) {
+	service := newTestService(t)
+	service.route = "/games"
+	service.registry.Register(context.Background(), &onDemandArtSource{
+		// GetArtURLs must not download anything
+		MockSource: MockSource{name: "mock", artErr: errors.New("downloaded")},
+		available:  map[string]bool{"header": true, "hero": true},
+	})
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "steam"},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+
+	urls, err := service.GetArtURLs("inst1")
+	if err != nil {
+		t.Fatalf("GetArtURLs failed: %v", err)
+	}
+	want := map[string]string{
+		"header": "/games/art/inst1/header",
+		"hero":   "/games/art/inst1/hero",
+	}
+	if !maps.Equal(urls, want) {
+		t.Errorf("expected the source's available types, got %v", urls)
+	}
+}
+
+// fileArtSource keeps its art on disk
+type fileArtSource struct {
+	MockSource
+	dir string
+}
+
+func (f *fileArtSource) GameArtPath(ctx context.Context, instance models.GameInstance, artType string) (string, error) {
+	path := filepath.Join(f.dir, artType+".png")
+	if _, err := os.Stat(path); err != nil {
+		return "", models.ErrArtNotFound
+	}
+	return path, nil
+}
+
+func TestServeHTTP_StreamsArtFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "cover.png"), []byte("cover-file"), 0644); err != nil {
+		t.Fatalf("failed to write art: %v", err)
+	}
+
+	service := newTestService(t)
+	service.registry.Register(context.Background(), &fileArtSource{
+		// GetGameArt must not be used when the file is available
+		MockSource: MockSource{name: "mock", artErr: errors.New("read into memory")},
+		dir:        dir,
+	})
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	service.ServeHTTP(rec, httptest.NewRequest("GET", "/art/inst1/grid", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "cover-file" {
+		t.Fatalf("expected streamed cover file, got %d %q", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Content-Type") != "image/png" {
+		t.Errorf("expected image/png, got %q", rec.Header().Get("Content-Type"))
+	}
+	if rec.Header().Get("Last-Modified") == "" {
+		t.Error("expected Last-Modified from the art file")
+	}
+	if rec.Header().Get("X-Art-Type") != "cover" {
+		t.Errorf("expected grid to fall back to cover, got %q", rec.Header().Get("X-Art-Type"))
+	}
+}
+
+func TestServeHTTP_WebP(t *testing.T) {
+	dir := t.TempDir()
+
+	// An uncompressed gradient PNG always loses to lossless WebP; noisy low-quality JPEG always wins
+	gradient := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	noise := image.NewRGBA(gradient.Rect)
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range noise.Pix {
+		gradient.Pix[i] = uint8(i / 64)
+		noise.Pix[i] = uint8(rng.IntN(256))
+	}
+	var pngData, jpegData bytes.Buffer
+	if err := (&png.Encoder{CompressionLevel: png.NoCompression}).Encode(&pngData, gradient); err != nil {
+		t.Fatalf("failed to encode png: %v", err)
+	}
+	if err := jpeg.Encode(&jpegData, noise, &jpeg.Options{Quality: 10}); err != nil {
+		t.Fatalf("failed to encode jpeg: %v", err)
+	}
+	for artType, data := range map[string][]byte{"cover": pngData.Bytes(), "hero": jpegData.Bytes()} {
+		if err := os.WriteFile(filepath.Join(dir, artType+".png"), data, 0644); err != nil {
+			t.Fatalf("failed to write art: %v", err)
+		}
+	}
+
+	service := newTestService(t)
+	service.artComposer = art.NewComposer(t.TempDir(), service.logger)
+	service.registry.Register(context.Background(), &fileArtSource{MockSource: MockSource{name: "files"}, dir: dir})
+	if _, err := service.syncSourceInstances("files", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "files", Platform: "nes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+
+	get := func(target, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", target, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		service.ServeHTTP(rec, req)
+		return rec
+	}
+	isWebP := func(rec *httptest.ResponseRecorder) bool {
+		if rec.Header().Get("Content-Type") != "image/webp" {
+			return false
+		}
+		if _, err := webp.Decode(rec.Body); err != nil {
+			t.Errorf("served invalid webp: %v", err)
+		}
+		return true
+	}
+
+	if rec := get("/art/inst1/cover", ""); rec.Header().Get("Content-Type") != "image/png" {
+		t.Errorf("expected png by default, got %q", rec.Header().Get("Content-Type"))
+	}
+	if rec := get("/art/inst1/cover", "image/avif,image/webp;q=0.9,*/*"); !isWebP(rec) || rec.Header().Get("Vary") != "Accept" {
+		t.Errorf("expected a negotiated webp varying on Accept, got %q", rec.Header().Get("Content-Type"))
+	}
+	if rec := get("/art/inst1/hero", "image/webp"); isWebP(rec) {
+		t.Error("expected a negotiated webp larger than the original to be skipped")
+	}
+	if rec := get("/art/inst1/hero?format=webp", ""); !isWebP(rec) {
+		t.Error("expected ?format=webp to force webp")
+	}
+	if rec := get("/art/inst1/cover?format=png", "image/webp"); isWebP(rec) {
+		t.Error("expected an explicit format to override the Accept header")
+	}
+}
+
+func TestServeHTTP_ArtRangeRequests(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "hero.png"), []byte("0123456789"), 0644); err != nil {
+		t.Fatalf("failed to write art: %v", err)
+	}
+
+	service := newTestService(t)
+	service.registry.Register(context.Background(), &fileArtSource{MockSource: MockSource{name: "files"}, dir: dir})
+	service.registry.Register(context.Background(), &artSource{
+		MockSource: MockSource{name: "memory"},
+		art:        map[string][]byte{"hero": []byte("0123456789")},
+	})
+	if _, err := service.syncSourceInstances("files", []models.GameInstance{
+		{ID: "file1", GameID: "game1", Source: "files", Platform: "nes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+	if _, err := service.syncSourceInstances("memory", []models.GameInstance{
+		{ID: "mem1", GameID: "game2", Source: "memory", Platform: "nes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+
+	for _, instanceID := range []string{"file1", "mem1"} {
+		t.Run(instanceID, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/art/"+instanceID+"/hero", nil)
+			req.Header.Set("Range", "bytes=2-5")
+			rec := httptest.NewRecorder()
+			service.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusPartialContent {
+				t.Fatalf("expected 206, got %d", rec.Code)
+			}
+			if rec.Body.String() != "2345" {
+				t.Errorf("expected bytes 2-5, got %q", rec.Body.String())
+			}
+			if got := rec.Header().Get("Content-Range"); got != "bytes 2-5/10" {
+				t.Errorf("unexpected Content-Range %q", got)
+			}
+			if rec.Header().Get("Content-Type") != "image/png" {
+				t.Errorf("expected image/png, got %q", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestServeHTTP_RejectsPathTraversal(t *testing.T) {
+	service := newTestService(t)
+	service.registry.Register(context.Background(), &MockSource{name: "mock", artErr: models.ErrArtNotFound})
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+
+	payloads := []string{
+		"/art/..%2F..%2Fetc/cover",
+		"/art/inst1/..%2F..%2Fpasswd",
+		"/art/../cover",
+		"/art/inst1/..",
+		"/art/.hidden/cover",
+		"/art/inst1/cover%00.png",
+		"/art/inst1/cover/extra",
+		"/art/inst1%5C..%5C..%5Cwindows/cover",
+		"/video/..%2F..%2Fetc/hero",
+	}
+	for _, payload := range payloads {
+		rec := httptest.NewRecorder()
+		service.ServeHTTP(rec, httptest.NewRequest("GET", payload, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", payload, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	service.ServeHTTP(rec, httptest.NewRequest("GET", "/art/inst1/cover", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected valid request to succeed, got %d", rec.Code)
+	}
+}
+
+func TestRecomposeHeaders(t *testing.T) {
+	service := newTestService(t)
+	service.artComposer = art.NewComposer(t.TempDir(), service.logger)
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "with-art", GameID: "game1", Source: "mock", Platform: "nes"},
+		{ID: "no-art", GameID: "game2", Source: "mock", Platform: "nes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instances: %v", err)
+	}
+
+	var cover bytes.Buffer
+	if err := png.Encode(&cover, image.NewRGBA(image.Rect(0, 0, 600, 900))); err != nil {
+		t.Fatalf("failed to encode cover: %v", err)
+	}
+	if err := service.artComposer.CacheArt("mock", "with-art", "cover", cover.Bytes()); err != nil {
+		t.Fatalf("failed to cache cover: %v", err)
+	}
+
+	if err := service.RecomposeHeaders(nil); err != nil {
+		t.Fatalf("RecomposeHeaders failed: %v", err)
+	}
+	if !service.artComposer.HasCachedArt("mock", "with-art", "header") {
+		t.Error("expected a header composed from the cached cover")
+	}
+	if service.artComposer.HasCachedArt("mock", "no-art", "header") {
+		t.Error("expected an instance without art to be skipped")
+	}
+
+	if err := service.RecomposeHeaders([]string{"missing"}); err == nil {
+		t.Error("expected an error for an unknown instance")
+	}
+}
@@ -0,0 +1,117 @@
+package games
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/games/art"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestInitConfig_ReportsLoadErrors(t *testing.T) {
+	service := newTestService(t)
+	service.artComposer = art.NewComposer(t.TempDir(), service.logger)
+
+	configPath := filepath.Join(t.TempDir(), "gentro.toml")
+	broken := "[filters.steam\nexcludeTools = false\n"
+	if err := os.WriteFile(configPath, []byte(broken), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	service.initConfig(configPath)
+
+	errs := service.GetConfigErrors()
+	if len(errs) != 1 || !strings.Contains(errs[0], configPath) {
+		t.Fatalf("expected one error naming the config file, got %v", errs)
+	}
+	if service.config != nil {
+		t.Error("expected no config manager after a failed load")
+	}
+
+	// The user's file must be left alone so they can fix it
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	if string(data) != broken {
+		t.Error("expected broken config file to be left untouched")
+	}
+}
+
+func TestInitConfig_NoErrors(t *testing.T) {
+	service := newTestService(t)
+	service.artComposer = art.NewComposer(t.TempDir(), service.logger)
+
+	service.initConfig(filepath.Join(t.TempDir(), "gentro.toml"))
+
+	if errs := service.GetConfigErrors(); len(errs) != 0 {
+		t.Errorf("expected no config errors, got %v", errs)
+	}
+	if service.config == nil {
+		t.Error("expected config manager to be set")
+	}
+}
+
+func TestSetDefaultView(t *testing.T) {
+	service := newTestService(t)
+	service.artComposer = art.NewComposer(t.TempDir(), service.logger)
+	service.initConfig(filepath.Join(t.TempDir(), "gentro.toml"))
+
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes", Installed: true},
+		{ID: "inst2", GameID: "game2", Source: "mock", Platform: "nes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instances: %v", err)
+	}
+
+	if err := service.SetDefaultView(models.GameFilter{}, models.GameSort{Field: "rating", Order: "asc"}); err == nil {
+		t.Error("expected an invalid sort field to be rejected")
+	}
+
+	sort := models.GameSort{Field: models.SortByLastPlayed, Order: models.SortOrderDesc}
+	if err := service.SetDefaultView(models.GameFilter{InstalledOnly: true}, sort); err != nil {
+		t.Fatalf("SetDefaultView failed: %v", err)
+	}
+
+	if got := service.GetDefaultSort(); got != sort {
+		t.Errorf("expected default sort %+v, got %+v", sort, got)
+	}
+
+	games, err := service.GetGames(nil, nil)
+	if err != nil {
+		t.Fatalf("GetGames failed: %v", err)
+	}
+	if len(games) != 1 || games[0].Instance.ID != "inst1" {
+		t.Errorf("expected only the installed game by default, got %d games", len(games))
+	}
+
+	// Changing the Steam filter keeps the default view
+	if err := service.UpdateFilterConfig(false); err != nil {
+		t.Fatalf("UpdateFilterConfig failed: %v", err)
+	}
+	if got := service.GetDefaultSort(); got != sort {
+		t.Errorf("expected default sort to survive a filter update, got %+v", got)
+	}
+}
+
+func TestAddRomExtension(t *testing.T) {
+	service := newTestService(t)
+	service.artComposer = art.NewComposer(t.TempDir(), service.logger)
+	service.initConfig(filepath.Join(t.TempDir(), "gentro.toml"))
+
+	if err := service.AddRomExtension("nes", ""); err == nil {
+		t.Error("expected an empty extension to be rejected")
+	}
+	for _, ext := range []string{"UNF", ".unf", "fds"} {
+		if err := service.AddRomExtension("nes", ext); err != nil {
+			t.Fatalf("AddRomExtension(%q) failed: %v", ext, err)
+		}
+	}
+
+	got := service.config.Get().Platforms["nes"].Extensions
+	if len(got) != 2 || got[0] != ".unf" || got[1] != ".fds" {
+		t.Errorf("expected [.unf .fds], got %v", got)
+	}
+}
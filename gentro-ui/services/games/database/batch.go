@@ -0,0 +1,279 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// ErrSavepoint indicates a savepoint could not be created, rolled back or released.
+// The batch transaction may be unusable and should be abandoned.
+var ErrSavepoint = errors.New("savepoint failed")
+
+// Batch groups many writes into a single transaction using prepared statements.
+// SQLite syncs to disk once per transaction, so batching is much faster than
+// issuing individual statements when importing large libraries.
+//
+// A batch holds the database write lock from BeginBatch until Commit or
+// Rollback, so other writes on the same DB block until it finishes.
+type Batch struct {
+	tx     *sql.Tx
+	stmts  map[string]*sql.Stmt
+	unlock func()
+}
+
+// BeginBatch starts a new write batch
+func (db *DB) BeginBatch() (*Batch, error) {
+	db.writeMu.Lock()
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		db.writeMu.Unlock()
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	return &Batch{
+		tx:     tx,
+		stmts:  make(map[string]*sql.Stmt),
+		unlock: sync.OnceFunc(db.writeMu.Unlock),
+	}, nil
+}
+
+// Commit commits the batch transaction
+func (b *Batch) Commit() error {
+	defer b.unlock()
+
+	if err := b.tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// Rollback aborts the batch transaction. It is safe to call after Commit.
+func (b *Batch) Rollback() error {
+	defer b.unlock()
+
+	if err := b.tx.Rollback(); err != nil && err != sql.ErrTxDone {
+		return fmt.Errorf("failed to rollback transaction: %w", err)
+	}
+	return nil
+}
+
+// Savepoint runs fn inside a savepoint. If fn fails, only its writes are
+// rolled back and the rest of the batch is left intact. Errors managing the
+// savepoint itself wrap ErrSavepoint.
+func (b *Batch) Savepoint(fn func() error) error {
+	if _, err := b.tx.Exec("SAVEPOINT batch_item"); err != nil {
+		return fmt.Errorf("%w: failed to create savepoint: %w", ErrSavepoint, err)
+	}
+
+	if err := fn(); err != nil {
+		if _, rbErr := b.tx.Exec("ROLLBACK TO batch_item"); rbErr != nil {
+			return fmt.Errorf("%w: failed to rollback savepoint: %w (after: %v)", ErrSavepoint, rbErr, err)
+		}
+		if _, relErr := b.tx.Exec("RELEASE batch_item"); relErr != nil {
+			return fmt.Errorf("%w: failed to release savepoint: %w (after: %v)", ErrSavepoint, relErr, err)
+		}
+		return err
+	}
+
+	if _, err := b.tx.Exec("RELEASE batch_item"); err != nil {
+		return fmt.Errorf("%w: failed to release savepoint: %w", ErrSavepoint, err)
+	}
+	return nil
+}
+
+// exec runs a query through a statement prepared once per batch
+func (b *Batch) exec(query string, args ...any) (sql.Result, error) {
+	stmt, ok := b.stmts[query]
+	if !ok {
+		var err error
+		stmt, err = b.tx.Prepare(query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare statement: %w", err)
+		}
+		b.stmts[query] = stmt
+	}
+	return stmt.Exec(args...)
+}
+
+// CreateGame creates a new game record with its genres and platforms
+func (b *Batch) CreateGame(game *models.Game) error {
+	_, err := b.exec(insertGameQuery, game.ID, game.Name, game.Description, game.ReleaseDate, game.Developer, game.Publisher, game.AgeRating)
+	if err != nil {
+		return fmt.Errorf("failed to create game: %w", err)
+	}
+
+	for _, genre := range game.Genres {
+		if _, err := b.exec(insertGenreQuery, game.ID, genre); err != nil {
+			return fmt.Errorf("failed to insert genre: %w", err)
+		}
+	}
+
+	for _, platform := range game.Platforms {
+		if _, err := b.exec(insertPlatformQuery, game.ID, platform); err != nil {
+			return fmt.Errorf("failed to insert platform: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CreateInstance creates a new game instance with custom metadata
+func (b *Batch) CreateInstance(instance *models.GameInstance) error {
+	files, err := encodeInstanceFiles(instance.Files)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.exec(insertInstanceQuery,
+		instance.ID, instance.GameID, instance.Source, instance.Platform,
+		instance.SourceID, instance.Path, instance.Filename,
+		instance.FileSize, instance.FileHash, instance.Installed,
+		instance.InstallPath, files,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create instance: %w", err)
+	}
+	instance.Version = 1
+
+	return b.insertCustomMetadata(instance.ID, instance.CustomMetadata)
+}
+
+// UpdateInstance updates basic instance fields that may change, from
+// instance.Version, which is bumped on success
+func (b *Batch) UpdateInstance(instance *models.GameInstance) error {
+	files, err := encodeInstanceFiles(instance.Files)
+	if err != nil {
+		return err
+	}
+
+	result, err := b.exec(updateInstanceQuery,
+		instance.Path,
+		instance.Filename,
+		instance.FileSize,
+		instance.Installed,
+		instance.InstallPath,
+		files,
+		instance.ID,
+		instance.Version,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update instance: %w", err)
+	}
+	if err := checkVersion(result, instance.ID, instance.Version); err != nil {
+		return err
+	}
+	instance.Version++
+	return nil
+}
+
+// UpdateInstanceCustomMetadata replaces all custom metadata for an instance and
+// bumps its version from expectedVersion
+func (b *Batch) UpdateInstanceCustomMetadata(instanceID string, expectedVersion int64, metadata map[string]any) error {
+	if err := b.bumpVersion(instanceID, expectedVersion); err != nil {
+		return err
+	}
+
+	if _, err := b.exec(deleteCustomMetadataQuery, instanceID); err != nil {
+		return fmt.Errorf("failed to clear custom metadata: %w", err)
+	}
+
+	return b.insertCustomMetadata(instanceID, metadata)
+}
+
+// SetInstanceCustomMetadataKeys sets the given custom metadata keys, leaving the
+// instance's other keys alone, and bumps its version from expectedVersion
+func (b *Batch) SetInstanceCustomMetadataKeys(instanceID string, expectedVersion int64, updates map[string]any) error {
+	if err := b.bumpVersion(instanceID, expectedVersion); err != nil {
+		return err
+	}
+
+	for key, value := range updates {
+		valueJSON, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal custom metadata value: %w", err)
+		}
+		if _, err := b.exec(upsertCustomMetadataQuery, instanceID, key, string(valueJSON)); err != nil {
+			return fmt.Errorf("failed to set custom metadata: %w", err)
+		}
+	}
+	return nil
+}
+
+// SetInstanceCustomMetadataKey sets one custom metadata key whatever version the
+// instance is at, for edits that don't start from a copy of the instance
+func (b *Batch) SetInstanceCustomMetadataKey(instanceID, key string, value any) error {
+	result, err := b.exec(touchInstanceQuery, instanceID)
+	if err != nil {
+		return fmt.Errorf("failed to update instance version: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to check instance: %w", err)
+	} else if rows == 0 {
+		return fmt.Errorf("instance not found: %s", instanceID)
+	}
+
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal custom metadata value: %w", err)
+	}
+	if _, err := b.exec(upsertCustomMetadataQuery, instanceID, key, string(valueJSON)); err != nil {
+		return fmt.Errorf("failed to set custom metadata: %w", err)
+	}
+	return nil
+}
+
+// DeleteInstanceCustomMetadataKey removes one custom metadata key and bumps the
+// instance's version from expectedVersion
+func (b *Batch) DeleteInstanceCustomMetadataKey(instanceID string, expectedVersion int64, key string) error {
+	if err := b.bumpVersion(instanceID, expectedVersion); err != nil {
+		return err
+	}
+
+	if _, err := b.exec(deleteCustomMetadataKeyQuery, instanceID, key); err != nil {
+		return fmt.Errorf("failed to delete custom metadata: %w", err)
+	}
+	return nil
+}
+
+// bumpVersion moves an instance on from expectedVersion before a custom metadata write
+func (b *Batch) bumpVersion(instanceID string, expectedVersion int64) error {
+	result, err := b.exec(bumpInstanceVersionQuery, instanceID, expectedVersion)
+	if err != nil {
+		return fmt.Errorf("failed to update instance version: %w", err)
+	}
+	return checkVersion(result, instanceID, expectedVersion)
+}
+
+// checkVersion reports a conflict when a versioned update matched no row: the
+// instance was changed, or deleted, since expectedVersion was read
+func checkVersion(result sql.Result, instanceID string, expectedVersion int64) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check instance version: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%w: %s is no longer at version %d", models.ErrVersionConflict, instanceID, expectedVersion)
+	}
+	return nil
+}
+
+// insertCustomMetadata stores each custom metadata value as JSON
+func (b *Batch) insertCustomMetadata(instanceID string, metadata map[string]any) error {
+	for key, value := range metadata {
+		valueJSON, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal custom metadata value: %w", err)
+		}
+		_, err = b.exec(insertCustomMetadataQuery, instanceID, key, string(valueJSON))
+		if err != nil {
+			return fmt.Errorf("failed to insert custom metadata: %w", err)
+		}
+	}
+	return nil
+}
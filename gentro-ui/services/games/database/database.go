@@ -4,18 +4,35 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/rhythmerc/gentro-ui/services/games/models"
 )
 
-// DB wraps the SQLite database
+// maxOpenConns bounds the connection pool. WAL allows many concurrent
+// readers but SQLite still serializes writers.
+const maxOpenConns = 8
+
+// DB wraps the SQLite database.
+//
+// SQLite allows a single writer at a time. Reads go straight to the pool and
+// run concurrently under WAL, while every write method (and every Batch, for
+// its whole lifetime) holds writeMu so writers queue in-process instead of
+// contending for the database lock. Write methods must not call each other.
 type DB struct {
-	conn *sql.DB
+	conn    *sql.DB
+	writeMu sync.Mutex
+	status  models.DatabaseStatus
 }
 
-// New creates a new database connection
+// New opens the database, creating and migrating it as needed. A corrupt file
+// is restored from the automatic backup or rebuilt. If migration fails the old
+// file is backed up and the schema rebuilt; if that fails too the database is
+// opened read-only. Check Status for any of these outcomes.
 func New(dbPath string) (*DB, error) {
 	// Ensure directory exists
 	dir := filepath.Dir(dbPath)
@@ -23,22 +40,65 @@ func New(dbPath string) (*DB, error) {
 		return nil, fmt.Errorf("failed to create database directory: %w", err)
 	}
 
-	conn, err := sql.Open("sqlite3", dbPath)
+	db, err := open(dbPath, false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
+		if isEnvironmentError(err) {
+			return nil, err
+		}
+		// Unreadable headers fail as soon as the connection is configured
+		return recoverFromCorruption(dbPath, err.Error())
+	}
+
+	integrity, err := db.integrityCheck()
+	switch {
+	case err != nil && isEnvironmentError(err):
+		db.status.Integrity = models.IntegrityUnknown
+	case err != nil:
+		db.Close()
+		return recoverFromCorruption(dbPath, err.Error())
+	case integrity != "ok":
+		db.Close()
+		return recoverFromCorruption(dbPath, integrity)
+	default:
+		db.status.Integrity = models.IntegrityOK
+	}
+
+	migrateErr := db.migrate()
+	if migrateErr == nil {
+		if db.status.Integrity == models.IntegrityOK {
+			db.backupIfStale()
+		}
+		return db, nil
 	}
+	db.Close()
+
+	return recoverFromMigration(dbPath, migrateErr)
+}
 
-	// Enable foreign keys
-	if _, err := conn.Exec("PRAGMA foreign_keys = ON"); err != nil {
-		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
+// open connects to the database file. Read-only connections reject writes.
+func open(dbPath string, readOnly bool) (*DB, error) {
+	// PRAGMAs only apply to the connection they run on, so they are passed in
+	// the DSN to configure every pooled connection. WAL lets art requests read
+	// while a refresh is writing, and the busy timeout makes writers wait for
+	// each other instead of failing with "database is locked". Immediate
+	// transactions take the write lock up front so they cannot deadlock when
+	// upgrading from a read lock.
+	dsn := dbPath + "?_foreign_keys=on&_journal_mode=WAL&_busy_timeout=5000&_synchronous=NORMAL&_txlock=immediate"
+	if readOnly {
+		dsn += "&_query_only=true"
+	}
+	conn, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	conn.SetMaxOpenConns(maxOpenConns)
 
-	db := &DB{conn: conn}
-	if err := db.migrate(); err != nil {
-		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	return db, nil
+	return &DB{conn: conn, status: models.DatabaseStatus{Path: dbPath, ReadOnly: readOnly}}, nil
 }
 
 // Close closes the database connection
@@ -115,6 +175,14 @@ func (db *DB) migrate() error {
 			PRIMARY KEY (game_id, source),
 			FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
 		)`,
+		`CREATE TABLE IF NOT EXISTS game_field_sources (
+			game_id TEXT NOT NULL,
+			field TEXT NOT NULL,
+			source TEXT NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (game_id, field),
+			FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
+		)`,
 		`CREATE INDEX IF NOT EXISTS idx_instances_game_id ON game_instances(game_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_instances_source ON game_instances(source)`,
 		`CREATE INDEX IF NOT EXISTS idx_instances_platform ON game_instances(platform)`,
@@ -165,62 +233,251 @@ func (db *DB) migrate() error {
 			FOREIGN KEY (instance_id) REFERENCES game_instances(id) ON DELETE CASCADE,
 			FOREIGN KEY (emulator_id) REFERENCES emulators(id) ON DELETE CASCADE
 		)`,
+		`CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS emulator_arg_profiles (
+			id TEXT PRIMARY KEY,
+			emulator_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			args TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (emulator_id) REFERENCES emulators(id) ON DELETE CASCADE,
+			UNIQUE(emulator_id, name)
+		)`,
+		`CREATE TABLE IF NOT EXISTS play_sessions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			instance_id TEXT NOT NULL,
+			started_at DATETIME NOT NULL,
+			ended_at DATETIME NOT NULL,
+			duration_seconds INTEGER NOT NULL,
+			FOREIGN KEY (instance_id) REFERENCES game_instances(id) ON DELETE CASCADE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_play_sessions_instance ON play_sessions(instance_id, started_at)`,
 	}
 
 	for _, query := range queries {
-		if _, err := db.conn.Exec(query); err != nil {
-			return fmt.Errorf("failed to execute migration: %w", err)
+		if err := db.execMigration(query); err != nil {
+			return err
 		}
 	}
 
+	// Columns added after their table was created. CREATE TABLE IF NOT EXISTS
+	// leaves existing tables alone, so these are added when missing.
+	columns := []struct{ table, column, definition string }{
+		{"instance_emulator_settings", "profile_id", "TEXT"},
+		{"games", "age_rating", "TEXT NOT NULL DEFAULT ''"},
+		{"platform_emulators", "user_assigned", "BOOLEAN NOT NULL DEFAULT 0"},
+		{"game_instances", "files", "TEXT NOT NULL DEFAULT ''"},
+		{"game_instances", "version", "INTEGER NOT NULL DEFAULT 1"},
+		{"game_instances", "playtime_seconds", "INTEGER NOT NULL DEFAULT 0"},
+		{"game_instances", "last_played_at", "DATETIME"},
+		{"external_metadata", "refresh_failed_at", "DATETIME"},
+	}
+	for _, c := range columns {
+		if err := db.addColumnIfMissing(c.table, c.column, c.definition); err != nil {
+			return err
+		}
+	}
+
+	// Emulator availability used to be persisted as custom metadata, where it went
+	// stale; it's now computed when instances are read
+	if err := db.execMigration(`DELETE FROM instance_custom_metadata WHERE key = 'emulator.available'`); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-// ensureDir creates the directory if it doesn't exist
+// execMigration runs one migration in its own transaction so a failure
+// never leaves it half-applied
+func (db *DB) execMigration(query string) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin migration: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(query); err != nil {
+		return fmt.Errorf("failed to execute migration: %w", err)
+	}
+	return tx.Commit()
+}
+
+// addColumnIfMissing adds a column to an existing table unless it's already there
+func (db *DB) addColumnIfMissing(table, column, definition string) error {
+	rows, err := db.conn.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, colType    string
+			defaultValue     sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return fmt.Errorf("failed to scan columns of %s: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+
+	if err := db.execMigration(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
+	return nil
+}
+
+// ensureDir creates the directory and any parents if they don't exist
 func ensureDir(path string) error {
-	// Implementation depends on OS - stub for now
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", path, err)
+	}
 	return nil
 }
 
+// Write queries shared between single-call methods and Batch
+const (
+	insertGameQuery = `
+		INSERT INTO games (id, name, description, release_date, developer, publisher, age_rating)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	insertGenreQuery    = "INSERT INTO game_genres (game_id, genre) VALUES (?, ?)"
+	insertPlatformQuery = "INSERT INTO game_platforms (game_id, platform) VALUES (?, ?)"
+	insertInstanceQuery = `
+		INSERT INTO game_instances (
+			id, game_id, source, platform, source_id, path, filename,
+			file_size, file_hash, installed, install_path, files, version
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1)
+	`
+	updateInstanceQuery = `
+		UPDATE game_instances SET
+			path = ?,
+			filename = ?,
+			file_size = ?,
+			installed = ?,
+			install_path = ?,
+			files = ?,
+			version = version + 1,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND version = ?
+	`
+	bumpInstanceVersionQuery = `
+		UPDATE game_instances SET version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND version = ?
+	`
+	touchInstanceQuery = `
+		UPDATE game_instances SET version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+	deleteCustomMetadataQuery = "DELETE FROM instance_custom_metadata WHERE instance_id = ?"
+	insertCustomMetadataQuery = "INSERT INTO instance_custom_metadata (instance_id, key, value) VALUES (?, ?, ?)"
+	upsertCustomMetadataQuery = `
+		INSERT INTO instance_custom_metadata (instance_id, key, value) VALUES (?, ?, ?)
+		ON CONFLICT (instance_id, key) DO UPDATE SET value = excluded.value
+	`
+	deleteCustomMetadataKeyQuery = "DELETE FROM instance_custom_metadata WHERE instance_id = ? AND key = ?"
+)
+
 // CreateGame creates a new game record
 func (db *DB) CreateGame(game *models.Game) error {
-	query := `
-		INSERT INTO games (id, name, description, release_date, developer, publisher)
-		VALUES (?, ?, ?, ?, ?, ?)
-	`
-	_, err := db.conn.Exec(query, game.ID, game.Name, game.Description, game.ReleaseDate, game.Developer, game.Publisher)
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
+	_, err := db.conn.Exec(insertGameQuery, game.ID, game.Name, game.Description, game.ReleaseDate, game.Developer, game.Publisher, game.AgeRating)
 	if err != nil {
 		return fmt.Errorf("failed to create game: %w", err)
 	}
 
 	// Insert genres
-	if len(game.Genres) > 0 {
-		for _, genre := range game.Genres {
-			_, err := db.conn.Exec("INSERT INTO game_genres (game_id, genre) VALUES (?, ?)", game.ID, genre)
-			if err != nil {
-				return fmt.Errorf("failed to insert genre: %w", err)
-			}
+	for _, genre := range game.Genres {
+		if _, err := db.conn.Exec(insertGenreQuery, game.ID, genre); err != nil {
+			return fmt.Errorf("failed to insert genre: %w", err)
 		}
 	}
 
 	// Insert platforms
-	if len(game.Platforms) > 0 {
-		for _, platform := range game.Platforms {
-			_, err := db.conn.Exec("INSERT INTO game_platforms (game_id, platform) VALUES (?, ?)", game.ID, platform)
-			if err != nil {
-				return fmt.Errorf("failed to insert platform: %w", err)
-			}
+	for _, platform := range game.Platforms {
+		if _, err := db.conn.Exec(insertPlatformQuery, game.ID, platform); err != nil {
+			return fmt.Errorf("failed to insert platform: %w", err)
 		}
 	}
 
 	return nil
 }
 
+// GetGameIDs returns the set of all known game IDs
+func (db *DB) GetGameIDs() (map[string]bool, error) {
+	ids, err := db.queryIDs("SELECT id FROM games")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game IDs: %w", err)
+	}
+	return ids, nil
+}
+
+// GetInstanceIDs returns the IDs of a source's instances
+func (db *DB) GetInstanceIDs(source string) (map[string]bool, error) {
+	ids, err := db.queryIDs("SELECT id FROM game_instances WHERE source = ?", source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance IDs: %w", err)
+	}
+	return ids, nil
+}
+
+// GetRecentInstanceIDs returns the IDs of the most recently added instances, newest first
+func (db *DB) GetRecentInstanceIDs(limit int) ([]string, error) {
+	rows, err := db.conn.Query(`SELECT id FROM game_instances ORDER BY created_at DESC, rowid DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent instances: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan recent instance: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate recent instances: %w", err)
+	}
+	return ids, nil
+}
+
+// queryIDs collects the single ID column of a query into a set
+func (db *DB) queryIDs(query string, args ...any) (map[string]bool, error) {
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+	return ids, rows.Err()
+}
+
 // GetGame retrieves a game by ID
 func (db *DB) GetGame(id string) (*models.Game, error) {
 	game := &models.Game{}
-	query := `SELECT id, name, description, release_date, developer, publisher, created_at, updated_at FROM games WHERE id = ?`
-	err := db.conn.QueryRow(query, id).Scan(&game.ID, &game.Name, &game.Description, &game.ReleaseDate, &game.Developer, &game.Publisher, &game.CreatedAt, &game.UpdatedAt)
+	query := `SELECT id, name, description, release_date, developer, publisher, age_rating, created_at, updated_at FROM games WHERE id = ?`
+	err := db.conn.QueryRow(query, id).Scan(&game.ID, &game.Name, &game.Description, &game.ReleaseDate, &game.Developer, &game.Publisher, &game.AgeRating, &game.CreatedAt, &game.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -267,6 +524,9 @@ func (db *DB) getGameGenres(gameID string) ([]string, error) {
 		}
 		genres = append(genres, genre)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate genres: %w", err)
+	}
 	return genres, nil
 }
 
@@ -285,6 +545,9 @@ func (db *DB) getGamePlatforms(gameID string) ([]string, error) {
 		}
 		platforms = append(platforms, platform)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate platforms: %w", err)
+	}
 	return platforms, nil
 }
 
@@ -303,11 +566,17 @@ func (db *DB) getGameArt(gameID string) (map[string]string, error) {
 		}
 		artURLs[artType] = url
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate art: %w", err)
+	}
 	return artURLs, nil
 }
 
 // StoreGameArt stores art URL with source for a game
 func (db *DB) StoreGameArt(gameID, artType, url, source string) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
 	query := `
 		INSERT INTO game_art (game_id, art_type, url, source)
 		VALUES (?, ?, ?, ?)
@@ -337,53 +606,18 @@ func (db *DB) GetGameArtSource(gameID, artType string) (string, error) {
 
 // CreateInstance creates a new game instance with custom metadata
 func (db *DB) CreateInstance(instance *models.GameInstance) error {
-	// Start a transaction to ensure atomicity
-	tx, err := db.conn.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback()
-
-	// Insert the instance
-	query := `
-		INSERT INTO game_instances (
-			id, game_id, source, platform, source_id, path, filename, 
-			file_size, file_hash, installed, install_path
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
-	_, err = tx.Exec(query,
-		instance.ID, instance.GameID, instance.Source, instance.Platform,
-		instance.SourceID, instance.Path, instance.Filename,
-		instance.FileSize, instance.FileHash, instance.Installed,
-		instance.InstallPath,
-	)
+	// Use a batch so the instance and its custom metadata are written atomically
+	batch, err := db.BeginBatch()
 	if err != nil {
-		return fmt.Errorf("failed to create instance: %w", err)
-	}
-
-	// Insert custom metadata if present
-	if len(instance.CustomMetadata) > 0 {
-		for key, value := range instance.CustomMetadata {
-			valueJSON, err := json.Marshal(value)
-			if err != nil {
-				return fmt.Errorf("failed to marshal custom metadata value: %w", err)
-			}
-			_, err = tx.Exec(
-				"INSERT INTO instance_custom_metadata (instance_id, key, value) VALUES (?, ?, ?)",
-				instance.ID, key, string(valueJSON),
-			)
-			if err != nil {
-				return fmt.Errorf("failed to insert custom metadata: %w", err)
-			}
-		}
+		return err
 	}
+	defer batch.Rollback()
 
-	// Commit the transaction
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	if err := batch.CreateInstance(instance); err != nil {
+		return err
 	}
 
-	return nil
+	return batch.Commit()
 }
 
 // GetInstance retrieves an instance by ID
@@ -391,18 +625,20 @@ func (db *DB) GetInstance(id string) (*models.GameInstance, error) {
 	instance := &models.GameInstance{}
 	query := `
 		SELECT id, game_id, source, platform, source_id, path, filename,
-			file_size, file_hash, installed, install_path,
-			metadata_state, metadata_message, metadata_error,
+			file_size, file_hash, installed, install_path, files, version,
+			playtime_seconds, last_played_at,
+			metadata_state, COALESCE(metadata_message, ''), COALESCE(metadata_error, ''),
 			metadata_started_at, metadata_completed_at,
 			created_at, updated_at
 		FROM game_instances WHERE id = ?
 	`
-	var metadataState string
+	var metadataState, filesJSON string
 	err := db.conn.QueryRow(query, id).Scan(
 		&instance.ID, &instance.GameID, &instance.Source, &instance.Platform,
 		&instance.SourceID, &instance.Path, &instance.Filename,
 		&instance.FileSize, &instance.FileHash, &instance.Installed,
-		&instance.InstallPath,
+		&instance.InstallPath, &filesJSON, &instance.Version,
+		&instance.PlaytimeSeconds, &instance.LastPlayedAt,
 		&metadataState, &instance.MetadataStatus.Message, &instance.MetadataStatus.Error,
 		&instance.MetadataStatus.StartedAt, &instance.MetadataStatus.CompletedAt,
 		&instance.CreatedAt, &instance.UpdatedAt,
@@ -415,6 +651,9 @@ func (db *DB) GetInstance(id string) (*models.GameInstance, error) {
 	}
 
 	instance.MetadataStatus.State = models.MetadataState(metadataState)
+	if instance.Files, err = decodeInstanceFiles(filesJSON); err != nil {
+		return nil, err
+	}
 
 	// Load custom metadata
 	customMeta, err := db.GetInstanceCustomMetadata(id)
@@ -448,11 +687,17 @@ func (db *DB) GetInstanceCustomMetadata(instanceID string) (map[string]any, erro
 			customMeta[key] = value
 		}
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate custom metadata: %w", err)
+	}
 	return customMeta, nil
 }
 
 // UpdateInstanceMetadataStatus updates the metadata status
 func (db *DB) UpdateInstanceMetadataStatus(instanceID string, status models.MetadataStatus) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
 	query := `
 		UPDATE game_instances SET
 			metadata_state = ?,
@@ -479,8 +724,9 @@ func (db *DB) GetInstances(filter models.GameFilter) ([]models.GameInstance, err
 	query := `
 		SELECT gi.id, gi.game_id, gi.source, gi.platform, gi.source_id, 
 			gi.path, gi.filename, gi.file_size, gi.file_hash, 
-			gi.installed, gi.install_path,
-			gi.metadata_state, gi.metadata_message, gi.metadata_error,
+			gi.installed, gi.install_path, gi.files, gi.version,
+			gi.playtime_seconds, gi.last_played_at,
+			gi.metadata_state, COALESCE(gi.metadata_message, ''), COALESCE(gi.metadata_error, ''),
 			gi.metadata_started_at, gi.metadata_completed_at,
 			gi.created_at, gi.updated_at,
 			icm.key, icm.value
@@ -513,23 +759,27 @@ func (db *DB) GetInstances(filter models.GameFilter) ([]models.GameInstance, err
 
 	for rows.Next() {
 		instance := models.GameInstance{}
-		var metadataState string
+		var metadataState, filesJSON string
 		var metaKey, metaValue sql.NullString
 
 		err := rows.Scan(
 			&instance.ID, &instance.GameID, &instance.Source, &instance.Platform,
 			&instance.SourceID, &instance.Path, &instance.Filename,
 			&instance.FileSize, &instance.FileHash, &instance.Installed,
-			&instance.InstallPath,
+			&instance.InstallPath, &filesJSON, &instance.Version,
+			&instance.PlaytimeSeconds, &instance.LastPlayedAt,
 			&metadataState, &instance.MetadataStatus.Message, &instance.MetadataStatus.Error,
 			&instance.MetadataStatus.StartedAt, &instance.MetadataStatus.CompletedAt,
 			&instance.CreatedAt, &instance.UpdatedAt,
 			&metaKey, &metaValue,
 		)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to scan instance: %w", err)
 		}
 		instance.MetadataStatus.State = models.MetadataState(metadataState)
+		if instance.Files, err = decodeInstanceFiles(filesJSON); err != nil {
+			return nil, err
+		}
 
 		// Check if we already have this instance
 		existing, found := instanceMap[instance.ID]
@@ -551,6 +801,9 @@ func (db *DB) GetInstances(filter models.GameFilter) ([]models.GameInstance, err
 			}
 		}
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate instances: %w", err)
+	}
 
 	// Convert map to slice
 	var instances []models.GameInstance
@@ -596,72 +849,153 @@ func (db *DB) FindInstanceByPath(path string) (*models.GameInstance, error) {
 
 // UpdateGame updates a game record
 func (db *DB) UpdateGame(game *models.Game) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `
 		UPDATE games SET
 			name = ?, description = ?, release_date = ?,
-			developer = ?, publisher = ?, updated_at = CURRENT_TIMESTAMP
+			developer = ?, publisher = ?, age_rating = ?, updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
 	`
-	_, err := db.conn.Exec(query, game.Name, game.Description, game.ReleaseDate,
-		game.Developer, game.Publisher, game.ID)
+	_, err = tx.Exec(query, game.Name, game.Description, game.ReleaseDate,
+		game.Developer, game.Publisher, game.AgeRating, game.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update game: %w", err)
 	}
+
+	// Replace genres so resolved or reverted genres take effect
+	if _, err := tx.Exec("DELETE FROM game_genres WHERE game_id = ?", game.ID); err != nil {
+		return fmt.Errorf("failed to clear genres: %w", err)
+	}
+	for _, genre := range game.Genres {
+		if _, err := tx.Exec(insertGenreQuery, game.ID, genre); err != nil {
+			return fmt.Errorf("failed to insert genre: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return nil
 }
 
-// UpdateInstance updates basic instance fields that may change
+// UpdateInstance updates basic instance fields that may change. The update is
+// made from instance.Version, which is bumped on success; if the stored instance
+// has moved on, models.ErrVersionConflict is returned.
 func (db *DB) UpdateInstance(instance *models.GameInstance) error {
-	query := `
-		UPDATE game_instances SET
-			path = ?,
-			file_size = ?,
-			installed = ?,
-			install_path = ?,
-			updated_at = CURRENT_TIMESTAMP
-		WHERE id = ?
-	`
-	_, err := db.conn.Exec(query,
-		instance.Path,
-		instance.FileSize,
-		instance.Installed,
-		instance.InstallPath,
-		instance.ID,
-	)
+	batch, err := db.BeginBatch()
 	if err != nil {
-		return fmt.Errorf("failed to update instance: %w", err)
+		return err
 	}
-	return nil
+	defer batch.Rollback()
+
+	if err := batch.UpdateInstance(instance); err != nil {
+		return err
+	}
+	return batch.Commit()
 }
 
-// UpdateInstanceCustomMetadata updates custom metadata for an instance
-func (db *DB) UpdateInstanceCustomMetadata(instanceID string, metadata map[string]any) error {
-	// Delete existing custom metadata
-	_, err := db.conn.Exec("DELETE FROM instance_custom_metadata WHERE instance_id = ?", instanceID)
+// encodeInstanceFiles stores an instance's files as JSON, or "" when it has none
+func encodeInstanceFiles(files []models.InstanceFile) (string, error) {
+	if len(files) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(files)
 	if err != nil {
-		return fmt.Errorf("failed to clear custom metadata: %w", err)
+		return "", fmt.Errorf("failed to encode instance files: %w", err)
 	}
+	return string(data), nil
+}
 
-	// Insert new values
-	for key, value := range metadata {
-		valueJSON, err := json.Marshal(value)
-		if err != nil {
-			return fmt.Errorf("failed to marshal metadata value: %w", err)
-		}
-		_, err = db.conn.Exec(
-			"INSERT INTO instance_custom_metadata (instance_id, key, value) VALUES (?, ?, ?)",
-			instanceID, key, string(valueJSON),
-		)
-		if err != nil {
-			return fmt.Errorf("failed to insert custom metadata: %w", err)
+// decodeInstanceFiles reads files stored by encodeInstanceFiles
+func decodeInstanceFiles(data string) ([]models.InstanceFile, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var files []models.InstanceFile
+	if err := json.Unmarshal([]byte(data), &files); err != nil {
+		return nil, fmt.Errorf("failed to decode instance files: %w", err)
+	}
+	return files, nil
+}
+
+// UpdateInstanceCustomMetadata replaces all custom metadata for an instance and
+// bumps its version. It returns models.ErrVersionConflict if the instance isn't
+// at expectedVersion.
+func (db *DB) UpdateInstanceCustomMetadata(instanceID string, expectedVersion int64, metadata map[string]any) error {
+	batch, err := db.BeginBatch()
+	if err != nil {
+		return err
+	}
+	defer batch.Rollback()
+
+	if err := batch.UpdateInstanceCustomMetadata(instanceID, expectedVersion, metadata); err != nil {
+		return err
+	}
+	return batch.Commit()
+}
+
+// SetInstanceCustomMetadataKeys sets the given custom metadata keys, leaving the
+// instance's other keys alone, and bumps its version. It returns
+// models.ErrVersionConflict if the instance isn't at expectedVersion.
+func (db *DB) SetInstanceCustomMetadataKeys(instanceID string, expectedVersion int64, updates map[string]any) error {
+	batch, err := db.BeginBatch()
+	if err != nil {
+		return err
+	}
+	defer batch.Rollback()
+
+	if err := batch.SetInstanceCustomMetadataKeys(instanceID, expectedVersion, updates); err != nil {
+		return err
+	}
+	return batch.Commit()
+}
+
+// BulkSetInstanceCustomMetadata sets one custom metadata key on every given
+// instance in a single transaction. Nothing is written if any instance is missing.
+func (db *DB) BulkSetInstanceCustomMetadata(instanceIDs []string, key string, value any) error {
+	batch, err := db.BeginBatch()
+	if err != nil {
+		return err
+	}
+	defer batch.Rollback()
+
+	for _, instanceID := range instanceIDs {
+		if err := batch.SetInstanceCustomMetadataKey(instanceID, key, value); err != nil {
+			return err
 		}
 	}
+	return batch.Commit()
+}
 
-	return nil
+// DeleteInstanceCustomMetadataKey removes one custom metadata key and bumps the
+// instance's version. It returns models.ErrVersionConflict if the instance isn't
+// at expectedVersion.
+func (db *DB) DeleteInstanceCustomMetadataKey(instanceID string, expectedVersion int64, key string) error {
+	batch, err := db.BeginBatch()
+	if err != nil {
+		return err
+	}
+	defer batch.Rollback()
+
+	if err := batch.DeleteInstanceCustomMetadataKey(instanceID, expectedVersion, key); err != nil {
+		return err
+	}
+	return batch.Commit()
 }
 
 // StoreExternalMetadata stores metadata from an external source
 func (db *DB) StoreExternalMetadata(gameID string, source string, data map[string]any) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
 	dataJSON, err := json.Marshal(data)
 	if err != nil {
 		return fmt.Errorf("failed to marshal external metadata: %w", err)
@@ -672,7 +1006,8 @@ func (db *DB) StoreExternalMetadata(gameID string, source string, data map[strin
 		VALUES (?, ?, ?)
 		ON CONFLICT(game_id, source) DO UPDATE SET
 			data = excluded.data,
-			fetched_at = CURRENT_TIMESTAMP
+			fetched_at = CURRENT_TIMESTAMP,
+			refresh_failed_at = NULL
 	`
 	_, err = db.conn.Exec(query, gameID, source, string(dataJSON))
 	if err != nil {
@@ -681,33 +1016,179 @@ func (db *DB) StoreExternalMetadata(gameID string, source string, data map[strin
 	return nil
 }
 
+// SetFieldSources records source as the provider of the given game fields
+func (db *DB) SetFieldSources(gameID, source string, fields []string) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO game_field_sources (game_id, field, source)
+		VALUES (?, ?, ?)
+		ON CONFLICT(game_id, field) DO UPDATE SET
+			source = excluded.source,
+			updated_at = CURRENT_TIMESTAMP
+	`
+	for _, field := range fields {
+		if _, err := tx.Exec(query, gameID, field, source); err != nil {
+			return fmt.Errorf("failed to store field source: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetFieldSources returns the recorded provider of each game field, keyed by field.
+// Art provenance from game_art is included under "art.<type>" keys.
+func (db *DB) GetFieldSources(gameID string) (map[string]models.FieldSource, error) {
+	query := `
+		SELECT field, source, updated_at FROM game_field_sources WHERE game_id = ?
+		UNION ALL
+		SELECT 'art.' || art_type, source, NULL FROM game_art WHERE game_id = ?
+	`
+	rows, err := db.conn.Query(query, gameID, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query field sources: %w", err)
+	}
+	defer rows.Close()
+
+	sources := make(map[string]models.FieldSource)
+	for rows.Next() {
+		var fs models.FieldSource
+		var updatedAt sql.NullTime
+		if err := rows.Scan(&fs.Field, &fs.Source, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan field source: %w", err)
+		}
+		if updatedAt.Valid {
+			fs.UpdatedAt = &updatedAt.Time
+		}
+		sources[fs.Field] = fs
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate field sources: %w", err)
+	}
+
+	return sources, nil
+}
+
+// GetExternalMetadataSources lists the sources with cached metadata for a game,
+// most recently fetched first
+func (db *DB) GetExternalMetadataSources(gameID string) ([]string, error) {
+	rows, err := db.conn.Query(`
+		SELECT source FROM external_metadata
+		WHERE game_id = ?
+		ORDER BY fetched_at DESC, source
+	`, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query external metadata sources: %w", err)
+	}
+	defer rows.Close()
+
+	var sources []string
+	for rows.Next() {
+		var source string
+		if err := rows.Scan(&source); err != nil {
+			return nil, fmt.Errorf("failed to scan external metadata source: %w", err)
+		}
+		sources = append(sources, source)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate external metadata sources: %w", err)
+	}
+
+	return sources, nil
+}
+
 // GetExternalMetadata retrieves cached metadata from an external source
 func (db *DB) GetExternalMetadata(gameID string, source string) (map[string]any, error) {
+	data, _, err := db.GetExternalMetadataWithTime(gameID, source)
+	return data, err
+}
+
+// GetExternalMetadataWithTime retrieves cached metadata along with when it was fetched.
+// Returns nil data if nothing is cached.
+func (db *DB) GetExternalMetadataWithTime(gameID string, source string) (map[string]any, time.Time, error) {
 	query := `
-		SELECT data FROM external_metadata
+		SELECT data, fetched_at FROM external_metadata
 		WHERE game_id = ? AND source = ?
 	`
 	var dataJSON string
-	err := db.conn.QueryRow(query, gameID, source).Scan(&dataJSON)
+	var fetchedAt sql.NullTime
+	err := db.conn.QueryRow(query, gameID, source).Scan(&dataJSON, &fetchedAt)
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, time.Time{}, nil
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get external metadata: %w", err)
+		return nil, time.Time{}, fmt.Errorf("failed to get external metadata: %w", err)
 	}
 
 	var data map[string]any
 	if err := json.Unmarshal([]byte(dataJSON), &data); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal external metadata: %w", err)
+		return nil, time.Time{}, fmt.Errorf("failed to unmarshal external metadata: %w", err)
+	}
+
+	return data, fetchedAt.Time, nil
+}
+
+// ExternalMetadataTimes is when a game's cached metadata from a source was
+// fetched, and when a refresh of it last failed since then, if one has
+type ExternalMetadataTimes struct {
+	FetchedAt       time.Time
+	RefreshFailedAt time.Time
+}
+
+// GetExternalMetadataFetchTimes returns when each game's cached metadata from a source
+// was fetched and last failed to refresh, keyed by game ID
+func (db *DB) GetExternalMetadataFetchTimes(source string) (map[string]ExternalMetadataTimes, error) {
+	rows, err := db.conn.Query(`SELECT game_id, fetched_at, refresh_failed_at FROM external_metadata WHERE source = ?`, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata fetch times: %w", err)
 	}
+	defer rows.Close()
+
+	times := make(map[string]ExternalMetadataTimes)
+	for rows.Next() {
+		var gameID string
+		var fetchedAt, failedAt sql.NullTime
+		if err := rows.Scan(&gameID, &fetchedAt, &failedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan metadata fetch time: %w", err)
+		}
+		times[gameID] = ExternalMetadataTimes{FetchedAt: fetchedAt.Time, RefreshFailedAt: failedAt.Time}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate metadata fetch times: %w", err)
+	}
+	return times, nil
+}
+
+// MarkExternalMetadataRefreshFailed records that refreshing a game's cached
+// metadata from a source failed. Games with nothing cached are left alone.
+func (db *DB) MarkExternalMetadataRefreshFailed(gameID, source string) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
 
-	return data, nil
+	query := `UPDATE external_metadata SET refresh_failed_at = CURRENT_TIMESTAMP WHERE game_id = ? AND source = ?`
+	if _, err := db.conn.Exec(query, gameID, source); err != nil {
+		return fmt.Errorf("failed to record metadata refresh failure: %w", err)
+	}
+	return nil
 }
 
 // Emulator methods
 
 // UpsertEmulator creates or updates an emulator record
 func (db *DB) UpsertEmulator(emu models.Emulator) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
 	platformsJSON, _ := json.Marshal(emu.SupportedPlatforms)
 	query := `
 		INSERT INTO emulators (id, name, display_name, type, executable_path, flatpak_id, command_template, default_args, supported_platforms, is_available)
@@ -765,11 +1246,17 @@ func (db *DB) GetEmulators() ([]models.Emulator, error) {
 		}
 		emulators = append(emulators, emu)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate emulators: %w", err)
+	}
 	return emulators, nil
 }
 
 // UpdateEmulatorAvailability updates the availability status of an emulator
 func (db *DB) UpdateEmulatorAvailability(id string, available bool) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
 	query := `UPDATE emulators SET is_available = ? WHERE id = ?`
 	_, err := db.conn.Exec(query, available, id)
 	return err
@@ -779,6 +1266,9 @@ func (db *DB) UpdateEmulatorAvailability(id string, available bool) error {
 
 // UpsertEmulatorCore creates or updates an emulator core record
 func (db *DB) UpsertEmulatorCore(core models.EmulatorCore) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
 	platformsJSON, _ := json.Marshal(core.SupportedPlatforms)
 	query := `
 		INSERT INTO emulator_cores (id, emulator_id, core_id, display_name, supported_platforms, is_available)
@@ -823,6 +1313,9 @@ func (db *DB) GetEmulatorCores(emulatorID string) ([]models.EmulatorCore, error)
 		json.Unmarshal([]byte(platformsJSON), &core.SupportedPlatforms)
 		cores = append(cores, core)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate emulator cores: %w", err)
+	}
 	return cores, nil
 }
 
@@ -843,6 +1336,9 @@ func (db *DB) GetCore(emulatorID, coreID string) (*models.EmulatorCore, error) {
 
 // UpdateEmulatorCoreAvailability updates the availability status of a core
 func (db *DB) UpdateEmulatorCoreAvailability(id string, available bool) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
 	query := `UPDATE emulator_cores SET is_available = ? WHERE id = ?`
 	_, err := db.conn.Exec(query, available, id)
 	return err
@@ -852,6 +1348,9 @@ func (db *DB) UpdateEmulatorCoreAvailability(id string, available bool) error {
 
 // UpsertPlatformEmulator creates or updates a platform-emulator mapping
 func (db *DB) UpsertPlatformEmulator(pe models.PlatformEmulator) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
 	query := `
 		INSERT INTO platform_emulators (id, platform, emulator_id, core_id, is_default)
 		VALUES (?, ?, ?, ?, ?)
@@ -865,9 +1364,51 @@ func (db *DB) UpsertPlatformEmulator(pe models.PlatformEmulator) error {
 	return err
 }
 
-// ClearPlatformEmulators removes all platform-emulator mappings
-func (db *DB) ClearPlatformEmulators() error {
-	_, err := db.conn.Exec("DELETE FROM platform_emulators")
+// GetPlatformEmulators returns every platform mapping, ordered by platform and ID
+func (db *DB) GetPlatformEmulators() ([]models.PlatformEmulator, error) {
+	rows, err := db.conn.Query(`SELECT id, platform, emulator_id, COALESCE(core_id, ''), is_default FROM platform_emulators ORDER BY platform, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get platform emulators: %w", err)
+	}
+	defer rows.Close()
+
+	var mappings []models.PlatformEmulator
+	for rows.Next() {
+		var pe models.PlatformEmulator
+		if err := rows.Scan(&pe.ID, &pe.Platform, &pe.EmulatorID, &pe.CoreID, &pe.IsDefault); err != nil {
+			return nil, fmt.Errorf("failed to scan platform emulator: %w", err)
+		}
+		mappings = append(mappings, pe)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate platform emulators: %w", err)
+	}
+	return mappings, nil
+}
+
+// AssignPlatformEmulator adds a user-assigned platform-emulator mapping, or marks an
+// existing one as user-assigned. Assigned mappings survive ClearGeneratedPlatformEmulators.
+// The mapping becomes the platform default when the platform has none.
+func (db *DB) AssignPlatformEmulator(pe models.PlatformEmulator) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
+	query := `
+		INSERT INTO platform_emulators (id, platform, emulator_id, core_id, is_default, user_assigned)
+		VALUES (?, ?, ?, ?, NOT EXISTS (SELECT 1 FROM platform_emulators WHERE platform = ? AND is_default = 1), 1)
+		ON CONFLICT(platform, emulator_id, core_id) DO UPDATE SET user_assigned = 1
+	`
+	_, err := db.conn.Exec(query, pe.ID, pe.Platform, pe.EmulatorID, pe.CoreID, pe.Platform)
+	return err
+}
+
+// ClearGeneratedPlatformEmulators removes the platform-emulator mappings generated from
+// SupportedPlatforms, keeping those the user assigned
+func (db *DB) ClearGeneratedPlatformEmulators() error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
+	_, err := db.conn.Exec("DELETE FROM platform_emulators WHERE user_assigned = 0")
 	return err
 }
 
@@ -976,6 +1517,9 @@ func (db *DB) GetEmulatorsForPlatform(platform string) ([]models.Emulator, []mod
 			cores = append(cores, core)
 		}
 	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to iterate platform emulators: %w", err)
+	}
 	return emulators, cores, nil
 }
 
@@ -1045,12 +1589,27 @@ func (db *DB) GetAvailableEmulatorsForPlatform(platform string) ([]AvailableEmul
 
 		pairs = append(pairs, pair)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate available emulators: %w", err)
+	}
 
 	return pairs, nil
 }
 
+// SetPlatformEmulatorDefault marks a single mapping as a default without clearing others
+func (db *DB) SetPlatformEmulatorDefault(id string) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
+	_, err := db.conn.Exec(`UPDATE platform_emulators SET is_default = 1 WHERE id = ?`, id)
+	return err
+}
+
 // SetPlatformDefaultEmulator sets the default emulator for a platform
 func (db *DB) SetPlatformDefaultEmulator(platform, emulatorID, coreID string) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
 	// Clear existing default
 	_, err := db.conn.Exec(`UPDATE platform_emulators SET is_default = 0 WHERE platform = ?`, platform)
 	if err != nil {
@@ -1066,6 +1625,9 @@ func (db *DB) SetPlatformDefaultEmulator(platform, emulatorID, coreID string) er
 
 // SetInstanceEmulatorSettings creates or updates instance-specific emulator settings
 func (db *DB) SetInstanceEmulatorSettings(instanceID, emulatorID, coreID, customArgs string) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
 	query := `
 		INSERT INTO instance_emulator_settings (instance_id, emulator_id, core_id, custom_args)
 		VALUES (?, ?, ?, ?)
@@ -1080,13 +1642,183 @@ func (db *DB) SetInstanceEmulatorSettings(instanceID, emulatorID, coreID, custom
 
 // GetInstanceEmulatorSettings retrieves emulator settings for an instance
 func (db *DB) GetInstanceEmulatorSettings(instanceID string) (*models.InstanceEmulatorSettings, error) {
-	query := `SELECT instance_id, emulator_id, core_id, custom_args FROM instance_emulator_settings WHERE instance_id = ?`
+	query := `SELECT instance_id, emulator_id, core_id, custom_args, COALESCE(profile_id, '') FROM instance_emulator_settings WHERE instance_id = ?`
 	row := db.conn.QueryRow(query, instanceID)
 
 	var settings models.InstanceEmulatorSettings
-	err := row.Scan(&settings.InstanceID, &settings.EmulatorID, &settings.CoreID, &settings.CustomArgs)
+	err := row.Scan(&settings.InstanceID, &settings.EmulatorID, &settings.CoreID, &settings.CustomArgs, &settings.ProfileID)
 	if err != nil {
 		return nil, err
 	}
 	return &settings, nil
 }
+
+// GetUnavailableInstanceEmulators returns instance overrides whose emulator, or core
+// when one is set, is missing or not available
+func (db *DB) GetUnavailableInstanceEmulators() ([]models.UnavailableInstanceEmulator, error) {
+	query := `
+		SELECT s.instance_id, i.game_id, s.emulator_id, COALESCE(s.core_id, '')
+		FROM instance_emulator_settings s
+		JOIN game_instances i ON i.id = s.instance_id
+		LEFT JOIN emulators e ON e.id = s.emulator_id
+		LEFT JOIN emulator_cores c ON c.emulator_id = s.emulator_id AND c.core_id = s.core_id
+		WHERE e.id IS NULL OR NOT e.is_available
+			OR (COALESCE(s.core_id, '') != '' AND (c.id IS NULL OR NOT c.is_available))
+		ORDER BY s.instance_id
+	`
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unavailable instance emulators: %w", err)
+	}
+	defer rows.Close()
+
+	var unavailable []models.UnavailableInstanceEmulator
+	for rows.Next() {
+		var u models.UnavailableInstanceEmulator
+		if err := rows.Scan(&u.InstanceID, &u.GameID, &u.EmulatorID, &u.CoreID); err != nil {
+			return nil, fmt.Errorf("failed to scan unavailable instance emulator: %w", err)
+		}
+		unavailable = append(unavailable, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate unavailable instance emulators: %w", err)
+	}
+	return unavailable, nil
+}
+
+// SetInstanceArgProfile points an instance at an args profile, switching it to the
+// profile's emulator. The core is kept only if the emulator doesn't change.
+func (db *DB) SetInstanceArgProfile(instanceID string, profile models.EmulatorArgProfile) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
+	query := `
+		INSERT INTO instance_emulator_settings (instance_id, emulator_id, core_id, custom_args, profile_id)
+		VALUES (?, ?, '', '', ?)
+		ON CONFLICT(instance_id) DO UPDATE SET
+			core_id = CASE WHEN emulator_id = excluded.emulator_id THEN core_id ELSE '' END,
+			emulator_id = excluded.emulator_id,
+			profile_id = excluded.profile_id
+	`
+	if _, err := db.conn.Exec(query, instanceID, profile.EmulatorID, profile.ID); err != nil {
+		return fmt.Errorf("failed to set args profile: %w", err)
+	}
+	return nil
+}
+
+// ClearInstanceArgProfile removes an instance's args profile, keeping its other settings
+func (db *DB) ClearInstanceArgProfile(instanceID string) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
+	if _, err := db.conn.Exec(`UPDATE instance_emulator_settings SET profile_id = NULL WHERE instance_id = ?`, instanceID); err != nil {
+		return fmt.Errorf("failed to clear args profile: %w", err)
+	}
+	return nil
+}
+
+// EmulatorArgProfile methods
+
+// CreateArgProfile stores a named args preset for an emulator
+func (db *DB) CreateArgProfile(profile models.EmulatorArgProfile) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
+	query := `INSERT INTO emulator_arg_profiles (id, emulator_id, name, args) VALUES (?, ?, ?, ?)`
+	if _, err := db.conn.Exec(query, profile.ID, profile.EmulatorID, profile.Name, profile.Args); err != nil {
+		return fmt.Errorf("failed to create args profile: %w", err)
+	}
+	return nil
+}
+
+// GetArgProfile returns an args profile, or nil if it doesn't exist
+func (db *DB) GetArgProfile(id string) (*models.EmulatorArgProfile, error) {
+	query := `SELECT id, emulator_id, name, args, created_at FROM emulator_arg_profiles WHERE id = ?`
+
+	var profile models.EmulatorArgProfile
+	err := db.conn.QueryRow(query, id).Scan(&profile.ID, &profile.EmulatorID, &profile.Name, &profile.Args, &profile.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get args profile: %w", err)
+	}
+	return &profile, nil
+}
+
+// GetArgProfiles lists an emulator's args profiles by name. An empty emulatorID lists all.
+func (db *DB) GetArgProfiles(emulatorID string) ([]models.EmulatorArgProfile, error) {
+	query := `SELECT id, emulator_id, name, args, created_at FROM emulator_arg_profiles`
+	var args []any
+	if emulatorID != "" {
+		query += ` WHERE emulator_id = ?`
+		args = append(args, emulatorID)
+	}
+	query += ` ORDER BY emulator_id, name`
+
+	rows, err := db.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get args profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []models.EmulatorArgProfile
+	for rows.Next() {
+		var profile models.EmulatorArgProfile
+		if err := rows.Scan(&profile.ID, &profile.EmulatorID, &profile.Name, &profile.Args, &profile.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan args profile: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate args profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+// DeleteArgProfile removes an args profile and detaches it from any instances
+func (db *DB) DeleteArgProfile(id string) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE instance_emulator_settings SET profile_id = NULL WHERE profile_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to detach args profile: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM emulator_arg_profiles WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete args profile: %w", err)
+	}
+	return tx.Commit()
+}
+
+// GetSetting returns a UI setting. ok is false if the key has never been set.
+func (db *DB) GetSetting(key string) (value string, ok bool, err error) {
+	err = db.conn.QueryRow("SELECT value FROM settings WHERE key = ?", key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get setting: %w", err)
+	}
+	return value, true, nil
+}
+
+// SetSetting stores a UI setting, replacing any previous value
+func (db *DB) SetSetting(key, value string) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
+	query := `
+		INSERT INTO settings (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value
+	`
+	if _, err := db.conn.Exec(query, key, value); err != nil {
+		return fmt.Errorf("failed to set setting: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,689 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// newTestDB creates a migrated database in a temporary directory
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+
+	db, err := New(filepath.Join(t.TempDir(), "games.db"))
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestNew_CreatesMissingDirectories(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "database", "games.db")
+
+	db, err := New(path)
+	if err != nil {
+		t.Fatalf("expected New to create parent directories, got %v", err)
+	}
+	defer db.Close()
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected database file to exist: %v", err)
+	}
+}
+
+func TestGetInstances_ScanError(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := db.CreateGame(&models.Game{ID: "game1", Name: "Game One"}); err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+
+	// A NULL path cannot be scanned into a string field
+	_, err := db.conn.Exec(`INSERT INTO game_instances (id, game_id, source, platform, path) VALUES ('inst1', 'game1', 'mock', 'nes', NULL)`)
+	if err != nil {
+		t.Fatalf("failed to insert instance: %v", err)
+	}
+
+	instances, err := db.GetInstances(models.GameFilter{})
+	if err == nil {
+		t.Fatal("expected scan error, got nil")
+	}
+	if instances != nil {
+		t.Errorf("expected nil instances on error, got %d", len(instances))
+	}
+}
+
+func TestGetEmulators_ScanError(t *testing.T) {
+	db := newTestDB(t)
+
+	// A NULL executable_path cannot be scanned into a string field
+	_, err := db.conn.Exec(`INSERT INTO emulators (id, name, display_name, type, executable_path, command_template) VALUES ('emu', 'emu', 'Emu', 'native', NULL, '{rom}')`)
+	if err != nil {
+		t.Fatalf("failed to insert emulator: %v", err)
+	}
+
+	if _, err := db.GetEmulators(); err == nil {
+		t.Fatal("expected scan error, got nil")
+	}
+
+	// The connection must still be usable after the failed iteration
+	if err := db.UpsertEmulator(models.Emulator{ID: "emu", Name: "emu", DisplayName: "Emu", Type: models.EmulatorTypeNative, CommandTemplate: "{rom}"}); err != nil {
+		t.Fatalf("failed to upsert emulator after scan error: %v", err)
+	}
+	emulators, err := db.GetEmulators()
+	if err != nil {
+		t.Fatalf("GetEmulators failed after fixing row: %v", err)
+	}
+	if len(emulators) != 1 {
+		t.Errorf("expected 1 emulator, got %d", len(emulators))
+	}
+}
+
+func TestBatch_SavepointIsolatesFailures(t *testing.T) {
+	db := newTestDB(t)
+
+	batch, err := db.BeginBatch()
+	if err != nil {
+		t.Fatalf("failed to begin batch: %v", err)
+	}
+	defer batch.Rollback()
+
+	games := []*models.Game{
+		{ID: "game1", Name: "Game One", Platforms: []string{"nes"}},
+		{ID: "game1", Name: "Duplicate", Platforms: []string{"snes"}},
+		{ID: "game2", Name: "Game Two", Genres: []string{"Platformer"}},
+	}
+
+	var failures int
+	for _, game := range games {
+		if err := batch.Savepoint(func() error { return batch.CreateGame(game) }); err != nil {
+			failures++
+		}
+	}
+	if failures != 1 {
+		t.Errorf("expected 1 failed item, got %d", failures)
+	}
+
+	if err := batch.Commit(); err != nil {
+		t.Fatalf("failed to commit batch: %v", err)
+	}
+
+	ids, err := db.GetGameIDs()
+	if err != nil {
+		t.Fatalf("GetGameIDs failed: %v", err)
+	}
+	if len(ids) != 2 || !ids["game1"] || !ids["game2"] {
+		t.Errorf("unexpected game IDs after batch: %v", ids)
+	}
+
+	game, err := db.GetGame("game1")
+	if err != nil {
+		t.Fatalf("GetGame failed: %v", err)
+	}
+	if game.Name != "Game One" || len(game.Platforms) != 1 || game.Platforms[0] != "nes" {
+		t.Errorf("failed item leaked into committed game: %+v", game)
+	}
+}
+
+func TestGetGameIDs(t *testing.T) {
+	db := newTestDB(t)
+
+	ids, err := db.GetGameIDs()
+	if err != nil {
+		t.Fatalf("GetGameIDs failed: %v", err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("expected no game IDs, got %v", ids)
+	}
+
+	for _, id := range []string{"game1", "game2"} {
+		if err := db.CreateGame(&models.Game{ID: id, Name: id}); err != nil {
+			t.Fatalf("failed to create game: %v", err)
+		}
+	}
+
+	ids, err = db.GetGameIDs()
+	if err != nil {
+		t.Fatalf("GetGameIDs failed: %v", err)
+	}
+	if len(ids) != 2 || !ids["game1"] || !ids["game2"] {
+		t.Errorf("unexpected game IDs: %v", ids)
+	}
+}
+
+func TestConcurrentReadWrite(t *testing.T) {
+	db := newTestDB(t)
+
+	var mode string
+	if err := db.conn.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatalf("failed to query journal mode: %v", err)
+	}
+	if mode != "wal" {
+		t.Errorf("expected wal journal mode, got %q", mode)
+	}
+
+	const workers = 8
+	const iterations = 25
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*iterations*2)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(2)
+
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				id := fmt.Sprintf("game-%d-%d", w, i)
+				if err := db.CreateGame(&models.Game{ID: id, Name: id, Platforms: []string{"nes"}}); err != nil {
+					errs <- fmt.Errorf("write %s: %w", id, err)
+				}
+			}
+		}(w)
+
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				if _, err := db.GetGameIDs(); err != nil {
+					errs <- fmt.Errorf("read: %w", err)
+				}
+				if _, err := db.GetInstances(models.GameFilter{}); err != nil {
+					errs <- fmt.Errorf("read instances: %w", err)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	ids, err := db.GetGameIDs()
+	if err != nil {
+		t.Fatalf("GetGameIDs failed: %v", err)
+	}
+	if len(ids) != workers*iterations {
+		t.Errorf("expected %d games, got %d", workers*iterations, len(ids))
+	}
+}
+
+func TestFieldSources(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := db.CreateGame(&models.Game{ID: "game1", Name: "Game One"}); err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+	if err := db.SetFieldSources("game1", "igdb", []string{"name", "description"}); err != nil {
+		t.Fatalf("SetFieldSources failed: %v", err)
+	}
+	if err := db.SetFieldSources("game1", "mobygames", []string{"description"}); err != nil {
+		t.Fatalf("SetFieldSources failed: %v", err)
+	}
+	if err := db.StoreGameArt("game1", "cover", "https://example.com/cover.png", "steamgriddb"); err != nil {
+		t.Fatalf("StoreGameArt failed: %v", err)
+	}
+
+	sources, err := db.GetFieldSources("game1")
+	if err != nil {
+		t.Fatalf("GetFieldSources failed: %v", err)
+	}
+
+	want := map[string]string{"name": "igdb", "description": "mobygames", "art.cover": "steamgriddb"}
+	if len(sources) != len(want) {
+		t.Errorf("expected %d field sources, got %v", len(want), sources)
+	}
+	for field, source := range want {
+		if sources[field].Source != source {
+			t.Errorf("expected %s from %s, got %q", field, source, sources[field].Source)
+		}
+	}
+	if sources["name"].UpdatedAt == nil {
+		t.Error("expected updatedAt for game field source")
+	}
+}
+
+func TestGetExternalMetadataSources(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := db.CreateGame(&models.Game{ID: "game1", Name: "Game One"}); err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+	for _, source := range []string{"igdb", "mobygames"} {
+		if err := db.StoreExternalMetadata("game1", source, map[string]any{"name": source}); err != nil {
+			t.Fatalf("StoreExternalMetadata failed: %v", err)
+		}
+	}
+
+	sources, err := db.GetExternalMetadataSources("game1")
+	if err != nil {
+		t.Fatalf("GetExternalMetadataSources failed: %v", err)
+	}
+	if len(sources) != 2 {
+		t.Errorf("expected 2 sources, got %v", sources)
+	}
+
+	sources, err = db.GetExternalMetadataSources("missing")
+	if err != nil {
+		t.Fatalf("GetExternalMetadataSources failed: %v", err)
+	}
+	if len(sources) != 0 {
+		t.Errorf("expected no sources for unknown game, got %v", sources)
+	}
+}
+
+func TestGetExternalMetadataWithTime(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := db.CreateGame(&models.Game{ID: "game1", Name: "Game One"}); err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+
+	data, fetchedAt, err := db.GetExternalMetadataWithTime("game1", "igdb")
+	if err != nil {
+		t.Fatalf("GetExternalMetadataWithTime failed: %v", err)
+	}
+	if data != nil || !fetchedAt.IsZero() {
+		t.Errorf("expected no cached metadata, got %v at %v", data, fetchedAt)
+	}
+
+	if err := db.StoreExternalMetadata("game1", "igdb", map[string]any{"name": "Game One"}); err != nil {
+		t.Fatalf("StoreExternalMetadata failed: %v", err)
+	}
+	if _, err := db.conn.Exec(`UPDATE external_metadata SET fetched_at = datetime('now', '-40 days')`); err != nil {
+		t.Fatalf("failed to age metadata: %v", err)
+	}
+
+	data, fetchedAt, err = db.GetExternalMetadataWithTime("game1", "igdb")
+	if err != nil {
+		t.Fatalf("GetExternalMetadataWithTime failed: %v", err)
+	}
+	if data["name"] != "Game One" {
+		t.Errorf("unexpected data: %v", data)
+	}
+	if age := time.Since(fetchedAt); age < 39*24*time.Hour || age > 41*24*time.Hour {
+		t.Errorf("expected fetched_at about 40 days ago, got %v", fetchedAt)
+	}
+}
+
+func TestGetExternalMetadataFetchTimes(t *testing.T) {
+	db := newTestDB(t)
+
+	for _, id := range []string{"game1", "game2"} {
+		if err := db.CreateGame(&models.Game{ID: id, Name: id}); err != nil {
+			t.Fatalf("failed to create game: %v", err)
+		}
+	}
+	if err := db.StoreExternalMetadata("game1", "igdb", map[string]any{"name": "Game One"}); err != nil {
+		t.Fatalf("StoreExternalMetadata failed: %v", err)
+	}
+	if err := db.StoreExternalMetadata("game2", "mobygames", map[string]any{"name": "Game Two"}); err != nil {
+		t.Fatalf("StoreExternalMetadata failed: %v", err)
+	}
+
+	times, err := db.GetExternalMetadataFetchTimes("igdb")
+	if err != nil {
+		t.Fatalf("GetExternalMetadataFetchTimes failed: %v", err)
+	}
+	if len(times) != 1 || times["game1"].FetchedAt.IsZero() || !times["game1"].RefreshFailedAt.IsZero() {
+		t.Errorf("expected a fetch time for game1 only, got %v", times)
+	}
+
+	if err := db.MarkExternalMetadataRefreshFailed("game1", "igdb"); err != nil {
+		t.Fatalf("MarkExternalMetadataRefreshFailed failed: %v", err)
+	}
+	if times, err = db.GetExternalMetadataFetchTimes("igdb"); err != nil || times["game1"].RefreshFailedAt.IsZero() {
+		t.Errorf("expected the failed refresh to be recorded, got %v (%v)", times, err)
+	}
+
+	// A successful fetch clears it
+	if err := db.StoreExternalMetadata("game1", "igdb", map[string]any{"name": "Game One"}); err != nil {
+		t.Fatalf("StoreExternalMetadata failed: %v", err)
+	}
+	if times, err = db.GetExternalMetadataFetchTimes("igdb"); err != nil || !times["game1"].RefreshFailedAt.IsZero() {
+		t.Errorf("expected a new fetch to clear the failure, got %v (%v)", times, err)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	db := newTestDB(t)
+
+	if _, ok, err := db.GetSetting("grid.size"); err != nil || ok {
+		t.Fatalf("expected unset setting, got ok=%v err=%v", ok, err)
+	}
+
+	if err := db.SetSetting("grid.size", "large"); err != nil {
+		t.Fatalf("SetSetting failed: %v", err)
+	}
+	if err := db.SetSetting("grid.size", "small"); err != nil {
+		t.Fatalf("SetSetting failed: %v", err)
+	}
+
+	value, ok, err := db.GetSetting("grid.size")
+	if err != nil {
+		t.Fatalf("GetSetting failed: %v", err)
+	}
+	if !ok || value != "small" {
+		t.Errorf("expected small, got %q (ok=%v)", value, ok)
+	}
+}
+
+func TestMigrate_AddsMissingColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.db")
+
+	// Settings table as created before args profiles existed
+	conn, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	if _, err := conn.Exec(`CREATE TABLE instance_emulator_settings (
+		instance_id TEXT PRIMARY KEY,
+		emulator_id TEXT NOT NULL,
+		core_id TEXT,
+		custom_args TEXT
+	)`); err != nil {
+		t.Fatalf("failed to create old table: %v", err)
+	}
+	if _, err := conn.Exec(`INSERT INTO instance_emulator_settings VALUES ('inst1', 'dolphin', '', '-b')`); err != nil {
+		t.Fatalf("failed to seed old table: %v", err)
+	}
+	conn.Close()
+
+	// Migrating twice must be a no-op the second time
+	for i := 0; i < 2; i++ {
+		db, err := New(path)
+		if err != nil {
+			t.Fatalf("failed to migrate database: %v", err)
+		}
+		settings, err := db.GetInstanceEmulatorSettings("inst1")
+		db.Close()
+		if err != nil {
+			t.Fatalf("GetInstanceEmulatorSettings failed: %v", err)
+		}
+		if settings.CustomArgs != "-b" || settings.ProfileID != "" {
+			t.Errorf("unexpected settings after migration: %+v", settings)
+		}
+	}
+}
+
+// writeBrokenSchema creates a database whose game_instances table lacks columns
+// the migrations index, so migrating it fails
+func writeBrokenSchema(t *testing.T, path string) {
+	t.Helper()
+
+	conn, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Exec(`CREATE TABLE game_instances (id TEXT PRIMARY KEY)`); err != nil {
+		t.Fatalf("failed to create broken schema: %v", err)
+	}
+}
+
+func TestNew_RebuildsAfterFailedMigration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.db")
+	writeBrokenSchema(t, path)
+
+	db, err := New(path)
+	if err != nil {
+		t.Fatalf("expected recovery, got %v", err)
+	}
+	defer db.Close()
+
+	status := db.Status()
+	if !status.Recovered || status.Degraded || status.Error == "" {
+		t.Errorf("unexpected status %+v", status)
+	}
+	if _, err := os.Stat(status.BackupPath); err != nil {
+		t.Errorf("expected backup of the broken database: %v", err)
+	}
+	if err := db.SetSetting("k", "v"); err != nil {
+		t.Errorf("expected rebuilt database to be writable: %v", err)
+	}
+}
+
+func TestNew_DegradedWhenRebuildFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.db")
+	writeBrokenSchema(t, path)
+
+	original := rebuild
+	rebuild = func(string) (string, *DB, error) { return "", nil, fmt.Errorf("disk full") }
+	t.Cleanup(func() { rebuild = original })
+
+	db, err := New(path)
+	if err != nil {
+		t.Fatalf("expected degraded mode, got %v", err)
+	}
+	defer db.Close()
+
+	status := db.Status()
+	if !status.Degraded || !status.ReadOnly {
+		t.Errorf("expected degraded read-only status, got %+v", status)
+	}
+	if err := db.SetSetting("k", "v"); err == nil {
+		t.Error("expected writes to fail in degraded mode")
+	}
+}
+
+// corrupt overwrites a closed database file with garbage
+func corrupt(t *testing.T, path string) {
+	t.Helper()
+
+	os.Remove(path + "-wal")
+	os.Remove(path + "-shm")
+	if err := os.WriteFile(path, []byte("definitely not an sqlite database, just some junk bytes"), 0644); err != nil {
+		t.Fatalf("failed to corrupt database: %v", err)
+	}
+}
+
+func TestNew_RestoresCorruptDatabaseFromBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.db")
+
+	db, err := New(path)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	if db.Status().Integrity != models.IntegrityOK {
+		t.Errorf("expected healthy database, got %+v", db.Status())
+	}
+	if err := db.SetSetting("grid.size", "large"); err != nil {
+		t.Fatalf("SetSetting failed: %v", err)
+	}
+	if err := db.Backup(path + ".bak"); err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+	db.Close()
+	corrupt(t, path)
+
+	db, err = New(path)
+	if err != nil {
+		t.Fatalf("expected recovery from backup, got %v", err)
+	}
+	defer db.Close()
+
+	status := db.Status()
+	if status.Integrity != models.IntegrityCorrupt || status.RestoredFrom != path+".bak" {
+		t.Errorf("unexpected status %+v", status)
+	}
+	if value, ok, err := db.GetSetting("grid.size"); err != nil || !ok || value != "large" {
+		t.Errorf("expected data from backup, got %q ok=%v err=%v", value, ok, err)
+	}
+
+	stats, err := db.Stats()
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if stats.SizeBytes == 0 || stats.LastBackup == nil || stats.Status.Integrity != models.IntegrityCorrupt {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestNew_RebuildsCorruptDatabaseWithoutBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.db")
+	corrupt(t, path)
+
+	db, err := New(path)
+	if err != nil {
+		t.Fatalf("expected rebuild, got %v", err)
+	}
+	defer db.Close()
+
+	status := db.Status()
+	if !status.Recovered || status.Integrity != models.IntegrityCorrupt || status.RestoredFrom != "" {
+		t.Errorf("unexpected status %+v", status)
+	}
+	if _, err := os.Stat(status.BackupPath); err != nil {
+		t.Errorf("expected corrupt file to be kept: %v", err)
+	}
+}
+
+func TestGetRecentInstanceIDs(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := db.CreateGame(&models.Game{ID: "game1", Name: "Game"}); err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+	added := map[string]string{
+		"old":    "2024-01-01 10:00:00",
+		"newest": "2024-03-01 10:00:00",
+		"middle": "2024-02-01 10:00:00",
+	}
+	for id, createdAt := range added {
+		if _, err := db.conn.Exec(`INSERT INTO game_instances (id, game_id, source, platform, created_at) VALUES (?, 'game1', 'mock', 'pc', ?)`, id, createdAt); err != nil {
+			t.Fatalf("failed to insert instance: %v", err)
+		}
+	}
+
+	ids, err := db.GetRecentInstanceIDs(2)
+	if err != nil {
+		t.Fatalf("GetRecentInstanceIDs failed: %v", err)
+	}
+	if fmt.Sprint(ids) != "[newest middle]" {
+		t.Errorf("expected [newest middle], got %v", ids)
+	}
+}
+
+func TestInstanceFiles(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := db.CreateGame(&models.Game{ID: "game1", Name: "Game"}); err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+	instance := &models.GameInstance{
+		ID: "inst1", GameID: "game1", Source: "emulated", Platform: "amiga", Path: "/roms/game (Disk 1).adf",
+		Files: []models.InstanceFile{{Path: "/roms/game (Disk 2).adf", Role: models.FileRoleDisk}},
+	}
+	if err := db.CreateInstance(instance); err != nil {
+		t.Fatalf("CreateInstance failed: %v", err)
+	}
+
+	got, err := db.GetInstance("inst1")
+	if err != nil {
+		t.Fatalf("GetInstance failed: %v", err)
+	}
+	if !slices.Equal(got.Files, instance.Files) {
+		t.Errorf("expected files %v, got %v", instance.Files, got.Files)
+	}
+
+	// Clearing the files stores none
+	instance.Files = nil
+	if err := db.UpdateInstance(instance); err != nil {
+		t.Fatalf("UpdateInstance failed: %v", err)
+	}
+	instances, err := db.GetInstances(models.GameFilter{})
+	if err != nil {
+		t.Fatalf("GetInstances failed: %v", err)
+	}
+	if len(instances) != 1 || instances[0].Files != nil {
+		t.Errorf("expected no files after clearing them, got %+v", instances)
+	}
+}
+
+func TestInstanceVersionConflict(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := db.CreateGame(&models.Game{ID: "game1", Name: "Game"}); err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+	if err := db.CreateInstance(&models.GameInstance{ID: "inst1", GameID: "game1", Source: "emulated", Platform: "nes"}); err != nil {
+		t.Fatalf("CreateInstance failed: %v", err)
+	}
+
+	// Two copies read at the same version: the first write wins
+	ui, err := db.GetInstance("inst1")
+	if err != nil {
+		t.Fatalf("GetInstance failed: %v", err)
+	}
+	refresh, _ := db.GetInstance("inst1")
+	if ui.Version != 1 {
+		t.Fatalf("expected a new instance at version 1, got %d", ui.Version)
+	}
+
+	if err := db.UpdateInstanceCustomMetadata("inst1", ui.Version, map[string]any{"favorite": true}); err != nil {
+		t.Fatalf("UpdateInstanceCustomMetadata failed: %v", err)
+	}
+	refresh.Path = "/roms/moved.nes"
+	if err := db.UpdateInstance(refresh); !errors.Is(err, models.ErrVersionConflict) {
+		t.Fatalf("expected a version conflict, got %v", err)
+	}
+	if err := db.UpdateInstanceCustomMetadata("inst1", 1, map[string]any{}); !errors.Is(err, models.ErrVersionConflict) {
+		t.Errorf("expected a version conflict for custom metadata, got %v", err)
+	}
+
+	// After re-reading, the update applies and bumps the version again
+	refresh, _ = db.GetInstance("inst1")
+	refresh.Path = "/roms/moved.nes"
+	if err := db.UpdateInstance(refresh); err != nil {
+		t.Fatalf("UpdateInstance failed: %v", err)
+	}
+	got, _ := db.GetInstance("inst1")
+	if got.Version != 3 || refresh.Version != 3 || got.Path != "/roms/moved.nes" || got.CustomMetadata["favorite"] != true {
+		t.Errorf("expected both updates at version 3, got %+v", got)
+	}
+}
+
+func TestSetInstanceCustomMetadataKeys(t *testing.T) {
+	db := newTestDB(t)
+
+	if err := db.CreateGame(&models.Game{ID: "game1", Name: "Game"}); err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+	instance := &models.GameInstance{ID: "inst1", GameID: "game1", Source: "steam", Platform: "pc",
+		CustomMetadata: map[string]any{"steam.playtime": "90", "favorite": true}}
+	if err := db.CreateInstance(instance); err != nil {
+		t.Fatalf("CreateInstance failed: %v", err)
+	}
+
+	if err := db.SetInstanceCustomMetadataKeys("inst1", 1, map[string]any{"favorite": false, "rating": "5"}); err != nil {
+		t.Fatalf("SetInstanceCustomMetadataKeys failed: %v", err)
+	}
+	if err := db.DeleteInstanceCustomMetadataKey("inst1", 2, "rating"); err != nil {
+		t.Fatalf("DeleteInstanceCustomMetadataKey failed: %v", err)
+	}
+	if err := db.DeleteInstanceCustomMetadataKey("inst1", 2, "favorite"); !errors.Is(err, models.ErrVersionConflict) {
+		t.Errorf("expected a version conflict, got %v", err)
+	}
+
+	got, err := db.GetInstance("inst1")
+	if err != nil {
+		t.Fatalf("GetInstance failed: %v", err)
+	}
+	want := map[string]any{"steam.playtime": "90", "favorite": false}
+	if !maps.Equal(got.CustomMetadata, want) || got.Version != 3 {
+		t.Errorf("expected %v at version 3, got %v at version %d", want, got.CustomMetadata, got.Version)
+	}
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+const (
+	// backupInterval is how old the automatic backup may get before New replaces it
+	backupInterval = 24 * time.Hour
+	// maxRestoreAge is the oldest backup restored automatically after corruption.
+	// Older backups would lose too many changes, so the database is rebuilt instead.
+	maxRestoreAge = 7 * 24 * time.Hour
+)
+
+// backupPath is where the automatic backup of a database is kept
+func backupPath(dbPath string) string {
+	return dbPath + ".bak"
+}
+
+// integrityCheck runs PRAGMA integrity_check and returns "ok" or the problems found
+func (db *DB) integrityCheck() (string, error) {
+	rows, err := db.conn.Query("PRAGMA integrity_check")
+	if err != nil {
+		return "", fmt.Errorf("failed to check integrity: %w", err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return "", fmt.Errorf("failed to read integrity check: %w", err)
+		}
+		problems = append(problems, line)
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("failed to check integrity: %w", err)
+	}
+	return strings.Join(problems, "; "), nil
+}
+
+// Backup writes a consistent copy of the database to dest, replacing it atomically
+func (db *DB) Backup(dest string) error {
+	tmp := dest + ".tmp"
+	os.Remove(tmp)
+
+	if _, err := db.conn.Exec("VACUUM INTO ?", tmp); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace database backup: %w", err)
+	}
+	return nil
+}
+
+// backupIfStale refreshes the automatic backup when it's missing or older than backupInterval
+func (db *DB) backupIfStale() {
+	dest := backupPath(db.status.Path)
+	if info, err := os.Stat(dest); err == nil && time.Since(info.ModTime()) < backupInterval {
+		return
+	}
+	if err := db.Backup(dest); err != nil {
+		db.status.BackupError = err.Error()
+	}
+}
+
+// Stats returns row counts, file size, integrity and backup state
+func (db *DB) Stats() (models.DatabaseStats, error) {
+	stats := models.DatabaseStats{Status: db.status}
+
+	counts := []struct {
+		table string
+		dest  *int
+	}{
+		{"games", &stats.Games},
+		{"game_instances", &stats.Instances},
+		{"emulators", &stats.Emulators},
+	}
+	for _, c := range counts {
+		if err := db.conn.QueryRow("SELECT COUNT(*) FROM " + c.table).Scan(c.dest); err != nil {
+			return stats, fmt.Errorf("failed to count %s: %w", c.table, err)
+		}
+	}
+
+	for _, path := range []string{db.status.Path, db.status.Path + "-wal"} {
+		if info, err := os.Stat(path); err == nil {
+			stats.SizeBytes += info.Size()
+		}
+	}
+	if info, err := os.Stat(backupPath(db.status.Path)); err == nil {
+		modTime := info.ModTime()
+		stats.LastBackup = &modTime
+	}
+
+	return stats, nil
+}
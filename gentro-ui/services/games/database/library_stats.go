@@ -0,0 +1,112 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// playtimeQuery sums playtime minutes per game. Each instance counts the larger
+// of the minutes its source reported under the custom metadata keys given as
+// parameters and the minutes tracked from its own play sessions, since sessions
+// launched here are usually in the source's count too. Values are stored
+// JSON-encoded, e.g. "\"90\"".
+const playtimeQuery = `
+	SELECT i.game_id, SUM(MAX(i.playtime_seconds / 60, COALESCE(m.minutes, 0))) AS minutes
+	FROM game_instances i
+	LEFT JOIN (
+		SELECT instance_id, SUM(CAST(TRIM(value, '"') AS INTEGER)) AS minutes
+		FROM instance_custom_metadata
+		WHERE key IN (%s)
+		GROUP BY instance_id
+	) m ON m.instance_id = i.id
+	GROUP BY i.game_id
+`
+
+// LibraryStats aggregates library totals in SQL. Playtime combines tracked play
+// sessions with the given instance custom metadata keys, which hold minutes played.
+func (db *DB) LibraryStats(playtimeKeys ...string) (models.LibraryStats, error) {
+	stats := models.LibraryStats{
+		InstancesBySource:   make(map[string]int),
+		InstancesByPlatform: make(map[string]int),
+		Genres:              make(map[string]int),
+	}
+
+	err := db.conn.QueryRow(`
+		SELECT
+			(SELECT COUNT(*) FROM games),
+			(SELECT COUNT(*) FROM game_instances),
+			(SELECT COALESCE(SUM(file_size), 0) FROM game_instances WHERE installed = 1)
+	`).Scan(&stats.Games, &stats.Instances, &stats.InstalledSizeBytes)
+	if err != nil {
+		return stats, fmt.Errorf("failed to count library: %w", err)
+	}
+
+	groups := []struct {
+		query string
+		dest  map[string]int
+	}{
+		{"SELECT source, COUNT(*) FROM game_instances GROUP BY source", stats.InstancesBySource},
+		{"SELECT platform, COUNT(*) FROM game_instances GROUP BY platform", stats.InstancesByPlatform},
+		{"SELECT genre, COUNT(DISTINCT game_id) FROM game_genres GROUP BY genre", stats.Genres},
+	}
+	for _, g := range groups {
+		if err := db.countGroups(g.query, g.dest); err != nil {
+			return stats, err
+		}
+	}
+
+	args := make([]any, len(playtimeKeys))
+	for i, key := range playtimeKeys {
+		args[i] = key
+	}
+	perGame := fmt.Sprintf(playtimeQuery, placeholders(len(playtimeKeys)))
+
+	if err := db.conn.QueryRow("SELECT COALESCE(SUM(minutes), 0) FROM ("+perGame+")", args...).Scan(&stats.PlaytimeMinutes); err != nil {
+		return stats, fmt.Errorf("failed to sum playtime: %w", err)
+	}
+
+	mostPlayed := &models.PlayedGame{}
+	err = db.conn.QueryRow(`
+		SELECT p.game_id, COALESCE(g.name, ''), p.minutes
+		FROM (`+perGame+`) p LEFT JOIN games g ON g.id = p.game_id
+		WHERE p.minutes > 0
+		ORDER BY p.minutes DESC LIMIT 1
+	`, args...).Scan(&mostPlayed.GameID, &mostPlayed.Name, &mostPlayed.PlaytimeMinutes)
+	if err == nil {
+		stats.MostPlayed = mostPlayed
+	} else if err != sql.ErrNoRows {
+		return stats, fmt.Errorf("failed to get most played game: %w", err)
+	}
+
+	return stats, nil
+}
+
+// countGroups scans rows of (key, count) into dest
+func (db *DB) countGroups(query string, dest map[string]int) error {
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return fmt.Errorf("failed to query library stats: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			return fmt.Errorf("failed to scan library stats: %w", err)
+		}
+		dest[key] = count
+	}
+	return rows.Err()
+}
+
+// placeholders returns n comma-separated query placeholders
+func placeholders(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return "?" + strings.Repeat(", ?", n-1)
+}
@@ -0,0 +1,75 @@
+package database
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestLibraryStats(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "games.db"))
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	games := []*models.Game{
+		{ID: "portal", Name: "Portal", Genres: []string{"Puzzle", "Shooter"}},
+		{ID: "zelda", Name: "Zelda", Genres: []string{"Adventure", "Puzzle"}},
+	}
+	for _, game := range games {
+		if err := db.CreateGame(game); err != nil {
+			t.Fatalf("CreateGame failed: %v", err)
+		}
+	}
+
+	instances := []models.GameInstance{
+		{ID: "steam_400", GameID: "portal", Source: "steam", Platform: "steam", FileSize: 100, Installed: true},
+		{ID: "emulated_1", GameID: "zelda", Source: "emulated", Platform: "nes", FileSize: 10, Installed: true},
+		{ID: "emulated_2", GameID: "zelda", Source: "emulated", Platform: "snes", FileSize: 1000},
+	}
+	for i := range instances {
+		if err := db.CreateInstance(&instances[i]); err != nil {
+			t.Fatalf("CreateInstance failed: %v", err)
+		}
+	}
+	if err := db.UpdateInstanceCustomMetadata("steam_400", instances[0].Version, map[string]any{"steam.playtime": "90"}); err != nil {
+		t.Fatalf("UpdateInstanceCustomMetadata failed: %v", err)
+	}
+	// Tracked sessions count unless the source already reports more
+	playtime := map[string]int64{"steam_400": 30 * 60, "emulated_1": 2 * 60 * 60}
+	for id, seconds := range playtime {
+		if err := db.AddPlaytime(id, seconds); err != nil {
+			t.Fatalf("AddPlaytime failed: %v", err)
+		}
+	}
+
+	stats, err := db.LibraryStats("steam.playtime")
+	if err != nil {
+		t.Fatalf("LibraryStats failed: %v", err)
+	}
+
+	if stats.Games != 2 || stats.Instances != 3 {
+		t.Errorf("expected 2 games and 3 instances, got %d and %d", stats.Games, stats.Instances)
+	}
+	if stats.InstalledSizeBytes != 110 {
+		t.Errorf("expected installed size 110, got %d", stats.InstalledSizeBytes)
+	}
+	if want := map[string]int{"steam": 1, "emulated": 2}; !reflect.DeepEqual(stats.InstancesBySource, want) {
+		t.Errorf("expected %v by source, got %v", want, stats.InstancesBySource)
+	}
+	if want := map[string]int{"steam": 1, "nes": 1, "snes": 1}; !reflect.DeepEqual(stats.InstancesByPlatform, want) {
+		t.Errorf("expected %v by platform, got %v", want, stats.InstancesByPlatform)
+	}
+	if want := map[string]int{"Puzzle": 2, "Shooter": 1, "Adventure": 1}; !reflect.DeepEqual(stats.Genres, want) {
+		t.Errorf("expected %v genres, got %v", want, stats.Genres)
+	}
+	if stats.PlaytimeMinutes != 210 {
+		t.Errorf("expected 210 minutes played, got %d", stats.PlaytimeMinutes)
+	}
+	if want := (&models.PlayedGame{GameID: "zelda", Name: "Zelda", PlaytimeMinutes: 120}); !reflect.DeepEqual(stats.MostPlayed, want) {
+		t.Errorf("expected most played %+v, got %+v", want, stats.MostPlayed)
+	}
+}
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// AddPlaySession records a finished play session. Times are stored in UTC and
+// the ID is set from the new row.
+func (db *DB) AddPlaySession(session *models.PlaySession) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
+	session.StartedAt = session.StartedAt.UTC()
+	session.EndedAt = session.EndedAt.UTC()
+	if session.DurationSeconds == 0 {
+		session.DurationSeconds = int64(session.EndedAt.Sub(session.StartedAt).Seconds())
+	}
+
+	query := `INSERT INTO play_sessions (instance_id, started_at, ended_at, duration_seconds) VALUES (?, ?, ?, ?)`
+	result, err := db.conn.Exec(query, session.InstanceID, session.StartedAt, session.EndedAt, session.DurationSeconds)
+	if err != nil {
+		return fmt.Errorf("failed to add play session: %w", err)
+	}
+	if session.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to get play session ID: %w", err)
+	}
+	return nil
+}
+
+// AddPlaytime adds seconds to an instance's total playtime. Playtime isn't
+// edited from a copy of the instance, so its version is left alone.
+func (db *DB) AddPlaytime(instanceID string, seconds int64) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
+	query := `UPDATE game_instances SET playtime_seconds = playtime_seconds + ? WHERE id = ?`
+	if _, err := db.conn.Exec(query, seconds, instanceID); err != nil {
+		return fmt.Errorf("failed to add playtime: %w", err)
+	}
+	return nil
+}
+
+// UpdateLastPlayed records when an instance was last played, stored in UTC
+func (db *DB) UpdateLastPlayed(instanceID string, t time.Time) error {
+	db.writeMu.Lock()
+	defer db.writeMu.Unlock()
+
+	query := `UPDATE game_instances SET last_played_at = ? WHERE id = ?`
+	if _, err := db.conn.Exec(query, t.UTC(), instanceID); err != nil {
+		return fmt.Errorf("failed to update last played: %w", err)
+	}
+	return nil
+}
+
+// GetPlaySessions returns an instance's play sessions, most recent first
+func (db *DB) GetPlaySessions(instanceID string) ([]models.PlaySession, error) {
+	query := `
+		SELECT id, instance_id, started_at, ended_at, duration_seconds
+		FROM play_sessions
+		WHERE instance_id = ?
+		ORDER BY started_at DESC, id DESC
+	`
+	rows, err := db.conn.Query(query, instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get play sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []models.PlaySession{}
+	for rows.Next() {
+		var session models.PlaySession
+		if err := rows.Scan(&session.ID, &session.InstanceID, &session.StartedAt, &session.EndedAt, &session.DurationSeconds); err != nil {
+			return nil, fmt.Errorf("failed to scan play session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate play sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// PlaytimeByDay returns the seconds played on each calendar day from since onwards,
+// keyed by "YYYY-MM-DD" in loc. Each session is split at loc's midnights, so a
+// session spanning midnight counts towards each day it covers and days stay
+// aligned across DST changes. Days without play are omitted.
+func (db *DB) PlaytimeByDay(since time.Time, loc *time.Location) (map[string]int, error) {
+	rows, err := db.conn.Query(`SELECT started_at, ended_at FROM play_sessions WHERE ended_at > ?`, since.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playtime by day: %w", err)
+	}
+	defer rows.Close()
+
+	byDay := make(map[string]int)
+	for rows.Next() {
+		var started, ended time.Time
+		if err := rows.Scan(&started, &ended); err != nil {
+			return nil, fmt.Errorf("failed to scan playtime by day: %w", err)
+		}
+		if started.Before(since) {
+			started = since
+		}
+		started, ended = started.In(loc), ended.In(loc)
+		for started.Before(ended) {
+			year, month, day := started.Date()
+			next := time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+			end := next
+			if ended.Before(next) {
+				end = ended
+			}
+			byDay[started.Format(time.DateOnly)] += int(end.Sub(started).Seconds())
+			started = next
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate playtime by day: %w", err)
+	}
+	return byDay, nil
+}
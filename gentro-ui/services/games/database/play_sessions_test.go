@@ -0,0 +1,103 @@
+package database
+
+import (
+	"maps"
+	"path/filepath"
+	"testing"
+	"time"
+	// Bundled so the DST test doesn't depend on the system's zone database
+	_ "time/tzdata"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestPlaytimeByDay(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "games.db"))
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.CreateGame(&models.Game{ID: "game1", Name: "Game"}); err != nil {
+		t.Fatalf("CreateGame failed: %v", err)
+	}
+	if err := db.CreateInstance(&models.GameInstance{ID: "inst1", GameID: "game1", Source: "emulated", Platform: "nes"}); err != nil {
+		t.Fatalf("CreateInstance failed: %v", err)
+	}
+
+	at := func(day, hour int) time.Time { return time.Date(2026, 3, day, hour, 0, 0, 0, time.UTC) }
+	sessions := []models.PlaySession{
+		// Before the range
+		{StartedAt: at(1, 10), EndedAt: at(1, 11)},
+		{StartedAt: at(2, 12), EndedAt: at(2, 13)},
+		// Spans midnight
+		{StartedAt: at(2, 22), EndedAt: at(3, 2)},
+	}
+	for _, session := range sessions {
+		session.InstanceID = "inst1"
+		if err := db.AddPlaySession(&session); err != nil {
+			t.Fatalf("AddPlaySession failed: %v", err)
+		}
+	}
+
+	byDay, err := db.PlaytimeByDay(at(2, 0), time.UTC)
+	if err != nil {
+		t.Fatalf("PlaytimeByDay failed: %v", err)
+	}
+	want := map[string]int{"2026-03-02": 3600 + 7200, "2026-03-03": 7200}
+	if !maps.Equal(byDay, want) {
+		t.Errorf("expected %v, got %v", want, byDay)
+	}
+
+	// Three hours ahead of UTC, the late session falls on the next day entirely
+	byDay, err = db.PlaytimeByDay(at(2, 0).Add(-3*time.Hour), time.FixedZone("UTC+3", 3*60*60))
+	if err != nil {
+		t.Fatalf("PlaytimeByDay failed: %v", err)
+	}
+	want = map[string]int{"2026-03-02": 3600, "2026-03-03": 4 * 3600}
+	if !maps.Equal(byDay, want) {
+		t.Errorf("expected %v with a +3h offset, got %v", want, byDay)
+	}
+}
+
+func TestPlaytimeByDay_DST(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "games.db"))
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.CreateGame(&models.Game{ID: "game1", Name: "Game"}); err != nil {
+		t.Fatalf("CreateGame failed: %v", err)
+	}
+	if err := db.CreateInstance(&models.GameInstance{ID: "inst1", GameID: "game1", Source: "emulated", Platform: "nes"}); err != nil {
+		t.Fatalf("CreateInstance failed: %v", err)
+	}
+
+	// New York moves its clocks forward on 2026-03-08, from UTC-5 to UTC-4
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+	at := func(day, hour int) time.Time { return time.Date(2026, 3, day, hour, 0, 0, 0, loc) }
+	sessions := []models.PlaySession{
+		// Late evenings either side of the change, each an hour before midnight
+		{StartedAt: at(6, 23), EndedAt: at(7, 0)},
+		{StartedAt: at(9, 23), EndedAt: at(10, 0)},
+	}
+	for _, session := range sessions {
+		session.InstanceID = "inst1"
+		if err := db.AddPlaySession(&session); err != nil {
+			t.Fatalf("AddPlaySession failed: %v", err)
+		}
+	}
+
+	byDay, err := db.PlaytimeByDay(at(1, 0), loc)
+	if err != nil {
+		t.Fatalf("PlaytimeByDay failed: %v", err)
+	}
+	want := map[string]int{"2026-03-06": 3600, "2026-03-09": 3600}
+	if !maps.Equal(byDay, want) {
+		t.Errorf("expected each session on its own local day, got %v", byDay)
+	}
+}
@@ -0,0 +1,188 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// rebuild moves a database that failed to migrate aside and creates a fresh one.
+// It's a variable so tests can make rebuilding fail.
+var rebuild = rebuildDatabase
+
+// environmentErrors are SQLite messages for failures caused by the file or system
+// rather than the schema, e.g. another process holding the lock or a full disk
+var environmentErrors = []string{
+	"database is locked",
+	"database table is locked",
+	"readonly database",
+	"disk I/O error",
+	"database or disk is full",
+	"access permission denied",
+	"unable to open database file",
+	"out of memory",
+}
+
+// isEnvironmentError reports whether a migration failed for reasons rebuilding
+// wouldn't fix, where rebuilding would discard a healthy database
+func isEnvironmentError(err error) bool {
+	msg := err.Error()
+	for _, envErr := range environmentErrors {
+		if strings.Contains(msg, envErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// recoverFromMigration handles a failed migration. Schema failures are recovered
+// by backing up the file and rebuilding; if that isn't possible, or the failure
+// wasn't the schema's fault, the original file is opened read-only.
+func recoverFromMigration(dbPath string, migrateErr error) (*DB, error) {
+	var backupPath string
+	var db *DB
+	err := errors.New("not attempted")
+	if !isEnvironmentError(migrateErr) {
+		backupPath, db, err = rebuild(dbPath)
+	}
+	if err == nil {
+		db.status.Recovered = true
+		db.status.BackupPath = backupPath
+		db.status.Error = migrateErr.Error()
+		return db, nil
+	}
+
+	// Leave whatever file is in place untouched and serve what can be read
+	db, openErr := open(dbPath, true)
+	if openErr != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w (rebuild %v)", migrateErr, err)
+	}
+	db.status.Degraded = true
+	db.status.BackupPath = backupPath
+	db.status.Error = fmt.Sprintf("%v; rebuild %v", migrateErr, err)
+	return db, nil
+}
+
+// rebuildDatabase moves the database aside and migrates a new, empty database
+// in its place. Library data is re-imported from sources on the next refresh.
+func rebuildDatabase(dbPath string) (string, *DB, error) {
+	backupPath, err := moveAside(dbPath, "broken")
+	if err != nil {
+		return backupPath, nil, err
+	}
+
+	db, err := openMigrated(dbPath)
+	return backupPath, db, err
+}
+
+// moveAside renames the database and its WAL files to a timestamped name
+func moveAside(dbPath, label string) (string, error) {
+	movedPath := fmt.Sprintf("%s.%s-%s", dbPath, label, time.Now().Format("20060102-150405"))
+	if err := os.Rename(dbPath, movedPath); err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to back up database: %w", err)
+	}
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Rename(dbPath+suffix, movedPath+suffix); err != nil && !os.IsNotExist(err) {
+			return movedPath, fmt.Errorf("failed to back up database %s file: %w", suffix, err)
+		}
+	}
+	return movedPath, nil
+}
+
+// openMigrated opens and migrates a database
+func openMigrated(dbPath string) (*DB, error) {
+	db, err := open(dbPath, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.migrate(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate rebuilt database: %w", err)
+	}
+	return db, nil
+}
+
+// recoverFromCorruption replaces a database that failed its integrity check with
+// the automatic backup if a recent, healthy one exists, or rebuilds it otherwise
+func recoverFromCorruption(dbPath, problem string) (*DB, error) {
+	backup := backupPath(dbPath)
+	if info, err := os.Stat(backup); err == nil && time.Since(info.ModTime()) < maxRestoreAge && fileIsHealthy(backup) {
+		db, err := restoreBackup(dbPath, backup)
+		if err == nil {
+			db.status.Integrity = models.IntegrityCorrupt
+			db.status.RestoredFrom = backup
+			db.status.Error = problem
+			return db, nil
+		}
+		problem = fmt.Sprintf("%s; restoring backup failed: %v", problem, err)
+	}
+
+	movedPath, db, err := rebuild(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("database is corrupt (%s) and could not be rebuilt: %w", problem, err)
+	}
+	db.status.Integrity = models.IntegrityCorrupt
+	db.status.Recovered = true
+	db.status.BackupPath = movedPath
+	db.status.Error = problem
+	return db, nil
+}
+
+// restoreBackup moves the corrupt database aside and copies the backup in its place
+func restoreBackup(dbPath, backup string) (*DB, error) {
+	movedPath, err := moveAside(dbPath, "corrupt")
+	if err != nil {
+		return nil, err
+	}
+	if err := copyFile(backup, dbPath); err != nil {
+		return nil, fmt.Errorf("failed to copy backup: %w", err)
+	}
+
+	db, err := openMigrated(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	db.status.BackupPath = movedPath
+	return db, nil
+}
+
+// fileIsHealthy reports whether a database file passes its integrity check
+func fileIsHealthy(path string) bool {
+	db, err := open(path, true)
+	if err != nil {
+		return false
+	}
+	defer db.Close()
+
+	result, err := db.integrityCheck()
+	return err == nil && result == "ok"
+}
+
+// copyFile copies src to dst, replacing dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// Status reports whether the database was rebuilt or opened read-only at startup
+func (db *DB) Status() models.DatabaseStatus {
+	return db.status
+}
@@ -0,0 +1,121 @@
+package games
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+var (
+	// duplicateTagRegex matches region, revision and dump tags like "(USA)" or "[!]"
+	duplicateTagRegex = regexp.MustCompile(`\([^)]*\)|\[[^\]]*\]`)
+	// duplicatePunctRegex matches everything that isn't a letter, digit or space
+	duplicatePunctRegex = regexp.MustCompile(`[^\p{L}\p{N} ]+`)
+)
+
+// normalizeDuplicateName reduces a game name to a key that ignores tags, case and punctuation
+func normalizeDuplicateName(name string) string {
+	name = duplicateTagRegex.ReplaceAllString(name, " ")
+	name = strings.ReplaceAll(name, "_", " ")
+	name = duplicatePunctRegex.ReplaceAllString(strings.ToLower(name), " ")
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// FindDuplicates groups instances that share a normalized name and platform, and
+// instances with identical file hashes that weren't already grouped by name.
+// Only groups with more than one instance are returned.
+func (s *GamesService) FindDuplicates() ([]models.DuplicateGroup, error) {
+	instances, err := s.db.GetInstances(models.GameFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instances: %w", err)
+	}
+
+	games := make(map[string]*models.Game)
+	byName := make(map[string][]models.DuplicateEntry)
+	byHash := make(map[string][]models.DuplicateEntry)
+	nameKeys := make(map[string]string)
+	platforms := make(map[string]string)
+
+	for _, instance := range instances {
+		game, ok := games[instance.GameID]
+		if !ok {
+			game, err = s.db.GetGame(instance.GameID)
+			if err != nil {
+				s.logger.Warn("failed to load game", "gameID", instance.GameID, "error", err)
+			}
+			games[instance.GameID] = game
+		}
+
+		name := s.getDisplayName(instance)
+		if game != nil && game.Name != "" {
+			name = game.Name
+		}
+
+		entry := models.DuplicateEntry{
+			InstanceID: instance.ID,
+			GameID:     instance.GameID,
+			Source:     instance.Source,
+			Name:       name,
+			Path:       instance.Path,
+			Filename:   instance.Filename,
+			FileSize:   instance.FileSize,
+			FileHash:   instance.FileHash,
+		}
+
+		if normalized := normalizeDuplicateName(name); normalized != "" {
+			key := instance.Platform + "\x00" + normalized
+			byName[key] = append(byName[key], entry)
+			nameKeys[instance.ID] = key
+			platforms[key] = instance.Platform
+		}
+		if instance.FileHash != "" {
+			byHash[instance.FileHash] = append(byHash[instance.FileHash], entry)
+		}
+	}
+
+	var groups []models.DuplicateGroup
+	for _, key := range slices.Sorted(maps.Keys(byName)) {
+		entries := byName[key]
+		if len(entries) < 2 {
+			continue
+		}
+		groups = append(groups, models.DuplicateGroup{
+			Reason:    models.DuplicateByName,
+			Name:      entries[0].Name,
+			Platform:  platforms[key],
+			Instances: entries,
+		})
+	}
+
+	for _, hash := range slices.Sorted(maps.Keys(byHash)) {
+		entries := byHash[hash]
+		if len(entries) < 2 || sameNameGroup(entries, nameKeys, byName) {
+			continue
+		}
+		groups = append(groups, models.DuplicateGroup{
+			Reason:    models.DuplicateByHash,
+			Name:      entries[0].Name,
+			Instances: entries,
+		})
+	}
+
+	return groups, nil
+}
+
+// sameNameGroup reports whether all entries already appear together in one name group
+func sameNameGroup(entries []models.DuplicateEntry, nameKeys map[string]string, byName map[string][]models.DuplicateEntry) bool {
+	key, ok := nameKeys[entries[0].InstanceID]
+	if !ok || len(byName[key]) < 2 {
+		return false
+	}
+	for _, entry := range entries[1:] {
+		if nameKeys[entry.InstanceID] != key {
+			return false
+		}
+	}
+	return true
+}
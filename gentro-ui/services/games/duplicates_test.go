@@ -0,0 +1,59 @@
+package games
+
+import (
+	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	service := newTestService(t)
+
+	instances := []models.GameInstance{
+		{ID: "a", GameID: "game_a", Source: "emulated", Platform: "snes", Filename: "Chrono Trigger (USA).sfc", FileSize: 100, FileHash: "h1"},
+		{ID: "b", GameID: "game_b", Source: "emulated", Platform: "snes", Filename: "Chrono_Trigger (Japan) [!].sfc", FileSize: 120, FileHash: "h2"},
+		// Same name on another platform isn't a duplicate
+		{ID: "c", GameID: "game_c", Source: "emulated", Platform: "nes", Filename: "Chrono Trigger.nes", FileHash: "h3"},
+		// Copies of one file under different names, which the emulated source keeps as separate instances
+		{ID: "d", GameID: "game_d", Source: "emulated", Platform: "n64", Path: "/roms/n64/Zelda.z64", Filename: "Zelda.z64", FileHash: "h4"},
+		{ID: "e", GameID: "game_e", Source: "emulated", Platform: "n64", Path: "/roms/n64/Ocarina of Time.z64", Filename: "Ocarina of Time.z64", FileHash: "h4"},
+	}
+	if _, err := service.syncSourceInstances("emulated", instances); err != nil {
+		t.Fatalf("failed to seed instances: %v", err)
+	}
+
+	groups, err := service.FindDuplicates()
+	if err != nil {
+		t.Fatalf("FindDuplicates failed: %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %+v", groups)
+	}
+
+	byName := groups[0]
+	if byName.Reason != models.DuplicateByName || byName.Platform != "snes" || len(byName.Instances) != 2 {
+		t.Errorf("unexpected name group %+v", byName)
+	}
+	if byName.Instances[0].FileSize == 0 || byName.Instances[0].Filename == "" {
+		t.Errorf("expected file details in entries, got %+v", byName.Instances[0])
+	}
+
+	byHash := groups[1]
+	if byHash.Reason != models.DuplicateByHash || len(byHash.Instances) != 2 {
+		t.Errorf("unexpected hash group %+v", byHash)
+	}
+}
+
+func TestNormalizeDuplicateName(t *testing.T) {
+	tests := map[string]string{
+		"Chrono Trigger (USA)":               "chrono trigger",
+		"Chrono_Trigger (Japan) [!]":         "chrono trigger",
+		"Sonic the Hedgehog 2 (Rev 1)":       "sonic the hedgehog 2",
+		"Pokémon: Red Version [T+Eng] (SGB)": "pokémon red version",
+	}
+	for input, want := range tests {
+		if got := normalizeDuplicateName(input); got != want {
+			t.Errorf("normalizeDuplicateName(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -10,9 +10,10 @@ type DefaultEmulatorConfig struct {
 
 // DefaultEmulatorsByPlatform maps platforms to their default emulator configuration
 var DefaultEmulatorsByPlatform = map[string]DefaultEmulatorConfig{
-	"nes":  {EmulatorID: "retroarch", CoreID: "mesen_libretro"},
-	"snes": {EmulatorID: "retroarch", CoreID: "snes9x_libretro"},
-	"wii":  {EmulatorID: "dolphin"},
+	"nes":    {EmulatorID: "retroarch", CoreID: "mesen_libretro"},
+	"snes":   {EmulatorID: "retroarch", CoreID: "snes9x_libretro"},
+	"wii":    {EmulatorID: "dolphin"},
+	"arcade": {EmulatorID: "mame"},
 }
 
 // DefaultEmulators returns pre-configured emulator definitions
@@ -48,6 +49,15 @@ func DefaultEmulators() []models.Emulator {
 			DefaultArgs:        "-b -e",
 			SupportedPlatforms: []string{"wii", "gamecube"},
 		},
+		{
+			ID:                 "mame",
+			Name:               "mame",
+			DisplayName:        "MAME",
+			Type:               models.EmulatorTypeFlatpak,
+			FlatpakID:          "org.mamedev.MAME",
+			CommandTemplate:    "flatpak run {flatpak_id} {args} -rompath {rom_dir} {romset}",
+			SupportedPlatforms: []string{"arcade"},
+		},
 	}
 }
 
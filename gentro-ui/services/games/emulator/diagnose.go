@@ -0,0 +1,95 @@
+package emulator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+const (
+	// testLaunchTimeout bounds a test launch. Emulators that ignore --version and
+	// open a window are stopped after this long and count as launched.
+	testLaunchTimeout = 10 * time.Second
+	// testOutputLimit caps the stdout and stderr returned from a test launch
+	testOutputLimit = 2048
+)
+
+// TestEmulator checks an emulator is installed and runs it with --version,
+// without a ROM, to show whether it starts at all
+func (s *Service) TestEmulator(ctx context.Context, emulatorID string) (models.EmulatorTestResult, error) {
+	emu, err := s.db.GetEmulator(emulatorID)
+	if err != nil {
+		return models.EmulatorTestResult{}, fmt.Errorf("emulator not found: %s", emulatorID)
+	}
+
+	result := models.EmulatorTestResult{EmulatorID: emu.ID}
+
+	switch emu.Type {
+	case models.EmulatorTypeFlatpak:
+		result.Available = s.checkFlatpakInstalled(emu.FlatpakID)
+		result.Command = []string{"flatpak", "run", emu.FlatpakID, "--version"}
+	default:
+		result.Available = s.checkNativeInstalled(emu.ExecutablePath)
+		result.Command = []string{emu.ExecutablePath, "--version"}
+	}
+
+	if result.Available != emu.IsAvailable {
+		if err := s.db.UpdateEmulatorAvailability(emu.ID, result.Available); err != nil {
+			s.logger.Warn("failed to update emulator availability", "id", emu.ID, "error", err)
+		}
+	}
+	if !result.Available {
+		result.Error = fmt.Sprintf("%s is not installed", emu.DisplayName)
+		return result, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, testLaunchTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, result.Command[0], result.Command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err = cmd.Run()
+	result.DurationMs = time.Since(start).Milliseconds()
+	result.Stdout = truncateOutput(stdout.String())
+	result.Stderr = truncateOutput(stderr.String())
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		// Still running when stopped, so it started
+		result.TimedOut = true
+		result.Launched = true
+	case err == nil:
+		result.Launched = true
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+		result.Error = fmt.Sprintf("%s exited with code %d", emu.DisplayName, result.ExitCode)
+	default:
+		result.Error = fmt.Sprintf("failed to start %s: %v", emu.DisplayName, err)
+	}
+
+	s.logger.Info("tested emulator",
+		"emulator", emu.ID,
+		"launched", result.Launched,
+		"exitCode", result.ExitCode,
+		"timedOut", result.TimedOut,
+	)
+	return result, nil
+}
+
+// truncateOutput keeps the start of command output, which usually holds the version or error
+func truncateOutput(output string) string {
+	if len(output) <= testOutputLimit {
+		return output
+	}
+	return output[:testOutputLimit] + "\n…"
+}
@@ -0,0 +1,158 @@
+package emulator
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// emulatorDirs are the base directories an emulator keeps its data under. A
+// flatpak's are inside its sandbox under ~/.var/app.
+type emulatorDirs struct {
+	config string
+	data   string
+	home   string
+}
+
+// saveLayout returns where an emulator keeps an instance's saves. stem is the
+// ROM's filename without its extension, which most emulators name saves after.
+type saveLayout func(dirs emulatorDirs, instance models.GameInstance, stem string) []models.SaveLocation
+
+// saveLayouts maps emulator IDs to where they keep save data
+var saveLayouts = map[string]saveLayout{
+	"retroarch": func(dirs emulatorDirs, instance models.GameInstance, stem string) []models.SaveLocation {
+		root := filepath.Join(dirs.config, "retroarch")
+		return []models.SaveLocation{
+			{Dir: filepath.Join(root, "saves"), Prefix: stem + "."},
+			{Dir: filepath.Join(root, "states"), Prefix: stem + "."},
+		}
+	},
+	"nestopia": func(dirs emulatorDirs, instance models.GameInstance, stem string) []models.SaveLocation {
+		root := filepath.Join(dirs.data, "nestopia")
+		return []models.SaveLocation{
+			{Dir: filepath.Join(root, "save"), Prefix: stem + "."},
+			{Dir: filepath.Join(root, "state"), Prefix: stem + "."},
+		}
+	},
+	"dolphin": func(dirs emulatorDirs, instance models.GameInstance, stem string) []models.SaveLocation {
+		root := filepath.Join(dirs.data, "dolphin-emu")
+		if instance.Platform != "wii" {
+			// GameCube memory cards are shared by every game in a region
+			return []models.SaveLocation{{Dir: filepath.Join(root, "GC")}}
+		}
+		titles := filepath.Join(root, "Wii", "title", "00010000")
+		if code, ok := discGameCode(instance.Path); ok {
+			return []models.SaveLocation{{Dir: filepath.Join(titles, hex.EncodeToString([]byte(code)))}}
+		}
+		return []models.SaveLocation{{Dir: titles}}
+	},
+	"mame": func(dirs emulatorDirs, instance models.GameInstance, stem string) []models.SaveLocation {
+		root := filepath.Join(dirs.home, ".mame")
+		return []models.SaveLocation{
+			{Dir: filepath.Join(root, "nvram", stem)},
+			{Dir: filepath.Join(root, "sta", stem)},
+		}
+	},
+}
+
+// GetSaveLocations returns where the emulator resolved for an instance keeps
+// its save data. Locations are returned whether or not anything was saved yet.
+func (s *Service) GetSaveLocations(instance models.GameInstance) ([]models.SaveLocation, error) {
+	emu, _, err := s.ResolveEmulator(instance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve emulator: %w", err)
+	}
+	if emu == nil {
+		return nil, fmt.Errorf("%w for platform %s", models.ErrEmulatorNotConfigured, instance.Platform)
+	}
+
+	layout, ok := saveLayouts[emu.ID]
+	if !ok {
+		return nil, fmt.Errorf("save data location unknown for %s", emu.DisplayName)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find home directory: %w", err)
+	}
+
+	stem := strings.TrimSuffix(filepath.Base(instance.Path), filepath.Ext(instance.Path))
+	locations := layout(dirsFor(emu, home), instance, stem)
+	for i := range locations {
+		_, err := os.Stat(locations[i].Dir)
+		locations[i].Exists = err == nil
+	}
+	return locations, nil
+}
+
+// GetSaveDataPaths returns the directories where the emulator resolved for an
+// instance keeps its saves and memory cards
+func (s *Service) GetSaveDataPaths(instance models.GameInstance) ([]string, error) {
+	locations, err := s.GetSaveLocations(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(locations))
+	for _, location := range locations {
+		paths = append(paths, location.Dir)
+	}
+	return paths, nil
+}
+
+// dirsFor returns the base directories of an emulator install
+func dirsFor(emu *models.Emulator, home string) emulatorDirs {
+	if emu.Type == models.EmulatorTypeFlatpak {
+		root := filepath.Join(home, ".var", "app", emu.FlatpakID)
+		return emulatorDirs{config: filepath.Join(root, "config"), data: filepath.Join(root, "data"), home: root}
+	}
+
+	return emulatorDirs{
+		config: xdgDir("XDG_CONFIG_HOME", filepath.Join(home, ".config")),
+		data:   xdgDir("XDG_DATA_HOME", filepath.Join(home, ".local", "share")),
+		home:   home,
+	}
+}
+
+// xdgDir returns an XDG base directory, or fallback when it isn't set
+func xdgDir(env, fallback string) string {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir
+	}
+	return fallback
+}
+
+// discGameCode reads the 4-character game code from a Wii disc image's header.
+// Compressed formats other than WBFS aren't readable and report false.
+func discGameCode(path string) (string, bool) {
+	var offset int64
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".iso":
+	case ".wbfs":
+		// WBFS files keep a copy of the disc header after their own
+		offset = 0x200
+	default:
+		return "", false
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer file.Close()
+
+	code := make([]byte, 4)
+	if _, err := file.ReadAt(code, offset); err != nil {
+		return "", false
+	}
+	for _, c := range code {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return "", false
+		}
+	}
+	return string(code), true
+}
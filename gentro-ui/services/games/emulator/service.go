@@ -11,10 +11,19 @@ import (
 	"github.com/rhythmerc/gentro-ui/services/games/models"
 )
 
+// OnUnavailableCallback is called with instance overrides whose emulator is no longer available
+type OnUnavailableCallback func(unavailable []models.UnavailableInstanceEmulator)
+
+// OnDiscoveredCallback is called after each discovery pass, once emulator and core
+// availability is up to date
+type OnDiscoveredCallback func()
+
 // Service manages emulator discovery and configuration
 type Service struct {
-	db     *database.DB
-	logger Logger
+	db            *database.DB
+	logger        Logger
+	onUnavailable OnUnavailableCallback
+	onDiscovered  OnDiscoveredCallback
 }
 
 // Logger interface for logging
@@ -33,6 +42,18 @@ func NewService(db *database.DB, logger Logger) *Service {
 	}
 }
 
+// SetOnUnavailableCallback sets the callback for instance overrides found pointing at
+// an unavailable emulator during discovery
+func (s *Service) SetOnUnavailableCallback(callback OnUnavailableCallback) {
+	s.onUnavailable = callback
+}
+
+// SetOnDiscoveredCallback sets the callback run after each discovery pass, so
+// caches of emulator availability can be refreshed
+func (s *Service) SetOnDiscoveredCallback(callback OnDiscoveredCallback) {
+	s.onDiscovered = callback
+}
+
 // Initialize seeds default emulators and mappings
 func (s *Service) Initialize() error {
 	s.logger.Info("Initializing emulator service")
@@ -61,12 +82,19 @@ func (s *Service) Initialize() error {
 	return nil
 }
 
-// regeneratePlatformMappings clears and rebuilds platform_emulators from SupportedPlatforms
+// regeneratePlatformMappings clears and rebuilds platform_emulators from SupportedPlatforms.
+// Mappings added with AssignEmulatorToPlatform are kept.
 func (s *Service) regeneratePlatformMappings() error {
 	s.logger.Info("Regenerating platform mappings from SupportedPlatforms")
 
-	// Clear existing mappings
-	if err := s.db.ClearPlatformEmulators(); err != nil {
+	// Remember defaults the user picked so regeneration doesn't reset them
+	previous, err := s.db.GetPlatformEmulators()
+	if err != nil {
+		return fmt.Errorf("failed to get platform mappings: %w", err)
+	}
+
+	// Clear existing generated mappings
+	if err := s.db.ClearGeneratedPlatformEmulators(); err != nil {
 		return fmt.Errorf("failed to clear platform mappings: %w", err)
 	}
 
@@ -81,7 +109,7 @@ func (s *Service) regeneratePlatformMappings() error {
 		for _, platform := range emu.SupportedPlatforms {
 			isDefault := s.isDefaultConfig(platform, emu.ID, "")
 			mapping := models.PlatformEmulator{
-				ID:         fmt.Sprintf("%s_%s", platform, emu.ID),
+				ID:         platformMappingID(platform, emu.ID, ""),
 				Platform:   platform,
 				EmulatorID: emu.ID,
 				IsDefault:  isDefault,
@@ -108,7 +136,7 @@ func (s *Service) regeneratePlatformMappings() error {
 		for _, platform := range core.SupportedPlatforms {
 			isDefault := s.isDefaultConfig(platform, core.EmulatorID, core.CoreID)
 			mapping := models.PlatformEmulator{
-				ID:         fmt.Sprintf("%s_%s_%s", platform, core.EmulatorID, core.CoreID),
+				ID:         platformMappingID(platform, core.EmulatorID, core.CoreID),
 				Platform:   platform,
 				EmulatorID: core.EmulatorID,
 				CoreID:     core.CoreID,
@@ -126,10 +154,73 @@ func (s *Service) regeneratePlatformMappings() error {
 		}
 	}
 
+	// Restore user-chosen defaults whose emulator or core still exists
+	for _, pe := range previous {
+		if !pe.IsDefault || s.isDefaultConfig(pe.Platform, pe.EmulatorID, pe.CoreID) {
+			continue
+		}
+		if err := s.db.SetPlatformEmulatorDefault(pe.ID); err != nil {
+			return fmt.Errorf("failed to restore default for %s: %w", pe.Platform, err)
+		}
+	}
+
+	if err := s.resolveDefaultConflicts(); err != nil {
+		return fmt.Errorf("failed to validate platform defaults: %w", err)
+	}
+
 	s.logger.Info("Platform mappings regenerated successfully")
 	return nil
 }
 
+// platformMappingID builds the platform_emulators ID for an emulator, or one of its cores
+func platformMappingID(platform, emulatorID, coreID string) string {
+	if coreID == "" {
+		return fmt.Sprintf("%s_%s", platform, emulatorID)
+	}
+	return fmt.Sprintf("%s_%s_%s", platform, emulatorID, coreID)
+}
+
+// resolveDefaultConflicts ensures each platform has at most one default emulator.
+// A user-chosen default wins over the built-in one; otherwise the first by ID is kept.
+func (s *Service) resolveDefaultConflicts() error {
+	mappings, err := s.db.GetPlatformEmulators()
+	if err != nil {
+		return err
+	}
+
+	defaults := make(map[string][]models.PlatformEmulator)
+	for _, pe := range mappings {
+		if pe.IsDefault {
+			defaults[pe.Platform] = append(defaults[pe.Platform], pe)
+		}
+	}
+
+	for platform, candidates := range defaults {
+		if len(candidates) < 2 {
+			continue
+		}
+
+		keep := candidates[0]
+		for _, pe := range candidates {
+			if !s.isDefaultConfig(platform, pe.EmulatorID, pe.CoreID) {
+				keep = pe
+				break
+			}
+		}
+
+		s.logger.Warn("multiple default emulators for platform, keeping one",
+			"platform", platform,
+			"count", len(candidates),
+			"kept", keep.ID,
+		)
+		if err := s.db.SetPlatformDefaultEmulator(platform, keep.EmulatorID, keep.CoreID); err != nil {
+			return fmt.Errorf("failed to resolve default for %s: %w", platform, err)
+		}
+	}
+
+	return nil
+}
+
 // isDefaultConfig checks if this emulator/core combo is the default for the platform
 func (s *Service) isDefaultConfig(platform, emulatorID, coreID string) bool {
 	defaultConfig, exists := DefaultEmulatorsByPlatform[platform]
@@ -182,9 +273,42 @@ func (s *Service) DiscoverAvailable() error {
 		s.logger.Info("RetroArch not available, skipping core discovery", "isAvailable", retroarch.IsAvailable)
 	}
 
+	if _, err := s.ValidateInstanceEmulators(); err != nil {
+		s.logger.Error("failed to validate instance emulators", "error", err)
+	}
+
+	if s.onDiscovered != nil {
+		s.onDiscovered()
+	}
+
 	return nil
 }
 
+// ValidateInstanceEmulators finds instance overrides whose emulator or core is no
+// longer available and reports them through the unavailable callback. Overrides are
+// flagged rather than cleared, so reinstalling the emulator restores them.
+func (s *Service) ValidateInstanceEmulators() ([]models.UnavailableInstanceEmulator, error) {
+	unavailable, err := s.db.GetUnavailableInstanceEmulators()
+	if err != nil {
+		return nil, err
+	}
+	if len(unavailable) == 0 {
+		return nil, nil
+	}
+
+	for _, u := range unavailable {
+		s.logger.Warn("instance emulator is unavailable, falling back",
+			"instanceId", u.InstanceID,
+			"emulatorId", u.EmulatorID,
+			"coreId", u.CoreID,
+		)
+	}
+	if s.onUnavailable != nil {
+		s.onUnavailable(unavailable)
+	}
+	return unavailable, nil
+}
+
 func (s *Service) checkFlatpakInstalled(flatpakID string) bool {
 	if flatpakID == "" {
 		return false
@@ -255,6 +379,36 @@ func (s *Service) discoverRetroArchCores() {
 // ResolveEmulator finds the appropriate emulator for a game instance
 // Priority: 1. Instance override (if available), 2. Default platform emulator, 3. Any available emulator
 func (s *Service) ResolveEmulator(instance models.GameInstance) (*models.Emulator, *models.EmulatorCore, error) {
+	emu, core, _, err := s.resolveEmulator(instance)
+	return emu, core, err
+}
+
+// ExplainResolution reports which emulator ResolveEmulator would pick for an instance and why.
+// When none is available the platform's configured default, if any, is reported as unavailable.
+func (s *Service) ExplainResolution(instance models.GameInstance) models.ResolvedEmulatorInfo {
+	emu, core, reason, err := s.resolveEmulator(instance)
+	if err != nil {
+		info := models.ResolvedEmulatorInfo{
+			Reason:  models.ResolutionNone,
+			Message: err.Error(),
+		}
+		if emu, core, defaultErr := s.db.GetDefaultEmulatorForPlatform(instance.Platform, false); defaultErr == nil {
+			info.Emulator = emu
+			info.Core = core
+		}
+		return info
+	}
+
+	return models.ResolvedEmulatorInfo{
+		Emulator:  emu,
+		Core:      core,
+		Reason:    reason,
+		Available: emu.IsAvailable && (core == nil || core.IsAvailable),
+	}
+}
+
+// resolveEmulator implements ResolveEmulator and reports which rule chose the emulator
+func (s *Service) resolveEmulator(instance models.GameInstance) (*models.Emulator, *models.EmulatorCore, models.ResolutionReason, error) {
 	s.logger.Info("resolving emulator",
 		"instanceId", instance.ID,
 		"platform", instance.Platform,
@@ -279,7 +433,7 @@ func (s *Service) ResolveEmulator(instance models.GameInstance) (*models.Emulato
 					"emulator", emu.DisplayName,
 					"core", coreNameOrEmpty(core),
 				)
-				return emu, core, nil
+				return emu, core, models.ResolutionInstanceOverride, nil
 			}
 		}
 
@@ -300,7 +454,7 @@ func (s *Service) ResolveEmulator(instance models.GameInstance) (*models.Emulato
 			"emulator", emu.DisplayName,
 			"core", coreNameOrEmpty(core),
 		)
-		return emu, core, nil
+		return emu, core, models.ResolutionPlatformDefault, nil
 	}
 
 	if err != nil {
@@ -319,7 +473,7 @@ func (s *Service) ResolveEmulator(instance models.GameInstance) (*models.Emulato
 			"platform", instance.Platform,
 			"error", err,
 		)
-		return nil, nil, fmt.Errorf("no emulator available for platform %s: %w", instance.Platform, err)
+		return nil, nil, models.ResolutionNone, fmt.Errorf("no emulator available for platform %s: %w", instance.Platform, err)
 	}
 
 	if len(availablePairs) > 0 {
@@ -330,10 +484,10 @@ func (s *Service) ResolveEmulator(instance models.GameInstance) (*models.Emulato
 			"emulator", pair.Emulator.DisplayName,
 			"core", coreNameOrEmpty(pair.Core),
 		)
-		return &pair.Emulator, pair.Core, nil
+		return &pair.Emulator, pair.Core, models.ResolutionFallback, nil
 	}
 
-	return nil, nil, fmt.Errorf("no available emulator for platform %s", instance.Platform)
+	return nil, nil, models.ResolutionNone, fmt.Errorf("no available emulator for platform %s", instance.Platform)
 }
 
 // coreNameOrEmpty returns the core display name or empty string if nil
@@ -361,8 +515,10 @@ func (s *Service) getEmulatorAndCore(emulatorID, coreID string) (*models.Emulato
 	return emulator, core, nil
 }
 
-// BuildCommand constructs the launch command for an emulator
-func (s *Service) BuildCommand(emulator *models.Emulator, core *models.EmulatorCore, romPath string, customArgs string) ([]string, error) {
+// BuildCommand constructs the launch command for an emulator. Args are the emulator's
+// defaults, then the instance's args profile, then its custom args. Files fill a
+// {files} argument in the template and are otherwise not passed.
+func (s *Service) BuildCommand(emulator *models.Emulator, core *models.EmulatorCore, romPath string, files []string, settings *models.InstanceEmulatorSettings) ([]string, error) {
 	if emulator == nil {
 		return nil, fmt.Errorf("emulator is nil")
 	}
@@ -386,15 +542,12 @@ func (s *Service) BuildCommand(emulator *models.Emulator, core *models.EmulatorC
 		)
 	}
 
-	// Combine default args with custom args
-	args := emulator.DefaultArgs
-	if customArgs != "" {
-		if args != "" {
-			args = args + " " + customArgs
-		} else {
-			args = customArgs
-		}
+	// Combine default args with profile and custom args
+	var customArgs string
+	if settings != nil {
+		customArgs = settings.CustomArgs
 	}
+	args := joinArgs(emulator.DefaultArgs, s.profileArgs(emulator, settings), customArgs)
 
 	s.logger.Info("building command",
 		"emulator", emulator.ID,
@@ -402,20 +555,74 @@ func (s *Service) BuildCommand(emulator *models.Emulator, core *models.EmulatorC
 		"template", emulator.CommandTemplate,
 		"coreLibPath", coreLibPath,
 		"romPath", romPath,
+		"files", files,
 		"args", args,
 	)
 
 	// Build command based on emulator type
+	var cmd []string
 	if emulator.Type == models.EmulatorTypeFlatpak {
-		return s.buildFlatpakCommand(emulator, coreLibPath, romPath, args), nil
+		cmd = s.buildFlatpakCommand(emulator, coreLibPath, romPath, args)
+	} else {
+		cmd = s.buildNativeCommand(emulator, romPath, args)
+	}
+	return expandFiles(cmd, files), nil
+}
+
+// filesPlaceholder in a command template is replaced by the instance's
+// additional files, such as further disks, each as its own argument
+const filesPlaceholder = "{files}"
+
+// expandFiles replaces the {files} argument with one argument per file. Files
+// are substituted after parsing so paths with spaces never need quoting.
+func expandFiles(cmd []string, files []string) []string {
+	expanded := make([]string, 0, len(cmd)+len(files))
+	for _, arg := range cmd {
+		if arg == filesPlaceholder {
+			expanded = append(expanded, files...)
+			continue
+		}
+		expanded = append(expanded, arg)
+	}
+	return expanded
+}
+
+// profileArgs returns the args of the instance's profile if it belongs to the emulator
+// being launched. A profile for another emulator is skipped when resolution fell back.
+func (s *Service) profileArgs(emulator *models.Emulator, settings *models.InstanceEmulatorSettings) string {
+	if settings == nil || settings.ProfileID == "" {
+		return ""
+	}
+
+	profile, err := s.db.GetArgProfile(settings.ProfileID)
+	if err != nil || profile == nil {
+		s.logger.Warn("args profile not found", "profileId", settings.ProfileID, "error", err)
+		return ""
+	}
+	if profile.EmulatorID != emulator.ID {
+		s.logger.Info("skipping args profile for another emulator",
+			"profile", profile.Name,
+			"profileEmulator", profile.EmulatorID,
+			"emulator", emulator.ID,
+		)
+		return ""
 	}
+	return profile.Args
+}
 
-	return s.buildNativeCommand(emulator, romPath, args), nil
+// joinArgs joins non-empty arg strings with spaces
+func joinArgs(parts ...string) string {
+	var nonEmpty []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	return strings.Join(nonEmpty, " ")
 }
 
 func (s *Service) buildFlatpakCommand(emulator *models.Emulator, coreLibPath, romPath, args string) []string {
 	// Quote paths that contain spaces
-	quotedRomPath := s.quotePathIfNeeded(romPath)
 	quotedCorePath := s.quotePathIfNeeded(coreLibPath)
 
 	// Template substitution
@@ -423,25 +630,33 @@ func (s *Service) buildFlatpakCommand(emulator *models.Emulator, coreLibPath, ro
 	cmd = strings.ReplaceAll(cmd, "{flatpak_id}", emulator.FlatpakID)
 	cmd = strings.ReplaceAll(cmd, "{core_lib_path}", quotedCorePath)
 	cmd = strings.ReplaceAll(cmd, "{args}", args)
-	cmd = strings.ReplaceAll(cmd, "{rom}", quotedRomPath)
+	cmd = s.replaceROM(cmd, romPath)
 
 	// Parse into slice, but handle quoted strings properly
 	return parseCommandWithQuotes(cmd)
 }
 
 func (s *Service) buildNativeCommand(emulator *models.Emulator, romPath, args string) []string {
-	// Quote paths that contain spaces
-	quotedRomPath := s.quotePathIfNeeded(romPath)
-
 	cmd := emulator.CommandTemplate
 	cmd = strings.ReplaceAll(cmd, "{executable}", emulator.ExecutablePath)
 	cmd = strings.ReplaceAll(cmd, "{args}", args)
-	cmd = strings.ReplaceAll(cmd, "{rom}", quotedRomPath)
+	cmd = s.replaceROM(cmd, romPath)
 
 	// Parse into slice, but handle quoted strings properly
 	return parseCommandWithQuotes(cmd)
 }
 
+// replaceROM fills the ROM placeholders of a command template: {rom} is the
+// ROM's path, {rom_dir} its directory, and {romset} its name without the
+// extension, which is how MAME-style emulators take a romset
+func (s *Service) replaceROM(cmd, romPath string) string {
+	romset := strings.TrimSuffix(filepath.Base(romPath), filepath.Ext(romPath))
+
+	cmd = strings.ReplaceAll(cmd, "{rom_dir}", s.quotePathIfNeeded(filepath.Dir(romPath)))
+	cmd = strings.ReplaceAll(cmd, "{romset}", s.quotePathIfNeeded(romset))
+	return strings.ReplaceAll(cmd, "{rom}", s.quotePathIfNeeded(romPath))
+}
+
 // GetEmulators returns all emulators
 func (s *Service) GetEmulators() ([]models.Emulator, error) {
 	return s.db.GetEmulators()
@@ -467,6 +682,36 @@ func (s *Service) SetPlatformDefault(platform, emulatorID, coreID string) error
 	return s.db.SetPlatformDefaultEmulator(platform, emulatorID, coreID)
 }
 
+// AssignEmulatorToPlatform makes an emulator, or one of its cores, available for a
+// platform it doesn't list in SupportedPlatforms. The assignment takes effect
+// immediately and is kept when mappings are regenerated.
+func (s *Service) AssignEmulatorToPlatform(platform, emulatorID, coreID string) error {
+	if platform == "" {
+		return fmt.Errorf("platform is required")
+	}
+	if _, err := s.db.GetEmulator(emulatorID); err != nil {
+		return fmt.Errorf("emulator not found: %s", emulatorID)
+	}
+	if coreID != "" {
+		if _, err := s.db.GetCore(emulatorID, coreID); err != nil {
+			return fmt.Errorf("core not found: %s/%s", emulatorID, coreID)
+		}
+	}
+
+	mapping := models.PlatformEmulator{
+		ID:         platformMappingID(platform, emulatorID, coreID),
+		Platform:   platform,
+		EmulatorID: emulatorID,
+		CoreID:     coreID,
+	}
+	if err := s.db.AssignPlatformEmulator(mapping); err != nil {
+		return fmt.Errorf("failed to assign emulator to platform: %w", err)
+	}
+
+	s.logger.Info("Assigned emulator to platform", "platform", platform, "emulator", emulatorID, "core", coreID)
+	return nil
+}
+
 // SetInstanceEmulator sets the emulator for a specific game instance
 func (s *Service) SetInstanceEmulator(instanceID, emulatorID, coreID, customArgs string) error {
 	return s.db.SetInstanceEmulatorSettings(instanceID, emulatorID, coreID, customArgs)
@@ -520,3 +765,52 @@ func parseCommandWithQuotes(cmd string) []string {
 func (s *Service) GetInstanceEmulatorSettings(instanceID string) (*models.InstanceEmulatorSettings, error) {
 	return s.db.GetInstanceEmulatorSettings(instanceID)
 }
+
+// CreateArgProfile saves a named args preset for an emulator
+func (s *Service) CreateArgProfile(emulatorID, name, args string) (models.EmulatorArgProfile, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.EmulatorArgProfile{}, fmt.Errorf("profile name is required")
+	}
+	if _, err := s.db.GetEmulator(emulatorID); err != nil {
+		return models.EmulatorArgProfile{}, fmt.Errorf("emulator not found: %s", emulatorID)
+	}
+
+	profile := models.EmulatorArgProfile{
+		ID:         emulatorID + "_" + strings.ReplaceAll(strings.ToLower(name), " ", "_"),
+		EmulatorID: emulatorID,
+		Name:       name,
+		Args:       strings.TrimSpace(args),
+	}
+	if err := s.db.CreateArgProfile(profile); err != nil {
+		return models.EmulatorArgProfile{}, err
+	}
+	return profile, nil
+}
+
+// GetArgProfiles lists an emulator's args profiles. An empty emulatorID lists all.
+func (s *Service) GetArgProfiles(emulatorID string) ([]models.EmulatorArgProfile, error) {
+	return s.db.GetArgProfiles(emulatorID)
+}
+
+// DeleteArgProfile removes an args profile from the emulator and any instances using it
+func (s *Service) DeleteArgProfile(profileID string) error {
+	return s.db.DeleteArgProfile(profileID)
+}
+
+// ApplyArgProfile launches an instance with a profile's emulator and args.
+// An empty profileID removes the instance's profile.
+func (s *Service) ApplyArgProfile(instanceID, profileID string) error {
+	if profileID == "" {
+		return s.db.ClearInstanceArgProfile(instanceID)
+	}
+
+	profile, err := s.db.GetArgProfile(profileID)
+	if err != nil {
+		return err
+	}
+	if profile == nil {
+		return fmt.Errorf("args profile not found: %s", profileID)
+	}
+	return s.db.SetInstanceArgProfile(instanceID, *profile)
+}
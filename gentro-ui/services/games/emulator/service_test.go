@@ -0,0 +1,431 @@
+package emulator
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/games/database"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// newTestService creates an initialized emulator service backed by a temporary database
+func newTestService(t *testing.T) (*Service, *database.DB) {
+	t.Helper()
+
+	db, err := database.New(filepath.Join(t.TempDir(), "games.db"))
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	service := NewService(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := service.Initialize(); err != nil {
+		t.Fatalf("failed to initialize emulator service: %v", err)
+	}
+	return service, db
+}
+
+// seedInstance creates a game instance that emulator settings can reference
+func seedInstance(t *testing.T, db *database.DB, id, platform string) {
+	t.Helper()
+
+	if err := db.CreateGame(&models.Game{ID: "game_" + id, Name: id}); err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+	if err := db.CreateInstance(&models.GameInstance{ID: id, GameID: "game_" + id, Source: "emulated", Platform: platform}); err != nil {
+		t.Fatalf("failed to create instance: %v", err)
+	}
+}
+
+func TestArgProfiles(t *testing.T) {
+	service, db := newTestService(t)
+	seedInstance(t, db, "inst1", "gamecube")
+
+	performance, err := service.CreateArgProfile("dolphin", "Performance", "--config=GFX.Settings.InternalResolution=1")
+	if err != nil {
+		t.Fatalf("CreateArgProfile failed: %v", err)
+	}
+	if _, err := service.CreateArgProfile("dolphin", "Performance", ""); err == nil {
+		t.Error("expected duplicate profile name to fail")
+	}
+	if _, err := service.CreateArgProfile("missing", "Accuracy", ""); err == nil {
+		t.Error("expected unknown emulator to fail")
+	}
+
+	if err := service.ApplyArgProfile("inst1", performance.ID); err != nil {
+		t.Fatalf("ApplyArgProfile failed: %v", err)
+	}
+	settings, err := service.GetInstanceEmulatorSettings("inst1")
+	if err != nil {
+		t.Fatalf("GetInstanceEmulatorSettings failed: %v", err)
+	}
+	if settings.ProfileID != performance.ID || settings.EmulatorID != "dolphin" {
+		t.Errorf("expected profile to select dolphin, got %+v", settings)
+	}
+
+	dolphin := &models.Emulator{
+		ID: "dolphin", Type: models.EmulatorTypeNative, IsAvailable: true,
+		ExecutablePath: "dolphin-emu", CommandTemplate: "{executable} {args} {rom}", DefaultArgs: "-b -e",
+	}
+	settings.CustomArgs = "--debugger"
+	cmd, err := service.BuildCommand(dolphin, nil, "/roms/game.iso", nil, settings)
+	if err != nil {
+		t.Fatalf("BuildCommand failed: %v", err)
+	}
+	want := []string{"dolphin-emu", "-b", "-e", "--config=GFX.Settings.InternalResolution=1", "--debugger", "/roms/game.iso"}
+	if !slices.Equal(cmd, want) {
+		t.Errorf("expected %v, got %v", want, cmd)
+	}
+
+	// A profile for another emulator is skipped after fallback
+	other := *dolphin
+	other.ID = "other"
+	cmd, err = service.BuildCommand(&other, nil, "/roms/game.iso", nil, settings)
+	if err != nil {
+		t.Fatalf("BuildCommand failed: %v", err)
+	}
+	if slices.Contains(cmd, "--config=GFX.Settings.InternalResolution=1") {
+		t.Errorf("expected profile args to be skipped for another emulator, got %v", cmd)
+	}
+
+	// Additional files fill {files}, one argument each
+	other.CommandTemplate = "{executable} {rom} {files}"
+	cmd, err = service.BuildCommand(&other, nil, "/roms/disk 1.adf", []string{"/roms/disk 2.adf", "/roms/disk 3.adf"}, nil)
+	if err != nil {
+		t.Fatalf("BuildCommand failed: %v", err)
+	}
+	want = []string{"dolphin-emu", "/roms/disk 1.adf", "/roms/disk 2.adf", "/roms/disk 3.adf"}
+	if !slices.Equal(cmd, want) {
+		t.Errorf("expected %v, got %v", want, cmd)
+	}
+
+	// Romsets are passed by name, with their folder as the ROM path
+	other.CommandTemplate = "{executable} -rompath {rom_dir} {romset}"
+	cmd, err = service.BuildCommand(&other, nil, "/roms/my arcade/sf2.zip", nil, nil)
+	if err != nil {
+		t.Fatalf("BuildCommand failed: %v", err)
+	}
+	want = []string{"dolphin-emu", "-rompath", "/roms/my arcade", "sf2"}
+	if !slices.Equal(cmd, want) {
+		t.Errorf("expected %v, got %v", want, cmd)
+	}
+
+	if err := service.DeleteArgProfile(performance.ID); err != nil {
+		t.Fatalf("DeleteArgProfile failed: %v", err)
+	}
+	settings, err = service.GetInstanceEmulatorSettings("inst1")
+	if err != nil {
+		t.Fatalf("GetInstanceEmulatorSettings failed: %v", err)
+	}
+	if settings.ProfileID != "" {
+		t.Errorf("expected deleted profile to be detached, got %q", settings.ProfileID)
+	}
+}
+
+// platformDefaults returns the IDs of a platform's default mappings
+func platformDefaults(t *testing.T, db *database.DB, platform string) []string {
+	t.Helper()
+
+	mappings, err := db.GetPlatformEmulators()
+	if err != nil {
+		t.Fatalf("GetPlatformEmulators failed: %v", err)
+	}
+	var ids []string
+	for _, pe := range mappings {
+		if pe.Platform == platform && pe.IsDefault {
+			ids = append(ids, pe.ID)
+		}
+	}
+	return ids
+}
+
+func TestResolveDefaultConflicts(t *testing.T) {
+	service, db := newTestService(t)
+
+	// Seed a second default next to the built-in RetroArch core
+	if err := db.SetPlatformEmulatorDefault("nes_nestopia"); err != nil {
+		t.Fatalf("failed to seed conflict: %v", err)
+	}
+	if got := platformDefaults(t, db, "nes"); len(got) != 2 {
+		t.Fatalf("expected seeded conflict, got %v", got)
+	}
+
+	if err := service.resolveDefaultConflicts(); err != nil {
+		t.Fatalf("resolveDefaultConflicts failed: %v", err)
+	}
+	if got := platformDefaults(t, db, "nes"); !slices.Equal(got, []string{"nes_nestopia"}) {
+		t.Errorf("expected the non-built-in default to win, got %v", got)
+	}
+}
+
+func TestInitialize_KeepsUserDefault(t *testing.T) {
+	service, db := newTestService(t)
+
+	if err := service.SetPlatformDefault("nes", "nestopia", ""); err != nil {
+		t.Fatalf("SetPlatformDefault failed: %v", err)
+	}
+	if err := service.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	if got := platformDefaults(t, db, "nes"); !slices.Equal(got, []string{"nes_nestopia"}) {
+		t.Errorf("expected user default to survive regeneration alone, got %v", got)
+	}
+	if got := platformDefaults(t, db, "snes"); len(got) != 1 {
+		t.Errorf("expected built-in default for untouched platforms, got %v", got)
+	}
+}
+
+func TestAssignEmulatorToPlatform(t *testing.T) {
+	service, db := newTestService(t)
+
+	if err := service.AssignEmulatorToPlatform("fds", "nestopia", ""); err != nil {
+		t.Fatalf("AssignEmulatorToPlatform failed: %v", err)
+	}
+	if got := platformDefaults(t, db, "fds"); !slices.Equal(got, []string{"fds_nestopia"}) {
+		t.Errorf("expected the only emulator to become the default, got %v", got)
+	}
+
+	// Assigning an already generated mapping keeps a single row and the existing default
+	if err := service.AssignEmulatorToPlatform("nes", "nestopia", ""); err != nil {
+		t.Fatalf("AssignEmulatorToPlatform failed: %v", err)
+	}
+	emulators, _, err := db.GetEmulatorsForPlatform("nes")
+	if err != nil {
+		t.Fatalf("GetEmulatorsForPlatform failed: %v", err)
+	}
+	count := 0
+	for _, emu := range emulators {
+		if emu.ID == "nestopia" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected one nestopia mapping for nes, got %d", count)
+	}
+	if got := platformDefaults(t, db, "nes"); slices.Contains(got, "nes_nestopia") {
+		t.Errorf("expected the built-in nes default to be kept, got %v", got)
+	}
+
+	// Regeneration keeps user assignments
+	if err := service.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	if got := platformDefaults(t, db, "fds"); !slices.Equal(got, []string{"fds_nestopia"}) {
+		t.Errorf("expected the assignment to survive regeneration, got %v", got)
+	}
+
+	if err := service.AssignEmulatorToPlatform("fds", "missing", ""); err == nil {
+		t.Error("expected an unknown emulator to be rejected")
+	}
+	if err := service.AssignEmulatorToPlatform("fds", "retroarch", "missing"); err == nil {
+		t.Error("expected an unknown core to be rejected")
+	}
+}
+
+func TestExplainResolution(t *testing.T) {
+	service, db := newTestService(t)
+	for _, id := range []string{"nestopia", "dolphin"} {
+		if err := db.UpdateEmulatorAvailability(id, true); err != nil {
+			t.Fatalf("failed to mark %s available: %v", id, err)
+		}
+	}
+	seedInstance(t, db, "override", "wii")
+	if err := service.SetInstanceEmulator("override", "dolphin", "", ""); err != nil {
+		t.Fatalf("SetInstanceEmulator failed: %v", err)
+	}
+
+	tests := []struct {
+		instance     models.GameInstance
+		wantReason   models.ResolutionReason
+		wantEmulator string
+		available    bool
+	}{
+		{models.GameInstance{ID: "override", Platform: "wii"}, models.ResolutionInstanceOverride, "dolphin", true},
+		{models.GameInstance{ID: "default", Platform: "gamecube"}, models.ResolutionFallback, "dolphin", true},
+		// The RetroArch default core isn't installed, so Nestopia is used instead
+		{models.GameInstance{ID: "fallback", Platform: "nes"}, models.ResolutionFallback, "nestopia", true},
+		// Nothing available: the configured default is reported as unavailable
+		{models.GameInstance{ID: "none", Platform: "snes"}, models.ResolutionNone, "retroarch", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.instance.ID, func(t *testing.T) {
+			info := service.ExplainResolution(tt.instance)
+			if info.Reason != tt.wantReason {
+				t.Errorf("expected reason %s, got %s (%s)", tt.wantReason, info.Reason, info.Message)
+			}
+			if info.Emulator == nil || info.Emulator.ID != tt.wantEmulator {
+				t.Errorf("expected emulator %s, got %+v", tt.wantEmulator, info.Emulator)
+			}
+			if info.Available != tt.available {
+				t.Errorf("expected available %v, got %v", tt.available, info.Available)
+			}
+		})
+	}
+
+	if err := service.SetPlatformDefault("wii", "dolphin", ""); err != nil {
+		t.Fatalf("SetPlatformDefault failed: %v", err)
+	}
+	if info := service.ExplainResolution(models.GameInstance{ID: "plain", Platform: "wii"}); info.Reason != models.ResolutionPlatformDefault {
+		t.Errorf("expected platform default, got %s", info.Reason)
+	}
+}
+
+func TestTestEmulator(t *testing.T) {
+	service, db := newTestService(t)
+
+	dir := t.TempDir()
+	script := func(name, body string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+			t.Fatalf("failed to write script: %v", err)
+		}
+		return path
+	}
+
+	for _, emu := range []models.Emulator{
+		{ID: "ok", Name: "ok", DisplayName: "OK", Type: models.EmulatorTypeNative, ExecutablePath: script("ok", `echo "emu $1"`)},
+		{ID: "fail", Name: "fail", DisplayName: "Fail", Type: models.EmulatorTypeNative, ExecutablePath: script("fail", "echo boom >&2; exit 3")},
+		{ID: "missing", Name: "missing", DisplayName: "Missing", Type: models.EmulatorTypeNative, ExecutablePath: filepath.Join(dir, "missing")},
+	} {
+		emu.CommandTemplate = "{executable} {rom}"
+		if err := db.UpsertEmulator(emu); err != nil {
+			t.Fatalf("failed to seed emulator: %v", err)
+		}
+	}
+
+	result, err := service.TestEmulator(context.Background(), "ok")
+	if err != nil {
+		t.Fatalf("TestEmulator failed: %v", err)
+	}
+	if !result.Available || !result.Launched || result.Stdout != "emu --version\n" {
+		t.Errorf("expected emulator to launch and print its version, got %+v", result)
+	}
+	if emu, _ := db.GetEmulator("ok"); !emu.IsAvailable {
+		t.Error("expected test to record the emulator as available")
+	}
+
+	result, err = service.TestEmulator(context.Background(), "fail")
+	if err != nil {
+		t.Fatalf("TestEmulator failed: %v", err)
+	}
+	if result.Launched || result.ExitCode != 3 || result.Stderr != "boom\n" || result.Error == "" {
+		t.Errorf("expected non-zero exit to be reported, got %+v", result)
+	}
+
+	result, err = service.TestEmulator(context.Background(), "missing")
+	if err != nil {
+		t.Fatalf("TestEmulator failed: %v", err)
+	}
+	if result.Available || result.Launched {
+		t.Errorf("expected missing emulator to be unavailable, got %+v", result)
+	}
+
+	if _, err := service.TestEmulator(context.Background(), "unknown"); err == nil {
+		t.Error("expected unknown emulator ID to fail")
+	}
+}
+
+func TestValidateInstanceEmulators(t *testing.T) {
+	service, db := newTestService(t)
+	for _, id := range []string{"nestopia", "retroarch"} {
+		if err := db.UpdateEmulatorAvailability(id, true); err != nil {
+			t.Fatalf("failed to mark %s available: %v", id, err)
+		}
+	}
+
+	overrides := []struct{ instance, emulator, core string }{
+		{"installed", "nestopia", ""},
+		{"uninstalled", "dolphin", ""},
+		{"missing_core", "retroarch", "mesen_libretro"},
+	}
+	for _, o := range overrides {
+		seedInstance(t, db, o.instance, "nes")
+		if err := service.SetInstanceEmulator(o.instance, o.emulator, o.core, ""); err != nil {
+			t.Fatalf("SetInstanceEmulator failed: %v", err)
+		}
+	}
+
+	var reported []models.UnavailableInstanceEmulator
+	service.SetOnUnavailableCallback(func(unavailable []models.UnavailableInstanceEmulator) {
+		reported = unavailable
+	})
+
+	unavailable, err := service.ValidateInstanceEmulators()
+	if err != nil {
+		t.Fatalf("ValidateInstanceEmulators failed: %v", err)
+	}
+	var ids []string
+	for _, u := range unavailable {
+		ids = append(ids, u.InstanceID)
+	}
+	if !slices.Equal(ids, []string{"missing_core", "uninstalled"}) {
+		t.Errorf("expected overrides on unavailable emulators and cores, got %v", ids)
+	}
+	if len(reported) != len(unavailable) {
+		t.Errorf("expected the callback to receive the same overrides, got %v", reported)
+	}
+
+	// Overrides are flagged, not cleared
+	if settings, err := db.GetInstanceEmulatorSettings("uninstalled"); err != nil || settings.EmulatorID != "dolphin" {
+		t.Errorf("expected the override to be kept, got %+v (%v)", settings, err)
+	}
+}
+
+func TestGetSaveLocations(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	service, db := newTestService(t)
+	for _, id := range []string{"nestopia", "dolphin"} {
+		if err := db.UpdateEmulatorAvailability(id, true); err != nil {
+			t.Fatalf("failed to mark %s available: %v", id, err)
+		}
+	}
+
+	// A Wii disc's header starts with its game code
+	disc := filepath.Join(t.TempDir(), "Game.iso")
+	if err := os.WriteFile(disc, []byte("RMGE01"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	nestopia := filepath.Join(home, ".var", "app", "ca._0ldsk00l.Nestopia", "data", "nestopia")
+	if err := os.MkdirAll(filepath.Join(nestopia, "save"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	dolphin := filepath.Join(home, ".var", "app", "org.DolphinEmu.dolphin-emu", "data", "dolphin-emu")
+
+	tests := []struct {
+		instance models.GameInstance
+		want     []models.SaveLocation
+	}{
+		{models.GameInstance{ID: "nes", Platform: "nes", Path: "/roms/nes/Mario (USA).nes"}, []models.SaveLocation{
+			{Dir: filepath.Join(nestopia, "save"), Prefix: "Mario (USA).", Exists: true},
+			{Dir: filepath.Join(nestopia, "state"), Prefix: "Mario (USA)."},
+		}},
+		{models.GameInstance{ID: "gc", Platform: "gamecube", Path: "/roms/gc/Game.iso"}, []models.SaveLocation{
+			{Dir: filepath.Join(dolphin, "GC")},
+		}},
+		{models.GameInstance{ID: "wii", Platform: "wii", Path: disc}, []models.SaveLocation{
+			{Dir: filepath.Join(dolphin, "Wii", "title", "00010000", "524d4745")},
+		}},
+	}
+	for _, tt := range tests {
+		got, err := service.GetSaveLocations(tt.instance)
+		if err != nil {
+			t.Errorf("%s: GetSaveLocations failed: %v", tt.instance.ID, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: expected %+v, got %+v", tt.instance.ID, tt.want, got)
+		}
+	}
+
+	if _, err := service.GetSaveLocations(models.GameInstance{ID: "pc", Platform: "pc"}); err == nil {
+		t.Error("expected an error for a platform without an emulator")
+	}
+}
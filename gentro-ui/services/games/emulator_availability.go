@@ -0,0 +1,102 @@
+package games
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// emulatorAvailableKey is the custom metadata key reporting whether an instance's
+// platform has an emulator. It's computed on read and never persisted.
+const emulatorAvailableKey = "emulator.available"
+
+// annotateEmulatorAvailability sets emulator.available on instances from sources
+// that require an emulator, checking each platform once
+func (s *GamesService) annotateEmulatorAvailability(instances []models.GameInstance) {
+	if s.emuService == nil {
+		return
+	}
+
+	availability := make(map[string]bool)
+	for i := range instances {
+		instance := &instances[i]
+		if !s.requiresEmulator(instance.Source) {
+			continue
+		}
+
+		available, ok := availability[instance.Platform]
+		if !ok {
+			available = s.platformHasEmulator(instance.Platform)
+			availability[instance.Platform] = available
+		}
+
+		if instance.CustomMetadata == nil {
+			instance.CustomMetadata = make(map[string]any)
+		}
+		instance.CustomMetadata[emulatorAvailableKey] = available
+	}
+
+	if len(availability) > 0 {
+		s.availabilityMu.Lock()
+		if s.reportedAvailability == nil {
+			s.reportedAvailability = make(map[string]bool)
+		}
+		maps.Copy(s.reportedAvailability, availability)
+		s.availabilityMu.Unlock()
+	}
+}
+
+// requiresEmulator reports whether a source's instances need an emulator to launch
+func (s *GamesService) requiresEmulator(sourceName string) bool {
+	source, ok := s.registry.Get(sourceName)
+	return ok && source.Capabilities().RequiresEmulator
+}
+
+// platformHasEmulator reports whether any available emulator handles a platform
+func (s *GamesService) platformHasEmulator(platform string) bool {
+	pairs, err := s.emuService.GetAvailableEmulatorsForPlatform(platform)
+	if err != nil {
+		s.logger.Debug("no emulator available for platform", "platform", platform, "error", err)
+		return false
+	}
+	return len(pairs) > 0
+}
+
+// onEmulatorsDiscovered rechecks the platforms whose availability the UI has seen
+// after a discovery pass and notifies it about instances on platforms that changed,
+// so installing an emulator mid-session makes its ROMs playable without a refresh
+func (s *GamesService) onEmulatorsDiscovered() {
+	s.availabilityMu.Lock()
+	changed := make(map[string]bool)
+	for _, platform := range slices.Sorted(maps.Keys(s.reportedAvailability)) {
+		available := s.platformHasEmulator(platform)
+		if available != s.reportedAvailability[platform] {
+			s.reportedAvailability[platform] = available
+			changed[platform] = available
+		}
+	}
+	s.availabilityMu.Unlock()
+
+	if len(changed) == 0 {
+		return
+	}
+
+	instances, err := s.db.GetInstances(models.GameFilter{})
+	if err != nil {
+		s.logger.Error("failed to get instances for emulator availability", "error", err)
+		return
+	}
+	for _, instance := range instances {
+		available, ok := changed[instance.Platform]
+		if !ok || !s.requiresEmulator(instance.Source) {
+			continue
+		}
+
+		s.logger.Debug("emulator availability changed", "instanceID", instance.ID, "platform", instance.Platform, "available", available)
+		s.events.EmitMetadataStatus(instance.ID, instance.GameID, models.MetadataStatus{
+			State:   models.MetadataStateCompleted,
+			Message: "Emulator availability changed",
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package games
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/games/emulator"
+	"github.com/rhythmerc/gentro-ui/services/games/events"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// emulatorSource is a mock source whose instances need an emulator
+type emulatorSource struct {
+	MockSource
+}
+
+func (e *emulatorSource) Capabilities() models.SourceCapabilities {
+	return models.SourceCapabilities{RequiresEmulator: true}
+}
+
+func TestEmulatorAvailability(t *testing.T) {
+	service := newTestService(t)
+	service.emuService = emulator.NewService(service.db, service.logger)
+	if err := service.emuService.Initialize(); err != nil {
+		t.Fatalf("failed to initialize emulators: %v", err)
+	}
+
+	service.registry.Register(context.Background(), &emulatorSource{MockSource{name: "roms"}})
+	service.registry.Register(context.Background(), &MockSource{name: "mock"})
+	if _, err := service.syncSourceInstances("roms", []models.GameInstance{
+		{ID: "nes1", GameID: "nes1", Source: "roms", Platform: "nes"},
+		{ID: "snes1", GameID: "snes1", Source: "roms", Platform: "snes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instances: %v", err)
+	}
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "pc1", GameID: "pc1", Source: "mock", Platform: "pc"},
+	}); err != nil {
+		t.Fatalf("failed to seed instances: %v", err)
+	}
+
+	availability := func() map[string]any {
+		t.Helper()
+		games, err := service.GetGames(&models.GameFilter{}, nil)
+		if err != nil {
+			t.Fatalf("GetGames failed: %v", err)
+		}
+		got := make(map[string]any)
+		for _, game := range games {
+			if available, ok := game.Instance.CustomMetadata[emulatorAvailableKey]; ok {
+				got[game.Instance.ID] = available
+			}
+		}
+		return got
+	}
+
+	if got := availability(); got["nes1"] != false || got["snes1"] != false || len(got) != 2 {
+		t.Errorf("expected no emulators for ROMs only, got %v", got)
+	}
+
+	var updated []string
+	service.events = events.NewEventsWithSink(service.logger, func(name string, data any) {
+		if update, ok := data.(models.MetadataStatusUpdate); ok {
+			updated = append(updated, update.InstanceID)
+		}
+	})
+
+	// Installing an emulator mid-session is picked up on the next read and discovery
+	if err := service.db.UpdateEmulatorAvailability("nestopia", true); err != nil {
+		t.Fatalf("failed to mark emulator available: %v", err)
+	}
+	service.onEmulatorsDiscovered()
+
+	if !slices.Equal(updated, []string{"nes1"}) {
+		t.Errorf("expected an update for the NES instance only, got %v", updated)
+	}
+	if got := availability(); got["nes1"] != true || got["snes1"] != false {
+		t.Errorf("expected the NES instance to have an emulator, got %v", got)
+	}
+
+	// Availability is never persisted
+	if instance, _ := service.db.GetInstance("nes1"); instance.CustomMetadata[emulatorAvailableKey] != nil {
+		t.Errorf("expected emulator availability not to be stored, got %v", instance.CustomMetadata)
+	}
+}
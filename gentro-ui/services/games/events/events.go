@@ -2,57 +2,272 @@ package events
 
 import (
 	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/rhythmerc/gentro-ui/services/games/models"
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
+// Sink delivers a named event to the frontend
+type Sink func(name string, data any)
+
+// SessionCallback is called when a running instance stops, with when it started and stopped
+type SessionCallback func(instanceID string, startedAt, endedAt time.Time)
+
+// Events emits game status events. A nil *Events discards everything.
 type Events struct {
 	logger *slog.Logger
+	sink   Sink
+
+	// running maps instances whose last launch status was running to when they started
+	running   map[string]time.Time
+	runningMu sync.Mutex
+
+	onSessionEnded SessionCallback
+
+	// subscribers receive every event in addition to the sink, keyed by subscription
+	subscribers    map[int]Sink
+	nextSubscriber int
+	subscribersMu  sync.RWMutex
 }
 
+// NewEvents creates an Events that emits through the running Wails application
 func NewEvents(logger *slog.Logger) *Events {
-	return &Events{logger}
+	return NewEventsWithSink(logger, appSink)
 }
 
-func (e *Events) EmitGameInstanceRunning(instance models.GameInstance) {
-	app := application.Get()
-	if app != nil {
-		update := models.LaunchStatusUpdate{
-			InstanceID: instance.ID,
-			GameID:     instance.GameID,
-			Status:     models.LaunchStatusRunning,
+// NewEventsWithSink creates an Events that delivers to sink instead of the application
+func NewEventsWithSink(logger *slog.Logger, sink Sink) *Events {
+	return &Events{logger: logger, sink: sink, running: make(map[string]time.Time)}
+}
+
+// SetOnSessionEnded sets the callback for play sessions, reported on each
+// running to stopped transition
+func (e *Events) SetOnSessionEnded(callback SessionCallback) {
+	e.onSessionEnded = callback
+}
+
+// appSink emits through the Wails application, if one is running
+func appSink(name string, data any) {
+	if app := application.Get(); app != nil {
+		app.Event.Emit(name, data)
+	}
+}
+
+// Subscribe delivers every subsequent event to sink as well as the frontend, until
+// the returned function is called. Sinks run on the emitting goroutine, so they
+// must not block.
+func (e *Events) Subscribe(sink Sink) (unsubscribe func()) {
+	if e == nil {
+		return func() {}
+	}
+
+	e.subscribersMu.Lock()
+	defer e.subscribersMu.Unlock()
+	if e.subscribers == nil {
+		e.subscribers = make(map[int]Sink)
+	}
+	id := e.nextSubscriber
+	e.nextSubscriber++
+	e.subscribers[id] = sink
+
+	return func() {
+		e.subscribersMu.Lock()
+		defer e.subscribersMu.Unlock()
+		delete(e.subscribers, id)
+	}
+}
+
+func (e *Events) emit(name string, data any) {
+	if e == nil {
+		return
+	}
+	if e.sink != nil {
+		e.sink(name, data)
+	}
+
+	e.subscribersMu.RLock()
+	defer e.subscribersMu.RUnlock()
+	for _, sink := range e.subscribers {
+		sink(name, data)
+	}
+}
+
+// EmitLaunchStatus emits a launch status update for an instance. Running is
+// emitted once per run and stopped only after running, so the UI sees exactly
+// one of each per launch however many times a monitor reports them. Launching
+// an instance that's already running is dropped.
+func (e *Events) EmitLaunchStatus(instanceID, gameID string, status models.LaunchStatus, errMsg string) {
+	e.emitLaunchStatus(models.LaunchStatusUpdate{
+		InstanceID: instanceID,
+		GameID:     gameID,
+		Status:     status,
+		Error:      errMsg,
+	})
+}
+
+func (e *Events) emitLaunchStatus(update models.LaunchStatusUpdate) {
+	if e == nil {
+		return
+	}
+
+	now := time.Now()
+	e.runningMu.Lock()
+	startedAt, wasRunning := e.running[update.InstanceID]
+	switch {
+	case update.Status == models.LaunchStatusRunning:
+		if !wasRunning {
+			e.running[update.InstanceID] = now
+		}
+	case update.Status == models.LaunchStatusLaunching && wasRunning:
+		// Launching again doesn't end the session already running
+	default:
+		delete(e.running, update.InstanceID)
+	}
+	e.runningMu.Unlock()
+
+	if (update.Status == models.LaunchStatusRunning && wasRunning) ||
+		(update.Status == models.LaunchStatusLaunching && wasRunning) ||
+		(update.Status == models.LaunchStatusStopped && !wasRunning) {
+		if e.logger != nil {
+			e.logger.Debug("dropping repeated launch status", "instanceId", update.InstanceID, "status", update.Status)
 		}
-		app.Event.Emit("launchStatusUpdate", update)
+		return
+	}
+
+	e.emit(models.EventLaunchStatus, update)
+
+	if wasRunning && update.Status != models.LaunchStatusRunning && e.onSessionEnded != nil {
+		e.onSessionEnded(update.InstanceID, startedAt, now)
 	}
 
 	if e.logger != nil {
-		e.logger.Info("game running",
-			"instanceId", instance.ID,
-			"gameId", instance.GameID,
+		e.logger.Info("launch status update",
+			"instanceId", update.InstanceID,
+			"gameId", update.GameID,
+			"status", update.Status,
 		)
 	}
 }
 
-// emitStopped emits a stopped status update
+// EmitLaunchError emits a failed status update followed by the classified error
+func (e *Events) EmitLaunchError(instanceID, gameID string, code models.LaunchErrorCode, err error) {
+	if e == nil {
+		return
+	}
+
+	e.EmitLaunchStatus(instanceID, gameID, models.LaunchStatusFailed, err.Error())
+	e.emit(models.EventLaunchError, models.LaunchError{
+		InstanceID: instanceID,
+		GameID:     gameID,
+		Code:       code,
+		Message:    err.Error(),
+	})
+}
+
+// RunningInstances returns the IDs of instances currently reported as running
+func (e *Events) RunningInstances() []string {
+	if e == nil {
+		return nil
+	}
+
+	e.runningMu.Lock()
+	defer e.runningMu.Unlock()
+	return slices.Sorted(maps.Keys(e.running))
+}
+
+// EmitGameInstanceRunning emits a running status update
+func (e *Events) EmitGameInstanceRunning(instance models.GameInstance) {
+	e.EmitLaunchStatus(instance.ID, instance.GameID, models.LaunchStatusRunning, "")
+}
+
+// EmitGameInstanceStopped emits a stopped status update
 func (e *Events) EmitGameInstanceStopped(instance models.GameInstance) {
-	app := application.Get()
-	if app != nil {
-		update := models.LaunchStatusUpdate{
-			InstanceID: instance.ID,
-			GameID:     instance.GameID,
-			Status:     models.LaunchStatusStopped,
-		}
-		app.Event.Emit("launchStatusUpdate", update)
+	e.EmitLaunchStatus(instance.ID, instance.GameID, models.LaunchStatusStopped, "")
+}
+
+// EmitGameInstanceExited emits a stopped status update with the process's exit code
+func (e *Events) EmitGameInstanceExited(instance models.GameInstance, exitCode int, crashed bool, errMsg string) {
+	e.emitLaunchStatus(models.LaunchStatusUpdate{
+		InstanceID: instance.ID,
+		GameID:     instance.GameID,
+		Status:     models.LaunchStatusStopped,
+		Error:      errMsg,
+		ExitCode:   exitCode,
+		Crashed:    crashed,
+	})
+}
+
+// EmitMetadataStatus emits a metadata status update for an instance
+func (e *Events) EmitMetadataStatus(instanceID, gameID string, status models.MetadataStatus) {
+	e.emit(models.EventMetadataStatus, models.MetadataStatusUpdate{
+		InstanceID: instanceID,
+		GameID:     gameID,
+		Status:     status,
+	})
+}
+
+// EmitInstanceUpdated notifies the UI that the user changed an instance
+func (e *Events) EmitInstanceUpdated(instanceID, gameID string, keys []string) {
+	e.emit(models.EventInstanceUpdate, models.InstanceUpdate{
+		InstanceID: instanceID,
+		GameID:     gameID,
+		Keys:       keys,
+	})
+}
+
+// EmitLibraryUpdated notifies the UI that the user changed many instances at once
+func (e *Events) EmitLibraryUpdated(instanceIDs, keys []string) {
+	e.emit(models.EventLibraryUpdated, models.LibraryUpdate{
+		InstanceIDs: instanceIDs,
+		Keys:        keys,
+	})
+}
+
+// EmitGameArtUpdated notifies the UI that an instance's cached art changed
+func (e *Events) EmitGameArtUpdated(instanceID, gameID, artType string) {
+	if e == nil {
+		return
 	}
 
+	e.emit(models.EventArtUpdated, models.ArtUpdate{
+		InstanceID: instanceID,
+		GameID:     gameID,
+		ArtType:    artType,
+	})
+
 	if e.logger != nil {
-		e.logger.Info("game stopped",
-			"instanceId", instance.ID,
-			"gameId", instance.GameID,
+		e.logger.Debug("art updated",
+			"instanceId", instanceID,
+			"gameId", gameID,
+			"artType", artType,
 		)
 	}
 }
 
-func (e *Events) EmitGameArtUpdated(instance models.GameInstance) {
+// EmitRefreshResult reports the outcome of a library refresh
+func (e *Events) EmitRefreshResult(result models.RefreshResult) {
+	e.emit(models.EventRefreshResult, result)
+}
+
+// EmitInstanceEmulatorsUnavailable reports instance overrides whose emulator was uninstalled
+func (e *Events) EmitInstanceEmulatorsUnavailable(unavailable []models.UnavailableInstanceEmulator) {
+	e.emit(models.EventInstanceEmulatorsUnavailable, unavailable)
+}
+
+// EmitArtPrefetchProgress reports how many of a source's background art downloads have finished
+func (e *Events) EmitArtPrefetchProgress(source string, completed, total int) {
+	e.emit(models.EventArtPrefetch, models.ArtPrefetchProgress{
+		Source:    source,
+		Completed: completed,
+		Total:     total,
+	})
+}
+
+// EmitVerifyProgress reports how many instances a library verification has checked
+func (e *Events) EmitVerifyProgress(checked, total int) {
+	e.emit(models.EventVerifyProgress, models.VerifyProgress{Checked: checked, Total: total})
 }
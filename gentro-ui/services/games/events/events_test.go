@@ -0,0 +1,108 @@
+package events
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// countingSink counts launch status updates by status
+func countingSink(counts map[models.LaunchStatus]int) Sink {
+	return func(name string, data any) {
+		if update, ok := data.(models.LaunchStatusUpdate); ok && name == models.EventLaunchStatus {
+			counts[update.Status]++
+		}
+	}
+}
+
+func TestEmitLaunchStatus_OneRunningAndStoppedPerLaunch(t *testing.T) {
+	counts := make(map[models.LaunchStatus]int)
+	e := NewEventsWithSink(nil, countingSink(counts))
+	instance := models.GameInstance{ID: "inst1", GameID: "game1"}
+
+	e.EmitLaunchStatus(instance.ID, instance.GameID, models.LaunchStatusLaunching, "")
+	e.EmitGameInstanceRunning(instance)
+	e.EmitGameInstanceRunning(instance)
+	e.EmitGameInstanceStopped(instance)
+	e.EmitGameInstanceStopped(instance)
+
+	want := map[models.LaunchStatus]int{
+		models.LaunchStatusLaunching: 1,
+		models.LaunchStatusRunning:   1,
+		models.LaunchStatusStopped:   1,
+	}
+	for status, n := range want {
+		if counts[status] != n {
+			t.Errorf("expected %d %s events, got %d", n, status, counts[status])
+		}
+	}
+
+	// A second launch gets its own running and stopped
+	e.EmitGameInstanceRunning(instance)
+	e.EmitGameInstanceStopped(instance)
+	if counts[models.LaunchStatusRunning] != 2 || counts[models.LaunchStatusStopped] != 2 {
+		t.Errorf("expected a second run to emit again, got %v", counts)
+	}
+}
+
+func TestEmitLaunchError_AlwaysEmitsFailed(t *testing.T) {
+	counts := make(map[models.LaunchStatus]int)
+	e := NewEventsWithSink(nil, countingSink(counts))
+
+	e.EmitLaunchError("inst1", "game1", models.LaunchErrorUnknownSource, errors.New("unknown source"))
+	e.EmitLaunchError("inst1", "game1", models.LaunchErrorUnknownSource, errors.New("unknown source"))
+
+	if counts[models.LaunchStatusFailed] != 2 {
+		t.Errorf("expected every failure to be reported, got %d", counts[models.LaunchStatusFailed])
+	}
+}
+
+func TestSessionEnded_OncePerRun(t *testing.T) {
+	e := NewEventsWithSink(nil, func(string, any) {})
+	var sessions []time.Duration
+	e.SetOnSessionEnded(func(instanceID string, startedAt, endedAt time.Time) {
+		if instanceID != "inst1" {
+			t.Errorf("unexpected instance %q", instanceID)
+		}
+		sessions = append(sessions, endedAt.Sub(startedAt))
+	})
+	instance := models.GameInstance{ID: "inst1", GameID: "game1"}
+
+	e.EmitGameInstanceStopped(instance)
+	e.EmitGameInstanceRunning(instance)
+	time.Sleep(10 * time.Millisecond)
+	// A repeated running keeps the original start time
+	e.EmitGameInstanceRunning(instance)
+	// So does launching it again while it runs
+	e.EmitLaunchStatus(instance.ID, instance.GameID, models.LaunchStatusLaunching, "")
+	e.EmitGameInstanceRunning(instance)
+	e.EmitGameInstanceStopped(instance)
+	e.EmitGameInstanceStopped(instance)
+
+	if len(sessions) != 1 {
+		t.Fatalf("expected one session, got %d", len(sessions))
+	}
+	if sessions[0] < 10*time.Millisecond {
+		t.Errorf("expected the session to span from the first running, got %v", sessions[0])
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	sinkCounts := make(map[models.LaunchStatus]int)
+	subscriberCounts := make(map[models.LaunchStatus]int)
+	e := NewEventsWithSink(nil, countingSink(sinkCounts))
+
+	unsubscribe := e.Subscribe(countingSink(subscriberCounts))
+	e.EmitLaunchStatus("inst1", "game1", models.LaunchStatusLaunching, "")
+	unsubscribe()
+	e.EmitLaunchStatus("inst1", "game1", models.LaunchStatusLaunching, "")
+
+	if sinkCounts[models.LaunchStatusLaunching] != 2 {
+		t.Errorf("expected the sink to get every event, got %d", sinkCounts[models.LaunchStatusLaunching])
+	}
+	if subscriberCounts[models.LaunchStatusLaunching] != 1 {
+		t.Errorf("expected the subscriber to get events until unsubscribed, got %d", subscriberCounts[models.LaunchStatusLaunching])
+	}
+}
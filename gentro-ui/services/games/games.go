@@ -1,19 +1,24 @@
 package games
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"maps"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/joho/godotenv"
-	"github.com/shirou/gopsutil/v4/process"
 	"github.com/wailsapp/wails/v3/pkg/application"
 
 	"github.com/rhythmerc/gentro-ui/services/config"
@@ -21,11 +26,11 @@ import (
 	"github.com/rhythmerc/gentro-ui/services/games/art"
 	"github.com/rhythmerc/gentro-ui/services/games/database"
 	"github.com/rhythmerc/gentro-ui/services/games/emulator"
+	"github.com/rhythmerc/gentro-ui/services/games/events"
 	"github.com/rhythmerc/gentro-ui/services/games/metadata"
 	"github.com/rhythmerc/gentro-ui/services/games/metadata/igdb"
+	"github.com/rhythmerc/gentro-ui/services/games/metadata/steamgriddb"
 	"github.com/rhythmerc/gentro-ui/services/games/models"
-	"github.com/rhythmerc/gentro-ui/services/games/sources/emulated"
-	"github.com/rhythmerc/gentro-ui/services/games/sources/steam"
 )
 
 // GamesService manages games from multiple sources
@@ -33,11 +38,49 @@ type GamesService struct {
 	db          *database.DB
 	registry    *SourceRegistry
 	fetcher     *metadata.Fetcher
+	igdb        *igdb.Resolver
 	emuService  *emulator.Service
 	config      *config.Manager
 	route       string
 	logger      *slog.Logger
 	artComposer *art.Composer
+	events      *events.Events
+	metrics     *metricsRecorder
+
+	// refreshMu guards refreshCancel, which aborts the running refresh and is
+	// nil when none is running
+	refreshMu     sync.Mutex
+	refreshCancel context.CancelFunc
+
+	// availabilityMu guards reportedAvailability, the emulator availability per
+	// platform last returned to the UI
+	availabilityMu       sync.Mutex
+	reportedAvailability map[string]bool
+
+	// saveSyncMu guards saveSyncer and saveSyncPlatforms, the platforms whose
+	// saves it syncs
+	saveSyncMu        sync.RWMutex
+	saveSyncer        SaveSyncer
+	saveSyncPlatforms []string
+
+	// heroVideoMu guards heroVideoFailures, when each instance's hero video
+	// lookup last failed, and videoDownloads, the hero video downloads in
+	// progress by destination path
+	heroVideoMu       sync.Mutex
+	heroVideoFailures map[string]time.Time
+	videoDownloads    map[string]*videoDownload
+
+	// apiMux routes the HTTP JSON API, built on first use
+	apiOnce sync.Once
+	apiMux  *http.ServeMux
+
+	// apiServerMu guards apiServer, the standalone API listener, nil unless
+	// the config gives the API an address
+	apiServerMu sync.Mutex
+	apiServer   *http.Server
+
+	// configErrors records config load failures for the UI
+	configErrors []string
 }
 
 // GamesServiceConfig holds service configuration
@@ -58,16 +101,21 @@ func NewGamesService(config GamesServiceConfig) (*GamesService, error) {
 		config.DatabasePath = filepath.Join(home, ".local", "share", "gentro", "database", "games.db")
 	}
 
-	// Ensure directories exist
-	if err := os.MkdirAll(filepath.Dir(config.DatabasePath), 0755); err != nil {
-		return nil, fmt.Errorf("failed to create database directory: %w", err)
-	}
-
-	// Initialize database
+	// Initialize database, creating its directory if needed
 	db, err := database.New(config.DatabasePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
 	}
+	if status := db.Status(); status.Degraded {
+		config.Logger.Error("database opened read-only after failed migration", "error", status.Error)
+	} else if status.RestoredFrom != "" {
+		config.Logger.Warn("database was corrupt and restored from backup", "backup", status.RestoredFrom, "error", status.Error)
+	} else if status.Recovered {
+		config.Logger.Warn("database rebuilt after it failed to open", "backup", status.BackupPath, "error", status.Error)
+	}
+	if status := db.Status(); status.BackupError != "" {
+		config.Logger.Warn("failed to back up database", "error", status.BackupError)
+	}
 
 	// Initialize source registry
 	registry := NewSourceRegistry()
@@ -90,67 +138,143 @@ func NewGamesService(config GamesServiceConfig) (*GamesService, error) {
 	// Register IGDB resolver if credentials are available
 	igdbClientID := os.Getenv("IGDB_CLIENT_ID")
 	igdbClientSecret := os.Getenv("IGDB_CLIENT_SECRET")
+	var igdbResolver *igdb.Resolver
 	if igdbClientID != "" && igdbClientSecret != "" {
-		igdbResolver := igdb.NewResolver(igdbClientID, igdbClientSecret, config.Logger)
+		igdbResolver = igdb.NewResolver(igdbClientID, igdbClientSecret, config.Logger)
 		fetcher.RegisterResolver(igdbResolver)
 		config.Logger.Info("registered IGDB metadata resolver")
 	} else {
 		config.Logger.Warn("IGDB credentials not found, skipping IGDB resolver")
 	}
 
+	// Register SteamGridDB after IGDB so its art is merged over IGDB's
+	if apiKey := os.Getenv("STEAMGRIDDB_API_KEY"); apiKey != "" {
+		fetcher.RegisterResolver(steamgriddb.NewResolver(apiKey, config.Logger))
+		config.Logger.Info("registered SteamGridDB art resolver")
+	}
+
 	// Create service instance
 	service := &GamesService{
 		db:          db,
 		registry:    registry,
 		fetcher:     fetcher,
+		igdb:        igdbResolver,
 		emuService:  emuService,
 		logger:      config.Logger,
 		artComposer: art.NewComposer(apppaths.ArtCache, config.Logger),
+		events:      events.NewEvents(config.Logger),
+		metrics:     newMetricsRecorder(),
 	}
 
-	// Set up metadata resolution callback
+	// Set up metadata resolution callbacks
 	fetcher.SetOnResolveCallback(service.onMetadataResolved)
+	fetcher.SetOnFailCallback(service.onMetadataFailed)
+
+	// Record a play session each time a running game stops
+	service.events.SetOnSessionEnded(service.onSessionEnded)
+
+	// Let the UI prompt for a new emulator when an assigned one is uninstalled
+	emuService.SetOnUnavailableCallback(service.events.EmitInstanceEmulatorsUnavailable)
+
+	// Keep sources' emulator availability current when emulators are rediscovered
+	emuService.SetOnDiscoveredCallback(service.onEmulatorsDiscovered)
 
 	return service, nil
 }
 
 // onMetadataResolved is called when metadata is successfully fetched from a resolver
 func (s *GamesService) onMetadataResolved(req models.FetchRequest, resolved models.ResolvedMetadata, resolverName string) {
+	// Art alone leaves the game's fields and cached metadata as they were
+	if !resolved.ArtOnly && !s.applyResolvedMetadata(req, resolved, resolverName) {
+		return
+	}
+
+	for artType, url := range resolved.ArtURLs {
+		artSource := resolverName
+		if name, ok := resolved.ArtSources[artType]; ok {
+			artSource = name
+		}
+		if err := s.db.StoreGameArt(req.GameID, artType, url, artSource); err != nil {
+			s.logger.Warn("failed to record art provenance", "error", err, "gameID", req.GameID, "artType", artType)
+		}
+	}
+
+	go func() {
+		s.downloadAndCacheArt(req.InstanceID, req.GameID, resolved.ArtURLs)
+
+		// Update instance status
+		completedAt := time.Now()
+		status := models.MetadataStatus{
+			State:       models.MetadataStateCompleted,
+			Message:     fmt.Sprintf("Resolved from %s", resolverName),
+			CompletedAt: &completedAt,
+		}
+
+		if err := s.db.UpdateInstanceMetadataStatus(req.InstanceID, status); err != nil {
+			s.logger.Warn("failed to update metadata status", "error", err)
+		}
+
+		// Emit update event
+		s.events.EmitMetadataStatus(req.InstanceID, req.GameID, status)
+	}()
+}
+
+// applyResolvedMetadata updates a game with a resolver's metadata, records which
+// fields it provided and caches the result. Reports false if the game couldn't be updated.
+func (s *GamesService) applyResolvedMetadata(req models.FetchRequest, resolved models.ResolvedMetadata, resolverName string) bool {
 	// Update game with resolved metadata
 	game, err := s.db.GetGame(req.GameID)
 	if err != nil {
 		s.logger.Error("failed to get game for metadata update", "error", err, "gameID", req.GameID)
-		return
+		return false
 	}
 	if game == nil {
 		s.logger.Error("game not found for metadata update", "gameID", req.GameID)
-		return
+		return false
 	}
 
-	// Update game fields
+	// Update game fields, tracking which ones this resolver provided
+	var fields []string
 	if resolved.GameMetadata.Name != "" {
 		game.Name = resolved.GameMetadata.Name
+		fields = append(fields, "name")
 	}
 	if resolved.GameMetadata.Description != "" {
 		game.Description = resolved.GameMetadata.Description
+		fields = append(fields, "description")
 	}
 	if resolved.GameMetadata.Developer != "" {
 		game.Developer = resolved.GameMetadata.Developer
+		fields = append(fields, "developer")
 	}
 	if resolved.GameMetadata.Publisher != "" {
 		game.Publisher = resolved.GameMetadata.Publisher
+		fields = append(fields, "publisher")
 	}
 	if resolved.GameMetadata.ReleaseDate != nil {
 		game.ReleaseDate = resolved.GameMetadata.ReleaseDate
+		fields = append(fields, "release_date")
+	}
+	if resolved.GameMetadata.AgeRating != "" {
+		game.AgeRating = resolved.GameMetadata.AgeRating
+		fields = append(fields, "age_rating")
 	}
 	if len(resolved.GameMetadata.Genres) > 0 {
-		game.Genres = resolved.GameMetadata.Genres
+		// The resolver's own names are kept in the external metadata cache below
+		game.Genres = s.normalizeGenres(resolved.GameMetadata.Genres)
+		fields = append(fields, "genres")
 	}
 	game.UpdatedAt = time.Now()
 
 	if err := s.db.UpdateGame(game); err != nil {
 		s.logger.Error("failed to update game with resolved metadata", "error", err)
-		return
+		return false
+	}
+
+	s.metrics.update(func(m *models.Metrics) { m.MetadataFetched++ })
+
+	if err := s.db.SetFieldSources(req.GameID, resolverName, fields); err != nil {
+		s.logger.Warn("failed to record metadata provenance", "error", err, "gameID", req.GameID)
 	}
 
 	// Store metadata in external_metadata table for caching
@@ -160,6 +284,7 @@ func (s *GamesService) onMetadataResolved(req models.FetchRequest, resolved mode
 		"developer":   resolved.GameMetadata.Developer,
 		"publisher":   resolved.GameMetadata.Publisher,
 		"genres":      resolved.GameMetadata.Genres,
+		"age_rating":  string(resolved.GameMetadata.AgeRating),
 		"resolver":    resolverName,
 	}
 	if resolved.GameMetadata.ReleaseDate != nil {
@@ -169,25 +294,18 @@ func (s *GamesService) onMetadataResolved(req models.FetchRequest, resolved mode
 	if err := s.db.StoreExternalMetadata(req.GameID, resolverName, metadataToCache); err != nil {
 		s.logger.Warn("failed to cache external metadata", "error", err)
 	}
+	return true
+}
 
-	go func() {
-		s.downloadAndCacheArt(req.InstanceID, req.GameID, resolved.ArtURLs)
-
-		// Update instance status
-		completedAt := time.Now()
-		status := models.MetadataStatus{
-			State:       models.MetadataStateCompleted,
-			Message:     fmt.Sprintf("Resolved from %s", resolverName),
-			CompletedAt: &completedAt,
-		}
-
-		if err := s.db.UpdateInstanceMetadataStatus(req.InstanceID, status); err != nil {
-			s.logger.Warn("failed to update metadata status", "error", err)
-		}
+// onMetadataFailed is called when no resolver could resolve a request
+func (s *GamesService) onMetadataFailed(req models.FetchRequest, sourcesTried []string) {
+	s.metrics.update(func(m *models.Metrics) { m.MetadataFailed++ })
+	s.metrics.countError(metricErrorMetadata)
 
-		// Emit update event
-		s.emitMetadataUpdate(req.InstanceID, req.GameID, status)
-	}()
+	// A failed refresh of stale metadata waits out the cooldown before the next try
+	if err := s.db.MarkExternalMetadataRefreshFailed(req.GameID, "igdb"); err != nil {
+		s.logger.Warn("failed to record metadata refresh failure", "error", err, "gameID", req.GameID)
+	}
 }
 
 // downloadAndCacheArt downloads and caches art images for a game
@@ -210,14 +328,27 @@ func (s *GamesService) downloadAndCacheArt(instanceID, gameID string, artURLs ma
 	source := instance.Source
 	s.logger.Info("downloading art", "instanceID", instanceID, "source", source, "artTypes", len(artURLs))
 
+	ctx := context.Background()
+
 	// Download all art types concurrently
-	artData := s.artComposer.DownloadAllArt(artURLs)
+	artData := s.artComposer.DownloadAllArt(ctx, artURLs)
+	s.metrics.update(func(m *models.Metrics) {
+		failed := int64(len(artURLs) - len(artData))
+		m.ArtDownloaded += int64(len(artData))
+		m.ArtFailed += failed
+		if failed > 0 {
+			m.Errors[metricErrorArtDownload] += failed
+		}
+	})
 
 	// Cache original art types
 	for artType, data := range artData {
 		if err := s.artComposer.CacheArt(source, instanceID, artType, data); err != nil {
 			s.logger.Warn("failed to cache art", "artType", artType, "error", err)
+			s.metrics.countError(metricErrorArtCache)
+			continue
 		}
+		s.events.EmitGameArtUpdated(instanceID, gameID, artType)
 	}
 
 	// Compose header image (screenshot + logo)
@@ -229,41 +360,160 @@ func (s *GamesService) downloadAndCacheArt(instanceID, gameID string, artURLs ma
 
 	if (!hasHeaderURL || headerURL == "") && (screenshotURL != "" || coverURL != "" || artworkURL != "") {
 		s.logger.Info("composing header", "instanceID", instanceID, "source", source)
-		headerData, err := s.artComposer.ComposeHeader(screenshotURL, logoURL, coverURL, artworkURL, gameID)
+		headerData, err := s.artComposer.ComposeHeader(ctx, screenshotURL, logoURL, coverURL, artworkURL, gameID)
 		if err != nil {
 			s.logger.Warn("failed to compose header", "error", err)
+			s.metrics.countError(metricErrorArtCompose)
 			// Update status to partial
 			status := models.MetadataStatus{
 				State:   models.MetadataStateError,
 				Message: "Metadata resolved, but art composition failed",
 			}
 			s.db.UpdateInstanceMetadataStatus(instanceID, status)
-			s.emitMetadataUpdate(instanceID, gameID, status)
+			s.events.EmitMetadataStatus(instanceID, gameID, status)
 		} else {
 			// Cache composed header
 			if err := s.artComposer.CacheArt(source, instanceID, "header", headerData); err != nil {
 				s.logger.Warn("failed to cache header", "error", err)
+			} else {
+				s.logger.Info("header composed and cached", "instanceID", instanceID, "source", source)
+				s.events.EmitGameArtUpdated(instanceID, gameID, "header")
 			}
-			s.logger.Info("header composed and cached", "instanceID", instanceID, "source", source)
+		}
+	}
+
+	// Compose portrait grid image (cover + logo), unless a ready-made grid was downloaded
+	if gridURL := artURLs["grid"]; coverURL != "" && gridURL == "" {
+		gridData, err := s.artComposer.ComposePortrait(ctx, coverURL, logoURL)
+		if err != nil {
+			s.logger.Warn("failed to compose grid", "error", err, "instanceID", instanceID)
+			s.metrics.countError(metricErrorArtCompose)
+		} else if err := s.artComposer.CacheArt(source, instanceID, "grid", gridData); err != nil {
+			s.logger.Warn("failed to cache grid", "error", err)
+		} else {
+			s.events.EmitGameArtUpdated(instanceID, gameID, "grid")
 		}
 	}
 }
 
-// ServiceStartup runs when the app starts
-func (s *GamesService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
-	// Set default route
-	s.route = "/games"
+// RecomposeHeaders rebuilds the cached headers of the given instances, or of every
+// instance when none are given, from their already-cached art using the current
+// composition options. Instances without cached background art are skipped.
+func (s *GamesService) RecomposeHeaders(instanceIDs []string) error {
+	var instances []models.GameInstance
+	if len(instanceIDs) == 0 {
+		all, err := s.db.GetInstances(models.GameFilter{})
+		if err != nil {
+			return fmt.Errorf("failed to get instances: %w", err)
+		}
+		instances = all
+	} else {
+		for _, id := range instanceIDs {
+			instance, err := s.db.GetInstance(id)
+			if err != nil {
+				return fmt.Errorf("failed to get instance: %w", err)
+			}
+			if instance == nil {
+				return fmt.Errorf("instance not found: %s", id)
+			}
+			instances = append(instances, *instance)
+		}
+	}
 
-	// Initialize config manager
-	configPath := config.DefaultConfigPath()
+	recomposed, failed := 0, 0
+	for _, instance := range instances {
+		if !s.hasCachedHeaderBackground(instance) {
+			continue
+		}
+		if err := s.artComposer.RecomposeHeader(instance.Source, instance.ID); err != nil {
+			s.logger.Warn("failed to recompose header", "error", err, "instanceID", instance.ID)
+			s.metrics.countError(metricErrorArtCompose)
+			failed++
+			continue
+		}
+		recomposed++
+		s.events.EmitGameArtUpdated(instance.ID, instance.GameID, "header")
+	}
+
+	s.logger.Info("recomposed headers", "recomposed", recomposed, "failed", failed)
+	if failed > 0 {
+		return fmt.Errorf("failed to recompose %d of %d headers", failed, recomposed+failed)
+	}
+	return nil
+}
+
+// hasCachedHeaderBackground reports whether an instance has cached art a header can be composed on
+func (s *GamesService) hasCachedHeaderBackground(instance models.GameInstance) bool {
+	for _, artType := range []string{"screenshot", "artwork", "cover"} {
+		if s.artComposer.HasCachedArt(instance.Source, instance.ID, artType) {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceInitTimeout bounds how long a single source may take to initialize at startup
+const sourceInitTimeout = 15 * time.Second
+
+// initConfig loads the config file. On failure the service runs on defaults
+// without a manager, so the user's file is never overwritten, and the error is
+// kept for GetConfigErrors.
+func (s *GamesService) initConfig(configPath string) {
 	s.logger.Info("Initializing config manager", "path", configPath)
 	cfgManager, err := config.NewManager(configPath)
 	if err != nil {
 		s.logger.Error("failed to initialize config manager", "error", err)
-		// Continue without config - we'll use defaults
-	} else {
-		s.config = cfgManager
+		s.configErrors = append(s.configErrors, fmt.Sprintf("%s: %v", configPath, err))
+		return
+	}
+
+	s.config = cfgManager
+	s.applyArtConfig(cfgManager.Get().Art)
+	s.applyMetadataConfig(cfgManager.Get().Metadata)
+	s.applySaveSyncConfig(cfgManager.Get().SaveSync)
+}
+
+// GetConfigErrors returns problems loading the config file. A non-empty result
+// means the user's settings were not applied and defaults are in use.
+func (s *GamesService) GetConfigErrors() []string {
+	if s.configErrors == nil {
+		return []string{}
+	}
+	return s.configErrors
+}
+
+// GetStatus reports startup problems: a rebuilt or read-only database and config errors
+func (s *GamesService) GetStatus() models.ServiceStatus {
+	return models.ServiceStatus{
+		Database:     s.db.Status(),
+		ConfigErrors: s.GetConfigErrors(),
 	}
+}
+
+// GetDatabaseStats returns library database counts, size, integrity and backup state
+func (s *GamesService) GetDatabaseStats() (models.DatabaseStats, error) {
+	return s.db.Stats()
+}
+
+// playtimeMetadataKeys are instance custom metadata keys holding minutes played
+var playtimeMetadataKeys = []string{"steam.playtime"}
+
+// lastPlayedMetadataKeys are instance custom metadata keys holding when a source
+// last saw the game played, in unix seconds
+var lastPlayedMetadataKeys = []string{"steam.lastPlayed"}
+
+// GetLibraryStats returns aggregate library totals for the stats dashboard
+func (s *GamesService) GetLibraryStats() (models.LibraryStats, error) {
+	return s.db.LibraryStats(playtimeMetadataKeys...)
+}
+
+// ServiceStartup runs when the app starts
+func (s *GamesService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	// Set default route
+	s.route = "/games"
+
+	// Initialize config manager
+	s.initConfig(config.DefaultConfigPath())
 
 	// Initialize emulators (seed defaults)
 	s.logger.Info("Initializing emulators")
@@ -277,32 +527,37 @@ func (s *GamesService) ServiceStartup(ctx context.Context, options application.S
 		s.logger.Error("failed to discover emulators", "error", err)
 	}
 
-	// Register default sources
-	emulatedSource := emulated.Source{
-		Logger:   s.logger,
-		ArtCache: filepath.Join(apppaths.ArtCache, "emulated"),
-	}
-
-	steamSource := steam.Source{
-		Logger:   s.logger,
-		ArtCache: filepath.Join(apppaths.ArtCache, "steam"),
+	// Register sources whose packages are imported
+	deps := SourceDeps{
+		Logger:          s.logger,
+		ArtCache:        apppaths.ArtCache,
+		EmulatorService: s.emuService,
+		Config:          s.config,
+		Events:          s.events,
 	}
+	for _, name := range RegisteredSources() {
+		source, _ := newRegisteredSource(name, deps)
 
-	if err := s.registry.Register(&emulatedSource); err != nil {
-		s.logger.Warn("failed to register emulated source", "error", err)
-	} else {
-		// Inject emulator service and logger into emulated source
-		emulatedSource.SetEmulatorService(s.emuService)
+		initCtx, cancel := context.WithTimeout(ctx, sourceInitTimeout)
+		err := s.registry.Register(initCtx, source)
+		cancel()
+		if err != nil {
+			s.logger.Warn("failed to register source", "source", name, "error", err)
+		}
 	}
 
-	if err := s.registry.Register(&steamSource); err != nil {
-		s.logger.Warn("failed to register steam source", "error", err)
-	}
+	// Restore persisted diagnostics counters
+	s.loadMetrics()
 
 	// Start metadata fetcher
 	s.fetcher.Start()
 
-	// Initial sync
+	// Serve the API on its own address if configured
+	if err := s.startAPIServer(); err != nil {
+		s.logger.Error("failed to start API server", "error", err)
+	}
+
+	// Initial sync; RefreshGames logs and emits its own summary
 	go s.RefreshGames()
 
 	return nil
@@ -310,7 +565,13 @@ func (s *GamesService) ServiceStartup(ctx context.Context, options application.S
 
 // ServiceShutdown runs when the app shuts down
 func (s *GamesService) ServiceShutdown(ctx context.Context) error {
+	if err := s.stopAPIServer(ctx); err != nil {
+		s.logger.Warn("failed to stop API server", "error", err)
+	}
 	s.fetcher.Stop()
+	if err := s.saveMetrics(); err != nil {
+		s.logger.Warn("failed to persist metrics", "error", err)
+	}
 	return s.db.Close()
 }
 
@@ -319,20 +580,14 @@ func (s *GamesService) GetGames(filter *models.GameFilter, sortOpts *models.Game
 	// Apply defaults if nil
 	effectiveFilter := filter
 	if effectiveFilter == nil {
-		effectiveFilter = &models.GameFilter{
-			InstalledOnly: false,
-			SourceFilters: map[string]map[string]any{
-				"steam": {"excludeTools": true},
-			},
-		}
+		defaultFilter := s.GetDefaultFilterConfig()
+		effectiveFilter = &defaultFilter
 	}
 
 	effectiveSort := sortOpts
 	if effectiveSort == nil {
-		effectiveSort = &models.GameSort{
-			Field: models.SortByName,
-			Order: models.SortOrderAsc,
-		}
+		defaultSort := s.GetDefaultSort()
+		effectiveSort = &defaultSort
 	}
 
 	// Get instances from database
@@ -343,6 +598,7 @@ func (s *GamesService) GetGames(filter *models.GameFilter, sortOpts *models.Game
 
 	// Apply source-specific filters
 	instances = s.applySourceFilters(instances, *effectiveFilter)
+	s.annotateEmulatorAvailability(instances)
 
 	// Build game map to avoid duplicates
 	gameMap := make(map[string]*models.Game)
@@ -376,6 +632,11 @@ func (s *GamesService) GetGames(filter *models.GameFilter, sortOpts *models.Game
 			continue
 		}
 
+		// Apply age rating filter
+		if maxRank := effectiveFilter.MaxAgeRating.Rank(); maxRank > 0 && game.AgeRating.Rank() > maxRank {
+			continue
+		}
+
 		// Apply genre filter
 		if len(effectiveFilter.Genres) > 0 {
 			// Check if game has any of the specified genres
@@ -405,6 +666,44 @@ func (s *GamesService) GetGames(filter *models.GameFilter, sortOpts *models.Game
 	return result, nil
 }
 
+// GetRecentlyAdded returns the most recently added instances with their games,
+// newest first, for a "new in your library" row
+func (s *GamesService) GetRecentlyAdded(limit int) ([]models.GameWithInstance, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive: %d", limit)
+	}
+
+	ids, err := s.db.GetRecentInstanceIDs(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]models.GameInstance, 0, len(ids))
+	for _, id := range ids {
+		instance, err := s.db.GetInstance(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get instance: %w", err)
+		}
+		if instance != nil {
+			instances = append(instances, *instance)
+		}
+	}
+	s.annotateEmulatorAvailability(instances)
+
+	result := make([]models.GameWithInstance, 0, len(instances))
+	for _, instance := range instances {
+		game, err := s.db.GetGame(instance.GameID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get game: %w", err)
+		}
+		if game == nil {
+			continue
+		}
+		result = append(result, models.GameWithInstance{Game: *game, Instance: instance})
+	}
+	return result, nil
+}
+
 // sortGames sorts games by the specified field and order
 func (s *GamesService) sortGames(games []models.GameWithInstance, sortOpts *models.GameSort) []models.GameWithInstance {
 	if sortOpts == nil || sortOpts.Field == "" {
@@ -421,6 +720,8 @@ func (s *GamesService) sortGames(games []models.GameWithInstance, sortOpts *mode
 			cmp = int(games[i].Instance.FileSize - games[j].Instance.FileSize)
 		case models.SortByDateAdded:
 			cmp = games[i].Instance.CreatedAt.Compare(games[j].Instance.CreatedAt)
+		case models.SortByLastPlayed:
+			cmp = compareLastPlayed(lastPlayed(games[i].Instance), lastPlayed(games[j].Instance))
 		default:
 			cmp = strings.Compare(strings.ToLower(games[i].Game.Name), strings.ToLower(games[j].Game.Name))
 		}
@@ -434,6 +735,37 @@ func (s *GamesService) sortGames(games []models.GameWithInstance, sortOpts *mode
 	return games
 }
 
+// lastPlayed returns the later of an instance's tracked last session and the
+// last play its source reported, or nil if neither has one. Games launched
+// outside the app only have the source's time.
+func lastPlayed(instance models.GameInstance) *time.Time {
+	latest := instance.LastPlayedAt
+	for _, key := range lastPlayedMetadataKeys {
+		value, _ := instance.CustomMetadata[key].(string)
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || seconds <= 0 {
+			continue
+		}
+		if at := time.Unix(seconds, 0); latest == nil || at.After(*latest) {
+			latest = &at
+		}
+	}
+	return latest
+}
+
+// compareLastPlayed orders last played times, with never played sorting oldest
+func compareLastPlayed(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	return a.Compare(*b)
+}
+
 // applySourceFilters groups instances by source and applies source-specific filters
 func (s *GamesService) applySourceFilters(instances []models.GameInstance, filter models.GameFilter) []models.GameInstance {
 	if len(instances) == 0 {
@@ -465,6 +797,66 @@ func (s *GamesService) applySourceFilters(instances []models.GameInstance, filte
 	return filteredInstances
 }
 
+// UpdateArtConfig saves art composition settings and applies them to future compositions
+func (s *GamesService) UpdateArtConfig(artConfig config.ArtConfig) error {
+	if s.config == nil {
+		return fmt.Errorf("config manager not initialized")
+	}
+
+	if err := s.config.SetArt(artConfig); err != nil {
+		return err
+	}
+	s.applyArtConfig(artConfig)
+	return nil
+}
+
+// applyArtConfig maps art settings onto the composer's options
+func (s *GamesService) applyArtConfig(artConfig config.ArtConfig) {
+	s.artComposer.SetOptions(art.ComposeOptions{
+		LogoAnchor:          art.LogoAnchor(artConfig.LogoAnchor),
+		LogoMaxWidthPercent: artConfig.LogoMaxWidthPercent,
+		LogoShadow:          artConfig.LogoShadow,
+		ScrimOpacity:        artConfig.ScrimOpacity,
+	})
+	s.artComposer.SetRetry(artConfig.DownloadAttempts, 0)
+}
+
+// UpdateMetadataConfig saves metadata settings and applies them to future fetches
+func (s *GamesService) UpdateMetadataConfig(metadataConfig config.MetadataConfig) error {
+	if s.config == nil {
+		return fmt.Errorf("config manager not initialized")
+	}
+
+	if err := s.config.SetMetadata(metadataConfig); err != nil {
+		return err
+	}
+	s.applyMetadataConfig(metadataConfig)
+	return nil
+}
+
+// applyMetadataConfig pushes metadata settings to the resolvers that cache them
+func (s *GamesService) applyMetadataConfig(metadataConfig config.MetadataConfig) {
+	if s.igdb != nil {
+		s.igdb.SetSearchUnmappedPlatforms(metadataConfig.SearchUnmappedPlatforms)
+	}
+}
+
+// igdbSearchLimit caps how many candidates SearchIGDB returns
+const igdbSearchLimit = 20
+
+// SearchIGDB searches IGDB by name so the user can pick the right game. An empty
+// or unmapped platform searches every platform.
+func (s *GamesService) SearchIGDB(query string, platform string) ([]models.IGDBResult, error) {
+	if s.igdb == nil {
+		return nil, fmt.Errorf("IGDB resolver not configured")
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return []models.IGDBResult{}, nil
+	}
+	return s.igdb.Search(query, platform, igdbSearchLimit)
+}
+
 // GetDefaultFilterConfig returns the default filter configuration from config
 func (s *GamesService) GetDefaultFilterConfig() models.GameFilter {
 	filter := models.GameFilter{
@@ -475,6 +867,7 @@ func (s *GamesService) GetDefaultFilterConfig() models.GameFilter {
 	// Add Steam filter defaults
 	if s.config != nil {
 		cfg := s.config.Get()
+		filter.InstalledOnly = cfg.Filters.DefaultInstalledOnly
 		filter.SourceFilters["steam"] = map[string]any{
 			"excludeTools": cfg.Filters.Steam.ExcludeTools,
 		}
@@ -494,13 +887,75 @@ func (s *GamesService) UpdateFilterConfig(steamExcludeTools bool) error {
 		return fmt.Errorf("config manager not initialized")
 	}
 
-	newFilters := config.FilterConfig{
-		Steam: config.SteamFilterConfig{
-			ExcludeTools: steamExcludeTools,
-		},
+	filters := s.config.Get().Filters
+	filters.Steam.ExcludeTools = steamExcludeTools
+
+	return s.config.SetFilters(filters)
+}
+
+// AddRomExtension recognizes an extra ROM file extension for an emulated
+// platform. It is saved to the config file and applies from the next refresh.
+func (s *GamesService) AddRomExtension(platform, ext string) error {
+	if s.config == nil {
+		return fmt.Errorf("config manager not initialized")
+	}
+
+	platform = strings.TrimSpace(platform)
+	ext = config.NormalizeExtension(ext)
+	if platform == "" || ext == "" {
+		return fmt.Errorf("platform and extension are required")
+	}
+
+	platforms := make(map[string]config.PlatformConfigOverride)
+	for id, override := range s.config.Get().Platforms {
+		override.Extensions = slices.Clone(override.Extensions)
+		platforms[id] = override
+	}
+
+	override := platforms[platform]
+	if slices.Contains(override.Extensions, ext) {
+		return nil
+	}
+	override.Extensions = append(override.Extensions, ext)
+	platforms[platform] = override
+
+	return s.config.SetPlatforms(platforms)
+}
+
+// GetDefaultSort returns the library sort used when GetGames is called without one
+func (s *GamesService) GetDefaultSort() models.GameSort {
+	sort := models.GameSort{Field: models.SortByName, Order: models.SortOrderAsc}
+	if s.config != nil {
+		if cfg := s.config.Get().Filters.DefaultSort; cfg.Field != "" {
+			sort = models.GameSort{Field: cfg.Field, Order: cfg.Order}
+		}
+	}
+	return sort
+}
+
+// SetDefaultView persists the filter and sort the library opens with
+func (s *GamesService) SetDefaultView(filter models.GameFilter, sort models.GameSort) error {
+	if s.config == nil {
+		return fmt.Errorf("config manager not initialized")
+	}
+
+	switch sort.Field {
+	case models.SortByName, models.SortByLastPlayed, models.SortByFileSize, models.SortByDateAdded:
+	default:
+		return fmt.Errorf("invalid sort field: %s", sort.Field)
+	}
+	if sort.Order != models.SortOrderAsc && sort.Order != models.SortOrderDesc {
+		return fmt.Errorf("invalid sort order: %s", sort.Order)
+	}
+
+	filters := s.config.Get().Filters
+	filters.DefaultInstalledOnly = filter.InstalledOnly
+	filters.DefaultSort = config.SortConfig{Field: sort.Field, Order: sort.Order}
+	if excludeTools, ok := filter.SourceFilters["steam"]["excludeTools"].(bool); ok {
+		filters.Steam.ExcludeTools = excludeTools
 	}
 
-	return s.config.SetFilters(newFilters)
+	return s.config.SetFilters(filters)
 }
 
 // GetGame returns a single game with all its instances
@@ -510,7 +965,7 @@ func (s *GamesService) GetGame(gameID string) (*models.Game, []models.GameInstan
 		return nil, nil, fmt.Errorf("failed to get game: %w", err)
 	}
 	if game == nil {
-		return nil, nil, fmt.Errorf("game not found: %s", gameID)
+		return nil, nil, fmt.Errorf("%w: %s", models.ErrGameNotFound, gameID)
 	}
 
 	// Get all instances for this game
@@ -526,159 +981,427 @@ func (s *GamesService) GetGame(gameID string) (*models.Game, []models.GameInstan
 			instances = append(instances, instance)
 		}
 	}
+	s.annotateEmulatorAvailability(instances)
 
 	return game, instances, nil
 }
 
-// RefreshGames rescans all sources and updates the database
-func (s *GamesService) RefreshGames() error {
+// GetInstanceFiles returns every file an instance is made of, the launched
+// file first followed by any disks, BIOS images or parent romset it uses
+func (s *GamesService) GetInstanceFiles(instanceID string) ([]models.InstanceFile, error) {
+	instance, err := s.db.GetInstance(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+	if instance == nil {
+		return nil, fmt.Errorf("instance not found: %s", instanceID)
+	}
+
+	files := []models.InstanceFile{}
+	if instance.Path != "" {
+		files = append(files, models.InstanceFile{Path: instance.Path, Role: models.FileRolePrimary})
+	}
+	return append(files, instance.Files...), nil
+}
+
+// RefreshGames rescans all sources and updates the database. Sources and
+// instances that fail are skipped and listed in the result's Errors; with
+// scan.failFast set, the refresh stops at the first failure and also returns
+// them as models.ScanErrors. CancelRefresh stops the scan between sources or
+// mid-source; sources already synced are kept and the result is marked
+// Cancelled. The result is emitted for the UI either way. Only one refresh
+// runs at a time; overlapping calls return models.ErrRefreshInProgress.
+func (s *GamesService) RefreshGames() (models.RefreshResult, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.refreshMu.Lock()
+	if s.refreshCancel != nil {
+		s.refreshMu.Unlock()
+		cancel()
+		s.logger.Info("skipping refresh, one is already running")
+		return models.RefreshResult{}, models.ErrRefreshInProgress
+	}
+	s.refreshCancel = cancel
+	s.refreshMu.Unlock()
+
 	s.logger.Info("refreshing games from all sources")
+	started := time.Now()
+	failFast := s.scanFailFast()
+	defer func() {
+		s.refreshMu.Lock()
+		s.refreshCancel = nil
+		s.refreshMu.Unlock()
+		cancel()
+	}()
 
+	result := models.RefreshResult{Errors: []models.ScanError{}}
+	aborted := false
 	for _, source := range s.registry.GetAll() {
+		if ctx.Err() != nil {
+			result.Cancelled = true
+			break
+		}
 		s.logger.Info("refreshing source", "source", source.Name())
 
-		instances, err := source.GetInstances(context.Background())
+		instances, err := source.GetInstances(ctx)
+		if ctx.Err() != nil {
+			// A partial scan would report unscanned instances as missing, so drop it
+			s.logger.Info("refresh cancelled", "source", source.Name())
+			result.Cancelled = true
+			break
+		}
 		if err != nil {
 			s.logger.Error("failed to get instances from source", "source", source.Name(), "error", err)
+			s.metrics.countError(metricErrorRefreshSource)
+			result.Errors = append(result.Errors, models.ScanError{Source: source.Name(), Message: err.Error()})
+			if aborted = failFast; aborted {
+				break
+			}
 			continue
 		}
+		s.metrics.update(func(m *models.Metrics) { m.GamesScanned += int64(len(instances)) })
 
-		for _, instance := range instances {
-			// Check if instance already exists
-			existing, err := s.db.GetInstance(instance.ID)
-			if err != nil {
-				s.logger.Error("failed to check existing instance", "error", err)
-				continue
+		synced, err := s.syncSourceInstances(source.Name(), instances)
+		result.Errors = append(result.Errors, synced.errors...)
+		if err != nil {
+			s.logger.Error("failed to sync source", "source", source.Name(), "error", err)
+			if len(synced.errors) == 0 {
+				result.Errors = append(result.Errors, models.ScanError{Source: source.Name(), Message: err.Error()})
 			}
+			if aborted = failFast; aborted {
+				break
+			}
+			continue
+		}
+		result.Added += synced.added
+		result.Updated += synced.updated
+		result.Relinked += synced.relinked
+		result.Missing += synced.missing
+
+		// Metadata fetches write to the database, so queue them once the batch is committed
+		for _, instance := range synced.toFetch {
+			s.queueMetadataFetch(instance)
+		}
 
-			if existing == nil {
-				// Check if game exists
-				game, err := s.db.GetGame(instance.GameID)
-				if err != nil {
-					s.logger.Error("failed to check game", "error", err)
-					continue
-				}
+		if prefetcher, ok := source.(ArtPrefetcher); ok {
+			go prefetcher.PrefetchArt(context.Background(), instances)
+		}
+	}
+	result.Duration = time.Since(started)
 
-				// Create game if not exists
-				if game == nil {
-					game = &models.Game{
-						ID:        instance.GameID,
-						Name:      s.getDisplayName(instance),
-						Platforms: []string{instance.Platform},
-						CreatedAt: time.Now(),
-						UpdatedAt: time.Now(),
-					}
-					if err := s.db.CreateGame(game); err != nil {
-						s.logger.Error("failed to create game", "error", err)
-						continue
-					}
-				}
+	s.logger.Info("game refresh complete",
+		"added", result.Added,
+		"updated", result.Updated,
+		"relinked", result.Relinked,
+		"missing", result.Missing,
+		"errors", len(result.Errors),
+		"cancelled", result.Cancelled,
+		"duration", result.Duration,
+	)
+	s.events.EmitRefreshResult(result)
 
-				// Create instance
-				if err := s.db.CreateInstance(&instance); err != nil {
-					s.logger.Error("failed to create instance", "error", err)
-					continue
-				}
+	if aborted {
+		return result, models.ScanErrors(result.Errors)
+	}
+	return result, nil
+}
 
-				// Queue metadata fetch
-				s.queueMetadataFetch(instance)
+// CancelRefresh aborts the running refresh, if any. It reports whether a
+// refresh was running.
+func (s *GamesService) CancelRefresh() bool {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
 
-				s.logger.Debug("added new instance", "id", instance.ID, "name", game.Name)
-			} else {
-				// Update existing instance
-				updated := false
-
-				// Sync CustomMetadata
-				if len(instance.CustomMetadata) > 0 {
-					// Check if metadata differs
-					needsUpdate := false
-					if existing.CustomMetadata == nil {
-						needsUpdate = true
-						s.logger.Debug("existing metadata is nil, will update",
-							"instanceID", instance.ID,
-							"platform", instance.Platform,
-						)
-					} else {
-						for key, value := range instance.CustomMetadata {
-							existingVal := existing.CustomMetadata[key]
-							if existingVal != value {
-								needsUpdate = true
-								s.logger.Debug("metadata value differs, will update",
-									"instanceID", instance.ID,
-									"platform", instance.Platform,
-									"key", key,
-									"existing", existingVal,
-									"new", value,
-								)
-								break
-							}
-						}
-					}
+	if s.refreshCancel == nil {
+		return false
+	}
+	s.logger.Info("cancelling game refresh")
+	s.refreshCancel()
+	return true
+}
 
-					if needsUpdate {
-						// Merge new metadata with existing
-						mergedMetadata := make(map[string]any)
-						for k, v := range existing.CustomMetadata {
-							mergedMetadata[k] = v
-						}
-						for k, v := range instance.CustomMetadata {
-							mergedMetadata[k] = v
-						}
-
-						if err := s.db.UpdateInstanceCustomMetadata(instance.ID, mergedMetadata); err != nil {
-							s.logger.Error("failed to update custom metadata", "error", err, "instanceID", instance.ID)
-						} else {
-							s.logger.Debug("updated custom metadata", "instanceID", instance.ID)
-							updated = true
-						}
-					}
-				}
+// scanFailFast reports whether a refresh should stop at the first error
+func (s *GamesService) scanFailFast() bool {
+	return s.config != nil && s.config.Get().Scan.FailFast
+}
 
-				// Update other instance fields if changed
-				if existing.InstallPath != instance.InstallPath ||
-					existing.FileSize != instance.FileSize ||
-					existing.Installed != instance.Installed {
-					existing.InstallPath = instance.InstallPath
-					existing.FileSize = instance.FileSize
-					existing.Installed = instance.Installed
-
-					if err := s.db.UpdateInstance(existing); err != nil {
-						s.logger.Error("failed to update instance", "error", err, "instanceID", instance.ID)
-					} else {
-						s.logger.Debug("updated instance fields", "instanceID", instance.ID)
-						updated = true
-					}
-				}
+// sourceSync is the outcome of syncing one source's scanned instances
+type sourceSync struct {
+	// toFetch are the instances that still need a metadata fetch
+	toFetch []models.GameInstance
+	// errors are the instances that failed and were skipped
+	errors []models.ScanError
+
+	added, updated int
+	// relinked counts stored instances whose file was found at a new path
+	relinked int
+	// missing counts stored instances the scan didn't find whose file is gone
+	missing int
+}
 
-				if updated {
-					s.logger.Info("synced instance changes", "instanceID", instance.ID, "source", source.Name())
-				}
+// syncSourceInstances writes a source's scanned instances to the database in a single batch.
+// Each instance is written inside its own savepoint so one failure doesn't discard the rest;
+// failed instances are reported in the result. With scan.failFast set, the first failure
+// aborts the batch and is returned as an error.
+func (s *GamesService) syncSourceInstances(sourceName string, instances []models.GameInstance) (sourceSync, error) {
+	var result sourceSync
 
-				// Check if metadata needs to be fetched for existing instances
-				if existing.MetadataStatus.State != models.MetadataStateCompleted {
-					s.logger.Debug("queueing metadata fetch for existing instance",
-						"instanceID", instance.ID,
-						"currentState", existing.MetadataStatus.State,
-					)
-					s.queueMetadataFetch(*existing)
-				}
+	knownGames, err := s.db.GetGameIDs()
+	if err != nil {
+		return result, fmt.Errorf("failed to load game IDs: %w", err)
+	}
+	unscanned, err := s.db.GetInstanceIDs(sourceName)
+	if err != nil {
+		return result, err
+	}
+	orphans, err := s.orphanedInstances(sourceName, instances)
+	if err != nil {
+		return result, err
+	}
+	// Completed instances are re-fetched once their cached metadata outlives the
+	// TTL, unless a refresh failed within the cooldown
+	fetchTimes, err := s.db.GetExternalMetadataFetchTimes("igdb")
+	if err != nil {
+		return result, err
+	}
+
+	batch, err := s.db.BeginBatch()
+	if err != nil {
+		return result, err
+	}
+	defer batch.Rollback()
+
+	failFast := s.scanFailFast()
+	// fail records an instance error and reports whether the batch should stop
+	fail := func(instanceID string, err error) bool {
+		result.errors = append(result.errors, models.ScanError{Source: sourceName, InstanceID: instanceID, Message: err.Error()})
+		return failFast || errors.Is(err, database.ErrSavepoint)
+	}
+
+	// Instances written earlier in this batch aren't visible to GetInstance until commit
+	added := make(map[string]bool)
+
+	for _, instance := range instances {
+		if added[instance.ID] {
+			s.logger.Warn("skipping duplicate instance in scan", "instanceID", instance.ID, "source", sourceName)
+			continue
+		}
+
+		// Check if instance already exists
+		existing, err := s.db.GetInstance(instance.ID)
+		if err != nil {
+			s.logger.Error("failed to check existing instance", "error", err, "instanceID", instance.ID)
+			if fail(instance.ID, err) {
+				return result, err
+			}
+			continue
+		}
+
+		// A file that moved takes over the instance left behind at its old path
+		relinked := false
+		if existing == nil {
+			if orphan, ok := takeOrphan(&orphans, instance); ok {
+				s.logger.Info("relinking moved instance",
+					"instanceID", orphan.ID,
+					"from", orphan.Path,
+					"to", instance.Path,
+					"source", sourceName,
+				)
+				instance = relinkTo(instance, orphan)
+				existing = orphan
+				relinked = true
+			}
+		}
+
+		delete(unscanned, instance.ID)
+
+		changed := false
+		err = batch.Savepoint(func() error {
+			if existing == nil {
+				return s.addInstance(batch, instance, knownGames)
 			}
+			var err error
+			changed, err = s.syncExistingInstance(batch, sourceName, instance, existing)
+			return err
+		})
+		if err != nil {
+			s.logger.Error("failed to sync instance", "error", err, "instanceID", instance.ID, "source", sourceName)
+			if fail(instance.ID, err) {
+				return result, err
+			}
+			continue
+		}
+
+		switch {
+		case existing == nil:
+			added[instance.ID] = true
+			knownGames[instance.GameID] = true
+			result.added++
+			result.toFetch = append(result.toFetch, instance)
+		case relinked:
+			result.relinked++
+		case changed:
+			result.updated++
+		}
+
+		if existing == nil {
+			continue
+		}
+		if existing.MetadataStatus.State != models.MetadataStateCompleted {
+			// Check if metadata needs to be fetched for existing instances
+			s.logger.Debug("queueing metadata fetch for existing instance",
+				"instanceID", instance.ID,
+				"currentState", existing.MetadataStatus.State,
+			)
+			result.toFetch = append(result.toFetch, *existing)
+		} else if times, ok := fetchTimes[existing.GameID]; ok && s.isMetadataStale(times.FetchedAt) &&
+			time.Since(times.RefreshFailedAt) > metadataRetryCooldown {
+			// queueMetadataFetch reapplies the cache and refreshes it in the background
+			s.logger.Debug("queueing metadata refresh for stale instance",
+				"instanceID", instance.ID,
+				"fetchedAt", times.FetchedAt,
+			)
+			result.toFetch = append(result.toFetch, *existing)
 		}
 	}
+	for id := range unscanned {
+		stored, err := s.db.GetInstance(id)
+		if err != nil {
+			return result, err
+		}
+		if stored != nil && !stillOnDisk(*stored) {
+			result.missing++
+		}
+	}
+
+	if err := batch.Commit(); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// addInstance creates a newly discovered instance, creating its game if needed
+func (s *GamesService) addInstance(batch *database.Batch, instance models.GameInstance, knownGames map[string]bool) error {
+	name := s.getDisplayName(instance)
+
+	// Create game if not exists
+	if !knownGames[instance.GameID] {
+		game := &models.Game{
+			ID:        instance.GameID,
+			Name:      name,
+			Platforms: []string{instance.Platform},
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		if err := batch.CreateGame(game); err != nil {
+			s.logger.Error("failed to create game", "error", err)
+			return err
+		}
+	}
+
+	// Create instance
+	if err := batch.CreateInstance(&instance); err != nil {
+		s.logger.Error("failed to create instance", "error", err)
+		return err
+	}
 
-	s.logger.Info("game refresh complete")
+	s.logger.Debug("added new instance", "id", instance.ID, "name", name)
 	return nil
 }
 
+// syncExistingInstance updates an existing instance with freshly scanned values
+// and reports whether anything changed
+func (s *GamesService) syncExistingInstance(batch *database.Batch, sourceName string, instance models.GameInstance, existing *models.GameInstance) (bool, error) {
+	updated := false
+
+	// Sync CustomMetadata, merging new values over existing ones
+	if customMetadataChanged(instance, existing) {
+		s.logger.Debug("custom metadata differs, will update",
+			"instanceID", instance.ID,
+			"platform", instance.Platform,
+		)
+
+		// Only the scanned keys are written, so keys the user set are kept
+		if err := batch.SetInstanceCustomMetadataKeys(instance.ID, existing.Version, instance.CustomMetadata); err != nil {
+			s.logger.Error("failed to update custom metadata", "error", err, "instanceID", instance.ID)
+			return false, err
+		}
+		existing.Version++
+		s.logger.Debug("updated custom metadata", "instanceID", instance.ID)
+		updated = true
+	}
+
+	// Update other instance fields if changed
+	if instanceFieldsChanged(instance, existing) {
+		existing.Path = instance.Path
+		existing.Filename = instance.Filename
+		existing.InstallPath = instance.InstallPath
+		existing.FileSize = instance.FileSize
+		existing.Installed = instance.Installed
+		existing.Files = instance.Files
+
+		if err := batch.UpdateInstance(existing); err != nil {
+			s.logger.Error("failed to update instance", "error", err, "instanceID", instance.ID)
+			return false, err
+		}
+		s.logger.Debug("updated instance fields", "instanceID", instance.ID)
+		updated = true
+	}
+
+	if updated {
+		s.logger.Info("synced instance changes", "instanceID", instance.ID, "source", sourceName)
+	}
+
+	return updated, nil
+}
+
+// customMetadataChanged reports whether scanned custom metadata differs from what is stored
+func customMetadataChanged(instance models.GameInstance, existing *models.GameInstance) bool {
+	if len(instance.CustomMetadata) == 0 {
+		return false
+	}
+	if existing.CustomMetadata == nil {
+		return true
+	}
+	for key, value := range instance.CustomMetadata {
+		if !metadataValueEqual(existing.CustomMetadata[key], value) {
+			return true
+		}
+	}
+	return false
+}
+
+// metadataValueEqual reports whether two custom metadata values are the same
+// once stored. Values are stored as JSON and read back untyped, so a scanned int
+// equals the float64 read back for it, and slices and maps compare by content.
+func metadataValueEqual(stored, scanned any) bool {
+	storedJSON, err := json.Marshal(stored)
+	if err != nil {
+		return false
+	}
+	scannedJSON, err := json.Marshal(scanned)
+	return err == nil && bytes.Equal(storedJSON, scannedJSON)
+}
+
+// instanceFieldsChanged reports whether scanned instance fields differ from what is stored
+func instanceFieldsChanged(instance models.GameInstance, existing *models.GameInstance) bool {
+	return existing.Path != instance.Path ||
+		existing.Filename != instance.Filename ||
+		existing.InstallPath != instance.InstallPath ||
+		existing.FileSize != instance.FileSize ||
+		existing.Installed != instance.Installed ||
+		!slices.Equal(existing.Files, instance.Files)
+}
+
 // RefreshSource rescans a specific source
-func (s *GamesService) RefreshSource(sourceName string) error {
+func (s *GamesService) RefreshSource(sourceName string) (models.RefreshResult, error) {
 	source, ok := s.registry.Get(sourceName)
 	if !ok {
-		return fmt.Errorf("source not found: %s", sourceName)
+		return models.RefreshResult{}, fmt.Errorf("source not found: %s", sourceName)
 	}
 
 	if err := source.Refresh(context.Background()); err != nil {
-		return fmt.Errorf("failed to refresh source: %w", err)
+		return models.RefreshResult{}, fmt.Errorf("failed to refresh source: %w", err)
 	}
 
 	return s.RefreshGames()
@@ -689,8 +1412,20 @@ func (s *GamesService) GetSources() []string {
 	return s.registry.GetNames()
 }
 
-// UpdateInstanceMetadata updates custom metadata for an instance
-func (s *GamesService) UpdateInstanceMetadata(instanceID string, updates map[string]any) error {
+// GetSourceCapabilities returns the supported operations of each registered source, keyed by name
+func (s *GamesService) GetSourceCapabilities() map[string]models.SourceCapabilities {
+	capabilities := make(map[string]models.SourceCapabilities)
+	for _, source := range s.registry.GetAll() {
+		capabilities[source.Name()] = source.Capabilities()
+	}
+	return capabilities
+}
+
+// UpdateInstanceMetadata updates custom metadata for an instance. expectedVersion
+// is the instance version the edit was made from; if the instance has changed
+// since, for example by a refresh, models.ErrVersionConflict is returned so the
+// UI can reload it and reapply the edit. 0 applies the edit to the current version.
+func (s *GamesService) UpdateInstanceMetadata(instanceID string, expectedVersion int64, updates map[string]any) error {
 	// Cancel any active fetch
 	s.fetcher.Cancel(instanceID)
 
@@ -702,28 +1437,65 @@ func (s *GamesService) UpdateInstanceMetadata(instanceID string, updates map[str
 	if instance == nil {
 		return fmt.Errorf("instance not found: %s", instanceID)
 	}
-
-	// Merge with existing custom metadata
-	if instance.CustomMetadata == nil {
-		instance.CustomMetadata = make(map[string]any)
+	if expectedVersion == 0 {
+		expectedVersion = instance.Version
 	}
 
-	maps.Copy(instance.CustomMetadata, updates)
-
-	// Update in database
-	if err := s.db.UpdateInstanceCustomMetadata(instanceID, instance.CustomMetadata); err != nil {
+	// Only the edited keys are written, so other keys are kept
+	if err := s.db.SetInstanceCustomMetadataKeys(instanceID, expectedVersion, updates); err != nil {
 		return fmt.Errorf("failed to update custom metadata: %w", err)
 	}
 
 	// Emit update event
-	s.emitMetadataUpdate(instanceID, instance.GameID, models.MetadataStatus{
+	s.events.EmitMetadataStatus(instanceID, instance.GameID, models.MetadataStatus{
 		State:   models.MetadataStateCompleted,
 		Message: "User edited",
 	})
+	s.events.EmitInstanceUpdated(instanceID, instance.GameID, slices.Sorted(maps.Keys(updates)))
 
 	return nil
 }
 
+// BulkSetMetadata sets one custom metadata key to the same value on many
+// instances, such as to tag a whole platform. All instances are updated in one
+// transaction, so if one is missing none are changed.
+func (s *GamesService) BulkSetMetadata(instanceIDs []string, key string, value any) error {
+	if key == "" {
+		return fmt.Errorf("metadata key is required")
+	}
+	if len(instanceIDs) == 0 {
+		return nil
+	}
+
+	if err := s.db.BulkSetInstanceCustomMetadata(instanceIDs, key, value); err != nil {
+		return fmt.Errorf("failed to update custom metadata: %w", err)
+	}
+
+	s.events.EmitLibraryUpdated(instanceIDs, []string{key})
+	return nil
+}
+
+// DeleteInstanceMetadataKey removes a custom metadata key from an instance.
+// expectedVersion works as in UpdateInstanceMetadata.
+func (s *GamesService) DeleteInstanceMetadataKey(instanceID string, expectedVersion int64, key string) error {
+	instance, err := s.db.GetInstance(instanceID)
+	if err != nil {
+		return fmt.Errorf("failed to get instance: %w", err)
+	}
+	if instance == nil {
+		return fmt.Errorf("instance not found: %s", instanceID)
+	}
+	if expectedVersion == 0 {
+		expectedVersion = instance.Version
+	}
+
+	if err := s.db.DeleteInstanceCustomMetadataKey(instanceID, expectedVersion, key); err != nil {
+		return fmt.Errorf("failed to delete custom metadata: %w", err)
+	}
+	s.events.EmitInstanceUpdated(instanceID, instance.GameID, []string{key})
+	return nil
+}
+
 // CancelMetadataFetch cancels an active metadata fetch
 func (s *GamesService) CancelMetadataFetch(instanceID string) error {
 	s.fetcher.Cancel(instanceID)
@@ -738,19 +1510,128 @@ func (s *GamesService) GetArtURL(instanceID string, artType string) (string, err
 	return fmt.Sprintf("%s/art/%s/%s", s.route, instanceID, artType), nil
 }
 
-// ServeHTTP implements http.Handler for serving game art
+// artURLTypes are the art types GetArtURLs reports, including the composed header and grid
+var artURLTypes = []string{"grid", "header", "cover", "hero", "logo", "icon", "screenshot", "artwork"}
+
+// GetArtURLs returns the URL of each art type the instance has, keyed by type.
+// Fallbacks are not applied, so the UI can pick the best type that really exists.
+// Sources that download art on demand, like Steam, report what they have cached
+// or know to be available; nothing is downloaded here.
+func (s *GamesService) GetArtURLs(instanceID string) (map[string]string, error) {
+	instance, err := s.db.GetInstance(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+	if instance == nil {
+		return nil, fmt.Errorf("instance not found: %s", instanceID)
+	}
+
+	source, ok := s.registry.Get(instance.Source)
+	if !ok {
+		return nil, fmt.Errorf("source not found: %s", instance.Source)
+	}
+
+	ctx := context.Background()
+	availability, onDemand := source.(ArtAvailability)
+	urls := make(map[string]string)
+	for _, artType := range artURLTypes {
+		if onDemand {
+			if !availability.HasGameArt(*instance, artType) {
+				continue
+			}
+		} else if _, err := s.getArt(ctx, source, *instance, artType); err != nil {
+			if !errors.Is(err, models.ErrArtNotFound) {
+				s.logger.Debug("failed to check art", "error", err, "instanceID", instanceID, "artType", artType)
+			}
+			continue
+		}
+		url, err := s.GetArtURL(instanceID, artType)
+		if err != nil {
+			return nil, err
+		}
+		urls[artType] = url
+	}
+
+	return urls, nil
+}
+
+// GetGalleryArtURLs returns URLs for an instance's indexed screenshots and artworks,
+// screenshots first, in resolver order. Each URL is served by ServeHTTP.
+func (s *GamesService) GetGalleryArtURLs(instanceID string) ([]string, error) {
+	instance, err := s.db.GetInstance(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+	if instance == nil {
+		return nil, fmt.Errorf("instance not found: %s", instanceID)
+	}
+
+	game, err := s.db.GetGame(instance.GameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game: %w", err)
+	}
+	if game == nil {
+		return nil, fmt.Errorf("game not found: %s", instance.GameID)
+	}
+
+	var urls []string
+	for _, base := range []string{"screenshot", "artwork"} {
+		var indexes []int
+		for artType := range game.ArtURLs {
+			prefix, index, ok := strings.Cut(artType, ".")
+			if !ok || prefix != base {
+				continue
+			}
+			if i, err := strconv.Atoi(index); err == nil {
+				indexes = append(indexes, i)
+			}
+		}
+		sort.Ints(indexes)
+
+		for _, i := range indexes {
+			url, err := s.GetArtURL(instanceID, fmt.Sprintf("%s.%d", base, i))
+			if err != nil {
+				return nil, err
+			}
+			urls = append(urls, url)
+		}
+	}
+
+	return urls, nil
+}
+
+// ServeHTTP implements http.Handler for serving game art and, when enabled, the
+// JSON API under /api/. Art is served as stored unless ?format=webp or the Accept
+// header asks for WebP.
 func (s *GamesService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// JSON API: /api/...
+	if strings.HasPrefix(r.URL.Path, apiPrefix) {
+		s.serveAPI(w, r)
+		return
+	}
+
 	// Parse URL: /art/{instanceID}/{artType}
 	path := strings.TrimPrefix(r.URL.Path, "/")
 	parts := strings.Split(path, "/")
 
-	if len(parts) < 3 {
+	if len(parts) != 3 {
 		http.Error(w, "Invalid URL", http.StatusBadRequest)
 		return
 	}
 
+	// Both segments end up in cache file paths, so reject anything that could escape them
 	instanceID := parts[1]
 	artType := parts[2]
+	if !validInstanceID(instanceID) || !validArtType(artType) {
+		http.Error(w, "Invalid URL", http.StatusBadRequest)
+		return
+	}
+
+	// Parse URL: /video/{instanceID}/hero
+	if parts[0] == "video" {
+		s.serveHeroVideo(w, r, instanceID)
+		return
+	}
 
 	// Get instance to find source
 	instance, err := s.db.GetInstance(instanceID)
@@ -770,16 +1651,137 @@ func (s *GamesService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get art from source
-	data, contentType, err := source.GetGameArt(r.Context(), instanceID, artType)
+	// Get art from source, falling back to related art types
+	found, err := s.getArtWithFallback(r.Context(), source, *instance, artType)
+	if errors.Is(err, models.ErrArtNotFound) {
+		s.servePlaceholder(w, r, *instance, artType)
+		return
+	}
 	if err != nil {
-		// If art not found, try to serve placeholder or 404
-		http.Error(w, "Art not found", http.StatusNotFound)
+		s.logger.Warn("failed to get art", "error", err, "instanceID", instanceID, "artType", artType)
+		http.Error(w, "Failed to get art", http.StatusInternalServerError)
 		return
 	}
 
-	w.Header().Set("Content-Type", contentType)
-	w.Write(data)
+	w.Header().Set("X-Art-Type", found.ArtType)
+	if format, explicit := artFormat(r); format == "webp" && s.serveArtWebP(w, r, *instance, found, explicit) {
+		return
+	}
+	if found.Path != "" {
+		s.serveArtFile(w, r, found.Path)
+		return
+	}
+
+	// In-memory art has no modification time, but ServeContent still handles ranges
+	w.Header().Set("Content-Type", found.ContentType)
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(found.Data))
+}
+
+// servePlaceholder serves stand-in art for an instance with none, drawn from the
+// game's name unless name placeholders are disabled
+func (s *GamesService) servePlaceholder(w http.ResponseWriter, r *http.Request, instance models.GameInstance, artType string) {
+	data := art.Placeholder()
+	if s.config == nil || s.config.Get().Art.NamePlaceholders {
+		name := instance.GameID
+		if game, err := s.db.GetGame(instance.GameID); err == nil && game != nil && game.Name != "" {
+			name = game.Name
+		}
+		width, height := art.PlaceholderSize(artType)
+		data = art.GeneratePlaceholder(name, width, height)
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("X-Art-Placeholder", "true")
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+}
+
+// serveArtFile streams an art file from disk. ServeContent sets the content type
+// from the extension and handles range and conditional requests.
+func (s *GamesService) serveArtFile(w http.ResponseWriter, r *http.Request, path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		s.logger.Warn("failed to open art file", "error", err, "path", path)
+		http.Error(w, "Failed to get art", http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		http.Error(w, "Failed to get art", http.StatusInternalServerError)
+		return
+	}
+
+	http.ServeContent(w, r, filepath.Base(path), info.ModTime(), file)
+}
+
+// artFormat picks the encoding art is served in. An explicit ?format wins;
+// otherwise WebP is negotiated from the Accept header. PNG or JPEG as stored
+// is the default.
+func artFormat(r *http.Request) (format string, explicit bool) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return strings.ToLower(format), true
+	}
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(accepted, ";")
+		if strings.TrimSpace(mediaType) == "image/webp" {
+			return "webp", false
+		}
+	}
+	return "", false
+}
+
+// serveArtWebP serves art transcoded to WebP, caching the result for art files.
+// A negotiated WebP copy is only served when it's smaller than the original,
+// which lossless WebP isn't for most JPEGs. Returns false to serve the original.
+func (s *GamesService) serveArtWebP(w http.ResponseWriter, r *http.Request, instance models.GameInstance, found resolvedArt, explicit bool) bool {
+	if !explicit {
+		w.Header().Add("Vary", "Accept")
+	}
+
+	original := int64(len(found.Data))
+	var data []byte
+	var path string
+	if found.Path != "" {
+		if s.artComposer == nil {
+			return false
+		}
+		info, err := os.Stat(found.Path)
+		if err != nil {
+			return false
+		}
+		original = info.Size()
+
+		path, err = s.artComposer.CachedWebP(found.Path, instance.Source, instance.ID, found.ArtType)
+		if err != nil {
+			s.logger.Debug("failed to transcode art to webp", "error", err, "instanceID", instance.ID, "artType", found.ArtType)
+			return false
+		}
+		if info, err = os.Stat(path); err != nil {
+			return false
+		}
+		if !explicit && info.Size() >= original {
+			return false
+		}
+	} else {
+		var err error
+		data, err = art.EncodeWebP(found.Data)
+		if err != nil {
+			s.logger.Debug("failed to transcode art to webp", "error", err, "instanceID", instance.ID, "artType", found.ArtType)
+			return false
+		}
+		if !explicit && int64(len(data)) >= original {
+			return false
+		}
+	}
+
+	w.Header().Set("Content-Type", "image/webp")
+	if path != "" {
+		s.serveArtFile(w, r, path)
+	} else {
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+	}
+	return true
 }
 
 // Helper functions
@@ -805,51 +1807,158 @@ func (s *GamesService) updateGameName(gameID string, name string) error {
 }
 
 // applyCachedMetadata applies cached external metadata to a game
-func (s *GamesService) applyCachedMetadata(instance models.GameInstance, cachedData map[string]any) error {
-	game, err := s.db.GetGame(instance.GameID)
+func (s *GamesService) applyCachedMetadata(instance models.GameInstance, source string, cachedData map[string]any) error {
+	if err := s.applyCachedGameMetadata(instance.GameID, source, cachedData); err != nil {
+		return err
+	}
+
+	// Update instance status
+	completedAt := time.Now()
+	s.db.UpdateInstanceMetadataStatus(instance.ID, models.MetadataStatus{
+		State:       models.MetadataStateCompleted,
+		Message:     "Using cached metadata",
+		CompletedAt: &completedAt,
+	})
+
+	// Emit completion event
+	s.events.EmitMetadataStatus(instance.ID, instance.GameID, models.MetadataStatus{
+		State:       models.MetadataStateCompleted,
+		Message:     "Using cached metadata",
+		CompletedAt: &completedAt,
+	})
+
+	return nil
+}
+
+// applyCachedGameMetadata applies a cached external metadata blob to the game record
+func (s *GamesService) applyCachedGameMetadata(gameID, source string, cachedData map[string]any) error {
+	game, err := s.db.GetGame(gameID)
 	if err != nil {
 		return fmt.Errorf("failed to get game: %w", err)
 	}
 	if game == nil {
-		return fmt.Errorf("game not found: %s", instance.GameID)
+		return fmt.Errorf("game not found: %s", gameID)
 	}
 
-	// Apply cached data
-	if name, ok := cachedData["name"].(string); ok && name != "" {
+	fields := s.applyMetadataFields(game, cachedData, nil)
+	game.UpdatedAt = time.Now()
+
+	if err := s.db.UpdateGame(game); err != nil {
+		return fmt.Errorf("failed to update game with cached metadata: %w", err)
+	}
+
+	if err := s.db.SetFieldSources(gameID, source, fields); err != nil {
+		s.logger.Warn("failed to record metadata provenance", "error", err, "gameID", gameID)
+	}
+	return nil
+}
+
+// GetMetadataSources lists the external sources with cached metadata for a game
+func (s *GamesService) GetMetadataSources(gameID string) ([]string, error) {
+	return s.db.GetExternalMetadataSources(gameID)
+}
+
+// GetRawMetadata returns the full cached external metadata blob for a game from source,
+// including fields not mapped onto Game. Returns nil if nothing is cached.
+func (s *GamesService) GetRawMetadata(gameID, source string) (map[string]any, error) {
+	return s.db.GetExternalMetadata(gameID, source)
+}
+
+// ApplyMetadataFromSource replaces a game's metadata with the blob cached from source
+func (s *GamesService) ApplyMetadataFromSource(gameID, source string) error {
+	cachedData, err := s.db.GetExternalMetadata(gameID, source)
+	if err != nil {
+		return err
+	}
+	if cachedData == nil {
+		return fmt.Errorf("no cached metadata from %s for game %s", source, gameID)
+	}
+
+	return s.applyCachedGameMetadata(gameID, source, cachedData)
+}
+
+// applyMetadataFields copies fields from a cached external metadata blob onto game.
+// If only is non-nil, just those fields are considered. Fields the source left
+// empty are skipped. Returns the fields applied.
+func (s *GamesService) applyMetadataFields(game *models.Game, data map[string]any, only map[string]bool) []string {
+	var applied []string
+	want := func(field string) bool { return only == nil || only[field] }
+
+	if name, ok := data["name"].(string); ok && name != "" && want("name") {
 		game.Name = name
+		applied = append(applied, "name")
 	}
-	if description, ok := cachedData["description"].(string); ok {
+	if description, ok := data["description"].(string); ok && description != "" && want("description") {
 		game.Description = description
+		applied = append(applied, "description")
 	}
-	if developer, ok := cachedData["developer"].(string); ok {
+	if developer, ok := data["developer"].(string); ok && developer != "" && want("developer") {
 		game.Developer = developer
+		applied = append(applied, "developer")
 	}
-	if publisher, ok := cachedData["publisher"].(string); ok {
+	if publisher, ok := data["publisher"].(string); ok && publisher != "" && want("publisher") {
 		game.Publisher = publisher
+		applied = append(applied, "publisher")
+	}
+	if ageRating, ok := data["age_rating"].(string); ok && ageRating != "" && want("age_rating") {
+		game.AgeRating = models.AgeRating(ageRating)
+		applied = append(applied, "age_rating")
+	}
+	// JSON numbers decode as float64
+	if releaseDate, ok := data["release_date"].(float64); ok && want("release_date") {
+		t := time.Unix(int64(releaseDate), 0)
+		game.ReleaseDate = &t
+		applied = append(applied, "release_date")
+	}
+	// The cache keeps the resolver's own genre names, decoded as []any
+	if cached, ok := data["genres"].([]any); ok && want("genres") {
+		genres := make([]string, 0, len(cached))
+		for _, genre := range cached {
+			if name, ok := genre.(string); ok {
+				genres = append(genres, name)
+			}
+		}
+		if genres = s.normalizeGenres(genres); len(genres) > 0 {
+			game.Genres = genres
+			applied = append(applied, "genres")
+		}
 	}
 
-	game.UpdatedAt = time.Now()
+	return applied
+}
 
-	if err := s.db.UpdateGame(game); err != nil {
-		return fmt.Errorf("failed to update game with cached metadata: %w", err)
+// GetMetadataProvenance returns which source provided each field of a game
+func (s *GamesService) GetMetadataProvenance(gameID string) (map[string]models.FieldSource, error) {
+	return s.db.GetFieldSources(gameID)
+}
+
+// RevertMetadataField replaces a single game field with the value cached from another source
+func (s *GamesService) RevertMetadataField(gameID, field, source string) error {
+	game, err := s.db.GetGame(gameID)
+	if err != nil {
+		return fmt.Errorf("failed to get game: %w", err)
+	}
+	if game == nil {
+		return fmt.Errorf("game not found: %s", gameID)
 	}
 
-	// Update instance status
-	completedAt := time.Now()
-	s.db.UpdateInstanceMetadataStatus(instance.ID, models.MetadataStatus{
-		State:       models.MetadataStateCompleted,
-		Message:     "Using cached metadata",
-		CompletedAt: &completedAt,
-	})
+	cachedData, err := s.db.GetExternalMetadata(gameID, source)
+	if err != nil {
+		return err
+	}
+	if cachedData == nil {
+		return fmt.Errorf("no cached metadata from %s for game %s", source, gameID)
+	}
 
-	// Emit completion event
-	s.emitMetadataUpdate(instance.ID, instance.GameID, models.MetadataStatus{
-		State:       models.MetadataStateCompleted,
-		Message:     "Using cached metadata",
-		CompletedAt: &completedAt,
-	})
+	if len(s.applyMetadataFields(game, cachedData, map[string]bool{field: true})) == 0 {
+		return fmt.Errorf("source %s has no value for field %s", source, field)
+	}
+	game.UpdatedAt = time.Now()
 
-	return nil
+	if err := s.db.UpdateGame(game); err != nil {
+		return fmt.Errorf("failed to update game: %w", err)
+	}
+	return s.db.SetFieldSources(gameID, source, []string{field})
 }
 
 func (s *GamesService) getDisplayName(instance models.GameInstance) string {
@@ -885,33 +1994,42 @@ func (s *GamesService) queueMetadataFetch(instance models.GameInstance) {
 		s.logger.Warn("failed to update game name", "error", err, "gameID", instance.GameID)
 	}
 
+	req := models.FetchRequest{
+		GameID:     instance.GameID,
+		InstanceID: instance.ID,
+		Priority:   1,
+		Platforms:  []string{instance.Platform},
+		Name:       displayName,
+		FileHash:   instance.FileHash,
+		Source:     instance.Source,
+		Platform:   instance.Platform,
+	}
+	if region, ok := instance.SourceData["region"].(string); ok {
+		req.Region = region
+	}
+
 	// Check if we already have cached IGDB metadata for this game
-	cachedMetadata, err := s.db.GetExternalMetadata(instance.GameID, "igdb")
+	cachedMetadata, fetchedAt, err := s.db.GetExternalMetadataWithTime(instance.GameID, "igdb")
 	if err != nil {
 		s.logger.Warn("failed to check cached metadata", "error", err)
 	} else if cachedMetadata != nil {
 		// Apply cached metadata synchronously
 		s.logger.Debug("applying cached IGDB metadata", "gameID", instance.GameID)
-		if err := s.applyCachedMetadata(instance, cachedMetadata); err != nil {
+		if err := s.applyCachedMetadata(instance, "igdb", cachedMetadata); err != nil {
 			s.logger.Warn("failed to apply cached metadata", "error", err)
 		} else {
-			// Skip network fetch - we have cached data
+			// Stale data stays visible while a fresh copy is fetched in the background
+			if s.isMetadataStale(fetchedAt) {
+				s.logger.Debug("cached metadata is stale, refreshing", "gameID", instance.GameID, "fetchedAt", fetchedAt)
+				if err := s.fetcher.Queue(req); err != nil {
+					s.logger.Error("failed to queue metadata refresh", "error", err)
+				}
+			}
 			return
 		}
 	}
 
 	// No cache hit - queue for async fetch
-	req := models.FetchRequest{
-		GameID:     instance.GameID,
-		InstanceID: instance.ID,
-		Priority:   1,
-		Platforms:  []string{instance.Platform},
-		Name:       displayName,
-		FileHash:   instance.FileHash,
-		Source:     instance.Source,
-		Platform:   instance.Platform,
-	}
-
 	// Update status
 	s.db.UpdateInstanceMetadataStatus(instance.ID, models.MetadataStatus{
 		State:     models.MetadataStateFetching,
@@ -920,7 +2038,7 @@ func (s *GamesService) queueMetadataFetch(instance models.GameInstance) {
 	})
 
 	// Emit status update
-	s.emitMetadataUpdate(instance.ID, instance.GameID, models.MetadataStatus{
+	s.events.EmitMetadataStatus(instance.ID, instance.GameID, models.MetadataStatus{
 		State:     models.MetadataStateFetching,
 		Message:   "Fetching metadata from IGDB...",
 		StartedAt: func() *time.Time { t := time.Now(); return &t }(),
@@ -932,16 +2050,20 @@ func (s *GamesService) queueMetadataFetch(instance models.GameInstance) {
 	}
 }
 
-func (s *GamesService) emitMetadataUpdate(instanceID, gameID string, status models.MetadataStatus) {
-	app := application.Get()
-	if app != nil {
-		update := models.MetadataStatusUpdate{
-			InstanceID: instanceID,
-			GameID:     gameID,
-			Status:     status,
-		}
-		app.Event.Emit("metadata:status-update", update)
+// metadataRetryCooldown is how long after a failed refresh stale cached metadata
+// waits before it's fetched again
+const metadataRetryCooldown = 24 * time.Hour
+
+// isMetadataStale reports whether cached metadata fetched at fetchedAt has outlived the configured TTL
+func (s *GamesService) isMetadataStale(fetchedAt time.Time) bool {
+	ttlDays := config.DefaultMetadataCacheTTLDays
+	if s.config != nil {
+		ttlDays = s.config.Get().Metadata.CacheTTLDays
+	}
+	if ttlDays <= 0 {
+		return false
 	}
+	return time.Since(fetchedAt) > time.Duration(ttlDays)*24*time.Hour
 }
 
 // Launch starts a game instance and monitors its process
@@ -963,14 +2085,16 @@ func (s *GamesService) Launch(instanceID string) error {
 
 	// Emit launching event immediately
 	s.logger.Info("emitting launching event")
-	s.emitLaunchStatus(instance.ID, instance.GameID, models.LaunchStatusLaunching, "")
+	s.events.EmitLaunchStatus(instance.ID, instance.GameID, models.LaunchStatusLaunching, "")
 
 	// Get source
 	source, ok := s.registry.Get(instance.Source)
 	if !ok {
 		s.logger.Error("unknown source", "source", instance.Source)
-		s.emitLaunchStatus(instance.ID, instance.GameID, models.LaunchStatusFailed, "unknown source: "+instance.Source)
-		return fmt.Errorf("unknown source: %s", instance.Source)
+		err := fmt.Errorf("unknown source: %s", instance.Source)
+		s.events.EmitLaunchError(instance.ID, instance.GameID, models.LaunchErrorUnknownSource, err)
+		s.metrics.countLaunchFailure(models.LaunchErrorUnknownSource)
+		return err
 	}
 
 	s.logger.Info("starting async launch", "source", source.Name())
@@ -979,116 +2103,52 @@ func (s *GamesService) Launch(instanceID string) error {
 	go func() {
 		ctx := context.Background()
 
+		s.pullSaves(*instance)
+
 		// Call source launch
 		s.logger.Info("calling source.Launch")
 		cmd, err := source.Launch(ctx, *instance)
 		if err != nil {
 			s.logger.Error("source.Launch failed", "error", err)
-			s.emitLaunchStatus(instance.ID, instance.GameID, models.LaunchStatusFailed, err.Error())
+			s.events.EmitLaunchError(instance.ID, instance.GameID, models.LaunchErrorCodeOf(err), err)
+			s.metrics.countLaunchFailure(models.LaunchErrorCodeOf(err))
 			return
 		}
+		s.metrics.update(func(m *models.Metrics) { m.Launches++ })
 
 		s.logger.Info("source.Launch succeeded, starting process monitoring")
 
-		// Emit "running" status immediately for emulated games
-		// (Steam games emit "running" via activity-based detection in monitorGameProcess)
-		if instance.Source == "emulated" {
-			s.emitLaunchStatus(instance.ID, instance.GameID, models.LaunchStatusRunning, "")
-		}
-
-		// Source-specific process monitoring
-		// - Emulated: Uses Wait() for immediate exit detection
-		// - Steam: Uses activity-based polling (falls back to monitorGameProcess)
+		// Sources own the running and stopped events, since only they know when the game starts:
+		// - Emulated: emits running once the process starts and uses Wait() for exit detection
+		// - Steam: uses activity-based polling
 		source.MonitorProcess(ctx, *instance, cmd)
 	}()
 
 	return nil
 }
 
-// monitorGameProcess monitors the game directory for running executables
-func (s *GamesService) monitorGameProcess(instance *models.GameInstance) {
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
-
-	const stopThreshold = 10 * time.Second
-	var lastSeenRunning time.Time
-	hasBeenRunning := false
-
-	for range ticker.C {
-		running, err := s.isProcessRunningInPath(instance.InstallPath)
-		if err != nil {
-			s.logger.Error("failed to check process status", "error", err)
-			continue
-		}
-
-		if running {
-			// Emit running on first detection
-			if !hasBeenRunning {
-				s.emitLaunchStatus(instance.ID, instance.GameID, models.LaunchStatusRunning, "")
-				hasBeenRunning = true
-			}
-			lastSeenRunning = time.Now()
-		} else if hasBeenRunning && time.Since(lastSeenRunning) > stopThreshold {
-			// Emit stopped after threshold
-			s.emitLaunchStatus(instance.ID, instance.GameID, models.LaunchStatusStopped, "")
-			return
-		}
-	}
-}
-
-// normalizeWinePath converts Wine/Proton paths to Linux format
-// Handles paths like "Z:\home\user\..." -> "/home/user/..."
-func normalizeWinePath(path string) string {
-	// Handle Wine/Proton paths with drive letter (e.g., "Z:\home\user\...")
-	if len(path) > 2 && path[1] == ':' {
-		// Remove drive letter and colon (e.g., "Z:")
-		path = path[2:]
+// GetRunningInstances returns the IDs of instances that are currently running,
+// so the UI can restore run state after a reload
+func (s *GamesService) GetRunningInstances() ([]string, error) {
+	running := s.events.RunningInstances()
+	if running == nil {
+		running = []string{}
 	}
-	// Convert Windows backslashes to Unix forward slashes
-	return strings.ReplaceAll(path, `\`, `/`)
+	return running, nil
 }
 
-// isProcessRunningInPath checks if any process executable is within the install path
-func (s *GamesService) isProcessRunningInPath(installPath string) (bool, error) {
-	processes, err := process.Processes()
-	if err != nil {
-		return false, err
-	}
-	for _, p := range processes {
-		// Check exe first (native Linux format)
-		exe, err := p.Exe()
-		if err == nil && strings.HasPrefix(exe, installPath) {
-			return true, nil
-		}
-		// Check cmdline for Wine/Proton paths
-		cmdline, err := p.Cmdline()
-		if err == nil {
-			normalizedCmdline := normalizeWinePath(cmdline)
-			if strings.Contains(normalizedCmdline, installPath) {
-				return true, nil
-			}
-		}
-	}
-	return false, nil
+// GetSetting returns a persisted UI preference, or "" if it has never been set
+func (s *GamesService) GetSetting(key string) (string, error) {
+	value, _, err := s.db.GetSetting(key)
+	return value, err
 }
 
-// emitLaunchStatus emits a launch status update event
-func (s *GamesService) emitLaunchStatus(instanceID, gameID string, status models.LaunchStatus, errMsg string) {
-	app := application.Get()
-	if app == nil {
-		s.logger.Error("cannot emit launch status: app not available", "instanceID", instanceID, "status", status)
-		return
-	}
-
-	update := models.LaunchStatusUpdate{
-		InstanceID: instanceID,
-		GameID:     gameID,
-		Status:     status,
-		Error:      errMsg,
+// SetSetting persists a UI preference that doesn't belong in the typed config
+func (s *GamesService) SetSetting(key, value string) error {
+	if key == "" {
+		return fmt.Errorf("setting key is required")
 	}
-
-	s.logger.Info("emitting launch status update", "instanceID", instanceID, "gameID", gameID, "status", status)
-	app.Event.Emit("launchStatusUpdate", update)
+	return s.db.SetSetting(key, value)
 }
 
 // Emulator API methods for Wails bindings
@@ -1108,9 +2168,98 @@ func (s *GamesService) SetPlatformDefaultEmulator(platform, emulatorID, coreID s
 	return s.emuService.SetPlatformDefault(platform, emulatorID, coreID)
 }
 
+// AssignEmulatorToPlatform adds an emulator, or one of its cores, to a platform's
+// choices without changing its SupportedPlatforms
+func (s *GamesService) AssignEmulatorToPlatform(platform, emulatorID, coreID string) error {
+	return s.emuService.AssignEmulatorToPlatform(platform, emulatorID, coreID)
+}
+
 // SetInstanceEmulator sets the emulator for a specific game instance
 func (s *GamesService) SetInstanceEmulator(instanceID, emulatorID, coreID string) error {
-	return s.emuService.SetInstanceEmulator(instanceID, emulatorID, coreID, "")
+	if err := s.emuService.SetInstanceEmulator(instanceID, emulatorID, coreID, ""); err != nil {
+		return err
+	}
+	s.emitInstanceUpdated(instanceID, "emulator")
+	return nil
+}
+
+// GetResolvedEmulator reports which emulator and core an instance will launch with,
+// which rule chose them, and whether they're available
+func (s *GamesService) GetResolvedEmulator(instanceID string) (models.ResolvedEmulatorInfo, error) {
+	instance, err := s.db.GetInstance(instanceID)
+	if err != nil {
+		return models.ResolvedEmulatorInfo{}, fmt.Errorf("failed to get instance: %w", err)
+	}
+	if instance == nil {
+		return models.ResolvedEmulatorInfo{}, fmt.Errorf("instance not found: %s", instanceID)
+	}
+	return s.emuService.ExplainResolution(*instance), nil
+}
+
+// GetSaveLocations returns where an instance's emulator keeps its saves and
+// memory cards, for backing them up
+func (s *GamesService) GetSaveLocations(instanceID string) ([]models.SaveLocation, error) {
+	instance, err := s.db.GetInstance(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+	if instance == nil {
+		return nil, fmt.Errorf("instance not found: %s", instanceID)
+	}
+	return s.saveLocations(*instance)
+}
+
+// saveLocations looks up where an instance's emulator keeps its saves
+func (s *GamesService) saveLocations(instance models.GameInstance) ([]models.SaveLocation, error) {
+	if s.emuService == nil {
+		return nil, fmt.Errorf("%w for platform %s", models.ErrEmulatorNotConfigured, instance.Platform)
+	}
+	return s.emuService.GetSaveLocations(instance)
+}
+
+// TestEmulator runs an emulator without a game to check that it starts, returning
+// its exit status and output so launch problems can be diagnosed
+func (s *GamesService) TestEmulator(emulatorID string) (models.EmulatorTestResult, error) {
+	return s.emuService.TestEmulator(context.Background(), emulatorID)
+}
+
+// CreateArgProfile saves a named args preset, e.g. "Performance", for an emulator
+func (s *GamesService) CreateArgProfile(emulatorID, name, args string) (models.EmulatorArgProfile, error) {
+	return s.emuService.CreateArgProfile(emulatorID, name, args)
+}
+
+// GetArgProfiles lists an emulator's args profiles. An empty emulatorID lists all.
+func (s *GamesService) GetArgProfiles(emulatorID string) ([]models.EmulatorArgProfile, error) {
+	return s.emuService.GetArgProfiles(emulatorID)
+}
+
+// DeleteArgProfile removes an args profile
+func (s *GamesService) DeleteArgProfile(profileID string) error {
+	return s.emuService.DeleteArgProfile(profileID)
+}
+
+// ApplyArgProfile sets the args profile an instance launches with. An empty profileID clears it.
+func (s *GamesService) ApplyArgProfile(instanceID, profileID string) error {
+	if err := s.emuService.ApplyArgProfile(instanceID, profileID); err != nil {
+		return err
+	}
+	s.emitInstanceUpdated(instanceID, "argProfile")
+	return nil
+}
+
+// emitInstanceUpdated reports a change to an instance's launch settings
+func (s *GamesService) emitInstanceUpdated(instanceID string, keys ...string) {
+	var gameID string
+	if instance, err := s.db.GetInstance(instanceID); err == nil && instance != nil {
+		gameID = instance.GameID
+	}
+	s.events.EmitInstanceUpdated(instanceID, gameID, keys)
+}
+
+// GetUnavailableInstanceEmulators returns instance overrides whose emulator or core is
+// no longer installed, for the UI to offer reassignment
+func (s *GamesService) GetUnavailableInstanceEmulators() ([]models.UnavailableInstanceEmulator, error) {
+	return s.db.GetUnavailableInstanceEmulators()
 }
 
 // RefreshEmulators re-discovers available emulators
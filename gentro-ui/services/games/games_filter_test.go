@@ -3,6 +3,7 @@ package games
 import (
 	"context"
 	"os/exec"
+	"reflect"
 	"testing"
 
 	"github.com/rhythmerc/gentro-ui/services/games/models"
@@ -12,15 +13,17 @@ import (
 type MockSource struct {
 	name      string
 	instances []models.GameInstance
+	scanErr   error
+	artErr    error
 }
 
-func (m *MockSource) Name() string                     { return m.name }
-func (m *MockSource) Init(config map[string]any) error { return nil }
+func (m *MockSource) Name() string                                          { return m.name }
+func (m *MockSource) Init(ctx context.Context, config map[string]any) error { return nil }
 func (m *MockSource) GetInstances(ctx context.Context) ([]models.GameInstance, error) {
-	return m.instances, nil
+	return m.instances, m.scanErr
 }
-func (m *MockSource) GetGameArt(ctx context.Context, instanceID string, artType string) ([]byte, string, error) {
-	return nil, "", nil
+func (m *MockSource) GetGameArt(ctx context.Context, instance models.GameInstance, artType string) ([]byte, string, error) {
+	return nil, "", m.artErr
 }
 func (m *MockSource) Refresh(ctx context.Context) error { return nil }
 func (m *MockSource) Launch(ctx context.Context, instance models.GameInstance) (*exec.Cmd, error) {
@@ -32,6 +35,9 @@ func (m *MockSource) MonitorProcess(ctx context.Context, instance models.GameIns
 func (m *MockSource) FilterInstances(instances []models.GameInstance, filter models.GameFilter) []models.GameInstance {
 	return instances
 }
+func (m *MockSource) Capabilities() models.SourceCapabilities {
+	return models.SourceCapabilities{}
+}
 
 func TestApplySourceFilters(t *testing.T) {
 	// Create a service with a mock registry
@@ -41,7 +47,7 @@ func TestApplySourceFilters(t *testing.T) {
 
 	// Register a mock source
 	mockSource := &MockSource{name: "mock"}
-	service.registry.Register(mockSource)
+	service.registry.Register(context.Background(), mockSource)
 
 	// Create test instances
 	instances := []models.GameInstance{
@@ -83,3 +89,46 @@ func TestGetDefaultFilterConfig(t *testing.T) {
 		t.Error("Expected excludeTools to default to true")
 	}
 }
+
+func TestGetGames_MaxAgeRating(t *testing.T) {
+	service := newTestService(t)
+	instances := []models.GameInstance{
+		{ID: "mock_kids", GameID: "kids", Source: "mock", Platform: "pc", Installed: true},
+		{ID: "mock_teen", GameID: "teen", Source: "mock", Platform: "pc", Installed: true},
+		{ID: "mock_adult", GameID: "adult", Source: "mock", Platform: "pc", Installed: true},
+		{ID: "mock_unrated", GameID: "unrated", Source: "mock", Platform: "pc", Installed: true},
+	}
+	if _, err := service.syncSourceInstances("mock", instances); err != nil {
+		t.Fatalf("failed to seed instances: %v", err)
+	}
+
+	ratings := map[string]models.AgeRating{
+		"kids":  models.AgeRatingEveryone,
+		"teen":  models.AgeRatingTeen,
+		"adult": models.AgeRatingAdult,
+	}
+	for gameID, rating := range ratings {
+		game, err := service.db.GetGame(gameID)
+		if err != nil || game == nil {
+			t.Fatalf("failed to get game %s: %v", gameID, err)
+		}
+		game.AgeRating = rating
+		if err := service.db.UpdateGame(game); err != nil {
+			t.Fatalf("UpdateGame failed: %v", err)
+		}
+	}
+
+	games, err := service.GetGames(&models.GameFilter{MaxAgeRating: models.AgeRatingTeen}, &models.GameSort{Field: models.SortByName})
+	if err != nil {
+		t.Fatalf("GetGames failed: %v", err)
+	}
+
+	got := make(map[string]bool)
+	for _, game := range games {
+		got[game.Game.ID] = true
+	}
+	want := map[string]bool{"kids": true, "teen": true, "unrated": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,828 @@
+package games
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/config"
+	"github.com/rhythmerc/gentro-ui/services/games/database"
+	"github.com/rhythmerc/gentro-ui/services/games/events"
+	"github.com/rhythmerc/gentro-ui/services/games/metadata"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// newTestService creates a GamesService backed by a temporary database
+func newTestService(t *testing.T) *GamesService {
+	t.Helper()
+
+	db, err := database.New(filepath.Join(t.TempDir(), "games.db"))
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return &GamesService{
+		db:       db,
+		registry: NewSourceRegistry(),
+		// Never started, so queued fetches are rejected without network access
+		fetcher: metadata.NewFetcher(1, logger),
+		logger:  logger,
+		events:  events.NewEvents(logger),
+		metrics: newMetricsRecorder(),
+	}
+}
+
+func TestSyncSourceInstances_IsolatesFailedInstance(t *testing.T) {
+	service := newTestService(t)
+
+	instances := []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes", Filename: "one.nes"},
+		{
+			ID: "inst2", GameID: "game2", Source: "mock", Platform: "nes", Filename: "two.nes",
+			// Channels can't be marshaled to JSON, so writing custom metadata fails
+			CustomMetadata: map[string]any{"bad": make(chan int)},
+		},
+		{ID: "inst3", GameID: "game1", Source: "mock", Platform: "nes", Filename: "three.nes"},
+	}
+
+	synced, err := service.syncSourceInstances("mock", instances)
+	if err != nil {
+		t.Fatalf("syncSourceInstances failed: %v", err)
+	}
+
+	if len(synced.toFetch) != 2 || synced.toFetch[0].ID != "inst1" || synced.toFetch[1].ID != "inst3" {
+		t.Errorf("expected inst1 and inst3 to be queued for fetch, got %+v", synced.toFetch)
+	}
+
+	for _, id := range []string{"inst1", "inst3"} {
+		instance, err := service.db.GetInstance(id)
+		if err != nil {
+			t.Fatalf("GetInstance(%s) failed: %v", id, err)
+		}
+		if instance == nil {
+			t.Errorf("expected %s to be committed", id)
+		}
+	}
+
+	bad, err := service.db.GetInstance("inst2")
+	if err != nil {
+		t.Fatalf("GetInstance(inst2) failed: %v", err)
+	}
+	if bad != nil {
+		t.Error("expected failed instance to be rolled back")
+	}
+
+	// The failed instance's game must be rolled back with it
+	ids, err := service.db.GetGameIDs()
+	if err != nil {
+		t.Fatalf("GetGameIDs failed: %v", err)
+	}
+	if !ids["game1"] || ids["game2"] {
+		t.Errorf("unexpected game IDs: %v", ids)
+	}
+}
+
+func TestSyncSourceInstances_FindsExistingFromOtherSource(t *testing.T) {
+	service := newTestService(t)
+
+	if err := service.db.CreateGame(&models.Game{ID: "game1", Name: "Game One"}); err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+	existing := models.GameInstance{ID: "inst1", GameID: "game1", Source: "other", Platform: "nes", FileSize: 1}
+	if err := service.db.CreateInstance(&existing); err != nil {
+		t.Fatalf("failed to create instance: %v", err)
+	}
+
+	scanned := existing
+	scanned.Source = "mock"
+	scanned.FileSize = 2
+
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{scanned}); err != nil {
+		t.Fatalf("syncSourceInstances failed: %v", err)
+	}
+
+	instance, err := service.db.GetInstance("inst1")
+	if err != nil {
+		t.Fatalf("GetInstance failed: %v", err)
+	}
+	if instance.FileSize != 2 {
+		t.Errorf("expected existing instance to be updated, got file size %d", instance.FileSize)
+	}
+}
+
+func TestSyncSourceInstances_UnchangedCustomMetadata(t *testing.T) {
+	service := newTestService(t)
+	scan := func() []models.GameInstance {
+		return []models.GameInstance{{ID: "inst1", GameID: "game1", Source: "mock", Platform: "pc",
+			CustomMetadata: map[string]any{"count": 3, "size": int64(1 << 40), "tags": []string{"rpg"}, "note": "x", "multiplayer": true}}}
+	}
+	if _, err := service.syncSourceInstances("mock", scan()); err != nil {
+		t.Fatalf("failed to seed instances: %v", err)
+	}
+
+	// Numbers read back as float64, which must not count as a change
+	synced, err := service.syncSourceInstances("mock", scan())
+	if err != nil {
+		t.Fatalf("syncSourceInstances failed: %v", err)
+	}
+	if synced.updated != 0 {
+		t.Errorf("expected no updates for unchanged metadata, got %d", synced.updated)
+	}
+	if instance, _ := service.db.GetInstance("inst1"); instance == nil || instance.Version != 1 {
+		t.Errorf("expected the instance not to be rewritten, got %+v", instance)
+	}
+
+	changed := scan()
+	changed[0].CustomMetadata["count"] = 4
+	if synced, err = service.syncSourceInstances("mock", changed); err != nil || synced.updated != 1 {
+		t.Errorf("expected a changed value to be written, got %+v (%v)", synced, err)
+	}
+}
+
+func TestMetadataValueEqual(t *testing.T) {
+	tests := []struct {
+		stored, scanned any
+		equal           bool
+	}{
+		{true, true, true},
+		{false, true, false},
+		{float64(3), 3, true},
+		{float64(3), int64(3), true},
+		{float64(3), "3", false},
+		{[]any{"rpg"}, []string{"rpg"}, true},
+		{map[string]any{"a": float64(1)}, map[string]int{"a": 1}, true},
+		{nil, false, false},
+	}
+	for _, tt := range tests {
+		if got := metadataValueEqual(tt.stored, tt.scanned); got != tt.equal {
+			t.Errorf("metadataValueEqual(%#v, %#v) = %v, want %v", tt.stored, tt.scanned, got, tt.equal)
+		}
+	}
+}
+
+func TestRefreshGames_ConcurrentReads(t *testing.T) {
+	service := newTestService(t)
+
+	var instances []models.GameInstance
+	for i := 0; i < 200; i++ {
+		instances = append(instances, models.GameInstance{
+			ID:       fmt.Sprintf("inst%d", i),
+			GameID:   fmt.Sprintf("game%d", i),
+			Source:   "mock",
+			Platform: "nes",
+			Filename: fmt.Sprintf("game%d.nes", i),
+		})
+	}
+	service.registry.Register(context.Background(), &MockSource{name: "mock", instances: instances})
+
+	done := make(chan struct{})
+	errs := make(chan error, 100)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if _, err := service.GetGames(&models.GameFilter{}, nil); err != nil {
+					select {
+					case errs <- err:
+					default:
+					}
+					return
+				}
+			}
+		}()
+	}
+
+	_, refreshErr := service.RefreshGames()
+	close(done)
+	wg.Wait()
+	close(errs)
+
+	if refreshErr != nil {
+		t.Fatalf("RefreshGames failed: %v", refreshErr)
+	}
+	for err := range errs {
+		t.Errorf("GetGames failed during refresh: %v", err)
+	}
+
+	games, err := service.GetGames(&models.GameFilter{}, nil)
+	if err != nil {
+		t.Fatalf("GetGames failed: %v", err)
+	}
+	if len(games) != len(instances) {
+		t.Errorf("expected %d games after refresh, got %d", len(instances), len(games))
+	}
+}
+
+func TestRevertMetadataField(t *testing.T) {
+	service := newTestService(t)
+
+	if err := service.db.CreateGame(&models.Game{ID: "game1", Name: "IGDB Name", Developer: "IGDB Dev"}); err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+	if err := service.db.SetFieldSources("game1", "igdb", []string{"name", "developer"}); err != nil {
+		t.Fatalf("SetFieldSources failed: %v", err)
+	}
+	if err := service.db.StoreExternalMetadata("game1", "mobygames", map[string]any{
+		"name": "Moby Name", "developer": "Moby Dev", "resolver": "mobygames",
+	}); err != nil {
+		t.Fatalf("StoreExternalMetadata failed: %v", err)
+	}
+
+	if err := service.RevertMetadataField("game1", "name", "mobygames"); err != nil {
+		t.Fatalf("RevertMetadataField failed: %v", err)
+	}
+
+	game, err := service.db.GetGame("game1")
+	if err != nil {
+		t.Fatalf("GetGame failed: %v", err)
+	}
+	if game.Name != "Moby Name" || game.Developer != "IGDB Dev" {
+		t.Errorf("expected only name to be reverted, got %+v", game)
+	}
+
+	sources, err := service.GetMetadataProvenance("game1")
+	if err != nil {
+		t.Fatalf("GetMetadataProvenance failed: %v", err)
+	}
+	if sources["name"].Source != "mobygames" || sources["developer"].Source != "igdb" {
+		t.Errorf("unexpected provenance: %+v", sources)
+	}
+
+	if err := service.RevertMetadataField("game1", "name", "steamgriddb"); err == nil {
+		t.Error("expected error reverting to an uncached source")
+	}
+}
+
+func TestApplyMetadataFromSource_SkipsEmptyFields(t *testing.T) {
+	service := newTestService(t)
+
+	if err := service.db.CreateGame(&models.Game{ID: "game1", Name: "IGDB Name", Description: "IGDB Desc", Developer: "IGDB Dev", Publisher: "IGDB Pub"}); err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+	if err := service.db.SetFieldSources("game1", "igdb", []string{"name", "description", "developer", "publisher"}); err != nil {
+		t.Fatalf("SetFieldSources failed: %v", err)
+	}
+	if err := service.db.StoreExternalMetadata("game1", "mobygames", map[string]any{
+		"name": "Moby Name", "description": "", "developer": "", "publisher": "", "resolver": "mobygames",
+	}); err != nil {
+		t.Fatalf("StoreExternalMetadata failed: %v", err)
+	}
+
+	if err := service.ApplyMetadataFromSource("game1", "mobygames"); err != nil {
+		t.Fatalf("ApplyMetadataFromSource failed: %v", err)
+	}
+
+	game, err := service.db.GetGame("game1")
+	if err != nil {
+		t.Fatalf("GetGame failed: %v", err)
+	}
+	if game.Name != "Moby Name" || game.Description != "IGDB Desc" || game.Developer != "IGDB Dev" || game.Publisher != "IGDB Pub" {
+		t.Errorf("expected only the name to change, got %+v", game)
+	}
+
+	sources, err := service.GetMetadataProvenance("game1")
+	if err != nil {
+		t.Fatalf("GetMetadataProvenance failed: %v", err)
+	}
+	for _, field := range []string{"description", "developer", "publisher"} {
+		if sources[field].Source != "igdb" {
+			t.Errorf("expected %s to stay from igdb, got %+v", field, sources[field])
+		}
+	}
+
+	if err := service.RevertMetadataField("game1", "developer", "mobygames"); err == nil {
+		t.Error("expected error reverting to an empty value")
+	}
+}
+
+func TestRevertMetadataField_Genres(t *testing.T) {
+	service := newTestService(t)
+
+	if err := service.db.CreateGame(&models.Game{ID: "game1", Name: "Zelda", Genres: []string{"Adventure"}}); err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+	if err := service.db.SetFieldSources("game1", "igdb", []string{"genres"}); err != nil {
+		t.Fatalf("SetFieldSources failed: %v", err)
+	}
+	if err := service.db.StoreExternalMetadata("game1", "mobygames", map[string]any{
+		"genres": []string{"Role-playing (RPG)", "Puzzle"}, "resolver": "mobygames",
+	}); err != nil {
+		t.Fatalf("StoreExternalMetadata failed: %v", err)
+	}
+
+	if err := service.RevertMetadataField("game1", "genres", "mobygames"); err != nil {
+		t.Fatalf("RevertMetadataField failed: %v", err)
+	}
+
+	game, err := service.db.GetGame("game1")
+	if err != nil {
+		t.Fatalf("GetGame failed: %v", err)
+	}
+	if want := []string{"Puzzle", "RPG"}; !reflect.DeepEqual(game.Genres, want) {
+		t.Errorf("expected normalized genres %v, got %v", want, game.Genres)
+	}
+
+	sources, err := service.GetMetadataProvenance("game1")
+	if err != nil {
+		t.Fatalf("GetMetadataProvenance failed: %v", err)
+	}
+	if sources["genres"].Source != "mobygames" {
+		t.Errorf("expected genres from mobygames, got %+v", sources["genres"])
+	}
+}
+
+func TestOnMetadataResolved_ArtOnly(t *testing.T) {
+	service := newTestService(t)
+	instance := models.GameInstance{ID: "inst1", GameID: "game1", Source: "mock", Platform: "pc"}
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{instance}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+	if err := service.db.UpdateGame(&models.Game{ID: "game1", Name: "Portal", Developer: "Valve"}); err != nil {
+		t.Fatalf("UpdateGame failed: %v", err)
+	}
+
+	service.onMetadataResolved(
+		models.FetchRequest{InstanceID: instance.ID, GameID: instance.GameID},
+		models.ResolvedMetadata{ArtURLs: map[string]string{"grid": "http://art/grid"}, ArtOnly: true},
+		"steamgriddb",
+	)
+
+	game, err := service.db.GetGame("game1")
+	if err != nil {
+		t.Fatalf("GetGame failed: %v", err)
+	}
+	if game.Name != "Portal" || game.Developer != "Valve" {
+		t.Errorf("expected art alone to leave the game alone, got %+v", game)
+	}
+	sources, err := service.GetMetadataSources("game1")
+	if err != nil {
+		t.Fatalf("GetMetadataSources failed: %v", err)
+	}
+	if len(sources) != 0 {
+		t.Errorf("expected no cached metadata sources, got %v", sources)
+	}
+	provenance, err := service.GetMetadataProvenance("game1")
+	if err != nil {
+		t.Fatalf("GetMetadataProvenance failed: %v", err)
+	}
+	for field, source := range provenance {
+		if field != "art.grid" {
+			t.Errorf("expected provenance for the art only, got %s from %s", field, source.Source)
+		}
+	}
+}
+
+func TestUpdateInstanceMetadata(t *testing.T) {
+	service := newTestService(t)
+	var updates []models.InstanceUpdate
+	service.events = events.NewEventsWithSink(service.logger, func(name string, data any) {
+		if update, ok := data.(models.InstanceUpdate); ok && name == models.EventInstanceUpdate {
+			updates = append(updates, update)
+		}
+	})
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "pc", CustomMetadata: map[string]any{"notes": "kept"}},
+	}); err != nil {
+		t.Fatalf("failed to seed instances: %v", err)
+	}
+
+	if err := service.UpdateInstanceMetadata("inst1", 0, map[string]any{"rating": 4, "favorite": true}); err != nil {
+		t.Fatalf("UpdateInstanceMetadata failed: %v", err)
+	}
+
+	want := []models.InstanceUpdate{{InstanceID: "inst1", GameID: "game1", Keys: []string{"favorite", "rating"}}}
+	if !reflect.DeepEqual(updates, want) {
+		t.Errorf("expected %+v, got %+v", want, updates)
+	}
+
+	// An edit made from the version before that one conflicts
+	if err := service.UpdateInstanceMetadata("inst1", 1, map[string]any{"rating": 5}); !errors.Is(err, models.ErrVersionConflict) {
+		t.Errorf("expected a version conflict, got %v", err)
+	}
+	if len(updates) != 1 {
+		t.Errorf("expected no event for a rejected edit, got %+v", updates)
+	}
+
+	if err := service.DeleteInstanceMetadataKey("inst1", 2, "rating"); err != nil {
+		t.Fatalf("DeleteInstanceMetadataKey failed: %v", err)
+	}
+	instance, err := service.db.GetInstance("inst1")
+	if err != nil {
+		t.Fatalf("GetInstance failed: %v", err)
+	}
+	// Keys that weren't edited are kept
+	if want := map[string]any{"notes": "kept", "favorite": true}; !reflect.DeepEqual(instance.CustomMetadata, want) {
+		t.Errorf("expected %v, got %v", want, instance.CustomMetadata)
+	}
+}
+
+func TestBulkSetMetadata(t *testing.T) {
+	service := newTestService(t)
+	var updates []models.LibraryUpdate
+	service.events = events.NewEventsWithSink(service.logger, func(name string, data any) {
+		if name == models.EventInstanceUpdate {
+			t.Errorf("expected no per-instance events, got %+v", data)
+		}
+		if update, ok := data.(models.LibraryUpdate); ok && name == models.EventLibraryUpdated {
+			updates = append(updates, update)
+		}
+	})
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "snes", CustomMetadata: map[string]any{"tag": "old"}},
+		{ID: "inst2", GameID: "game2", Source: "mock", Platform: "snes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instances: %v", err)
+	}
+
+	if err := service.BulkSetMetadata([]string{"inst1", "inst2"}, "tag", "retro"); err != nil {
+		t.Fatalf("BulkSetMetadata failed: %v", err)
+	}
+	want := []models.LibraryUpdate{{InstanceIDs: []string{"inst1", "inst2"}, Keys: []string{"tag"}}}
+	if !reflect.DeepEqual(updates, want) {
+		t.Errorf("expected %+v, got %+v", want, updates)
+	}
+	for _, id := range []string{"inst1", "inst2"} {
+		instance, err := service.db.GetInstance(id)
+		if err != nil || instance.CustomMetadata["tag"] != "retro" || instance.Version != 2 {
+			t.Errorf("expected %s to be tagged at version 2, got %+v (%v)", id, instance, err)
+		}
+	}
+
+	// A missing instance rolls back the whole update
+	if err := service.BulkSetMetadata([]string{"inst1", "missing"}, "tag", "new"); err == nil {
+		t.Error("expected an error for a missing instance")
+	}
+	if instance, _ := service.db.GetInstance("inst1"); instance.CustomMetadata["tag"] != "retro" {
+		t.Errorf("expected no instance to change, got %v", instance.CustomMetadata)
+	}
+	if len(updates) != 1 {
+		t.Errorf("expected no event for a failed update, got %+v", updates)
+	}
+}
+
+func TestApplyMetadataFromSource(t *testing.T) {
+	service := newTestService(t)
+
+	if err := service.db.CreateGame(&models.Game{ID: "game1", Name: "IGDB Name"}); err != nil {
+		t.Fatalf("failed to create game: %v", err)
+	}
+	if err := service.db.StoreExternalMetadata("game1", "mobygames", map[string]any{
+		"name": "Moby Name", "publisher": "Moby Pub",
+	}); err != nil {
+		t.Fatalf("StoreExternalMetadata failed: %v", err)
+	}
+
+	if err := service.ApplyMetadataFromSource("game1", "mobygames"); err != nil {
+		t.Fatalf("ApplyMetadataFromSource failed: %v", err)
+	}
+
+	game, err := service.db.GetGame("game1")
+	if err != nil {
+		t.Fatalf("GetGame failed: %v", err)
+	}
+	if game.Name != "Moby Name" || game.Publisher != "Moby Pub" {
+		t.Errorf("expected mobygames metadata to be applied, got %+v", game)
+	}
+
+	if err := service.ApplyMetadataFromSource("game1", "igdb"); err == nil {
+		t.Error("expected error applying an uncached source")
+	}
+}
+
+func TestIsMetadataStale(t *testing.T) {
+	service := newTestService(t)
+
+	// Without a config manager the default TTL applies
+	if service.isMetadataStale(time.Now().Add(-24 * time.Hour)) {
+		t.Error("expected day-old metadata to be fresh")
+	}
+	if !service.isMetadataStale(time.Now().Add(-60 * 24 * time.Hour)) {
+		t.Error("expected 60-day-old metadata to be stale")
+	}
+
+	manager, err := config.NewManager(filepath.Join(t.TempDir(), "gentro.toml"))
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	service.config = manager
+
+	cfg := manager.Get()
+	cfg.Metadata.CacheTTLDays = 0
+	if err := manager.SetMetadata(cfg.Metadata); err != nil {
+		t.Fatalf("SetMetadata failed: %v", err)
+	}
+	if service.isMetadataStale(time.Now().Add(-365 * 24 * time.Hour)) {
+		t.Error("expected a TTL of 0 to disable expiry")
+	}
+}
+
+func TestSyncSourceInstances_RequeuesStaleMetadata(t *testing.T) {
+	service := newTestService(t)
+
+	// A database at a known path so the cached metadata can be aged directly
+	path := filepath.Join(t.TempDir(), "games.db")
+	db, err := database.New(path)
+	if err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	service.db = db
+
+	instances := []models.GameInstance{
+		{ID: "fresh", GameID: "game1", Source: "mock", Platform: "nes", Filename: "fresh.nes"},
+		{ID: "stale", GameID: "game2", Source: "mock", Platform: "nes", Filename: "stale.nes"},
+	}
+	if _, err := service.syncSourceInstances("mock", instances); err != nil {
+		t.Fatalf("syncSourceInstances failed: %v", err)
+	}
+	for _, instance := range instances {
+		if err := db.StoreExternalMetadata(instance.GameID, "igdb", map[string]any{"name": instance.Filename}); err != nil {
+			t.Fatalf("StoreExternalMetadata failed: %v", err)
+		}
+		if err := db.UpdateInstanceMetadataStatus(instance.ID, models.MetadataStatus{State: models.MetadataStateCompleted}); err != nil {
+			t.Fatalf("UpdateInstanceMetadataStatus failed: %v", err)
+		}
+	}
+
+	conn, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Exec(`UPDATE external_metadata SET fetched_at = datetime('now', '-60 days') WHERE game_id = 'game2'`); err != nil {
+		t.Fatalf("failed to age metadata: %v", err)
+	}
+
+	synced, err := service.syncSourceInstances("mock", instances)
+	if err != nil {
+		t.Fatalf("syncSourceInstances failed: %v", err)
+	}
+	if len(synced.toFetch) != 1 || synced.toFetch[0].ID != "stale" {
+		t.Errorf("expected only the stale instance to be re-queued, got %+v", synced.toFetch)
+	}
+
+	// A failed refresh isn't retried until the cooldown passes
+	service.onMetadataFailed(models.FetchRequest{InstanceID: "stale", GameID: "game2"}, []string{"igdb"})
+	if synced, err = service.syncSourceInstances("mock", instances); err != nil {
+		t.Fatalf("syncSourceInstances failed: %v", err)
+	}
+	if len(synced.toFetch) != 0 {
+		t.Errorf("expected a failed refresh to wait out the cooldown, got %+v", synced.toFetch)
+	}
+
+	if _, err := conn.Exec(`UPDATE external_metadata SET refresh_failed_at = datetime('now', '-2 days') WHERE game_id = 'game2'`); err != nil {
+		t.Fatalf("failed to age refresh failure: %v", err)
+	}
+	if synced, err = service.syncSourceInstances("mock", instances); err != nil {
+		t.Fatalf("syncSourceInstances failed: %v", err)
+	}
+	if len(synced.toFetch) != 1 || synced.toFetch[0].ID != "stale" {
+		t.Errorf("expected a retry after the cooldown, got %+v", synced.toFetch)
+	}
+}
+
+func TestGetGalleryArtURLs(t *testing.T) {
+	service := newTestService(t)
+	service.route = "/games"
+
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+
+	for _, artType := range []string{"screenshot", "screenshot.10", "screenshot.2", "artwork.0", "cover"} {
+		if err := service.db.StoreGameArt("game1", artType, "https://example.com/"+artType, "igdb"); err != nil {
+			t.Fatalf("StoreGameArt failed: %v", err)
+		}
+	}
+
+	urls, err := service.GetGalleryArtURLs("inst1")
+	if err != nil {
+		t.Fatalf("GetGalleryArtURLs failed: %v", err)
+	}
+
+	want := []string{
+		"/games/art/inst1/screenshot.2",
+		"/games/art/inst1/screenshot.10",
+		"/games/art/inst1/artwork.0",
+	}
+	if len(urls) != len(want) {
+		t.Fatalf("expected %v, got %v", want, urls)
+	}
+	for i := range want {
+		if urls[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, urls)
+			break
+		}
+	}
+}
+
+func TestRefreshGames_ReportsScanErrors(t *testing.T) {
+	service := newTestService(t)
+	service.registry.Register(context.Background(), &MockSource{name: "broken", scanErr: errors.New("library folder missing")})
+	service.registry.Register(context.Background(), &MockSource{name: "mock", instances: []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes"},
+		{ID: "inst2", GameID: "game2", Source: "mock", Platform: "nes", CustomMetadata: map[string]any{"bad": make(chan int)}},
+	}})
+
+	result, err := service.RefreshGames()
+	if err != nil {
+		t.Fatalf("expected partial failures to be reported in the result, got %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, scanErr := range result.Errors {
+		got[scanErr.Source] = scanErr.InstanceID
+	}
+	if want := map[string]string{"broken": "", "mock": "inst2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected errors for %v, got %v", want, got)
+	}
+
+	// The failure was skipped, so the good instance was still saved
+	if instance, _ := service.db.GetInstance("inst1"); instance == nil {
+		t.Error("expected inst1 to be saved")
+	}
+}
+
+func TestSyncSourceInstances_FailFast(t *testing.T) {
+	service := newTestService(t)
+	manager, err := config.NewManager(filepath.Join(t.TempDir(), "gentro.toml"))
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	if err := manager.SetScan(config.ScanConfig{FailFast: true}); err != nil {
+		t.Fatalf("SetScan failed: %v", err)
+	}
+	service.config = manager
+
+	synced, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes"},
+		{ID: "inst2", GameID: "game2", Source: "mock", Platform: "nes", CustomMetadata: map[string]any{"bad": make(chan int)}},
+		{ID: "inst3", GameID: "game3", Source: "mock", Platform: "nes"},
+	})
+	if err == nil {
+		t.Fatal("expected fail fast to return the instance error")
+	}
+	if len(synced.errors) != 1 || synced.errors[0].InstanceID != "inst2" {
+		t.Errorf("expected inst2 to be reported, got %+v", synced.errors)
+	}
+
+	for _, id := range []string{"inst1", "inst3"} {
+		if instance, _ := service.db.GetInstance(id); instance != nil {
+			t.Errorf("expected %s not to be saved after fail fast", id)
+		}
+	}
+}
+
+func TestRefreshGames_Summary(t *testing.T) {
+	service := newTestService(t)
+	var emitted []models.RefreshResult
+	service.events = events.NewEventsWithSink(service.logger, func(name string, data any) {
+		if name == models.EventRefreshResult {
+			emitted = append(emitted, data.(models.RefreshResult))
+		}
+	})
+
+	source := &MockSource{name: "mock", instances: []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes", FileSize: 1},
+		{ID: "inst2", GameID: "game2", Source: "mock", Platform: "nes", FileSize: 1},
+	}}
+	service.registry.Register(context.Background(), source)
+
+	result, err := service.RefreshGames()
+	if err != nil {
+		t.Fatalf("RefreshGames failed: %v", err)
+	}
+	if result.Added != 2 || result.Updated != 0 || result.Missing != 0 {
+		t.Errorf("expected 2 added, got %+v", result)
+	}
+
+	// Change one instance and drop the other from the scan
+	source.instances = []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes", FileSize: 2},
+	}
+	result, err = service.RefreshGames()
+	if err != nil {
+		t.Fatalf("RefreshGames failed: %v", err)
+	}
+	if result.Added != 0 || result.Updated != 1 || result.Missing != 1 {
+		t.Errorf("expected 1 updated and 1 missing, got %+v", result)
+	}
+
+	if len(emitted) != 2 || emitted[1].Updated != 1 {
+		t.Errorf("expected each refresh to emit its result, got %+v", emitted)
+	}
+}
+
+// blockingSource scans until its context is cancelled
+type blockingSource struct {
+	MockSource
+	started chan struct{}
+}
+
+func (b *blockingSource) GetInstances(ctx context.Context) ([]models.GameInstance, error) {
+	close(b.started)
+	<-ctx.Done()
+	return b.instances, ctx.Err()
+}
+
+func TestRefreshGames_Cancel(t *testing.T) {
+	service := newTestService(t)
+	if service.CancelRefresh() {
+		t.Error("expected no refresh to cancel")
+	}
+
+	source := &blockingSource{
+		MockSource: MockSource{name: "slow", instances: []models.GameInstance{
+			{ID: "inst1", GameID: "game1", Source: "slow", Platform: "nes"},
+		}},
+		started: make(chan struct{}),
+	}
+	service.registry.Register(context.Background(), source)
+
+	done := make(chan models.RefreshResult)
+	go func() {
+		result, err := service.RefreshGames()
+		if err != nil {
+			t.Errorf("RefreshGames failed: %v", err)
+		}
+		done <- result
+	}()
+
+	<-source.started
+	if !service.CancelRefresh() {
+		t.Error("expected the running refresh to be cancelled")
+	}
+
+	select {
+	case result := <-done:
+		if !result.Cancelled || result.Added != 0 {
+			t.Errorf("expected a cancelled refresh with nothing added, got %+v", result)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("refresh did not stop after CancelRefresh")
+	}
+
+	// The partial scan is discarded rather than synced
+	if instance, _ := service.db.GetInstance("inst1"); instance != nil {
+		t.Error("expected the cancelled source's instances not to be written")
+	}
+}
+
+func TestRefreshGames_RejectsOverlap(t *testing.T) {
+	service := newTestService(t)
+	source := &blockingSource{
+		MockSource: MockSource{name: "slow"},
+		started:    make(chan struct{}),
+	}
+	service.registry.Register(context.Background(), source)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		service.RefreshGames()
+	}()
+	<-source.started
+
+	// Every overlapping caller is turned away while the first refresh runs
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := service.RefreshGames(); !errors.Is(err, models.ErrRefreshInProgress) {
+				t.Errorf("expected ErrRefreshInProgress, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	service.CancelRefresh()
+	<-done
+
+	// The guard is released once the refresh finishes
+	service.registry = NewSourceRegistry()
+	if _, err := service.RefreshGames(); err != nil {
+		t.Errorf("expected a refresh to run after the previous one finished, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package games
+
+import "strings"
+
+// defaultGenreAliases maps IGDB genre names, lowercased, to the labels shown in the library
+var defaultGenreAliases = map[string]string{
+	"role-playing (rpg)":         "RPG",
+	"hack and slash/beat 'em up": "Beat 'em up",
+	"real time strategy (rts)":   "Strategy",
+	"turn-based strategy (tbs)":  "Strategy",
+	"point-and-click":            "Adventure",
+	"quiz/trivia":                "Trivia",
+	"card & board game":          "Card & Board",
+	"simulator":                  "Simulation",
+	"moba":                       "MOBA",
+}
+
+// normalizeGenres maps resolver genre names to library labels, dropping duplicates
+// that map to the same label. Aliases set in the config take precedence over the
+// defaults; unknown genres are kept as-is.
+func (s *GamesService) normalizeGenres(genres []string) []string {
+	var overrides map[string]string
+	if s.config != nil {
+		overrides = s.config.Get().Metadata.GenreAliases
+	}
+
+	normalized := make([]string, 0, len(genres))
+	seen := make(map[string]bool)
+	for _, genre := range genres {
+		label := genre
+		key := strings.ToLower(strings.TrimSpace(genre))
+		if alias, ok := lookupFold(overrides, key); ok {
+			label = alias
+		} else if alias, ok := defaultGenreAliases[key]; ok {
+			label = alias
+		}
+
+		if label == "" || seen[strings.ToLower(label)] {
+			continue
+		}
+		seen[strings.ToLower(label)] = true
+		normalized = append(normalized, label)
+	}
+	return normalized
+}
+
+// lookupFold finds key in a map whose keys may use any case
+func lookupFold(m map[string]string, key string) (string, bool) {
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return "", false
+}
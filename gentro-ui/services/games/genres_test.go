@@ -0,0 +1,67 @@
+package games
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/config"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestNormalizeGenres(t *testing.T) {
+	service := newTestService(t)
+
+	got := service.normalizeGenres([]string{"Role-playing (RPG)", "Real Time Strategy (RTS)", "Turn-based strategy (TBS)", "Platform"})
+	want := []string{"RPG", "Strategy", "Platform"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	manager, err := config.NewManager(filepath.Join(t.TempDir(), "gentro.toml"))
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	metadataConfig := manager.Get().Metadata
+	metadataConfig.GenreAliases = map[string]string{"Role-Playing (RPG)": "Role-playing", "Indie": ""}
+	if err := manager.SetMetadata(metadataConfig); err != nil {
+		t.Fatalf("SetMetadata failed: %v", err)
+	}
+	service.config = manager
+
+	got = service.normalizeGenres([]string{"Role-playing (RPG)", "Indie", "Puzzle"})
+	want = []string{"Role-playing", "Puzzle"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected config aliases to win, got %v", got)
+	}
+}
+
+func TestOnMetadataResolved_PreservesRawGenres(t *testing.T) {
+	service := newTestService(t)
+	instance := models.GameInstance{ID: "emulated_1", GameID: "zelda", Source: "emulated", Platform: "nes"}
+	if _, err := service.syncSourceInstances("emulated", []models.GameInstance{instance}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+
+	service.onMetadataResolved(
+		models.FetchRequest{InstanceID: instance.ID, GameID: instance.GameID},
+		models.ResolvedMetadata{GameMetadata: models.GameMetadata{Genres: []string{"Role-playing (RPG)"}}},
+		"igdb",
+	)
+
+	game, err := service.db.GetGame("zelda")
+	if err != nil || game == nil {
+		t.Fatalf("failed to get game: %v", err)
+	}
+	if !reflect.DeepEqual(game.Genres, []string{"RPG"}) {
+		t.Errorf("expected normalized genres, got %v", game.Genres)
+	}
+
+	raw, err := service.db.GetExternalMetadata("zelda", "igdb")
+	if err != nil {
+		t.Fatalf("failed to get external metadata: %v", err)
+	}
+	if genres, _ := raw["genres"].([]any); len(genres) != 1 || genres[0] != "Role-playing (RPG)" {
+		t.Errorf("expected raw genre to be preserved, got %v", raw["genres"])
+	}
+}
@@ -0,0 +1,177 @@
+package games
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/games/events"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// launchSource reports running and stopped through the shared events, like the emulated source
+type launchSource struct {
+	MockSource
+	events    *events.Events
+	launchErr error
+}
+
+func (l *launchSource) Launch(ctx context.Context, instance models.GameInstance) (*exec.Cmd, error) {
+	return nil, l.launchErr
+}
+
+func (l *launchSource) MonitorProcess(ctx context.Context, instance models.GameInstance, cmd *exec.Cmd) {
+	l.events.EmitGameInstanceRunning(instance)
+	l.events.EmitGameInstanceStopped(instance)
+}
+
+// recordLaunchStatuses replaces the service's events with a fake sink and returns its launch updates
+func recordLaunchStatuses(service *GamesService) <-chan models.LaunchStatusUpdate {
+	updates := make(chan models.LaunchStatusUpdate, 16)
+	service.events = events.NewEventsWithSink(service.logger, func(name string, data any) {
+		if update, ok := data.(models.LaunchStatusUpdate); ok && name == "launchStatusUpdate" {
+			updates <- update
+		}
+	})
+	return updates
+}
+
+func expectLaunchStatuses(t *testing.T, updates <-chan models.LaunchStatusUpdate, want ...models.LaunchStatus) {
+	t.Helper()
+	for i, status := range want {
+		select {
+		case update := <-updates:
+			if update.Status != status {
+				t.Fatalf("event %d: expected %s, got %s", i, status, update.Status)
+			}
+			if update.InstanceID != "inst1" || update.GameID != "game1" {
+				t.Errorf("event %d: unexpected instance %+v", i, update)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("event %d: timed out waiting for %s", i, status)
+		}
+	}
+}
+
+func TestLaunch_EmitsStatusSequence(t *testing.T) {
+	tests := []struct {
+		name      string
+		launchErr error
+		want      []models.LaunchStatus
+	}{
+		{
+			name: "success",
+			want: []models.LaunchStatus{models.LaunchStatusLaunching, models.LaunchStatusRunning, models.LaunchStatusStopped},
+		},
+		{
+			name:      "source launch fails",
+			launchErr: errors.New("emulator missing"),
+			want:      []models.LaunchStatus{models.LaunchStatusLaunching, models.LaunchStatusFailed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestService(t)
+			updates := recordLaunchStatuses(service)
+
+			source := &launchSource{MockSource: MockSource{name: "mock"}, events: service.events, launchErr: tt.launchErr}
+			service.registry.Register(context.Background(), source)
+			if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+				{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes"},
+			}); err != nil {
+				t.Fatalf("failed to seed instance: %v", err)
+			}
+
+			if err := service.Launch("inst1"); err != nil {
+				t.Fatalf("Launch failed: %v", err)
+			}
+			expectLaunchStatuses(t, updates, tt.want...)
+		})
+	}
+}
+
+func TestLaunch_EmitsLaunchErrorCode(t *testing.T) {
+	service := newTestService(t)
+
+	launchErrors := make(chan models.LaunchError, 1)
+	service.events = events.NewEventsWithSink(service.logger, func(name string, data any) {
+		if launchErr, ok := data.(models.LaunchError); ok && name == models.EventLaunchError {
+			launchErrors <- launchErr
+		}
+	})
+
+	source := &launchSource{
+		MockSource: MockSource{name: "mock"},
+		launchErr:  fmt.Errorf("%w: RetroArch", models.ErrEmulatorNotInstalled),
+	}
+	service.registry.Register(context.Background(), source)
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+
+	if err := service.Launch("inst1"); err != nil {
+		t.Fatalf("Launch failed: %v", err)
+	}
+
+	select {
+	case launchErr := <-launchErrors:
+		if launchErr.Code != models.LaunchErrorEmulatorNotInstalled {
+			t.Errorf("expected %s, got %s", models.LaunchErrorEmulatorNotInstalled, launchErr.Code)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for launch error")
+	}
+}
+
+func TestGetRunningInstances(t *testing.T) {
+	service := newTestService(t)
+
+	service.events.EmitLaunchStatus("inst1", "game1", models.LaunchStatusLaunching, "")
+	service.events.EmitLaunchStatus("inst1", "game1", models.LaunchStatusRunning, "")
+	service.events.EmitLaunchStatus("inst2", "game2", models.LaunchStatusRunning, "")
+
+	running, err := service.GetRunningInstances()
+	if err != nil {
+		t.Fatalf("GetRunningInstances failed: %v", err)
+	}
+	if len(running) != 2 || running[0] != "inst1" || running[1] != "inst2" {
+		t.Errorf("expected inst1 and inst2 running, got %v", running)
+	}
+
+	service.events.EmitLaunchStatus("inst1", "game1", models.LaunchStatusStopped, "")
+	running, _ = service.GetRunningInstances()
+	if len(running) != 1 || running[0] != "inst2" {
+		t.Errorf("expected only inst2 running, got %v", running)
+	}
+}
+
+func TestLaunch_SingleRunningEventForEmulated(t *testing.T) {
+	service := newTestService(t)
+	updates := recordLaunchStatuses(service)
+
+	// Launch used to emit running for emulated games before the source did
+	source := &launchSource{MockSource: MockSource{name: "emulated"}, events: service.events}
+	service.registry.Register(context.Background(), source)
+	if _, err := service.syncSourceInstances("emulated", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "emulated", Platform: "nes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+
+	if err := service.Launch("inst1"); err != nil {
+		t.Fatalf("Launch failed: %v", err)
+	}
+	expectLaunchStatuses(t, updates, models.LaunchStatusLaunching, models.LaunchStatusRunning, models.LaunchStatusStopped)
+
+	select {
+	case update := <-updates:
+		t.Errorf("expected no further events, got %s", update.Status)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,71 @@
+package games
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// AddRom adds a ROM file outside the scanned directories, detecting its platform
+// from the extension and parent directories. Extensions shared by several platforms
+// return an error wrapping models.ErrAmbiguousPlatform; use AddRomForPlatform then.
+func (s *GamesService) AddRom(path string) (*models.GameWithInstance, error) {
+	return s.AddRomForPlatform(path, "")
+}
+
+// AddRomForPlatform adds a ROM file as the given platform. An empty platform is detected.
+func (s *GamesService) AddRomForPlatform(path, platform string) (*models.GameWithInstance, error) {
+	source, adder, err := s.manualAddSource()
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := adder.AddManual(context.Background(), path, platform)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add ROM: %w", err)
+	}
+
+	synced, err := s.syncSourceInstances(source.Name(), []models.GameInstance{instance})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save ROM: %w", err)
+	}
+	if len(synced.errors) > 0 {
+		return nil, fmt.Errorf("failed to save ROM: %s", synced.errors[0].Message)
+	}
+	for _, pending := range synced.toFetch {
+		s.queueMetadataFetch(pending)
+	}
+
+	stored, err := s.db.GetInstance(instance.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load instance: %w", err)
+	}
+	if stored == nil {
+		return nil, fmt.Errorf("instance %s was not saved", instance.ID)
+	}
+
+	game, err := s.db.GetGame(stored.GameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load game: %w", err)
+	}
+	if game == nil {
+		return nil, fmt.Errorf("game %s was not saved", stored.GameID)
+	}
+
+	s.logger.Info("added ROM manually", "instanceID", stored.ID, "platform", stored.Platform, "path", stored.Path)
+	return &models.GameWithInstance{Game: *game, Instance: *stored}, nil
+}
+
+// manualAddSource returns the first registered source that accepts manually added files
+func (s *GamesService) manualAddSource() (GameSource, ManualAdder, error) {
+	for _, source := range s.registry.GetAll() {
+		if !source.Capabilities().SupportsManualAdd {
+			continue
+		}
+		if adder, ok := source.(ManualAdder); ok {
+			return source, adder, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("no source supports adding games manually")
+}
@@ -0,0 +1,98 @@
+package games
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// manualSource is a MockSource that accepts manually added files
+type manualSource struct {
+	MockSource
+	platforms []string
+}
+
+func (m *manualSource) Capabilities() models.SourceCapabilities {
+	return models.SourceCapabilities{SupportsManualAdd: true}
+}
+
+func (m *manualSource) AddManual(ctx context.Context, path, platform string) (models.GameInstance, error) {
+	if platform == "" {
+		if len(m.platforms) != 1 {
+			return models.GameInstance{}, models.ErrAmbiguousPlatform
+		}
+		platform = m.platforms[0]
+	}
+	return models.GameInstance{
+		ID: "manual_" + platform, GameID: "game_" + platform, Source: m.name, Platform: platform,
+		Path: path, Filename: "Okami.iso", Installed: true,
+	}, nil
+}
+
+func TestAddRom(t *testing.T) {
+	service := newTestService(t)
+
+	if _, err := service.AddRom("/downloads/Okami.iso"); err == nil {
+		t.Fatal("expected error without a source that supports manual add")
+	}
+
+	source := &manualSource{MockSource: MockSource{name: "manual"}, platforms: []string{"ps2", "gamecube"}}
+	service.registry.Register(context.Background(), source)
+
+	if _, err := service.AddRom("/downloads/Okami.iso"); !errors.Is(err, models.ErrAmbiguousPlatform) {
+		t.Fatalf("expected ambiguous platform error, got %v", err)
+	}
+
+	added, err := service.AddRomForPlatform("/downloads/Okami.iso", "ps2")
+	if err != nil {
+		t.Fatalf("AddRomForPlatform failed: %v", err)
+	}
+	if added.Instance.ID != "manual_ps2" || added.Instance.Path != "/downloads/Okami.iso" {
+		t.Errorf("unexpected instance %+v", added.Instance)
+	}
+	if added.Game.ID != "game_ps2" {
+		t.Errorf("expected game to be created, got %+v", added.Game)
+	}
+
+	stored, err := service.db.GetInstance("manual_ps2")
+	if err != nil || stored == nil {
+		t.Fatalf("expected instance to be saved, got %v, %v", stored, err)
+	}
+}
+
+func TestAddRom_NotMissingAfterRefresh(t *testing.T) {
+	service := newTestService(t)
+	source := &manualSource{MockSource: MockSource{name: "manual"}, platforms: []string{"ps2"}}
+	service.registry.Register(context.Background(), source)
+
+	path := filepath.Join(t.TempDir(), "Okami.iso")
+	if err := os.WriteFile(path, []byte("iso"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.AddRom(path); err != nil {
+		t.Fatalf("AddRom failed: %v", err)
+	}
+
+	// The ROM is outside the scanned folders, so no scan reports it
+	result, err := service.RefreshGames()
+	if err != nil {
+		t.Fatalf("RefreshGames failed: %v", err)
+	}
+	if result.Missing != 0 {
+		t.Errorf("expected a manually added ROM that still exists not to be missing, got %+v", result)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if result, err = service.RefreshGames(); err != nil {
+		t.Fatalf("RefreshGames failed: %v", err)
+	}
+	if result.Missing != 1 {
+		t.Errorf("expected a deleted ROM to be missing, got %+v", result)
+	}
+}
@@ -2,6 +2,7 @@ package metadata
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
@@ -13,6 +14,9 @@ import (
 // OnResolveCallback is called when metadata is successfully resolved
 type OnResolveCallback func(req models.FetchRequest, resolved models.ResolvedMetadata, resolverName string)
 
+// OnFailCallback is called when no resolver could resolve a request
+type OnFailCallback func(req models.FetchRequest, sourcesTried []string)
+
 // Fetcher manages the async metadata fetching queue
 type Fetcher struct {
 	queue     chan models.FetchRequest
@@ -20,6 +24,7 @@ type Fetcher struct {
 	resolvers []Resolver
 	cancelMap map[string]context.CancelFunc
 	onResolve OnResolveCallback
+	onFail    OnFailCallback
 	mu        sync.RWMutex
 	logger    *slog.Logger
 	isRunning bool
@@ -33,6 +38,20 @@ type Resolver interface {
 	Resolve(ctx context.Context, req models.FetchRequest) (models.ResolvedMetadata, error)
 }
 
+// ArtResolver is implemented by resolvers that only provide art. They don't end
+// the search like other resolvers: every art resolver that supports a game is
+// tried, and its art is merged over the art of the resolver that provided the
+// metadata, taking precedence for the art types it has.
+type ArtResolver interface {
+	ArtOnly() bool
+}
+
+// isArtOnly reports whether a resolver only provides art
+func isArtOnly(resolver Resolver) bool {
+	art, ok := resolver.(ArtResolver)
+	return ok && art.ArtOnly()
+}
+
 // NewFetcher creates a new metadata fetcher
 func NewFetcher(workers int, logger *slog.Logger) *Fetcher {
 	if workers <= 0 {
@@ -62,6 +81,11 @@ func (f *Fetcher) SetOnResolveCallback(callback OnResolveCallback) {
 	f.onResolve = callback
 }
 
+// SetOnFailCallback sets the callback for requests no resolver could resolve
+func (f *Fetcher) SetOnFailCallback(callback OnFailCallback) {
+	f.onFail = callback
+}
+
 // Start begins the fetcher workers
 func (f *Fetcher) Start() {
 	f.mu.Lock()
@@ -171,8 +195,14 @@ func (f *Fetcher) processRequest(req models.FetchRequest) {
 		"name", req.Name,
 	)
 
-	// Try each resolver in order, filtering by source/platform support
+	// Try each resolver in order, filtering by source/platform support. The first
+	// metadata resolver to succeed wins; art resolvers are always tried and their
+	// art is merged into the result.
 	var sourcesTried []string
+	var resolved models.ResolvedMetadata
+	var resolverName string
+	var art models.ResolvedMetadata
+	var artResolverName string
 	for _, resolver := range f.resolvers {
 		select {
 		case <-ctx.Done():
@@ -181,6 +211,11 @@ func (f *Fetcher) processRequest(req models.FetchRequest) {
 		default:
 		}
 
+		artOnly := isArtOnly(resolver)
+		if !artOnly && resolverName != "" {
+			continue
+		}
+
 		// Check if this resolver supports the game source/platform
 		if !resolver.Supports(req.Source, req.Platform) {
 			f.logger.Debug("resolver does not support this game",
@@ -193,7 +228,7 @@ func (f *Fetcher) processRequest(req models.FetchRequest) {
 
 		sourcesTried = append(sourcesTried, resolver.Name())
 
-		resolved, err := resolver.Resolve(ctx, req)
+		result, err := f.resolve(ctx, resolver, req)
 		if err != nil {
 			f.logger.Debug("resolver failed",
 				"resolver", resolver.Name(),
@@ -203,26 +238,110 @@ func (f *Fetcher) processRequest(req models.FetchRequest) {
 			continue
 		}
 
+		if artOnly {
+			f.logger.Info("art resolved",
+				"resolver", resolver.Name(),
+				"instanceID", req.InstanceID,
+				"artTypes", len(result.ArtURLs),
+			)
+			if artResolverName == "" {
+				art, artResolverName = result, resolver.Name()
+			} else {
+				art = mergeArt(art, artResolverName, result, resolver.Name())
+			}
+			continue
+		}
+
 		f.logger.Info("metadata resolved",
 			"resolver", resolver.Name(),
 			"instanceID", req.InstanceID,
-			"gameName", resolved.GameMetadata.Name,
+			"gameName", result.GameMetadata.Name,
 		)
+		resolved, resolverName = result, resolver.Name()
+	}
 
-		// Call the resolve callback if set
-		if f.onResolve != nil {
-			f.onResolve(req, resolved, resolver.Name())
+	switch {
+	case resolverName != "":
+		resolved = mergeArt(resolved, resolverName, art, artResolverName)
+	case artResolverName != "":
+		// Art alone is still worth keeping when no metadata was found
+		resolved, resolverName = art, artResolverName
+		resolved.ArtOnly = true
+	default:
+		// No resolver succeeded
+		f.logger.Debug("all metadata resolvers failed",
+			"instanceID", req.InstanceID,
+			"sourcesTried", sourcesTried,
+		)
+		if f.onFail != nil {
+			f.onFail(req, sourcesTried)
 		}
-
-		// Success - we're done
 		return
 	}
 
-	// No resolver succeeded
-	f.logger.Debug("all metadata resolvers failed",
+	// Call the resolve callback if set
+	if f.onResolve != nil {
+		f.onResolve(req, resolved, resolverName)
+	}
+}
+
+// mergeArt adds the art of an art resolver to resolved, which came from
+// resolvedName. The art resolver's URLs replace those of the same type.
+func mergeArt(resolved models.ResolvedMetadata, resolvedName string, art models.ResolvedMetadata, artName string) models.ResolvedMetadata {
+	if len(art.ArtURLs) == 0 {
+		return resolved
+	}
+	if resolved.ArtURLs == nil {
+		resolved.ArtURLs = make(map[string]string)
+	}
+	if resolved.ArtSources == nil {
+		resolved.ArtSources = make(map[string]string)
+	}
+	for artType, url := range art.ArtURLs {
+		source := artName
+		if name, ok := art.ArtSources[artType]; ok {
+			source = name
+		}
+		resolved.ArtURLs[artType] = url
+		if source == resolvedName {
+			delete(resolved.ArtSources, artType)
+		} else {
+			resolved.ArtSources[artType] = source
+		}
+	}
+	return resolved
+}
+
+// retryDelay is how long to wait before retrying a resolver after a retryable error
+var retryDelay = 2 * time.Second
+
+// retryableError is implemented by resolver errors that may succeed when retried,
+// such as rate limits and server errors
+type retryableError interface {
+	Retryable() bool
+}
+
+// resolve runs a resolver, retrying once after a delay when it reports a retryable error
+func (f *Fetcher) resolve(ctx context.Context, resolver Resolver, req models.FetchRequest) (models.ResolvedMetadata, error) {
+	resolved, err := resolver.Resolve(ctx, req)
+
+	var retryable retryableError
+	if err == nil || !errors.As(err, &retryable) || !retryable.Retryable() {
+		return resolved, err
+	}
+
+	f.logger.Debug("retrying resolver after retryable error",
+		"resolver", resolver.Name(),
 		"instanceID", req.InstanceID,
-		"sourcesTried", sourcesTried,
+		"error", err,
 	)
+
+	select {
+	case <-ctx.Done():
+		return resolved, err
+	case <-time.After(retryDelay):
+	}
+	return resolver.Resolve(ctx, req)
 }
 
 // LocalCacheResolver implements a local-only metadata resolver
@@ -0,0 +1,98 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// stubResolver resolves every request to a fixed result or error
+type stubResolver struct {
+	name     string
+	artOnly  bool
+	resolved models.ResolvedMetadata
+	err      error
+	calls    int
+}
+
+func (r *stubResolver) Name() string                                 { return r.name }
+func (r *stubResolver) Supports(source string, platform string) bool { return true }
+func (r *stubResolver) ArtOnly() bool                                { return r.artOnly }
+
+func (r *stubResolver) Resolve(ctx context.Context, req models.FetchRequest) (models.ResolvedMetadata, error) {
+	r.calls++
+	return r.resolved, r.err
+}
+
+// resolveWith runs a request through a fetcher with the given resolvers and
+// returns what was passed to onResolve
+func resolveWith(t *testing.T, resolvers ...Resolver) (models.ResolvedMetadata, string, bool) {
+	t.Helper()
+
+	fetcher := NewFetcher(1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	for _, resolver := range resolvers {
+		fetcher.RegisterResolver(resolver)
+	}
+
+	var resolved models.ResolvedMetadata
+	var resolverName string
+	var called bool
+	fetcher.SetOnResolveCallback(func(req models.FetchRequest, r models.ResolvedMetadata, name string) {
+		resolved, resolverName, called = r, name, true
+	})
+	fetcher.processRequest(models.FetchRequest{InstanceID: "i", GameID: "g", Name: "Game"})
+	return resolved, resolverName, called
+}
+
+func TestProcessRequest_MergesArtResolvers(t *testing.T) {
+	metadataResolver := &stubResolver{name: "meta", resolved: models.ResolvedMetadata{
+		GameMetadata: models.GameMetadata{Name: "Game"},
+		ArtURLs:      map[string]string{"cover": "meta/cover", "logo": "meta/logo"},
+	}}
+	laterResolver := &stubResolver{name: "later", resolved: models.ResolvedMetadata{
+		GameMetadata: models.GameMetadata{Name: "Other"},
+	}}
+	artResolver := &stubResolver{name: "art", artOnly: true, resolved: models.ResolvedMetadata{
+		ArtURLs: map[string]string{"logo": "art/logo", "hero": "art/hero"},
+	}}
+
+	resolved, name, called := resolveWith(t, metadataResolver, laterResolver, artResolver)
+	if !called || name != "meta" || resolved.GameMetadata.Name != "Game" {
+		t.Fatalf("expected the metadata resolver to win, got %q %+v", name, resolved)
+	}
+	if laterResolver.calls != 0 {
+		t.Error("expected metadata resolvers after the first success to be skipped")
+	}
+	if artResolver.calls != 1 {
+		t.Error("expected the art resolver to run after the metadata resolver")
+	}
+
+	wantArt := map[string]string{"cover": "meta/cover", "logo": "art/logo", "hero": "art/hero"}
+	for artType, url := range wantArt {
+		if resolved.ArtURLs[artType] != url {
+			t.Errorf("expected %s %q, got %q", artType, url, resolved.ArtURLs[artType])
+		}
+	}
+	if len(resolved.ArtSources) != 2 || resolved.ArtSources["logo"] != "art" || resolved.ArtSources["hero"] != "art" {
+		t.Errorf("expected the art resolver's types to be attributed to it, got %v", resolved.ArtSources)
+	}
+}
+
+func TestProcessRequest_ArtOnly(t *testing.T) {
+	failing := &stubResolver{name: "meta", err: errors.New("no match")}
+	artResolver := &stubResolver{name: "art", artOnly: true, resolved: models.ResolvedMetadata{
+		ArtURLs: map[string]string{"grid": "art/grid"},
+	}}
+
+	resolved, name, called := resolveWith(t, artResolver, failing)
+	if failing.calls != 1 {
+		t.Error("expected an art resolver not to short-circuit the metadata resolvers")
+	}
+	if !called || name != "art" || resolved.ArtURLs["grid"] != "art/grid" || !resolved.ArtOnly {
+		t.Errorf("expected the art to be kept without metadata, got %q %+v", name, resolved)
+	}
+}
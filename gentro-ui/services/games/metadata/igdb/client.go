@@ -3,10 +3,11 @@ package igdb
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -31,8 +32,36 @@ var PlatformIDs = map[string]int{
 	"nds":       20,
 	"3ds":       37,
 	"psp":       38,
+	"msx":       27,
+	"amiga":     16,
+	"arcade":    52,
 }
 
+// Rate limit handling for executeQuery. IGDB allows 4 requests per second.
+const (
+	maxRateLimitRetries = 2
+	maxRateLimitBackoff = 10 * time.Second
+)
+
+// rateLimitBackoff is the first wait after a 429 without Retry-After; it doubles per retry
+var rateLimitBackoff = time.Second
+
+// RegionIDs maps release region codes from models.FetchRequest to IGDB release_dates regions
+var RegionIDs = map[string]int{
+	"eu":    1,
+	"us":    2,
+	"au":    3,
+	"jp":    5,
+	"cn":    6,
+	"asia":  7,
+	"world": 8,
+	"kr":    9,
+	"br":    10,
+}
+
+// searchCandidates is how many matches are compared when a region is preferred
+const searchCandidates = 10
+
 // Client handles IGDB API communication
 type Client struct {
 	clientID     string
@@ -40,6 +69,9 @@ type Client struct {
 	accessToken  string
 	expiresAt    time.Time
 	httpClient   *http.Client
+	authURL      string
+	baseURL      string
+	names        *nameCache
 }
 
 // Game represents an IGDB game result
@@ -53,6 +85,23 @@ type Game struct {
 	Cover       int    `json:"cover"`
 	Screenshots []int  `json:"screenshots"`
 	Artworks    []int  `json:"artworks"`
+	// ReleaseDates and AgeRatings are only filled in by SearchGame
+	ReleaseDates []ReleaseDate `json:"release_dates"`
+	AgeRatings   []AgeRating   `json:"age_ratings"`
+}
+
+// AgeRating is a game's rating from one rating board
+type AgeRating struct {
+	// Rating is IGDB's rating enum: 1-5 are PEGI 3/7/12/16/18, 6 is RP,
+	// 7-12 are ESRB EC/E/E10+/T/M/AO
+	Rating int `json:"rating"`
+}
+
+// ReleaseDate is a regional release of a game on one platform
+type ReleaseDate struct {
+	Date     int64 `json:"date"`
+	Platform int   `json:"platform"`
+	Region   int   `json:"region"`
 }
 
 // Cover represents an IGDB cover image
@@ -62,6 +111,14 @@ type Cover struct {
 	Game int    `json:"game"`
 }
 
+// SearchResult is a candidate match from SearchGames, with its cover expanded
+type SearchResult struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	ReleaseDate int64  `json:"first_release_date"`
+	Cover       *Cover `json:"cover"`
+}
+
 // Screenshot represents an IGDB screenshot
 type Screenshot struct {
 	ID   int    `json:"id"`
@@ -103,6 +160,9 @@ func NewClient(clientID, clientSecret string) *Client {
 		clientID:     clientID,
 		clientSecret: clientSecret,
 		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		authURL:      twitchAuthURL,
+		baseURL:      igdbBaseURL,
+		names:        newNameCache(),
 	}
 }
 
@@ -117,15 +177,19 @@ func (c *Client) authenticate() error {
 	data.Set("client_secret", c.clientSecret)
 	data.Set("grant_type", "client_credentials")
 
-	resp, err := c.httpClient.PostForm(twitchAuthURL, data)
+	resp, err := c.httpClient.PostForm(c.authURL, data)
 	if err != nil {
-		return fmt.Errorf("failed to authenticate with Twitch: %w", err)
+		return fmt.Errorf("failed to authenticate with Twitch: %w", networkError(err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("authentication failed: %s (status %d)", string(body), resp.StatusCode)
+		apiErr := classifyResponse(resp)
+		// The token endpoint rejecting our credentials is not an expired token
+		if apiErr.class == ErrAuthExpired {
+			apiErr.class = ErrPermanent
+		}
+		return fmt.Errorf("authentication failed: %w", apiErr)
 	}
 
 	var result struct {
@@ -143,17 +207,32 @@ func (c *Client) authenticate() error {
 	return nil
 }
 
-// SearchGame searches for a game by name and platform
-func (c *Client) SearchGame(name string, platformID int) (*Game, error) {
+// SearchGame searches for a game by name and platform. When region is a known
+// region code, a match released in that region on the platform is preferred,
+// and its regional release date replaces first_release_date; otherwise the
+// first match is returned. A platformID of 0 searches every platform, ignoring region.
+func (c *Client) SearchGame(name string, platformID int, region string) (*Game, error) {
 	if err := c.authenticate(); err != nil {
 		return nil, err
 	}
 
+	regionID, preferRegion := RegionIDs[region]
+	preferRegion = preferRegion && platformID > 0
+	limit := 1
+	if preferRegion {
+		limit = searchCandidates
+	}
+
+	where := fmt.Sprintf(`name ~ "%s"`, escapeQuery(name))
+	if platformID > 0 {
+		where += fmt.Sprintf(" & platforms = (%d)", platformID)
+	}
 	query := fmt.Sprintf(
-		`fields id, name, summary, first_release_date, involved_companies, genres, cover, screenshots, artworks;
-		where name ~ "%s" & platforms = (%d);
-		limit 1;`,
-		escapeQuery(name), platformID,
+		`fields id, name, summary, first_release_date, involved_companies, genres, cover, screenshots, artworks,
+			release_dates.date, release_dates.platform, release_dates.region, age_ratings.rating;
+		where %s;
+		limit %d;`,
+		where, limit,
 	)
 
 	games, err := c.queryGames(query)
@@ -162,12 +241,56 @@ func (c *Client) SearchGame(name string, platformID int) (*Game, error) {
 	}
 
 	if len(games) == 0 {
+		if platformID == 0 {
+			return nil, fmt.Errorf("no game found for '%s'", name)
+		}
 		return nil, fmt.Errorf("no game found for '%s' on platform %d", name, platformID)
 	}
 
+	if preferRegion {
+		for i := range games {
+			if release, ok := games[i].regionalRelease(platformID, regionID); ok {
+				if release.Date > 0 {
+					games[i].ReleaseDate = release.Date
+				}
+				return &games[i], nil
+			}
+		}
+	}
+
 	return &games[0], nil
 }
 
+// SearchGames returns up to limit games matching name, for the user to pick
+// from. A platformID of 0 searches every platform.
+func (c *Client) SearchGames(name string, platformID, limit int) ([]SearchResult, error) {
+	if err := c.authenticate(); err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`search "%s"; fields id, name, first_release_date, cover.url;`, escapeQuery(name))
+	if platformID > 0 {
+		query += fmt.Sprintf(" where platforms = (%d);", platformID)
+	}
+	query += fmt.Sprintf(" limit %d;", limit)
+
+	var results []SearchResult
+	if err := c.executeQuery("/games", query, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// regionalRelease finds the game's release on a platform in a region
+func (g *Game) regionalRelease(platformID, regionID int) (ReleaseDate, bool) {
+	for _, release := range g.ReleaseDates {
+		if release.Platform == platformID && release.Region == regionID {
+			return release, true
+		}
+	}
+	return ReleaseDate{}, false
+}
+
 // GetGameByID retrieves a game by its IGDB ID
 func (c *Client) GetGameByID(gameID int) (*Game, error) {
 	if err := c.authenticate(); err != nil {
@@ -262,44 +385,166 @@ func (c *Client) GetLogos(gameID int) ([]Logo, error) {
 	return c.queryLogos(query)
 }
 
-// GetCompanies retrieves company names by IDs
+// GetCompanies retrieves company names by IDs. Names are cached, so only
+// companies not seen before are queried.
 func (c *Client) GetCompanies(companyIDs []int) ([]Company, error) {
 	if len(companyIDs) == 0 {
 		return nil, nil
 	}
 
-	if err := c.authenticate(); err != nil {
-		return nil, err
-	}
+	if _, missing := c.names.lookup(c.names.companies, companyIDs); len(missing) > 0 {
+		if err := c.authenticate(); err != nil {
+			return nil, err
+		}
 
-	idsStr := joinInts(companyIDs)
-	query := fmt.Sprintf(
-		`fields id, name;
-		where id = (%s);`,
-		idsStr,
-	)
+		query := fmt.Sprintf(
+			`fields id, name;
+			where id = (%s);`,
+			joinInts(missing),
+		)
+		fetched, err := c.queryCompanies(query)
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]Genre, 0, len(fetched))
+		for _, company := range fetched {
+			entries = append(entries, Genre(company))
+		}
+		c.names.store(c.names.companies, entries)
+	}
 
-	return c.queryCompanies(query)
+	cached, _ := c.names.lookup(c.names.companies, companyIDs)
+	companies := make([]Company, 0, len(cached))
+	for _, company := range cached {
+		companies = append(companies, Company(company))
+	}
+	return companies, nil
 }
 
-// GetGenres retrieves genre names by IDs
+// GetGenres retrieves genre names by IDs. The full genre list is cached on
+// first use, so later calls only query genres added since.
 func (c *Client) GetGenres(genreIDs []int) ([]Genre, error) {
 	if len(genreIDs) == 0 {
 		return nil, nil
 	}
 
+	if query := c.genresQuery(genreIDs); query != "" {
+		if err := c.authenticate(); err != nil {
+			return nil, err
+		}
+		fetched, err := c.queryGenres(query)
+		if err != nil {
+			return nil, err
+		}
+		c.storeGenres(query, fetched)
+	}
+
+	genres, _ := c.names.lookup(c.names.genres, genreIDs)
+	return genres, nil
+}
+
+// genresQuery returns the query for genres not yet cached: the full list on
+// first use, only the missing IDs after that, or "" when all are cached
+func (c *Client) genresQuery(genreIDs []int) string {
+	if !c.names.hasAllGenres() {
+		return allGenresQuery
+	}
+	if _, missing := c.names.lookup(c.names.genres, genreIDs); len(missing) > 0 {
+		return fmt.Sprintf("fields id, name; where id = (%s);", joinInts(missing))
+	}
+	return ""
+}
+
+// storeGenres caches genres fetched by query
+func (c *Client) storeGenres(query string, genres []Genre) {
+	if query == allGenresQuery {
+		c.names.loadGenres(genres)
+	} else {
+		c.names.store(c.names.genres, genres)
+	}
+}
+
+// logoArtworkTypes are the artwork_type IDs IGDB uses for logos
+var logoArtworkTypes = map[int]bool{5: true, 6: true, 7: true}
+
+// GameAssets holds a game's cover, screenshots, artworks, logos and genres
+type GameAssets struct {
+	Cover       *Cover
+	Screenshots []Screenshot
+	Artworks    []Artwork
+	Logos       []Logo
+	Genres      []Genre
+}
+
+// multiqueryResult is one named result from the /multiquery endpoint
+type multiqueryResult struct {
+	Name   string          `json:"name"`
+	Result json.RawMessage `json:"result"`
+}
+
+// GetGameAssets fetches all of a game's sub-entities in a single /multiquery
+// request. Artworks are fetched once and split into artworks and logos by type,
+// and genres come from the name cache, which the same request fills in.
+func (c *Client) GetGameAssets(game *Game) (*GameAssets, error) {
 	if err := c.authenticate(); err != nil {
 		return nil, err
 	}
 
-	idsStr := joinInts(genreIDs)
-	query := fmt.Sprintf(
-		`fields id, name;
-		where id = (%s);`,
-		idsStr,
-	)
+	var query strings.Builder
+	if game.Cover > 0 {
+		fmt.Fprintf(&query, "query covers \"cover\" { fields id, url, game; where id = %d; };\n", game.Cover)
+	}
+	if len(game.Screenshots) > 0 {
+		fmt.Fprintf(&query, "query screenshots \"screenshots\" { fields id, url, game; where game = %d; };\n", game.ID)
+	}
+	fmt.Fprintf(&query, "query artworks \"artworks\" { fields id, url, game, artwork_type; where game = %d; limit 50; };\n", game.ID)
+	var genresQuery string
+	if len(game.Genres) > 0 {
+		genresQuery = c.genresQuery(game.Genres)
+	}
+	if genresQuery != "" {
+		fmt.Fprintf(&query, "query genres \"genres\" { %s };\n", genresQuery)
+	}
+
+	var results []multiqueryResult
+	if err := c.executeQuery("/multiquery", query.String(), &results); err != nil {
+		return nil, err
+	}
+
+	assets := &GameAssets{}
+	for _, result := range results {
+		var err error
+		switch result.Name {
+		case "cover":
+			var covers []Cover
+			err = json.Unmarshal(result.Result, &covers)
+			if len(covers) > 0 {
+				assets.Cover = &covers[0]
+			}
+		case "screenshots":
+			err = json.Unmarshal(result.Result, &assets.Screenshots)
+		case "artworks":
+			var artworks []Artwork
+			err = json.Unmarshal(result.Result, &artworks)
+			for _, artwork := range artworks {
+				if logoArtworkTypes[artwork.ArtworkType] {
+					assets.Logos = append(assets.Logos, Logo(artwork))
+				} else {
+					assets.Artworks = append(assets.Artworks, artwork)
+				}
+			}
+		case "genres":
+			var genres []Genre
+			err = json.Unmarshal(result.Result, &genres)
+			c.storeGenres(genresQuery, genres)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", result.Name, err)
+		}
+	}
+	assets.Genres, _ = c.names.lookup(c.names.genres, game.Genres)
 
-	return c.queryGenres(query)
+	return assets, nil
 }
 
 // queryGames executes a games query
@@ -365,9 +610,45 @@ func (c *Client) queryGenres(query string) ([]Genre, error) {
 	return genres, nil
 }
 
-// executeQuery executes an IGDB API query
+// executeQuery executes an IGDB API query. Failures are returned as *APIError;
+// rate limited queries are retried after a backoff, and a rejected token is
+// renewed once, since it can expire between authenticate and the query.
 func (c *Client) executeQuery(endpoint, query string, result interface{}) error {
-	url := igdbBaseURL + endpoint
+	backoff := rateLimitBackoff
+	reauthenticated := false
+	for attempt := 0; ; attempt++ {
+		err := c.doQuery(endpoint, query, result)
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			return err
+		}
+
+		if apiErr.class == ErrAuthExpired && !reauthenticated {
+			reauthenticated = true
+			c.accessToken = ""
+			if authErr := c.authenticate(); authErr != nil {
+				return authErr
+			}
+			continue
+		}
+
+		if attempt >= maxRateLimitRetries || apiErr.class != ErrRateLimited {
+			return err
+		}
+
+		wait := apiErr.RetryAfter
+		if wait <= 0 {
+			wait = backoff
+			backoff *= 2
+		}
+		time.Sleep(min(wait, maxRateLimitBackoff))
+	}
+}
+
+// doQuery sends a single IGDB API query
+func (c *Client) doQuery(endpoint, query string, result interface{}) error {
+	url := c.baseURL + endpoint
 
 	req, err := http.NewRequest("POST", url, bytes.NewBufferString(query))
 	if err != nil {
@@ -380,13 +661,12 @@ func (c *Client) executeQuery(endpoint, query string, result interface{}) error
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to execute query: %w", err)
+		return fmt.Errorf("failed to execute query: %w", networkError(err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("query failed: %s (status %d)", string(body), resp.StatusCode)
+		return fmt.Errorf("query failed: %w", classifyResponse(resp))
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
@@ -0,0 +1,279 @@
+package igdb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client already holding a valid token, pointed at server
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewClient("id", "secret")
+	client.authURL = server.URL + "/token"
+	client.baseURL = server.URL
+	client.accessToken = "token"
+	client.expiresAt = time.Now().Add(time.Hour)
+	return client
+}
+
+func TestExecuteQuery_ClassifiesErrors(t *testing.T) {
+	originalBackoff := rateLimitBackoff
+	rateLimitBackoff = time.Millisecond
+	t.Cleanup(func() { rateLimitBackoff = originalBackoff })
+
+	tests := []struct {
+		status    int
+		class     error
+		retryable bool
+	}{
+		{http.StatusTooManyRequests, ErrRateLimited, true},
+		{http.StatusBadRequest, ErrPermanent, false},
+		{http.StatusNotFound, ErrPermanent, false},
+		{http.StatusInternalServerError, ErrTransient, true},
+		{http.StatusServiceUnavailable, ErrTransient, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.status), func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "nope", tt.status)
+			})
+
+			var games []Game
+			err := client.executeQuery("/games", "fields id;", &games)
+			if !errors.Is(err, tt.class) {
+				t.Fatalf("expected %v, got %v", tt.class, err)
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected *APIError, got %T", err)
+			}
+			if apiErr.StatusCode != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, apiErr.StatusCode)
+			}
+			if apiErr.Retryable() != tt.retryable {
+				t.Errorf("expected Retryable %v", tt.retryable)
+			}
+		})
+	}
+}
+
+func TestExecuteQuery_RetriesRateLimit(t *testing.T) {
+	originalBackoff := rateLimitBackoff
+	rateLimitBackoff = time.Millisecond
+	t.Cleanup(func() { rateLimitBackoff = originalBackoff })
+
+	var requests int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`[{"id": 1, "name": "Zelda"}]`))
+	})
+
+	games, err := client.queryGames("fields id, name;")
+	if err != nil {
+		t.Fatalf("expected rate limited query to succeed on retry: %v", err)
+	}
+	if len(games) != 1 || games[0].Name != "Zelda" {
+		t.Errorf("unexpected games %+v", games)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+}
+
+func TestExecuteQuery_NetworkErrorIsTransient(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {})
+	client.baseURL = "http://127.0.0.1:0"
+
+	var games []Game
+	err := client.executeQuery("/games", "fields id;", &games)
+	if !errors.Is(err, ErrTransient) {
+		t.Fatalf("expected ErrTransient, got %v", err)
+	}
+}
+
+func TestAuthenticate_RejectedCredentialsArePermanent(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid client", http.StatusUnauthorized)
+	})
+	client.accessToken = ""
+
+	if err := client.authenticate(); !errors.Is(err, ErrPermanent) {
+		t.Fatalf("expected ErrPermanent, got %v", err)
+	}
+}
+
+func TestExecuteQuery_ReauthenticatesOn401(t *testing.T) {
+	var tokenRequests, queries int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenRequests++
+			w.Write([]byte(`{"access_token": "fresh", "expires_in": 3600}`))
+			return
+		}
+
+		queries++
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[{"id": 1, "name": "Zelda"}]`))
+	})
+	client.accessToken = "stale"
+
+	games, err := client.queryGames("fields id, name;")
+	if err != nil {
+		t.Fatalf("expected query to succeed after re-authentication: %v", err)
+	}
+	if len(games) != 1 {
+		t.Errorf("unexpected games %+v", games)
+	}
+	if tokenRequests != 1 || queries != 2 {
+		t.Errorf("expected 1 token request and 2 queries, got %d and %d", tokenRequests, queries)
+	}
+	if client.accessToken != "fresh" {
+		t.Errorf("expected cached token to be replaced, got %q", client.accessToken)
+	}
+}
+
+func TestExecuteQuery_RetriesAuthOnce(t *testing.T) {
+	var queries int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			w.Write([]byte(`{"access_token": "fresh", "expires_in": 3600}`))
+			return
+		}
+		queries++
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	var games []Game
+	err := client.executeQuery("/games", "fields id;", &games)
+	if !errors.Is(err, ErrAuthExpired) {
+		t.Fatalf("expected ErrAuthExpired, got %v", err)
+	}
+	if queries != 2 {
+		t.Errorf("expected a single retry, got %d queries", queries)
+	}
+}
+
+func TestSearchGame_PrefersRegion(t *testing.T) {
+	var limits []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		limits = append(limits, regexp.MustCompile(`limit \d+`).FindString(string(body)))
+		w.Write([]byte(`[
+			{"id": 1, "name": "Mother", "first_release_date": 100, "release_dates": [{"date": 100, "platform": 18, "region": 5}]},
+			{"id": 2, "name": "EarthBound Beginnings", "first_release_date": 100, "release_dates": [
+				{"date": 100, "platform": 18, "region": 5},
+				{"date": 200, "platform": 18, "region": 2}
+			]}
+		]`))
+	})
+
+	game, err := client.SearchGame("Mother", 18, "us")
+	if err != nil {
+		t.Fatalf("SearchGame failed: %v", err)
+	}
+	if game.ID != 2 {
+		t.Errorf("expected the US release, got game %d", game.ID)
+	}
+	if game.ReleaseDate != 200 {
+		t.Errorf("expected the US release date, got %d", game.ReleaseDate)
+	}
+
+	// Unknown or missing regions take the first result
+	for _, region := range []string{"", "de"} {
+		game, err := client.SearchGame("Mother", 18, region)
+		if err != nil {
+			t.Fatalf("SearchGame failed: %v", err)
+		}
+		if game.ID != 1 {
+			t.Errorf("expected first result for region %q, got game %d", region, game.ID)
+		}
+	}
+
+	// No match in the region falls back to the first result
+	if game, _ := client.SearchGame("Mother", 18, "eu"); game == nil || game.ID != 1 {
+		t.Errorf("expected first result when no release matches the region, got %+v", game)
+	}
+
+	if limits[0] != "limit 10" || limits[1] != "limit 1" {
+		t.Errorf("expected candidates only when a region is preferred, got %v", limits)
+	}
+}
+
+func TestGetGameAssets_CachesGenres(t *testing.T) {
+	var queries []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		queries = append(queries, string(body))
+		switch r.URL.Path {
+		case "/multiquery":
+			if strings.Contains(string(body), `"genres"`) {
+				w.Write([]byte(`[{"name": "genres", "result": [{"id": 3, "name": "Adventure"}, {"id": 5, "name": "Shooter"}]}]`))
+				return
+			}
+			w.Write([]byte(`[]`))
+		case "/genres":
+			w.Write([]byte(`[{"id": 8, "name": "Platform"}]`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+
+	// The first game loads the full genre list
+	assets, err := client.GetGameAssets(&Game{ID: 1, Genres: []int{3}})
+	if err != nil {
+		t.Fatalf("GetGameAssets failed: %v", err)
+	}
+	if len(assets.Genres) != 1 || assets.Genres[0].Name != "Adventure" {
+		t.Errorf("unexpected genres %v", assets.Genres)
+	}
+	if !strings.Contains(queries[0], allGenresQuery) {
+		t.Errorf("expected the first query to load every genre, got %s", queries[0])
+	}
+
+	// Later games are answered from the cache
+	assets, err = client.GetGameAssets(&Game{ID: 2, Genres: []int{5, 3}})
+	if err != nil {
+		t.Fatalf("GetGameAssets failed: %v", err)
+	}
+	if strings.Contains(queries[1], "genres") {
+		t.Errorf("expected cached genres not to be queried, got %s", queries[1])
+	}
+	if len(assets.Genres) != 2 || assets.Genres[0].Name != "Shooter" || assets.Genres[1].Name != "Adventure" {
+		t.Errorf("unexpected genres %v", assets.Genres)
+	}
+
+	// A genre added since is fetched on its own
+	genres, err := client.GetGenres([]int{3, 8})
+	if err != nil {
+		t.Fatalf("GetGenres failed: %v", err)
+	}
+	if !strings.Contains(queries[2], "where id = (8)") {
+		t.Errorf("expected only the missing genre to be queried, got %s", queries[2])
+	}
+	if len(genres) != 2 || genres[1].Name != "Platform" {
+		t.Errorf("unexpected genres %v", genres)
+	}
+	if _, err := client.GetGenres([]int{8}); err != nil || len(queries) != 3 {
+		t.Errorf("expected a cached genre not to be queried, got %d queries (%v)", len(queries), err)
+	}
+}
@@ -0,0 +1,91 @@
+package igdb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Error classes for failed IGDB and Twitch requests. Match them with errors.Is.
+var (
+	// ErrAuthExpired means the access token was rejected (401)
+	ErrAuthExpired = errors.New("igdb authentication expired")
+	// ErrRateLimited means too many requests were made (429)
+	ErrRateLimited = errors.New("igdb rate limit exceeded")
+	// ErrPermanent means the request itself was rejected and retrying won't help (other 4xx)
+	ErrPermanent = errors.New("igdb rejected request")
+	// ErrTransient means the server or network failed and a later retry may succeed (5xx)
+	ErrTransient = errors.New("igdb temporarily unavailable")
+)
+
+// maxErrorBody bounds how much of an error response is kept in APIError
+const maxErrorBody = 512
+
+// APIError is a classified non-200 response or network failure
+type APIError struct {
+	// StatusCode is the HTTP status, or 0 when the request never got a response
+	StatusCode int
+	// Body is the start of the response body
+	Body string
+	// RetryAfter is the server's requested delay for rate limited responses
+	RetryAfter time.Duration
+
+	class error
+	err   error
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("%v: %v", e.class, e.err)
+	}
+	return fmt.Sprintf("%v: %s (status %d)", e.class, e.Body, e.StatusCode)
+}
+
+// Unwrap exposes the error class and any underlying network error
+func (e *APIError) Unwrap() []error {
+	if e.err != nil {
+		return []error{e.class, e.err}
+	}
+	return []error{e.class}
+}
+
+// Retryable reports whether retrying the request later may succeed
+func (e *APIError) Retryable() bool {
+	return e.class == ErrTransient || e.class == ErrRateLimited
+}
+
+// classifyResponse builds an APIError for a non-200 response
+func classifyResponse(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	apiErr := &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		apiErr.class = ErrAuthExpired
+	case resp.StatusCode == http.StatusTooManyRequests:
+		apiErr.class = ErrRateLimited
+		apiErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	case resp.StatusCode >= 500:
+		apiErr.class = ErrTransient
+	default:
+		apiErr.class = ErrPermanent
+	}
+	return apiErr
+}
+
+// networkError classifies a request that failed without a response
+func networkError(err error) *APIError {
+	return &APIError{class: ErrTransient, err: err}
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,66 @@
+package igdb
+
+import "sync"
+
+// allGenresQuery fetches every IGDB genre; there are only a few dozen
+const allGenresQuery = "fields id, name; limit 500;"
+
+// nameCache remembers genre and company names by ID. IGDB rarely renames them,
+// so they're kept for the life of the client instead of re-fetched per game.
+type nameCache struct {
+	mu        sync.Mutex
+	genres    map[int]string
+	companies map[int]string
+	// genresLoaded is set once the full genre list has been cached
+	genresLoaded bool
+}
+
+// lookup returns the cached entries for ids in order, and the IDs not cached
+func (n *nameCache) lookup(names map[int]string, ids []int) ([]Genre, []int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var found []Genre
+	var missing []int
+	for _, id := range ids {
+		if name, ok := names[id]; ok {
+			found = append(found, Genre{ID: id, Name: name})
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return found, missing
+}
+
+// store caches fetched entries in names
+func (n *nameCache) store(names map[int]string, entries []Genre) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, entry := range entries {
+		names[entry.ID] = entry.Name
+	}
+}
+
+// loadGenres caches the full genre list
+func (n *nameCache) loadGenres(genres []Genre) {
+	n.store(n.genres, genres)
+
+	n.mu.Lock()
+	n.genresLoaded = true
+	n.mu.Unlock()
+}
+
+// hasAllGenres reports whether the full genre list has been cached
+func (n *nameCache) hasAllGenres() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.genresLoaded
+}
+
+func newNameCache() *nameCache {
+	return &nameCache{
+		genres:    make(map[int]string),
+		companies: make(map[int]string),
+	}
+}
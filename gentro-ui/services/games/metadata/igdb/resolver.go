@@ -5,15 +5,22 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/rhythmerc/gentro-ui/services/games/models"
 )
 
+// maxGalleryImages limits how many screenshots and artworks are kept for the gallery
+const maxGalleryImages = 10
+
 // Resolver implements the metadata.Resolver interface for IGDB
 type Resolver struct {
 	client *Client
 	logger *slog.Logger
+
+	// searchUnmapped resolves platforms missing from PlatformIDs by name alone
+	searchUnmapped atomic.Bool
 }
 
 // NewResolver creates a new IGDB resolver
@@ -33,7 +40,14 @@ func (r *Resolver) Name() string {
 	return "igdb"
 }
 
-// Supports returns true for emulated games on supported platforms
+// SetSearchUnmappedPlatforms enables a name-only search across all of IGDB for
+// platforms missing from PlatformIDs, which otherwise get no metadata at all
+func (r *Resolver) SetSearchUnmappedPlatforms(enabled bool) {
+	r.searchUnmapped.Store(enabled)
+}
+
+// Supports returns true for emulated games on supported platforms, and on any
+// platform when unmapped platforms are searched by name
 func (r *Resolver) Supports(source, platform string) bool {
 	// Only support emulated games (not Steam)
 	if source != "emulated" {
@@ -42,7 +56,7 @@ func (r *Resolver) Supports(source, platform string) bool {
 
 	// Check if platform is supported
 	_, supported := PlatformIDs[strings.ToLower(platform)]
-	return supported
+	return supported || r.searchUnmapped.Load()
 }
 
 // Resolve fetches metadata from IGDB
@@ -56,17 +70,21 @@ func (r *Resolver) Resolve(ctx context.Context, req models.FetchRequest) (models
 	// Get platform ID
 	platformID, ok := PlatformIDs[strings.ToLower(req.Platform)]
 	if !ok {
-		return result, fmt.Errorf("unsupported platform: %s", req.Platform)
+		if !r.searchUnmapped.Load() {
+			return result, fmt.Errorf("unsupported platform: %s", req.Platform)
+		}
+		return r.resolveByName(req, result)
 	}
 
 	r.logger.Info("searching IGDB for game",
 		"name", req.Name,
 		"platform", req.Platform,
 		"platformID", platformID,
+		"region", req.Region,
 	)
 
-	// Search for the game
-	game, err := r.client.SearchGame(req.Name, platformID)
+	// Search for the game, preferring a release in the ROM's region
+	game, err := r.client.SearchGame(req.Name, platformID, req.Region)
 	if err != nil {
 		return result, fmt.Errorf("failed to search game: %w", err)
 	}
@@ -79,102 +97,67 @@ func (r *Resolver) Resolve(ctx context.Context, req models.FetchRequest) (models
 	// Fill in basic metadata
 	result.GameMetadata.Name = game.Name
 	result.GameMetadata.Description = game.Summary
+	result.GameMetadata.AgeRating = normalizeAgeRating(game.AgeRatings)
 
 	if game.ReleaseDate > 0 {
 		releaseDate := time.Unix(game.ReleaseDate, 0)
 		result.GameMetadata.ReleaseDate = &releaseDate
 	}
 
-	// Fetch genres
-	if len(game.Genres) > 0 {
-		genres, err := r.client.GetGenres(game.Genres)
-		if err != nil {
-			r.logger.Warn("failed to fetch genres", "error", err)
-		} else {
-			for _, g := range genres {
-				result.GameMetadata.Genres = append(result.GameMetadata.Genres, g.Name)
-			}
-		}
-	}
-
-	// Fetch companies (developers/publishers)
+	// Fetch developers/publishers
 	if len(game.Developers) > 0 {
 		// Note: IGDB's involved_companies is more complex, this is simplified
 		// In a full implementation, we'd fetch involved_companies first
 		r.logger.Info("developers found but not fully implemented", "count", len(game.Developers))
 	}
 
-	// Fetch cover art
-	if game.Cover > 0 {
-		cover, err := r.client.GetCover(game.Cover)
-		if err != nil {
-			r.logger.Warn("failed to fetch cover", "error", err)
-		} else if cover.URL != "" {
-			// IGDB URLs need to be converted to full URLs
-			result.ArtURLs["cover"] = expandImageURL(cover.URL)
-		}
+	// Fetch genres and art in one request
+	assets, err := r.client.GetGameAssets(game)
+	if err != nil {
+		r.logger.Warn("failed to fetch game assets", "error", err)
+		assets = &GameAssets{}
 	}
 
-	// Fetch screenshots
-	if len(game.Screenshots) > 0 {
-		screenshots, err := r.client.GetScreenshots(game.ID)
-		if err != nil {
-			r.logger.Warn("failed to fetch screenshots", "error", err)
-		} else if len(screenshots) > 0 {
-			// Use first screenshot as library art
-			result.ArtURLs["screenshot"] = expandImageURL(screenshots[0].URL)
-		}
+	for _, g := range assets.Genres {
+		result.GameMetadata.Genres = append(result.GameMetadata.Genres, g.Name)
 	}
 
-	// Fetch artworks (hero images)
-	if len(game.Artworks) > 0 {
-		artworks, err := r.client.GetArtworks(game.ID)
-		if err != nil {
-			r.logger.Warn("failed to fetch artworks", "error", err)
-		} else if len(artworks) > 0 {
-			result.ArtURLs["artwork"] = expandImageURL(artworks[0].URL)
-		}
+	// IGDB URLs need to be converted to full URLs
+	if assets.Cover != nil && assets.Cover.URL != "" {
+		result.ArtURLs["cover"] = expandImageURL(assets.Cover.URL)
 	}
 
-	// Fetch logos
-	logos, err := r.client.GetLogos(game.ID)
-	if err != nil {
-		r.logger.Warn("failed to fetch logos", "error", err)
-	} else if len(logos) > 0 {
-		// Prioritize: color (7) > white (5) > black (6)
-		var colorLogo, whiteLogo, blackLogo *Logo
-		for i := range logos {
-			switch logos[i].ArtworkType {
-			case 7:
-				colorLogo = &logos[i]
-			case 5:
-				whiteLogo = &logos[i]
-			case 6:
-				blackLogo = &logos[i]
+	// Use first screenshot as library art, and keep the rest for the gallery
+	if len(assets.Screenshots) > 0 {
+		result.ArtURLs["screenshot"] = expandImageURL(assets.Screenshots[0].URL)
+		for i, screenshot := range assets.Screenshots {
+			if i >= maxGalleryImages {
+				break
 			}
+			result.ArtURLs[fmt.Sprintf("screenshot.%d", i)] = expandImageURL(screenshot.URL)
 		}
+	}
 
-		// Select best available logo
-		var selectedLogo *Logo
-		if colorLogo != nil {
-			selectedLogo = colorLogo
-			r.logger.Debug("using color logo", "game", game.Name)
-		} else if whiteLogo != nil {
-			selectedLogo = whiteLogo
-			r.logger.Debug("using white logo", "game", game.Name)
-		} else if blackLogo != nil {
-			selectedLogo = blackLogo
-			r.logger.Debug("using black logo", "game", game.Name)
+	// Artworks (hero images)
+	if len(assets.Artworks) > 0 {
+		result.ArtURLs["artwork"] = expandImageURL(assets.Artworks[0].URL)
+		for i, artwork := range assets.Artworks {
+			if i >= maxGalleryImages {
+				break
+			}
+			result.ArtURLs[fmt.Sprintf("artwork.%d", i)] = expandImageURL(artwork.URL)
 		}
+	}
 
-		if selectedLogo != nil {
-			result.ArtURLs["logo"] = expandImageURL(selectedLogo.URL)
-		}
+	if logo := selectLogo(assets.Logos); logo != nil {
+		result.ArtURLs["logo"] = expandImageURL(logo.URL)
 	}
 
 	// Set platform-specific metadata
 	result.PlatformMetadata[req.Platform] = models.PlatformMetadata{
-		Platform: req.Platform,
+		Platform:    req.Platform,
+		ReleaseDate: result.GameMetadata.ReleaseDate,
+		Region:      req.Region,
 	}
 
 	r.logger.Info("successfully resolved metadata from IGDB",
@@ -186,6 +169,113 @@ func (r *Resolver) Resolve(ctx context.Context, req models.FetchRequest) (models
 	return result, nil
 }
 
+// Search returns IGDB games matching query for manual matching. An empty or
+// unmapped platform searches every platform.
+func (r *Resolver) Search(query, platform string, limit int) ([]models.IGDBResult, error) {
+	platformID := PlatformIDs[strings.ToLower(platform)]
+	matches, err := r.client.SearchGames(query, platformID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search games: %w", err)
+	}
+
+	results := make([]models.IGDBResult, 0, len(matches))
+	for _, match := range matches {
+		result := models.IGDBResult{ID: match.ID, Name: match.Name}
+		if match.ReleaseDate > 0 {
+			result.ReleaseYear = time.Unix(match.ReleaseDate, 0).UTC().Year()
+		}
+		if match.Cover != nil && match.Cover.URL != "" {
+			result.CoverURL = thumbImageURL(match.Cover.URL)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// resolveByName fills in just the name and cover for a platform IGDB isn't mapped
+// for. Without a platform the match may be another release of the game, so
+// platform-specific details like release dates are left out.
+func (r *Resolver) resolveByName(req models.FetchRequest, result models.ResolvedMetadata) (models.ResolvedMetadata, error) {
+	r.logger.Info("searching IGDB by name for unmapped platform", "name", req.Name, "platform", req.Platform)
+
+	game, err := r.client.SearchGame(req.Name, 0, "")
+	if err != nil {
+		return result, fmt.Errorf("failed to search game: %w", err)
+	}
+	result.GameMetadata.Name = game.Name
+
+	if game.Cover > 0 {
+		cover, err := r.client.GetCover(game.Cover)
+		if err != nil {
+			r.logger.Warn("failed to fetch cover", "error", err, "gameID", game.ID)
+		} else if cover != nil && cover.URL != "" {
+			result.ArtURLs["cover"] = expandImageURL(cover.URL)
+		}
+	}
+
+	r.logger.Info("resolved name-only metadata from IGDB", "game", game.Name, "platform", req.Platform)
+	return result, nil
+}
+
+// igdbAgeRatings maps IGDB's age rating enum to normalized ratings. RP (rating pending) is omitted.
+var igdbAgeRatings = map[int]models.AgeRating{
+	1:  models.AgeRatingEveryone,   // PEGI 3
+	2:  models.AgeRatingEveryone10, // PEGI 7
+	3:  models.AgeRatingTeen,       // PEGI 12
+	4:  models.AgeRatingMature,     // PEGI 16
+	5:  models.AgeRatingAdult,      // PEGI 18
+	7:  models.AgeRatingEveryone,   // ESRB EC
+	8:  models.AgeRatingEveryone,   // ESRB E
+	9:  models.AgeRatingEveryone10, // ESRB E10+
+	10: models.AgeRatingTeen,       // ESRB T
+	11: models.AgeRatingMature,     // ESRB M
+	12: models.AgeRatingAdult,      // ESRB AO
+}
+
+// normalizeAgeRating returns the most restrictive of a game's ratings, so
+// boards that disagree never make a game look suitable for younger players
+func normalizeAgeRating(ratings []AgeRating) models.AgeRating {
+	var strictest models.AgeRating
+	for _, rating := range ratings {
+		if normalized := igdbAgeRatings[rating.Rating]; normalized.Rank() > strictest.Rank() {
+			strictest = normalized
+		}
+	}
+	return strictest
+}
+
+// selectLogo picks the best logo: color (7) > white (5) > black (6)
+func selectLogo(logos []Logo) *Logo {
+	var colorLogo, whiteLogo, blackLogo *Logo
+	for i := range logos {
+		switch logos[i].ArtworkType {
+		case 7:
+			colorLogo = &logos[i]
+		case 5:
+			whiteLogo = &logos[i]
+		case 6:
+			blackLogo = &logos[i]
+		}
+	}
+
+	switch {
+	case colorLogo != nil:
+		return colorLogo
+	case whiteLogo != nil:
+		return whiteLogo
+	default:
+		return blackLogo
+	}
+}
+
+// thumbImageURL converts an IGDB image URL to its small cover size, for lists
+func thumbImageURL(url string) string {
+	if strings.HasPrefix(url, "//") {
+		url = "https:" + url
+	}
+	return strings.Replace(url, "t_thumb", "t_cover_small", 1)
+}
+
 // expandImageURL converts IGDB's image URL format to a full URL
 // and replaces size modifiers with t_720p to get a high resolution image
 func expandImageURL(url string) string {
@@ -0,0 +1,165 @@
+package igdb
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestResolve_BatchesSubQueries(t *testing.T) {
+	requests := make(map[string]int)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/games":
+			w.Write([]byte(`[{"id": 1, "name": "Zelda", "cover": 9, "genres": [3], "screenshots": [4], "artworks": [5]}]`))
+		case "/multiquery":
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), `"cover"`) || !strings.Contains(string(body), `"genres"`) {
+				t.Errorf("expected cover and genres in multiquery, got %s", body)
+			}
+			w.Write([]byte(`[
+				{"name": "cover", "result": [{"id": 9, "url": "//images.igdb.com/t_thumb/cover.jpg"}]},
+				{"name": "screenshots", "result": [{"id": 4, "url": "//images.igdb.com/t_thumb/shot.jpg"}]},
+				{"name": "artworks", "result": [
+					{"id": 5, "url": "//images.igdb.com/t_thumb/art.jpg", "artwork_type": 1},
+					{"id": 6, "url": "//images.igdb.com/t_thumb/white.jpg", "artwork_type": 5},
+					{"id": 7, "url": "//images.igdb.com/t_thumb/color.jpg", "artwork_type": 7}
+				]},
+				{"name": "genres", "result": [{"id": 3, "name": "Adventure"}]}
+			]`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+	resolver := &Resolver{client: client, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	resolved, err := resolver.Resolve(context.Background(), models.FetchRequest{Name: "Zelda", Source: "emulated", Platform: "nes"})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	// Previously search, genres, cover, screenshots, artworks and logos: 6 requests
+	total := 0
+	for _, n := range requests {
+		total += n
+	}
+	if total != 2 {
+		t.Errorf("expected 2 requests per resolved game, got %d: %v", total, requests)
+	}
+
+	if len(resolved.GameMetadata.Genres) != 1 || resolved.GameMetadata.Genres[0] != "Adventure" {
+		t.Errorf("unexpected genres %v", resolved.GameMetadata.Genres)
+	}
+	want := map[string]string{
+		"cover":      "https://images.igdb.com/t_720p/cover.png",
+		"screenshot": "https://images.igdb.com/t_720p/shot.png",
+		"artwork":    "https://images.igdb.com/t_720p/art.png",
+		"logo":       "https://images.igdb.com/t_720p/color.png",
+	}
+	for artType, url := range want {
+		if resolved.ArtURLs[artType] != url {
+			t.Errorf("expected %s %q, got %q", artType, url, resolved.ArtURLs[artType])
+		}
+	}
+	if _, ok := resolved.ArtURLs["artwork.1"]; ok {
+		t.Error("expected logos to be excluded from artworks")
+	}
+}
+
+func TestResolve_UnmappedPlatform(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/games":
+			if strings.Contains(string(body), "platforms =") {
+				t.Errorf("expected a name-only search, got %s", body)
+			}
+			w.Write([]byte(`[{"id": 1, "name": "Pong", "summary": "Tennis", "cover": 9, "genres": [3]}]`))
+		case "/covers":
+			w.Write([]byte(`[{"id": 9, "url": "//images.igdb.com/t_thumb/cover.jpg"}]`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	})
+	resolver := &Resolver{client: client, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	req := models.FetchRequest{Name: "pong", Source: "emulated", Platform: "channelf"}
+
+	if resolver.Supports(req.Source, req.Platform) {
+		t.Error("expected unmapped platforms to be unsupported by default")
+	}
+	resolver.SetSearchUnmappedPlatforms(true)
+	if !resolver.Supports(req.Source, req.Platform) {
+		t.Fatal("expected unmapped platforms to be supported when enabled")
+	}
+
+	resolved, err := resolver.Resolve(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if resolved.GameMetadata.Name != "Pong" {
+		t.Errorf("expected the matched name, got %q", resolved.GameMetadata.Name)
+	}
+	if resolved.GameMetadata.Description != "" || len(resolved.GameMetadata.Genres) != 0 {
+		t.Errorf("expected only the name, got %+v", resolved.GameMetadata)
+	}
+	if len(resolved.ArtURLs) != 1 || resolved.ArtURLs["cover"] != "https://images.igdb.com/t_720p/cover.png" {
+		t.Errorf("expected only a cover, got %v", resolved.ArtURLs)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `search "zelda"`) || !strings.Contains(string(body), "platforms = (18)") || !strings.Contains(string(body), "limit 5") {
+			t.Errorf("unexpected query %s", body)
+		}
+		w.Write([]byte(`[
+			{"id": 1, "name": "The Legend of Zelda", "first_release_date": 509328000, "cover": {"id": 9, "url": "//images.igdb.com/t_thumb/cover.jpg"}},
+			{"id": 2, "name": "Zelda II"}
+		]`))
+	})
+	resolver := &Resolver{client: client, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	results, err := resolver.Search("zelda", "NES", 5)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	want := []models.IGDBResult{
+		{ID: 1, Name: "The Legend of Zelda", ReleaseYear: 1986, CoverURL: "https://images.igdb.com/t_cover_small/cover.jpg"},
+		{ID: 2, Name: "Zelda II"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %v", len(want), results)
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("result %d: expected %+v, got %+v", i, want[i], results[i])
+		}
+	}
+}
+
+func TestNormalizeAgeRating(t *testing.T) {
+	tests := []struct {
+		ratings []AgeRating
+		want    models.AgeRating
+	}{
+		{nil, ""},
+		{[]AgeRating{{Rating: 6}}, ""},
+		{[]AgeRating{{Rating: 8}}, models.AgeRatingEveryone},
+		{[]AgeRating{{Rating: 10}, {Rating: 4}}, models.AgeRatingMature},
+		{[]AgeRating{{Rating: 5}, {Rating: 11}}, models.AgeRatingAdult},
+		{[]AgeRating{{Rating: 9}, {Rating: 2}}, models.AgeRatingEveryone10},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeAgeRating(tt.ratings); got != tt.want {
+			t.Errorf("normalizeAgeRating(%v) = %q, want %q", tt.ratings, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package steamgriddb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const steamGridDBBaseURL = "https://www.steamgriddb.com/api/v2"
+
+// maxErrorBody bounds how much of an error response is kept in APIError
+const maxErrorBody = 512
+
+// ErrNotFound means the search or art listing had no results
+var ErrNotFound = errors.New("steamgriddb has no match")
+
+// Client handles SteamGridDB API communication
+type Client struct {
+	apiKey     string
+	httpClient *http.Client
+	baseURL    string
+}
+
+// Game is a SteamGridDB search result
+type Game struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Image is a grid, hero, logo or icon uploaded for a game
+type Image struct {
+	ID  int    `json:"id"`
+	URL string `json:"url"`
+}
+
+// APIError is a non-200 response from SteamGridDB
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("steamgriddb request failed: %s (status %d)", e.Body, e.StatusCode)
+}
+
+// Retryable reports whether retrying the request later may succeed
+func (e *APIError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// NewClient creates a new SteamGridDB client
+func NewClient(apiKey string) *Client {
+	return &Client{
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		baseURL:    steamGridDBBaseURL,
+	}
+}
+
+// SearchGame returns the best match for a game name
+func (c *Client) SearchGame(ctx context.Context, name string) (*Game, error) {
+	var games []Game
+	if err := c.get(ctx, "/search/autocomplete/"+url.PathEscape(name), nil, &games); err != nil {
+		return nil, err
+	}
+	if len(games) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return &games[0], nil
+}
+
+// GetImage returns the top-rated image of a kind ("grids", "heroes", "logos"
+// or "icons") for a game
+func (c *Client) GetImage(ctx context.Context, kind string, gameID int, params url.Values) (*Image, error) {
+	var images []Image
+	if err := c.get(ctx, fmt.Sprintf("/%s/game/%d", kind, gameID), params, &images); err != nil {
+		return nil, err
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("%w: no %s for game %d", ErrNotFound, kind, gameID)
+	}
+	return &images[0], nil
+}
+
+// get sends an API request and decodes the data of its response into result
+func (c *Client) get(ctx context.Context, endpoint string, params url.Values, result any) error {
+	reqURL := c.baseURL + endpoint
+	if len(params) > 0 {
+		reqURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrNotFound, endpoint)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return &APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var envelope struct {
+		Success bool            `json:"success"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if !envelope.Success {
+		return fmt.Errorf("%w: %s", ErrNotFound, endpoint)
+	}
+	if err := json.Unmarshal(envelope.Data, result); err != nil {
+		return fmt.Errorf("failed to decode response data: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package steamgriddb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// artKinds maps the art types the resolver fills in to SteamGridDB's image
+// kinds. Grids are limited to the portrait size the library uses.
+var artKinds = []struct {
+	artType string
+	kind    string
+	params  url.Values
+}{
+	{"grid", "grids", url.Values{"dimensions": {"600x900"}}},
+	{"hero", "heroes", nil},
+	{"logo", "logos", nil},
+	{"icon", "icons", nil},
+}
+
+// Resolver implements the metadata.Resolver interface for SteamGridDB. It only
+// provides art, so it reports itself as an art resolver to the fetcher.
+type Resolver struct {
+	client *Client
+	logger *slog.Logger
+}
+
+// NewResolver creates a new SteamGridDB resolver
+func NewResolver(apiKey string, logger *slog.Logger) *Resolver {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Resolver{
+		client: NewClient(apiKey),
+		logger: logger,
+	}
+}
+
+// Name returns the resolver name
+func (r *Resolver) Name() string {
+	return "steamgriddb"
+}
+
+// ArtOnly reports that SteamGridDB provides art and no metadata
+func (r *Resolver) ArtOnly() bool {
+	return true
+}
+
+// Supports returns true for every game; SteamGridDB art isn't tied to a platform
+func (r *Resolver) Supports(source, platform string) bool {
+	return true
+}
+
+// Resolve searches SteamGridDB by name and returns the top match's grid, hero,
+// logo and icon. Art types without any uploads are left out.
+func (r *Resolver) Resolve(ctx context.Context, req models.FetchRequest) (models.ResolvedMetadata, error) {
+	result := models.ResolvedMetadata{
+		ArtURLs: make(map[string]string),
+	}
+
+	game, err := r.client.SearchGame(ctx, req.Name)
+	if err != nil {
+		return result, fmt.Errorf("failed to search game: %w", err)
+	}
+
+	r.logger.Info("found game on SteamGridDB", "gameID", game.ID, "name", game.Name)
+
+	for _, art := range artKinds {
+		image, err := r.client.GetImage(ctx, art.kind, game.ID, art.params)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to get %s: %w", art.kind, err)
+		}
+		result.ArtURLs[art.artType] = image.URL
+	}
+
+	if len(result.ArtURLs) == 0 {
+		return result, fmt.Errorf("%w: no art for %s", ErrNotFound, game.Name)
+	}
+	return result, nil
+}
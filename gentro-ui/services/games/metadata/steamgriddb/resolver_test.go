@@ -0,0 +1,86 @@
+package steamgriddb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// newTestResolver returns a resolver whose client talks to server
+func newTestResolver(t *testing.T, handler http.HandlerFunc) *Resolver {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	resolver := NewResolver("key", nil)
+	resolver.client.baseURL = server.URL
+	return resolver
+}
+
+func TestResolve(t *testing.T) {
+	resolver := newTestResolver(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			http.Error(w, `{"success":false}`, http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/search/autocomplete/Super Metroid":
+			fmt.Fprint(w, `{"success":true,"data":[{"id":42,"name":"Super Metroid"},{"id":7,"name":"Metroid"}]}`)
+		case "/grids/game/42":
+			if r.URL.Query().Get("dimensions") != "600x900" {
+				t.Errorf("expected portrait grids, got %q", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"success":true,"data":[{"id":1,"url":"https://cdn/grid.png"},{"id":2,"url":"https://cdn/other.png"}]}`)
+		case "/heroes/game/42":
+			fmt.Fprint(w, `{"success":true,"data":[{"id":3,"url":"https://cdn/hero.png"}]}`)
+		case "/logos/game/42":
+			fmt.Fprint(w, `{"success":true,"data":[]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	resolved, err := resolver.Resolve(context.Background(), models.FetchRequest{Name: "Super Metroid"})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	want := map[string]string{"grid": "https://cdn/grid.png", "hero": "https://cdn/hero.png"}
+	if len(resolved.ArtURLs) != len(want) {
+		t.Fatalf("expected %v, got %v", want, resolved.ArtURLs)
+	}
+	for artType, url := range want {
+		if resolved.ArtURLs[artType] != url {
+			t.Errorf("expected %s %q, got %q", artType, url, resolved.ArtURLs[artType])
+		}
+	}
+	if resolved.GameMetadata.Name != "" {
+		t.Errorf("expected no metadata, got %+v", resolved.GameMetadata)
+	}
+}
+
+func TestResolve_Errors(t *testing.T) {
+	resolver := newTestResolver(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search/autocomplete/Unknown":
+			fmt.Fprint(w, `{"success":true,"data":[]}`)
+		default:
+			http.Error(w, "down", http.StatusServiceUnavailable)
+		}
+	})
+
+	if _, err := resolver.Resolve(context.Background(), models.FetchRequest{Name: "Unknown"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound without a match, got %v", err)
+	}
+
+	_, err := resolver.Resolve(context.Background(), models.FetchRequest{Name: "Down"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !apiErr.Retryable() {
+		t.Errorf("expected a retryable APIError, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package games
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// metricsSettingKey stores persisted metrics in the settings table
+const metricsSettingKey = "metrics"
+
+// Error types counted in Metrics.Errors besides launch error codes
+const (
+	metricErrorRefreshSource = "refresh_source"
+	metricErrorMetadata      = "metadata"
+	metricErrorArtDownload   = "art_download"
+	metricErrorArtCompose    = "art_compose"
+	metricErrorArtCache      = "art_cache"
+)
+
+// metricsRecorder keeps in-process counters. They never leave the machine.
+// A nil *metricsRecorder discards everything.
+type metricsRecorder struct {
+	mu      sync.Mutex
+	metrics models.Metrics
+}
+
+func newMetricsRecorder() *metricsRecorder {
+	return &metricsRecorder{metrics: models.Metrics{Since: time.Now(), Errors: make(map[string]int64)}}
+}
+
+// update applies fn to the counters under the lock
+func (m *metricsRecorder) update(fn func(*models.Metrics)) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fn(&m.metrics)
+}
+
+// countError increments the counter for an error type
+func (m *metricsRecorder) countError(errorType string) {
+	m.update(func(metrics *models.Metrics) { metrics.Errors[errorType]++ })
+}
+
+// countLaunchFailure records a failed launch under its error code
+func (m *metricsRecorder) countLaunchFailure(code models.LaunchErrorCode) {
+	m.update(func(metrics *models.Metrics) {
+		metrics.LaunchFailures++
+		metrics.Errors["launch_"+string(code)]++
+	})
+}
+
+// snapshot returns a copy of the counters
+func (m *metricsRecorder) snapshot() models.Metrics {
+	if m == nil {
+		return models.Metrics{Errors: map[string]int64{}}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := m.metrics
+	snapshot.Errors = maps.Clone(m.metrics.Errors)
+	return snapshot
+}
+
+// restore replaces the counters with previously persisted ones
+func (m *metricsRecorder) restore(saved models.Metrics) {
+	if saved.Errors == nil {
+		saved.Errors = make(map[string]int64)
+	}
+	m.update(func(metrics *models.Metrics) { *metrics = saved })
+}
+
+// GetMetrics returns cumulative counters since startup, or since counting began
+// when persistence is enabled
+func (s *GamesService) GetMetrics() models.Metrics {
+	return s.metrics.snapshot()
+}
+
+// loadMetrics restores persisted counters when persistence is enabled
+func (s *GamesService) loadMetrics() {
+	if s.config == nil || !s.config.Get().Metrics.Persist {
+		return
+	}
+
+	value, ok, err := s.db.GetSetting(metricsSettingKey)
+	if err != nil || !ok {
+		return
+	}
+	var saved models.Metrics
+	if err := json.Unmarshal([]byte(value), &saved); err != nil {
+		s.logger.Warn("ignoring unreadable persisted metrics", "error", err)
+		return
+	}
+	s.metrics.restore(saved)
+}
+
+// saveMetrics persists counters when persistence is enabled
+func (s *GamesService) saveMetrics() error {
+	if s.config == nil || !s.config.Get().Metrics.Persist {
+		return nil
+	}
+
+	data, err := json.Marshal(s.metrics.snapshot())
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+	return s.db.SetSetting(metricsSettingKey, string(data))
+}
@@ -0,0 +1,73 @@
+package games
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/config"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestMetrics(t *testing.T) {
+	service := newTestService(t)
+	service.registry.Register(context.Background(), &MockSource{name: "mock", instances: []models.GameInstance{
+		{ID: "mock_1", GameID: "g1", Source: "mock", Platform: "pc"},
+		{ID: "mock_2", GameID: "g2", Source: "mock", Platform: "pc"},
+	}})
+
+	if _, err := service.RefreshGames(); err != nil {
+		t.Fatalf("RefreshGames failed: %v", err)
+	}
+	if err := service.Launch("mock_1"); err != nil {
+		t.Fatalf("Launch failed: %v", err)
+	}
+	service.metrics.countLaunchFailure(models.LaunchErrorEmulatorNotInstalled)
+
+	got := service.GetMetrics()
+	if got.GamesScanned != 2 {
+		t.Errorf("expected 2 games scanned, got %d", got.GamesScanned)
+	}
+	if got.LaunchFailures != 1 || got.Errors["launch_emulator_not_installed"] != 1 {
+		t.Errorf("expected one launch failure by code, got %d %v", got.LaunchFailures, got.Errors)
+	}
+
+	// Snapshots are copies
+	got.Errors["launch_emulator_not_installed"] = 99
+	if service.GetMetrics().Errors["launch_emulator_not_installed"] != 1 {
+		t.Error("expected GetMetrics to return a copy")
+	}
+}
+
+func TestMetrics_Persist(t *testing.T) {
+	service := newTestService(t)
+	manager, err := config.NewManager(filepath.Join(t.TempDir(), "gentro.toml"))
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	service.config = manager
+	service.metrics.update(func(m *models.Metrics) { m.GamesScanned = 5 })
+
+	// Not persisted unless opted in
+	if err := service.saveMetrics(); err != nil {
+		t.Fatalf("saveMetrics failed: %v", err)
+	}
+	if _, ok, _ := service.db.GetSetting(metricsSettingKey); ok {
+		t.Fatal("expected metrics not to be persisted by default")
+	}
+
+	cfg := manager.Get()
+	cfg.Metrics.Persist = true
+	if err := manager.SetMetrics(cfg.Metrics); err != nil {
+		t.Fatalf("SetMetrics failed: %v", err)
+	}
+	if err := service.saveMetrics(); err != nil {
+		t.Fatalf("saveMetrics failed: %v", err)
+	}
+
+	service.metrics = newMetricsRecorder()
+	service.loadMetrics()
+	if got := service.GetMetrics().GamesScanned; got != 5 {
+		t.Errorf("expected restored GamesScanned 5, got %d", got)
+	}
+}
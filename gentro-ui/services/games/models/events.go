@@ -0,0 +1,122 @@
+package models
+
+import "errors"
+
+// Event names emitted to the frontend. Each one is registered with its payload type in main.go.
+const (
+	EventMetadataStatus = "metadata:status-update"
+	EventLaunchStatus   = "launchStatusUpdate"
+	EventLaunchError    = "game:launch-error"
+	EventArtUpdated     = "art:updated"
+	EventArtPrefetch    = "art:prefetch-progress"
+	EventRefreshResult  = "games:refresh-complete"
+	EventVerifyProgress = "library:verify-progress"
+	EventInstanceUpdate = "instance:updated"
+	EventLibraryUpdated = "library:updated"
+
+	EventInstanceEmulatorsUnavailable = "emulator:instance-unavailable"
+)
+
+// MetadataStatusUpdate is sent via Wails events
+type MetadataStatusUpdate struct {
+	InstanceID string         `json:"instanceId"`
+	GameID     string         `json:"gameId"`
+	Status     MetadataStatus `json:"status"`
+}
+
+// InstanceUpdate is sent when the user changes an instance, so lists showing
+// its custom metadata or settings can refresh
+type InstanceUpdate struct {
+	InstanceID string `json:"instanceId"`
+	GameID     string `json:"gameId"`
+	// Keys are the custom metadata keys that changed, or "emulator" and
+	// "argProfile" for launch settings
+	Keys []string `json:"keys"`
+}
+
+// LibraryUpdate is sent once after a bulk edit of many instances, in place of an
+// InstanceUpdate for each
+type LibraryUpdate struct {
+	InstanceIDs []string `json:"instanceIds"`
+	// Keys are the custom metadata keys that changed
+	Keys []string `json:"keys"`
+}
+
+// ArtUpdate is sent when cached art for an instance has been replaced
+type ArtUpdate struct {
+	InstanceID string `json:"instanceId"`
+	GameID     string `json:"gameId"`
+	ArtType    string `json:"artType"`
+}
+
+// ArtPrefetchProgress reports background art downloads for a source
+type ArtPrefetchProgress struct {
+	Source    string `json:"source"`
+	Completed int    `json:"completed"`
+	Total     int    `json:"total"`
+}
+
+// VerifyProgress reports how many instances VerifyLibrary has checked
+type VerifyProgress struct {
+	Checked int `json:"checked"`
+	Total   int `json:"total"`
+}
+
+// LaunchStatus represents the state of game launching/running
+type LaunchStatus string
+
+const (
+	LaunchStatusLaunching LaunchStatus = "launching"
+	LaunchStatusRunning   LaunchStatus = "running"
+	LaunchStatusStopped   LaunchStatus = "stopped"
+	LaunchStatusFailed    LaunchStatus = "failed"
+)
+
+// LaunchStatusUpdate is sent via Wails events when game launch status changes
+type LaunchStatusUpdate struct {
+	InstanceID string       `json:"instanceId"`
+	GameID     string       `json:"gameId"`
+	Status     LaunchStatus `json:"status"`
+	Error      string       `json:"error,omitempty"`
+	// ExitCode is the process exit code on stopped, when the source knows it.
+	// Processes killed by a signal report 128 plus the signal number.
+	ExitCode int `json:"exitCode,omitempty"`
+	// Crashed is set on stopped when the process exited non-zero or was killed
+	Crashed bool `json:"crashed,omitempty"`
+}
+
+// LaunchErrorCode classifies why a launch failed so the UI can offer a fix
+type LaunchErrorCode string
+
+const (
+	LaunchErrorUnknownSource         LaunchErrorCode = "unknown_source"
+	LaunchErrorEmulatorNotConfigured LaunchErrorCode = "emulator_not_configured"
+	LaunchErrorEmulatorNotInstalled  LaunchErrorCode = "emulator_not_installed"
+	LaunchErrorFailed                LaunchErrorCode = "launch_failed"
+)
+
+// Sources wrap these so launch failures can be classified
+var (
+	ErrEmulatorNotConfigured = errors.New("no emulator configured")
+	ErrEmulatorNotInstalled  = errors.New("emulator not installed")
+)
+
+// LaunchError is sent via Wails events alongside a failed LaunchStatusUpdate
+type LaunchError struct {
+	InstanceID string          `json:"instanceId"`
+	GameID     string          `json:"gameId"`
+	Code       LaunchErrorCode `json:"code"`
+	Message    string          `json:"message"`
+}
+
+// LaunchErrorCodeOf classifies a source launch error
+func LaunchErrorCodeOf(err error) LaunchErrorCode {
+	switch {
+	case errors.Is(err, ErrEmulatorNotConfigured):
+		return LaunchErrorEmulatorNotConfigured
+	case errors.Is(err, ErrEmulatorNotInstalled):
+		return LaunchErrorEmulatorNotInstalled
+	default:
+		return LaunchErrorFailed
+	}
+}
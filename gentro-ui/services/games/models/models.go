@@ -1,9 +1,28 @@
 package models
 
 import (
+	"errors"
+	"fmt"
 	"time"
 )
 
+// ErrAmbiguousPlatform is returned when a ROM's platform can't be told from its file
+var ErrAmbiguousPlatform = errors.New("ambiguous platform")
+
+// ErrArtNotFound is returned by GameSource.GetGameArt when the requested art
+// does not exist, as opposed to failing to read it
+var ErrArtNotFound = errors.New("art not found")
+
+// ErrGameNotFound is returned when a game ID doesn't match any game
+var ErrGameNotFound = errors.New("game not found")
+
+// ErrRefreshInProgress is returned by RefreshGames while another refresh is running
+var ErrRefreshInProgress = errors.New("refresh already in progress")
+
+// ErrVersionConflict is returned when an instance is updated from a copy that
+// another write has changed since it was read. Re-read the instance and retry.
+var ErrVersionConflict = errors.New("instance was changed since it was read")
+
 // MetadataState represents the state of metadata fetching
 type MetadataState string
 
@@ -23,6 +42,7 @@ type Game struct {
 	ReleaseDate *time.Time        `json:"releaseDate,omitempty" db:"release_date"`
 	Developer   string            `json:"developer" db:"developer"`
 	Publisher   string            `json:"publisher" db:"publisher"`
+	AgeRating   AgeRating         `json:"ageRating,omitempty" db:"age_rating"`
 	Genres      []string          `json:"genres" db:"-"`
 	Platforms   []string          `json:"platforms" db:"-"`
 	ArtURLs     map[string]string `json:"artUrls" db:"-"`
@@ -32,22 +52,52 @@ type Game struct {
 
 // GameInstance represents a specific copy/installation of a game
 type GameInstance struct {
-	ID             string         `json:"id" db:"id"`
-	GameID         string         `json:"gameId" db:"game_id"`
-	Source         string         `json:"source" db:"source"`
-	Platform       string         `json:"platform" db:"platform"`
-	SourceID       string         `json:"sourceId" db:"source_id"`
-	Path           string         `json:"path,omitempty" db:"path"`
-	Filename       string         `json:"filename,omitempty" db:"filename"`
-	FileSize       int64          `json:"fileSize,omitempty" db:"file_size"`
-	FileHash       string         `json:"fileHash,omitempty" db:"file_hash"`
-	Installed      bool           `json:"installed" db:"installed"`
-	InstallPath    string         `json:"installPath,omitempty" db:"install_path"`
+	ID          string `json:"id" db:"id"`
+	GameID      string `json:"gameId" db:"game_id"`
+	Source      string `json:"source" db:"source"`
+	Platform    string `json:"platform" db:"platform"`
+	SourceID    string `json:"sourceId" db:"source_id"`
+	Path        string `json:"path,omitempty" db:"path"`
+	Filename    string `json:"filename,omitempty" db:"filename"`
+	FileSize    int64  `json:"fileSize,omitempty" db:"file_size"`
+	FileHash    string `json:"fileHash,omitempty" db:"file_hash"`
+	Installed   bool   `json:"installed" db:"installed"`
+	InstallPath string `json:"installPath,omitempty" db:"install_path"`
+	// Files are the files used alongside Path, such as further disks, BIOS
+	// images or a parent romset. Nil for single-file games.
+	Files          []InstanceFile `json:"files,omitempty" db:"files"`
 	MetadataStatus MetadataStatus `json:"metadataStatus" db:"-"`
 	CustomMetadata map[string]any `json:"customMetadata" db:"-"`
 	SourceData     map[string]any `json:"sourceData,omitempty" db:"-"`
-	CreatedAt      time.Time      `json:"createdAt" db:"created_at"`
-	UpdatedAt      time.Time      `json:"updatedAt" db:"updated_at"`
+	// Version is bumped by every update, which must be made from the current
+	// version so concurrent edits don't silently overwrite each other
+	Version int64 `json:"version" db:"version"`
+	// PlaytimeSeconds is the total time played, summed from finished sessions
+	PlaytimeSeconds int64 `json:"playtimeSeconds" db:"playtime_seconds"`
+	// LastPlayedAt is when the last session ended, or nil if never played
+	LastPlayedAt *time.Time `json:"lastPlayedAt,omitempty" db:"last_played_at"`
+	CreatedAt    time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updatedAt" db:"updated_at"`
+}
+
+// InstanceFileRole describes what a file contributes to an instance
+type InstanceFileRole string
+
+const (
+	// FileRolePrimary is the file launched, the instance's Path
+	FileRolePrimary InstanceFileRole = "primary"
+	// FileRoleDisk is a further disk of a multi-disk game
+	FileRoleDisk InstanceFileRole = "disk"
+	// FileRoleBIOS is a system BIOS image the game needs
+	FileRoleBIOS InstanceFileRole = "bios"
+	// FileRoleParent is the parent romset a clone romset depends on
+	FileRoleParent InstanceFileRole = "parent"
+)
+
+// InstanceFile is one of the files an instance is made of
+type InstanceFile struct {
+	Path string           `json:"path"`
+	Role InstanceFileRole `json:"role"`
 }
 
 // MetadataStatus tracks async metadata fetching progress
@@ -61,6 +111,13 @@ type MetadataStatus struct {
 	SourcesTried []string      `json:"sourcesTried,omitempty"`
 }
 
+// FieldSource records which metadata source provided a game field
+type FieldSource struct {
+	Field     string     `json:"field"`
+	Source    string     `json:"source"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
 // MetadataLayer represents the fallback hierarchy
 type MetadataLayer struct {
 	External         map[string]any            `json:"external,omitempty"`
@@ -69,6 +126,18 @@ type MetadataLayer struct {
 	InstanceCustom   map[string]any            `json:"instanceCustom,omitempty"`
 }
 
+// SourceCapabilities describes which operations a game source supports,
+// so the UI only offers actions that will work
+type SourceCapabilities struct {
+	CanLaunch         bool `json:"canLaunch"`
+	CanInstall        bool `json:"canInstall"`
+	CanScanArt        bool `json:"canScanArt"`
+	SupportsManualAdd bool `json:"supportsManualAdd"`
+	// RequiresEmulator marks sources whose instances only launch with an emulator
+	// installed for their platform
+	RequiresEmulator bool `json:"requiresEmulator"`
+}
+
 // GameFilter represents filtering options for games
 type GameFilter struct {
 	InstalledOnly bool     `json:"installedOnly"`
@@ -76,12 +145,42 @@ type GameFilter struct {
 	Platform      string   `json:"platform,omitempty"`
 	Search        string   `json:"search,omitempty"`
 	Genres        []string `json:"genres,omitempty"`
+	// MaxAgeRating hides games rated above it. Unrated games are kept.
+	MaxAgeRating AgeRating `json:"maxAgeRating,omitempty"`
 
 	// SourceFilters allows source-specific filtering
 	// Key is source name (e.g., "steam"), value is map of filter options
 	SourceFilters map[string]map[string]any `json:"sourceFilters,omitempty"`
 }
 
+// AgeRating is a content rating normalized across rating boards such as ESRB and PEGI
+type AgeRating string
+
+// Age ratings from least to most restrictive. ESRB EC/E and PEGI 3 are
+// "everyone", E10+ and PEGI 7 "everyone10", T and PEGI 12 "teen", M and
+// PEGI 16 "mature", AO and PEGI 18 "adult".
+const (
+	AgeRatingEveryone   AgeRating = "everyone"
+	AgeRatingEveryone10 AgeRating = "everyone10"
+	AgeRatingTeen       AgeRating = "teen"
+	AgeRatingMature     AgeRating = "mature"
+	AgeRatingAdult      AgeRating = "adult"
+)
+
+// ageRatingRanks orders the ratings for comparison
+var ageRatingRanks = map[AgeRating]int{
+	AgeRatingEveryone:   1,
+	AgeRatingEveryone10: 2,
+	AgeRatingTeen:       3,
+	AgeRatingMature:     4,
+	AgeRatingAdult:      5,
+}
+
+// Rank orders ratings from least to most restrictive. Unknown or empty ratings are 0.
+func (r AgeRating) Rank() int {
+	return ageRatingRanks[r]
+}
+
 // GameSort represents sorting options for games
 type GameSort struct {
 	Field string `json:"field"` // "name", "lastPlayed", "fileSize", "dateAdded"
@@ -105,6 +204,212 @@ type GameWithInstance struct {
 	Instance GameInstance `json:"instance"`
 }
 
+// RefreshPlan summarizes what a library refresh would change
+type RefreshPlan struct {
+	New            int                `json:"new"`
+	Updated        int                `json:"updated"`
+	Relinked       int                `json:"relinked"`
+	Missing        int                `json:"missing"`
+	Unchanged      int                `json:"unchanged"`
+	NewSamples     []RefreshPlanEntry `json:"newSamples"`
+	UpdatedSamples []RefreshPlanEntry `json:"updatedSamples"`
+	// RelinkedSamples are at their new path
+	RelinkedSamples []RefreshPlanEntry `json:"relinkedSamples"`
+	MissingSamples  []RefreshPlanEntry `json:"missingSamples"`
+	FailedSources   []string           `json:"failedSources,omitempty"`
+}
+
+// RefreshPlanEntry describes a single instance in a RefreshPlan
+type RefreshPlanEntry struct {
+	InstanceID string `json:"instanceId"`
+	GameID     string `json:"gameId"`
+	Source     string `json:"source"`
+	Platform   string `json:"platform"`
+	Name       string `json:"name"`
+	Path       string `json:"path,omitempty"`
+}
+
+// Duplicate group reasons
+const (
+	DuplicateByName = "name"
+	DuplicateByHash = "hash"
+)
+
+// DuplicateGroup is a set of instances that look like copies of the same game
+type DuplicateGroup struct {
+	// Reason is DuplicateByName (same normalized name and platform) or DuplicateByHash (identical files)
+	Reason    string           `json:"reason"`
+	Name      string           `json:"name"`
+	Platform  string           `json:"platform"`
+	Instances []DuplicateEntry `json:"instances"`
+}
+
+// DuplicateEntry describes one instance in a DuplicateGroup
+type DuplicateEntry struct {
+	InstanceID string `json:"instanceId"`
+	GameID     string `json:"gameId"`
+	Source     string `json:"source"`
+	Name       string `json:"name"`
+	Path       string `json:"path,omitempty"`
+	Filename   string `json:"filename,omitempty"`
+	FileSize   int64  `json:"fileSize"`
+	FileHash   string `json:"fileHash,omitempty"`
+}
+
+// DatabaseStatus reports problems found when opening the library database
+type DatabaseStatus struct {
+	Path string `json:"path"`
+	// Recovered is set when migration failed and the database was rebuilt empty
+	Recovered bool `json:"recovered"`
+	// Degraded is set when the database couldn't be migrated or rebuilt and was opened read-only
+	Degraded bool `json:"degraded"`
+	ReadOnly bool `json:"readOnly"`
+	// BackupPath is where the database that failed to migrate or was corrupt was moved
+	BackupPath string `json:"backupPath,omitempty"`
+	Error      string `json:"error,omitempty"`
+	// Integrity is the result of the startup integrity check
+	Integrity IntegrityStatus `json:"integrity"`
+	// RestoredFrom is the backup restored after the database was found corrupt
+	RestoredFrom string `json:"restoredFrom,omitempty"`
+	// BackupError is set when the automatic backup couldn't be written
+	BackupError string `json:"backupError,omitempty"`
+}
+
+// IntegrityStatus is the result of a database integrity check
+type IntegrityStatus string
+
+const (
+	IntegrityOK      IntegrityStatus = "ok"
+	IntegrityCorrupt IntegrityStatus = "corrupt"
+	IntegrityUnknown IntegrityStatus = "unknown"
+)
+
+// DatabaseStats summarizes the library database for the settings UI
+type DatabaseStats struct {
+	Games      int            `json:"games"`
+	Instances  int            `json:"instances"`
+	Emulators  int            `json:"emulators"`
+	SizeBytes  int64          `json:"sizeBytes"`
+	LastBackup *time.Time     `json:"lastBackup,omitempty"`
+	Status     DatabaseStatus `json:"status"`
+}
+
+// ScanError is a source or instance that failed during a refresh
+type ScanError struct {
+	Source string `json:"source"`
+	// InstanceID is empty when the whole source failed
+	InstanceID string `json:"instanceId,omitempty"`
+	Message    string `json:"message"`
+}
+
+// RefreshResult summarizes a library refresh
+type RefreshResult struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	// Relinked counts instances whose file was found at a new path and moved there
+	Relinked int `json:"relinked"`
+	// Missing counts instances their source no longer reports and whose file is
+	// gone. They are kept in the library so custom metadata and play history
+	// survive a missing drive. Instances whose file still exists, like ROMs added
+	// from outside the scanned folders, aren't counted.
+	Missing  int           `json:"missing"`
+	Errors   []ScanError   `json:"errors"`
+	Duration time.Duration `json:"duration"`
+	// Cancelled is set when CancelRefresh stopped the scan before every source was synced
+	Cancelled bool `json:"cancelled"`
+}
+
+// ScanErrors is returned by a refresh that skipped some sources or instances
+type ScanErrors []ScanError
+
+func (e ScanErrors) Error() string {
+	if len(e) == 1 {
+		return fmt.Sprintf("refresh failed for %s: %s", e[0].Source, e[0].Message)
+	}
+	return fmt.Sprintf("refresh had %d errors, first in %s: %s", len(e), e[0].Source, e[0].Message)
+}
+
+// LibraryStats are aggregate totals for the stats dashboard
+type LibraryStats struct {
+	Games               int            `json:"games"`
+	Instances           int            `json:"instances"`
+	InstancesBySource   map[string]int `json:"instancesBySource"`
+	InstancesByPlatform map[string]int `json:"instancesByPlatform"`
+	InstalledSizeBytes  int64          `json:"installedSizeBytes"`
+	PlaytimeMinutes     int64          `json:"playtimeMinutes"`
+	MostPlayed          *PlayedGame    `json:"mostPlayed,omitempty"`
+	// Genres counts games per genre
+	Genres map[string]int `json:"genres"`
+}
+
+// VerifyIssueKind classifies a problem VerifyLibrary found with a file
+type VerifyIssueKind string
+
+const (
+	// VerifyMissing is a file that no longer exists: deleted, or moved elsewhere
+	VerifyMissing VerifyIssueKind = "missing"
+	// VerifySizeChanged is a file whose size differs from when it was scanned
+	VerifySizeChanged VerifyIssueKind = "size_changed"
+	// VerifyModified is a file of the same size whose content has changed
+	VerifyModified VerifyIssueKind = "modified"
+	// VerifyUnreadable is a file that couldn't be checked
+	VerifyUnreadable VerifyIssueKind = "unreadable"
+)
+
+// VerifyIssue is a problem with one of an instance's files
+type VerifyIssue struct {
+	InstanceID string          `json:"instanceId"`
+	GameID     string          `json:"gameId"`
+	Path       string          `json:"path"`
+	Kind       VerifyIssueKind `json:"kind"`
+	Message    string          `json:"message,omitempty"`
+}
+
+// VerifyReport is the result of checking the library's files against the database
+type VerifyReport struct {
+	// Checked counts instances whose files were checked
+	Checked int `json:"checked"`
+	// Skipped counts instances without files to check, such as Steam installs
+	Skipped int           `json:"skipped"`
+	Issues  []VerifyIssue `json:"issues"`
+}
+
+// PlayedGame is a game with its total playtime across instances
+type PlayedGame struct {
+	GameID          string `json:"gameId"`
+	Name            string `json:"name"`
+	PlaytimeMinutes int64  `json:"playtimeMinutes"`
+}
+
+// PlaySession is one run of an instance, from running to stopped
+type PlaySession struct {
+	ID              int64     `json:"id"`
+	InstanceID      string    `json:"instanceId"`
+	StartedAt       time.Time `json:"startedAt"`
+	EndedAt         time.Time `json:"endedAt"`
+	DurationSeconds int64     `json:"durationSeconds"`
+}
+
+// ServiceStatus summarizes startup problems for the UI
+type ServiceStatus struct {
+	Database     DatabaseStatus `json:"database"`
+	ConfigErrors []string       `json:"configErrors"`
+}
+
+// Metrics are local, cumulative counters for the diagnostics panel
+type Metrics struct {
+	Since           time.Time `json:"since"`
+	GamesScanned    int64     `json:"gamesScanned"`
+	MetadataFetched int64     `json:"metadataFetched"`
+	MetadataFailed  int64     `json:"metadataFailed"`
+	ArtDownloaded   int64     `json:"artDownloaded"`
+	ArtFailed       int64     `json:"artFailed"`
+	Launches        int64     `json:"launches"`
+	LaunchFailures  int64     `json:"launchFailures"`
+	// Errors counts errors by type, e.g. a launch error code or "refresh_source"
+	Errors map[string]int64 `json:"errors"`
+}
+
 // FetchRequest represents a metadata fetch request
 type FetchRequest struct {
 	GameID     string
@@ -115,6 +420,9 @@ type FetchRequest struct {
 	FileHash   string
 	Source     string
 	Platform   string
+	// Region is the release region parsed from the filename ("us", "eu", "jp",
+	// "world", ...), or "" when unknown
+	Region string
 }
 
 // ResolvedMetadata contains metadata from external sources
@@ -122,6 +430,11 @@ type ResolvedMetadata struct {
 	GameMetadata     GameMetadata
 	PlatformMetadata map[string]PlatformMetadata
 	ArtURLs          map[string]string
+	// ArtSources names the resolver of each art type that came from a resolver
+	// other than the one the metadata was resolved from
+	ArtSources map[string]string
+	// ArtOnly is set when only art resolvers matched, so GameMetadata is empty
+	ArtOnly bool
 }
 
 // GameMetadata represents game-level metadata from external sources
@@ -132,6 +445,16 @@ type GameMetadata struct {
 	Developer   string
 	Publisher   string
 	Genres      []string
+	AgeRating   AgeRating
+}
+
+// IGDBResult is a game from an IGDB name search, offered for manual matching
+type IGDBResult struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// ReleaseYear is 0 when IGDB has no release date
+	ReleaseYear int    `json:"releaseYear"`
+	CoverURL    string `json:"coverUrl"`
 }
 
 // PlatformMetadata represents platform-specific metadata
@@ -142,31 +465,6 @@ type PlatformMetadata struct {
 	Rating      string
 }
 
-// MetadataStatusUpdate is sent via Wails events
-type MetadataStatusUpdate struct {
-	InstanceID string         `json:"instanceId"`
-	GameID     string         `json:"gameId"`
-	Status     MetadataStatus `json:"status"`
-}
-
-// LaunchStatus represents the state of game launching/running
-type LaunchStatus string
-
-const (
-	LaunchStatusLaunching LaunchStatus = "launching"
-	LaunchStatusRunning   LaunchStatus = "running"
-	LaunchStatusStopped   LaunchStatus = "stopped"
-	LaunchStatusFailed    LaunchStatus = "failed"
-)
-
-// LaunchStatusUpdate is sent via Wails events when game launch status changes
-type LaunchStatusUpdate struct {
-	InstanceID string       `json:"instanceId"`
-	GameID     string       `json:"gameId"`
-	Status     LaunchStatus `json:"status"`
-	Error      string       `json:"error,omitempty"`
-}
-
 // EmulatorType represents how the emulator is installed
 type EmulatorType string
 
@@ -192,6 +490,17 @@ type Emulator struct {
 	UpdatedAt          time.Time    `json:"updatedAt" db:"updated_at"`
 }
 
+// SaveLocation is where an emulator keeps an instance's save data
+type SaveLocation struct {
+	// Dir holds the saves
+	Dir string `json:"dir"`
+	// Prefix limits the saves to files in Dir whose names start with it, such as
+	// the ROM's name; empty means the whole directory is the game's save data
+	Prefix string `json:"prefix,omitempty"`
+	// Exists is false until the emulator has saved anything there
+	Exists bool `json:"exists"`
+}
+
 // EmulatorCore represents a RetroArch core (Option B)
 type EmulatorCore struct {
 	ID                 string   `json:"id" db:"id"`
@@ -217,4 +526,58 @@ type InstanceEmulatorSettings struct {
 	EmulatorID string `json:"emulatorId" db:"emulator_id"`
 	CoreID     string `json:"coreId,omitempty" db:"core_id"`
 	CustomArgs string `json:"customArgs,omitempty" db:"custom_args"`
+	ProfileID  string `json:"profileId,omitempty" db:"profile_id"`
+}
+
+// UnavailableInstanceEmulator is an instance override whose emulator or core is
+// no longer installed. The override is kept so reinstalling restores it; until
+// then the instance launches with the platform's fallback.
+type UnavailableInstanceEmulator struct {
+	InstanceID string `json:"instanceId"`
+	GameID     string `json:"gameId"`
+	EmulatorID string `json:"emulatorId"`
+	CoreID     string `json:"coreId,omitempty"`
+}
+
+// ResolutionReason records which rule picked an instance's emulator
+type ResolutionReason string
+
+const (
+	ResolutionInstanceOverride ResolutionReason = "instance_override"
+	ResolutionPlatformDefault  ResolutionReason = "platform_default"
+	ResolutionFallback         ResolutionReason = "fallback"
+	ResolutionNone             ResolutionReason = "none"
+)
+
+// ResolvedEmulatorInfo describes the emulator an instance will launch with
+type ResolvedEmulatorInfo struct {
+	Emulator  *Emulator        `json:"emulator,omitempty"`
+	Core      *EmulatorCore    `json:"core,omitempty"`
+	Reason    ResolutionReason `json:"reason"`
+	Available bool             `json:"available"`
+	// Message explains why no emulator could be resolved
+	Message string `json:"message,omitempty"`
+}
+
+// EmulatorTestResult reports whether an emulator starts outside of a game launch
+type EmulatorTestResult struct {
+	EmulatorID string   `json:"emulatorId"`
+	Available  bool     `json:"available"`
+	Command    []string `json:"command"`
+	Launched   bool     `json:"launched"`
+	ExitCode   int      `json:"exitCode"`
+	TimedOut   bool     `json:"timedOut"`
+	DurationMs int64    `json:"durationMs"`
+	Stdout     string   `json:"stdout,omitempty"`
+	Stderr     string   `json:"stderr,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// EmulatorArgProfile is a named, reusable set of emulator args, e.g. "Performance"
+type EmulatorArgProfile struct {
+	ID         string    `json:"id" db:"id"`
+	EmulatorID string    `json:"emulatorId" db:"emulator_id"`
+	Name       string    `json:"name" db:"name"`
+	Args       string    `json:"args" db:"args"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
 }
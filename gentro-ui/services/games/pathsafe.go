@@ -0,0 +1,22 @@
+package games
+
+import "regexp"
+
+var (
+	// instanceIDPattern matches IDs generated by sources: letters, digits, '_', '-' and '.'
+	instanceIDPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,254}$`)
+	// artTypePattern matches art type names such as "hero" or "grid_portrait", with an
+	// optional gallery index such as "screenshot.2"
+	artTypePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}(\.[0-9]{1,3})?$`)
+)
+
+// validInstanceID reports whether an instance ID from a URL is safe to use as a
+// path segment. Leading dots are rejected, which rules out "." and "..".
+func validInstanceID(id string) bool {
+	return instanceIDPattern.MatchString(id)
+}
+
+// validArtType reports whether an art type from a URL is safe to use in a file name
+func validArtType(artType string) bool {
+	return artTypePattern.MatchString(artType)
+}
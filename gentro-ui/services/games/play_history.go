@@ -0,0 +1,67 @@
+package games
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// onSessionEnded records a finished session and shares the saves it made
+func (s *GamesService) onSessionEnded(instanceID string, startedAt, endedAt time.Time) {
+	s.recordPlaySession(instanceID, startedAt, endedAt)
+	go s.pushSaves(instanceID)
+}
+
+// recordPlaySession stores a finished session reported by the events and adds
+// it to the instance's playtime. The events time every source's sessions from
+// running to stopped, emulated games included.
+func (s *GamesService) recordPlaySession(instanceID string, startedAt, endedAt time.Time) {
+	session := models.PlaySession{InstanceID: instanceID, StartedAt: startedAt, EndedAt: endedAt}
+	if err := s.db.AddPlaySession(&session); err != nil {
+		s.logger.Warn("failed to record play session", "instanceId", instanceID, "error", err)
+		return
+	}
+	if err := s.db.AddPlaytime(instanceID, session.DurationSeconds); err != nil {
+		s.logger.Warn("failed to add playtime", "instanceId", instanceID, "error", err)
+	}
+	if err := s.db.UpdateLastPlayed(instanceID, endedAt); err != nil {
+		s.logger.Warn("failed to update last played", "instanceId", instanceID, "error", err)
+	}
+	s.logger.Info("recorded play session", "instanceId", instanceID, "durationSeconds", session.DurationSeconds)
+}
+
+// GetPlayHistory returns an instance's play sessions, most recent first
+func (s *GamesService) GetPlayHistory(instanceID string) ([]models.PlaySession, error) {
+	sessions, err := s.db.GetPlaySessions(instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get play history: %w", err)
+	}
+	return sessions, nil
+}
+
+// GetPlaytimeByDay returns the seconds played on each of the last days calendar
+// days, today included, keyed by local "YYYY-MM-DD". Every day in the range is
+// present so a heatmap needs no gap filling. Sessions spanning midnight count
+// towards each day they cover.
+func (s *GamesService) GetPlaytimeByDay(days int) (map[string]int, error) {
+	if days <= 0 {
+		return nil, fmt.Errorf("days must be positive: %d", days)
+	}
+
+	now := time.Now()
+	year, month, day := now.Date()
+	first := time.Date(year, month, day-(days-1), 0, 0, 0, 0, now.Location())
+
+	byDay, err := s.db.PlaytimeByDay(first, now.Location())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playtime by day: %w", err)
+	}
+	for d := first; !d.After(now); d = d.AddDate(0, 0, 1) {
+		key := d.Format(time.DateOnly)
+		if _, ok := byDay[key]; !ok {
+			byDay[key] = 0
+		}
+	}
+	return byDay, nil
+}
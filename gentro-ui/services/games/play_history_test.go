@@ -0,0 +1,165 @@
+package games
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestGetPlayHistory(t *testing.T) {
+	service := newTestService(t)
+	service.events.SetOnSessionEnded(service.recordPlaySession)
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+	instance := models.GameInstance{ID: "inst1", GameID: "game1"}
+
+	// Each running to stopped transition is one session
+	for i := 0; i < 2; i++ {
+		service.events.EmitGameInstanceRunning(instance)
+		service.events.EmitGameInstanceStopped(instance)
+	}
+
+	sessions, err := service.GetPlayHistory("inst1")
+	if err != nil {
+		t.Fatalf("GetPlayHistory failed: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(sessions))
+	}
+	if sessions[0].ID <= sessions[1].ID {
+		t.Errorf("expected the most recent session first, got %+v", sessions)
+	}
+
+	// Stored times round-trip and determine the duration
+	started := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	session := models.PlaySession{InstanceID: "inst1", StartedAt: started, EndedAt: started.Add(90 * time.Minute)}
+	if err := service.db.AddPlaySession(&session); err != nil {
+		t.Fatalf("AddPlaySession failed: %v", err)
+	}
+	sessions, err = service.GetPlayHistory("inst1")
+	if err != nil {
+		t.Fatalf("GetPlayHistory failed: %v", err)
+	}
+	oldest := sessions[len(sessions)-1]
+	if !oldest.StartedAt.Equal(started) || oldest.DurationSeconds != 5400 {
+		t.Errorf("expected the backdated session last with 5400s, got %+v", oldest)
+	}
+
+	if sessions, err := service.GetPlayHistory("other"); err != nil || len(sessions) != 0 {
+		t.Errorf("expected no sessions for another instance, got %v (%v)", sessions, err)
+	}
+}
+
+func TestGetPlaytimeByDay(t *testing.T) {
+	service := newTestService(t)
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+
+	ended := time.Now()
+	session := models.PlaySession{InstanceID: "inst1", StartedAt: ended.Add(-time.Minute), EndedAt: ended}
+	if err := service.db.AddPlaySession(&session); err != nil {
+		t.Fatalf("AddPlaySession failed: %v", err)
+	}
+
+	byDay, err := service.GetPlaytimeByDay(7)
+	if err != nil {
+		t.Fatalf("GetPlaytimeByDay failed: %v", err)
+	}
+	if len(byDay) != 7 {
+		t.Errorf("expected every day in the range, got %v", byDay)
+	}
+	total := 0
+	for _, seconds := range byDay {
+		total += seconds
+	}
+	if total != 60 {
+		t.Errorf("expected 60 seconds played in total, got %d (%v)", total, byDay)
+	}
+
+	if _, err := service.GetPlaytimeByDay(0); err == nil {
+		t.Error("expected a non-positive range to be rejected")
+	}
+}
+
+func TestRecordPlaySession_Playtime(t *testing.T) {
+	service := newTestService(t)
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes", Filename: "b.nes"},
+		{ID: "inst2", GameID: "game2", Source: "mock", Platform: "nes", Filename: "a.nes"},
+		{ID: "inst3", GameID: "game3", Source: "mock", Platform: "nes", Filename: "c.nes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instances: %v", err)
+	}
+
+	started := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	service.recordPlaySession("inst1", started, started.Add(30*time.Minute))
+	service.recordPlaySession("inst1", started.Add(time.Hour), started.Add(2*time.Hour))
+	service.recordPlaySession("inst2", started.Add(3*time.Hour), started.Add(4*time.Hour))
+
+	instance, err := service.db.GetInstance("inst1")
+	if err != nil {
+		t.Fatalf("GetInstance failed: %v", err)
+	}
+	if instance.PlaytimeSeconds != 5400 {
+		t.Errorf("expected sessions to add up to 5400s, got %d", instance.PlaytimeSeconds)
+	}
+	if instance.LastPlayedAt == nil || !instance.LastPlayedAt.Equal(started.Add(2*time.Hour)) {
+		t.Errorf("expected the last session's end, got %v", instance.LastPlayedAt)
+	}
+	if instance.Version != 1 {
+		t.Errorf("expected playtime not to bump the version, got %d", instance.Version)
+	}
+
+	games, err := service.GetGames(&models.GameFilter{}, &models.GameSort{Field: models.SortByLastPlayed, Order: models.SortOrderDesc})
+	if err != nil {
+		t.Fatalf("GetGames failed: %v", err)
+	}
+	var order []string
+	for _, game := range games {
+		order = append(order, game.Instance.ID)
+	}
+	if want := []string{"inst2", "inst1", "inst3"}; !slices.Equal(order, want) {
+		t.Errorf("expected most recently played first and never played last, got %v", order)
+	}
+}
+
+func TestSortGames_SourceLastPlayed(t *testing.T) {
+	service := newTestService(t)
+	tracked := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	reported := func(at time.Time) map[string]any {
+		return map[string]any{"steam.lastPlayed": strconv.FormatInt(at.Unix(), 10)}
+	}
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes", Filename: "a.nes"},
+		// Played outside the app, so only the source knows
+		{ID: "inst2", GameID: "game2", Source: "mock", Platform: "nes", Filename: "b.nes", CustomMetadata: reported(tracked.Add(time.Hour))},
+		// The source's older time doesn't hide a newer tracked session
+		{ID: "inst3", GameID: "game3", Source: "mock", Platform: "nes", Filename: "c.nes", CustomMetadata: reported(tracked.Add(-time.Hour))},
+		{ID: "inst4", GameID: "game4", Source: "mock", Platform: "nes", Filename: "d.nes", CustomMetadata: map[string]any{"steam.lastPlayed": "0"}},
+	}); err != nil {
+		t.Fatalf("failed to seed instances: %v", err)
+	}
+	service.recordPlaySession("inst1", tracked.Add(-2*time.Hour), tracked.Add(-90*time.Minute))
+	service.recordPlaySession("inst3", tracked.Add(-time.Hour), tracked)
+
+	games, err := service.GetGames(&models.GameFilter{}, &models.GameSort{Field: models.SortByLastPlayed, Order: models.SortOrderDesc})
+	if err != nil {
+		t.Fatalf("GetGames failed: %v", err)
+	}
+	var order []string
+	for _, game := range games {
+		order = append(order, game.Instance.ID)
+	}
+	if want := []string{"inst2", "inst3", "inst1", "inst4"}; !slices.Equal(order, want) {
+		t.Errorf("expected the later of tracked and reported play first, got %v", order)
+	}
+}
@@ -0,0 +1,112 @@
+package games
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// maxRefreshPlanSamples bounds the sample entries returned per change kind
+const maxRefreshPlanSamples = 20
+
+// PreviewRefresh scans all sources and reports what RefreshGames would change,
+// without writing anything to the database.
+func (s *GamesService) PreviewRefresh() (models.RefreshPlan, error) {
+	var plan models.RefreshPlan
+
+	for _, source := range s.registry.GetAll() {
+		instances, err := source.GetInstances(context.Background())
+		if err != nil {
+			s.logger.Error("failed to get instances from source", "source", source.Name(), "error", err)
+			plan.FailedSources = append(plan.FailedSources, source.Name())
+			continue
+		}
+
+		if err := s.previewSource(&plan, source.Name(), instances); err != nil {
+			return models.RefreshPlan{}, err
+		}
+	}
+
+	return plan, nil
+}
+
+// previewSource diffs one source's scanned instances against the database and adds them to plan
+func (s *GamesService) previewSource(plan *models.RefreshPlan, sourceName string, instances []models.GameInstance) error {
+	stored, err := s.db.GetInstances(models.GameFilter{Source: sourceName})
+	if err != nil {
+		return fmt.Errorf("failed to get stored instances for %s: %w", sourceName, err)
+	}
+
+	orphans, err := s.orphanedInstances(sourceName, instances)
+	if err != nil {
+		return err
+	}
+
+	scanned := make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		if scanned[instance.ID] {
+			continue
+		}
+		scanned[instance.ID] = true
+
+		existing, err := s.db.GetInstance(instance.ID)
+		if err != nil {
+			return fmt.Errorf("failed to check existing instance: %w", err)
+		}
+
+		entry := models.RefreshPlanEntry{
+			InstanceID: instance.ID,
+			GameID:     instance.GameID,
+			Source:     sourceName,
+			Platform:   instance.Platform,
+			Name:       s.getDisplayName(instance),
+			Path:       instance.Path,
+		}
+
+		if existing == nil {
+			if orphan, ok := takeOrphan(&orphans, instance); ok {
+				scanned[orphan.ID] = true
+				entry.InstanceID, entry.GameID = orphan.ID, orphan.GameID
+				plan.Relinked++
+				plan.RelinkedSamples = appendSample(plan.RelinkedSamples, entry)
+				continue
+			}
+		}
+
+		switch {
+		case existing == nil:
+			plan.New++
+			plan.NewSamples = appendSample(plan.NewSamples, entry)
+		case customMetadataChanged(instance, existing) || instanceFieldsChanged(instance, existing):
+			plan.Updated++
+			plan.UpdatedSamples = appendSample(plan.UpdatedSamples, entry)
+		default:
+			plan.Unchanged++
+		}
+	}
+
+	for _, instance := range stored {
+		if scanned[instance.ID] || stillOnDisk(instance) {
+			continue
+		}
+		plan.Missing++
+		plan.MissingSamples = appendSample(plan.MissingSamples, models.RefreshPlanEntry{
+			InstanceID: instance.ID,
+			GameID:     instance.GameID,
+			Source:     sourceName,
+			Platform:   instance.Platform,
+			Name:       s.getDisplayName(instance),
+			Path:       instance.Path,
+		})
+	}
+
+	return nil
+}
+
+func appendSample(samples []models.RefreshPlanEntry, entry models.RefreshPlanEntry) []models.RefreshPlanEntry {
+	if len(samples) >= maxRefreshPlanSamples {
+		return samples
+	}
+	return append(samples, entry)
+}
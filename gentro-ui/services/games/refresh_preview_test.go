@@ -0,0 +1,62 @@
+package games
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestPreviewRefresh(t *testing.T) {
+	service := newTestService(t)
+
+	stored := []models.GameInstance{
+		{ID: "inst1", GameID: "game1", Source: "mock", Platform: "nes", FileSize: 1},
+		{ID: "inst2", GameID: "game1", Source: "mock", Platform: "nes", FileSize: 1},
+		{ID: "inst3", GameID: "game1", Source: "mock", Platform: "nes", FileSize: 1},
+	}
+	if _, err := service.syncSourceInstances("mock", stored); err != nil {
+		t.Fatalf("failed to seed instances: %v", err)
+	}
+
+	scanned := []models.GameInstance{
+		stored[0],
+		{ID: "inst2", GameID: "game1", Source: "mock", Platform: "nes", FileSize: 2},
+		{ID: "inst4", GameID: "game2", Source: "mock", Platform: "nes", Filename: "four.nes"},
+	}
+	service.registry.Register(context.Background(), &MockSource{name: "mock", instances: scanned})
+
+	plan, err := service.PreviewRefresh()
+	if err != nil {
+		t.Fatalf("PreviewRefresh failed: %v", err)
+	}
+
+	if plan.New != 1 || plan.Updated != 1 || plan.Missing != 1 || plan.Unchanged != 1 {
+		t.Errorf("unexpected plan counts: %+v", plan)
+	}
+	if len(plan.NewSamples) != 1 || plan.NewSamples[0].InstanceID != "inst4" || plan.NewSamples[0].Name != "four" {
+		t.Errorf("unexpected new samples: %+v", plan.NewSamples)
+	}
+	if len(plan.UpdatedSamples) != 1 || plan.UpdatedSamples[0].InstanceID != "inst2" {
+		t.Errorf("unexpected updated samples: %+v", plan.UpdatedSamples)
+	}
+	if len(plan.MissingSamples) != 1 || plan.MissingSamples[0].InstanceID != "inst3" {
+		t.Errorf("unexpected missing samples: %+v", plan.MissingSamples)
+	}
+
+	// Nothing may be written
+	instance, err := service.db.GetInstance("inst4")
+	if err != nil {
+		t.Fatalf("GetInstance failed: %v", err)
+	}
+	if instance != nil {
+		t.Error("preview must not create instances")
+	}
+	instance, err = service.db.GetInstance("inst2")
+	if err != nil {
+		t.Fatalf("GetInstance failed: %v", err)
+	}
+	if instance.FileSize != 1 {
+		t.Errorf("preview must not update instances, got file size %d", instance.FileSize)
+	}
+}
@@ -0,0 +1,104 @@
+package games
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// orphan is a stored instance the scan didn't find under its ID
+type orphan struct {
+	models.GameInstance
+	// rescanned is set when the scan found the instance's file under a new ID,
+	// e.g. one stored before the source changed how it derives IDs. Only the
+	// instance scanned at the same path may take it over.
+	rescanned bool
+}
+
+// orphanedInstances returns a source's stored instances that the scan didn't
+// find and whose file is gone or was scanned under a new ID. A newly found copy
+// of a missing file was most likely moved there, so it can take over the
+// instance. Instances whose file still exists but wasn't scanned, like ROMs
+// added from outside the scanned folders, aren't orphaned.
+func (s *GamesService) orphanedInstances(sourceName string, scanned []models.GameInstance) ([]orphan, error) {
+	stored, err := s.db.GetInstances(models.GameFilter{Source: sourceName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stored instances for %s: %w", sourceName, err)
+	}
+
+	found := make(map[string]bool, len(scanned))
+	scannedPaths := make(map[string]bool, len(scanned))
+	for _, instance := range scanned {
+		found[instance.ID] = true
+		if instance.Path != "" {
+			scannedPaths[instance.Path] = true
+		}
+	}
+
+	var orphans []orphan
+	for _, instance := range stored {
+		if found[instance.ID] || instance.Path == "" {
+			continue
+		}
+		if scannedPaths[instance.Path] {
+			orphans = append(orphans, orphan{GameInstance: instance, rescanned: true})
+			continue
+		}
+		if _, err := os.Stat(instance.Path); errors.Is(err, fs.ErrNotExist) {
+			orphans = append(orphans, orphan{GameInstance: instance})
+		}
+	}
+	return orphans, nil
+}
+
+// stillOnDisk reports whether a stored instance's file exists. A scan that doesn't
+// report such an instance, like a ROM added from outside the scanned folders,
+// hasn't lost it.
+func stillOnDisk(instance models.GameInstance) bool {
+	if instance.Path == "" {
+		return false
+	}
+	_, err := os.Stat(instance.Path)
+	return err == nil
+}
+
+// takeOrphan finds the orphan a newly found instance replaces and removes it
+// from orphans so it's relinked only once. An instance rescanned at the same
+// path wins; otherwise a missing file is matched by file hash or source ID on
+// the same platform.
+func takeOrphan(orphans *[]orphan, instance models.GameInstance) (*models.GameInstance, bool) {
+	take := func(i int) (*models.GameInstance, bool) {
+		taken := (*orphans)[i].GameInstance
+		*orphans = append((*orphans)[:i], (*orphans)[i+1:]...)
+		return &taken, true
+	}
+
+	for i, orphan := range *orphans {
+		if orphan.rescanned && orphan.Path == instance.Path {
+			return take(i)
+		}
+	}
+	for i, orphan := range *orphans {
+		if orphan.rescanned || orphan.Platform != instance.Platform {
+			continue
+		}
+		sameFile := orphan.FileHash != "" && orphan.FileHash == instance.FileHash
+		sameSourceID := orphan.SourceID != "" && orphan.SourceID == instance.SourceID
+		if sameFile || sameSourceID {
+			return take(i)
+		}
+	}
+	return nil, false
+}
+
+// relinkTo moves a newly found instance onto the orphan it replaces. The
+// orphan's IDs are kept so its custom metadata, play history and metadata
+// survive the move.
+func relinkTo(instance models.GameInstance, orphan *models.GameInstance) models.GameInstance {
+	instance.ID = orphan.ID
+	instance.GameID = orphan.GameID
+	return instance
+}
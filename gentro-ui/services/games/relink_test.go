@@ -0,0 +1,125 @@
+package games
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestSyncSourceInstances_RelinksMovedFiles(t *testing.T) {
+	service := newTestService(t)
+	dir := t.TempDir()
+	kept := filepath.Join(dir, "kept.nes")
+	if err := os.WriteFile(kept, []byte("kept"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "moved", GameID: "game1", Source: "mock", Platform: "nes", Path: filepath.Join(dir, "old", "game.nes"), FileHash: "h1",
+			CustomMetadata: map[string]any{"favorite": true}},
+		{ID: "kept", GameID: "game2", Source: "mock", Platform: "nes", Path: kept, FileHash: "h2"},
+	}); err != nil {
+		t.Fatalf("failed to seed instances: %v", err)
+	}
+	if err := service.db.AddPlaySession(&models.PlaySession{InstanceID: "moved", StartedAt: time.Now().Add(-time.Hour), EndedAt: time.Now()}); err != nil {
+		t.Fatalf("failed to add play session: %v", err)
+	}
+
+	// Like emulated ROMs, a moved file is scanned under a new ID with the same hash
+	newPath := filepath.Join(dir, "new", "game.nes")
+	scanned := []models.GameInstance{
+		{ID: "moved-new", GameID: "game1-new", Source: "mock", Platform: "nes", Path: newPath, Filename: "game.nes", FileHash: "h1"},
+		// A copy of a file that still exists is a new instance
+		{ID: "copy", GameID: "game2", Source: "mock", Platform: "nes", Path: filepath.Join(dir, "copy.nes"), FileHash: "h2"},
+	}
+
+	// The preview agrees with the refresh
+	service.registry.Register(context.Background(), &MockSource{name: "mock", instances: scanned})
+	plan, err := service.PreviewRefresh()
+	if err != nil {
+		t.Fatalf("PreviewRefresh failed: %v", err)
+	}
+	// kept wasn't scanned, but its file still exists so it isn't missing
+	if plan.Relinked != 1 || plan.New != 1 || plan.Missing != 0 {
+		t.Errorf("unexpected plan counts: %+v", plan)
+	}
+	if len(plan.RelinkedSamples) != 1 || plan.RelinkedSamples[0].InstanceID != "moved" || plan.RelinkedSamples[0].Path != newPath {
+		t.Errorf("unexpected relinked samples: %+v", plan.RelinkedSamples)
+	}
+
+	synced, err := service.syncSourceInstances("mock", scanned)
+	if err != nil {
+		t.Fatalf("syncSourceInstances failed: %v", err)
+	}
+	if synced.relinked != 1 || synced.added != 1 || synced.missing != 0 {
+		t.Errorf("expected 1 relinked, 1 added and none missing, got %+v", synced)
+	}
+
+	moved, err := service.db.GetInstance("moved")
+	if err != nil || moved == nil {
+		t.Fatalf("expected the moved instance to be kept: %v", err)
+	}
+	if moved.Path != newPath || moved.Filename != "game.nes" || moved.GameID != "game1" {
+		t.Errorf("expected the instance to point at the new path, got %+v", moved)
+	}
+	if moved.CustomMetadata["favorite"] != true {
+		t.Errorf("expected custom metadata to survive the move, got %v", moved.CustomMetadata)
+	}
+	if sessions, err := service.db.GetPlaySessions("moved"); err != nil || len(sessions) != 1 {
+		t.Errorf("expected play history to survive the move, got %v (%v)", sessions, err)
+	}
+	if duplicate, _ := service.db.GetInstance("moved-new"); duplicate != nil {
+		t.Error("expected no duplicate instance at the new path")
+	}
+	if copied, _ := service.db.GetInstance("copy"); copied == nil {
+		t.Error("expected a copy of an existing file to be added")
+	}
+}
+
+func TestSyncSourceInstances_TakesOverRescannedPath(t *testing.T) {
+	service := newTestService(t)
+	dir := t.TempDir()
+	rom := filepath.Join(dir, "zelda.nes")
+	if err := os.WriteFile(rom, []byte("rom"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Stored under an ID the source no longer derives
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "old-id", GameID: "game1", Source: "mock", Platform: "nes", Path: rom, FileHash: "h1",
+			CustomMetadata: map[string]any{"favorite": true}},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+
+	scanned := []models.GameInstance{
+		// A copy with the same hash, scanned first, mustn't take the instance from the file at its path
+		{ID: "copy-id", GameID: "game1", Source: "mock", Platform: "nes", Path: filepath.Join(dir, "copy.nes"), FileHash: "h1"},
+		{ID: "new-id", GameID: "game1", Source: "mock", Platform: "nes", Path: rom, FileHash: "h1"},
+	}
+	synced, err := service.syncSourceInstances("mock", scanned)
+	if err != nil {
+		t.Fatalf("syncSourceInstances failed: %v", err)
+	}
+	if synced.relinked != 1 || synced.added != 1 {
+		t.Errorf("expected 1 relinked and 1 added, got %+v", synced)
+	}
+
+	kept, err := service.db.GetInstance("old-id")
+	if err != nil || kept == nil {
+		t.Fatalf("expected the stored instance to be kept: %v", err)
+	}
+	if kept.Path != rom || kept.CustomMetadata["favorite"] != true {
+		t.Errorf("expected the stored instance to keep its path and metadata, got %+v", kept)
+	}
+	if duplicate, _ := service.db.GetInstance("new-id"); duplicate != nil {
+		t.Error("expected no second instance for the same path")
+	}
+	if copied, _ := service.db.GetInstance("copy-id"); copied == nil {
+		t.Error("expected the copy to be added")
+	}
+}
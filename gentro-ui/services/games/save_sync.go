@@ -0,0 +1,190 @@
+package games
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/rhythmerc/gentro-ui/services/config"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// SaveSyncer shares an instance's saves between devices. PullSaves runs before
+// the instance launches and PushSaves after it stops.
+type SaveSyncer interface {
+	PullSaves(instance models.GameInstance) error
+	PushSaves(instance models.GameInstance) error
+}
+
+// SaveLocator returns where an instance's emulator keeps its saves
+type SaveLocator func(instance models.GameInstance) ([]models.SaveLocation, error)
+
+// FolderSyncer syncs saves through a folder the user keeps in sync themselves,
+// like a cloud drive. Each instance's saves are kept under a folder named after
+// its ID, laid out like a BackupSaves archive. The newer copy of each file wins
+// and nothing is deleted.
+type FolderSyncer struct {
+	dir    string
+	locate SaveLocator
+}
+
+// NewFolderSyncer creates a FolderSyncer that keeps saves in dir
+func NewFolderSyncer(dir string, locate SaveLocator) *FolderSyncer {
+	return &FolderSyncer{dir: dir, locate: locate}
+}
+
+// PullSaves copies saves from the synced folder that are newer than the local ones
+func (f *FolderSyncer) PullSaves(instance models.GameInstance) error {
+	locations, err := f.locate(instance)
+	if err != nil {
+		return err
+	}
+
+	root := filepath.Join(f.dir, instance.ID)
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		dest, err := saveEntryPath(locations, filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+		return copyIfNewer(p, dest)
+	})
+}
+
+// PushSaves copies local saves that are newer than the synced ones to the folder
+func (f *FolderSyncer) PushSaves(instance models.GameInstance) error {
+	locations, err := f.locate(instance)
+	if err != nil {
+		return err
+	}
+
+	files, err := collectSaveFiles(locations)
+	if err != nil {
+		return err
+	}
+	root := filepath.Join(f.dir, instance.ID)
+	for _, file := range files {
+		if err := copyIfNewer(file.path, filepath.Join(root, filepath.FromSlash(file.name))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyIfNewer copies src to dest, keeping its modification time, unless dest is
+// at least as new
+func copyIfNewer(src, dest string) error {
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("failed to stat save file: %w", err)
+	}
+	if destInfo, err := os.Stat(dest); err == nil && !srcInfo.ModTime().After(destInfo.ModTime()) {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create save directory: %w", err)
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open save file: %w", err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("failed to create save file: %w", err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy save file: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to copy save file: %w", err)
+	}
+	return os.Chtimes(dest, srcInfo.ModTime(), srcInfo.ModTime())
+}
+
+// UpdateSaveSyncConfig saves save sync settings and applies them to future launches
+func (s *GamesService) UpdateSaveSyncConfig(saveSyncConfig config.SaveSyncConfig) error {
+	if s.config == nil {
+		return fmt.Errorf("config manager not initialized")
+	}
+
+	if err := s.config.SetSaveSync(saveSyncConfig); err != nil {
+		return err
+	}
+	s.applySaveSyncConfig(saveSyncConfig)
+	return nil
+}
+
+// applySaveSyncConfig syncs the configured platforms through the configured folder
+func (s *GamesService) applySaveSyncConfig(saveSyncConfig config.SaveSyncConfig) {
+	if saveSyncConfig.Folder == "" {
+		s.setSaveSyncer(nil, nil)
+		return
+	}
+	s.setSaveSyncer(NewFolderSyncer(saveSyncConfig.Folder, s.saveLocations), saveSyncConfig.Platforms)
+}
+
+// setSaveSyncer syncs the saves of instances on platforms through syncer. A nil
+// syncer turns syncing off.
+func (s *GamesService) setSaveSyncer(syncer SaveSyncer, platforms []string) {
+	s.saveSyncMu.Lock()
+	defer s.saveSyncMu.Unlock()
+	s.saveSyncer = syncer
+	s.saveSyncPlatforms = slices.Clone(platforms)
+}
+
+// saveSyncerFor returns the syncer for an instance, or nil if its platform isn't synced
+func (s *GamesService) saveSyncerFor(instance models.GameInstance) SaveSyncer {
+	s.saveSyncMu.RLock()
+	defer s.saveSyncMu.RUnlock()
+	if s.saveSyncer == nil || !slices.Contains(s.saveSyncPlatforms, instance.Platform) {
+		return nil
+	}
+	return s.saveSyncer
+}
+
+// pullSaves fetches an instance's synced saves before it launches. Failures are
+// logged rather than stopping the launch.
+func (s *GamesService) pullSaves(instance models.GameInstance) {
+	syncer := s.saveSyncerFor(instance)
+	if syncer == nil {
+		return
+	}
+	if err := syncer.PullSaves(instance); err != nil {
+		s.logger.Warn("failed to pull saves", "instanceId", instance.ID, "error", err)
+	}
+}
+
+// pushSaves shares an instance's saves after it stops
+func (s *GamesService) pushSaves(instanceID string) {
+	instance, err := s.db.GetInstance(instanceID)
+	if err != nil || instance == nil {
+		return
+	}
+	syncer := s.saveSyncerFor(*instance)
+	if syncer == nil {
+		return
+	}
+	if err := syncer.PushSaves(*instance); err != nil {
+		s.logger.Warn("failed to push saves", "instanceId", instanceID, "error", err)
+	}
+}
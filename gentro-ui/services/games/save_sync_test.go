@@ -0,0 +1,83 @@
+package games
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/config"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestFolderSaveSync(t *testing.T) {
+	saves := t.TempDir()
+	locate := func(instance models.GameInstance) ([]models.SaveLocation, error) {
+		return []models.SaveLocation{{Dir: saves, Prefix: "Mario."}}, nil
+	}
+	service := newTestService(t)
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "mario", GameID: "mario", Source: "mock", Platform: "nes"},
+		{ID: "sonic", GameID: "sonic", Source: "mock", Platform: "genesis"},
+	}); err != nil {
+		t.Fatalf("failed to seed instances: %v", err)
+	}
+
+	synced := t.TempDir()
+	service.setSaveSyncer(NewFolderSyncer(synced, locate), []string{"nes"})
+
+	write := func(path, content string, modified time.Time) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func(path string) string {
+		data, _ := os.ReadFile(path)
+		return string(data)
+	}
+	now := time.Now()
+	local := filepath.Join(saves, "Mario.sav")
+	remote := filepath.Join(synced, "mario", "0", "Mario.sav")
+	write(local, "local", now.Add(-time.Hour))
+	write(filepath.Join(saves, "Zelda.sav"), "zelda", now)
+
+	service.pushSaves("mario")
+	if got := read(remote); got != "local" {
+		t.Fatalf("expected the save to be pushed, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(synced, "mario", "0", "Zelda.sav")); !os.IsNotExist(err) {
+		t.Errorf("expected only the game's own saves to be pushed, got %v", err)
+	}
+
+	// Another device saved since: pulling takes its newer copy
+	write(remote, "other device", now)
+	service.pullSaves(models.GameInstance{ID: "mario", Platform: "nes"})
+	if got := read(local); got != "other device" {
+		t.Errorf("expected the newer synced save to be pulled, got %q", got)
+	}
+
+	// An older synced copy doesn't overwrite a newer local save
+	write(local, "newest", now.Add(time.Hour))
+	service.pullSaves(models.GameInstance{ID: "mario", Platform: "nes"})
+	if got := read(local); got != "newest" {
+		t.Errorf("expected the newer local save to be kept, got %q", got)
+	}
+
+	// Platforms that aren't enabled aren't synced
+	service.pushSaves("sonic")
+	if _, err := os.Stat(filepath.Join(synced, "sonic")); !os.IsNotExist(err) {
+		t.Errorf("expected an unsynced platform to be skipped, got %v", err)
+	}
+
+	service.applySaveSyncConfig(config.SaveSyncConfig{Platforms: []string{"nes"}})
+	if service.saveSyncerFor(models.GameInstance{Platform: "nes"}) != nil {
+		t.Error("expected syncing to be off without a folder")
+	}
+}
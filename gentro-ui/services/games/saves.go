@@ -0,0 +1,206 @@
+package games
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// BackupSaves archives an instance's save files into a zip in destDir and
+// returns the zip's path. Each file is stored under the index of the save
+// location it came from, so RestoreSaves can put it back where the emulator
+// expects it.
+func (s *GamesService) BackupSaves(instanceID, destDir string) (string, error) {
+	locations, err := s.GetSaveLocations(instanceID)
+	if err != nil {
+		return "", err
+	}
+
+	files, err := collectSaveFiles(locations)
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no save data found for instance %s", instanceID)
+	}
+
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	zipPath := filepath.Join(destDir, fmt.Sprintf("%s-saves-%s.zip", instanceID, time.Now().Format("20060102-150405")))
+	if err := writeSaveArchive(zipPath, files); err != nil {
+		os.Remove(zipPath)
+		return "", err
+	}
+
+	s.logger.Info("backed up saves", "instance", instanceID, "files", len(files), "path", zipPath)
+	return zipPath, nil
+}
+
+// RestoreSaves extracts a backup made by BackupSaves into the instance's save
+// locations, overwriting existing saves of the same name, and returns how many
+// files were restored
+func (s *GamesService) RestoreSaves(instanceID, zipPath string) (int, error) {
+	locations, err := s.GetSaveLocations(instanceID)
+	if err != nil {
+		return 0, err
+	}
+
+	archive, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open save backup: %w", err)
+	}
+	defer archive.Close()
+
+	restored := 0
+	for _, entry := range archive.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+		dest, err := saveEntryPath(locations, entry.Name)
+		if err != nil {
+			return restored, err
+		}
+		if err := extractSaveFile(entry, dest); err != nil {
+			return restored, err
+		}
+		restored++
+	}
+
+	s.logger.Info("restored saves", "instance", instanceID, "files", restored, "path", zipPath)
+	return restored, nil
+}
+
+// saveFile is a save file and its name inside a backup
+type saveFile struct {
+	path string
+	name string
+}
+
+// collectSaveFiles lists the files in each save location, limited to names
+// starting with the location's prefix. Locations that don't exist yet are skipped.
+func collectSaveFiles(locations []models.SaveLocation) ([]saveFile, error) {
+	var files []saveFile
+	for i, location := range locations {
+		err := filepath.WalkDir(location.Dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if p == location.Dir && errors.Is(err, fs.ErrNotExist) {
+					return fs.SkipDir
+				}
+				return err
+			}
+			if d.IsDir() || !strings.HasPrefix(d.Name(), location.Prefix) {
+				return nil
+			}
+			rel, err := filepath.Rel(location.Dir, p)
+			if err != nil {
+				return err
+			}
+			files = append(files, saveFile{path: p, name: path.Join(strconv.Itoa(i), filepath.ToSlash(rel))})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read saves in %s: %w", location.Dir, err)
+		}
+	}
+	return files, nil
+}
+
+// writeSaveArchive zips files into zipPath
+func writeSaveArchive(zipPath string, files []saveFile) error {
+	out, err := os.Create(zipPath)
+	if err != nil {
+		return fmt.Errorf("failed to create save backup: %w", err)
+	}
+	defer out.Close()
+
+	archive := zip.NewWriter(out)
+	for _, file := range files {
+		if err := addSaveFile(archive, file); err != nil {
+			return err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to write save backup: %w", err)
+	}
+	return nil
+}
+
+// addSaveFile copies a save file into the archive, keeping its modification time
+func addSaveFile(archive *zip.Writer, file saveFile) error {
+	in, err := os.Open(file.path)
+	if err != nil {
+		return fmt.Errorf("failed to open save file: %w", err)
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat save file: %w", err)
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return fmt.Errorf("failed to create zip header: %w", err)
+	}
+	header.Name = file.name
+	header.Method = zip.Deflate
+
+	w, err := archive.CreateHeader(header)
+	if err != nil {
+		return fmt.Errorf("failed to add save file: %w", err)
+	}
+	if _, err := io.Copy(w, in); err != nil {
+		return fmt.Errorf("failed to write save file: %w", err)
+	}
+	return nil
+}
+
+// saveEntryPath maps a backup entry back to a path in its save location,
+// rejecting entries that don't belong to one or would escape it
+func saveEntryPath(locations []models.SaveLocation, name string) (string, error) {
+	index, rel, ok := strings.Cut(name, "/")
+	i, err := strconv.Atoi(index)
+	if !ok || err != nil || i < 0 || i >= len(locations) {
+		return "", fmt.Errorf("backup entry %q doesn't match this game's save locations", name)
+	}
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("backup entry %q has an invalid path", name)
+	}
+	return filepath.Join(locations[i].Dir, filepath.FromSlash(rel)), nil
+}
+
+// extractSaveFile writes a backup entry to dest, restoring its modification time
+func extractSaveFile(entry *zip.File, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create save directory: %w", err)
+	}
+
+	in, err := entry.Open()
+	if err != nil {
+		return fmt.Errorf("failed to read backup entry: %w", err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("failed to create save file: %w", err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to write save file: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to write save file: %w", err)
+	}
+	return os.Chtimes(dest, entry.Modified, entry.Modified)
+}
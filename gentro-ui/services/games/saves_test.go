@@ -0,0 +1,85 @@
+package games
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/games/emulator"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestBackupAndRestoreSaves(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	service := newTestService(t)
+	service.emuService = emulator.NewService(service.db, service.logger)
+	if err := service.emuService.Initialize(); err != nil {
+		t.Fatalf("failed to initialize emulators: %v", err)
+	}
+	if err := service.db.UpdateEmulatorAvailability("nestopia", true); err != nil {
+		t.Fatalf("failed to mark nestopia available: %v", err)
+	}
+	if _, err := service.syncSourceInstances("mock", []models.GameInstance{
+		{ID: "mario", GameID: "mario", Source: "mock", Platform: "nes", Path: "/roms/nes/Mario.nes"},
+	}); err != nil {
+		t.Fatalf("failed to seed instances: %v", err)
+	}
+
+	nestopia := filepath.Join(home, ".var", "app", "ca._0ldsk00l.Nestopia", "data", "nestopia")
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save := filepath.Join(nestopia, "save", "Mario.sav")
+	state := filepath.Join(nestopia, "state", "Mario.ss1")
+	other := filepath.Join(nestopia, "save", "Zelda.sav")
+	write(save, "battery")
+	write(state, "state")
+	write(other, "zelda")
+
+	zipPath, err := service.BackupSaves("mario", filepath.Join(t.TempDir(), "backups"))
+	if err != nil {
+		t.Fatalf("BackupSaves failed: %v", err)
+	}
+
+	write(save, "overwritten")
+	if err := os.Remove(state); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(other); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := service.RestoreSaves("mario", zipPath)
+	if err != nil {
+		t.Fatalf("RestoreSaves failed: %v", err)
+	}
+	if restored != 2 {
+		t.Errorf("expected 2 files restored, got %d", restored)
+	}
+	for path, want := range map[string]string{save: "battery", state: "state"} {
+		if data, err := os.ReadFile(path); err != nil || string(data) != want {
+			t.Errorf("expected %s to contain %q, got %q (%v)", filepath.Base(path), want, data, err)
+		}
+	}
+	// Other games' saves aren't part of the backup
+	if _, err := os.Stat(other); !os.IsNotExist(err) {
+		t.Errorf("expected another game's save not to be restored, got %v", err)
+	}
+
+	if _, err := service.BackupSaves("mario", t.TempDir()); err != nil {
+		t.Errorf("expected a second backup to succeed: %v", err)
+	}
+	if err := os.RemoveAll(nestopia); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.BackupSaves("mario", t.TempDir()); err == nil {
+		t.Error("expected an error when there are no saves")
+	}
+}
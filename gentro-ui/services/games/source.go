@@ -2,10 +2,16 @@ package games
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"maps"
 	"os/exec"
 	"slices"
+	"sync"
 
+	"github.com/rhythmerc/gentro-ui/services/config"
+	"github.com/rhythmerc/gentro-ui/services/games/emulator"
+	"github.com/rhythmerc/gentro-ui/services/games/events"
 	"github.com/rhythmerc/gentro-ui/services/games/models"
 )
 
@@ -14,8 +20,9 @@ type GameSource interface {
 	// Name returns the source identifier (e.g., "steam", "emulated")
 	Name() string
 
-	// Init initializes the source with configuration
-	Init(config map[string]any) error
+	// Init initializes the source with configuration. Network detection
+	// should honor ctx, which carries the startup timeout.
+	Init(ctx context.Context, config map[string]any) error
 
 	// GetInstances returns all game instances from this source
 	// For Steam: returns installed games (Web API for library games planned)
@@ -23,8 +30,9 @@ type GameSource interface {
 	GetInstances(ctx context.Context) ([]models.GameInstance, error)
 
 	// GetGameArt returns art data for a specific game
-	// Returns: (data []byte, contentType string, error)
-	GetGameArt(ctx context.Context, instanceID string, artType string) ([]byte, string, error)
+	// Returns: (data []byte, contentType string, error); ErrArtNotFound if the art does not exist.
+	// The instance is the stored row, so sources can rely on SourceID rather than parsing IDs.
+	GetGameArt(ctx context.Context, instance models.GameInstance, artType string) ([]byte, string, error)
 
 	// Refresh updates the source's internal cache/state
 	Refresh(ctx context.Context) error
@@ -33,7 +41,8 @@ type GameSource interface {
 	// Returns (*exec.Cmd, error) where cmd.Process is the started process
 	Launch(ctx context.Context, instance models.GameInstance) (*exec.Cmd, error)
 
-	// MonitorProcess watches the game process and emits status events
+	// MonitorProcess watches the game process and emits its running and stopped
+	// events through events.Events. Launch emits nothing after the source starts the game.
 	// Source-specific implementation:
 	// - Emulated: Wait() for direct process exit
 	// - Steam: Activity-based polling with threshold
@@ -42,6 +51,105 @@ type GameSource interface {
 	// FilterInstances applies source-specific filters to a batch of instances
 	// Each source handles its own filtering logic (e.g., Steam tools filtering)
 	FilterInstances(instances []models.GameInstance, filter models.GameFilter) []models.GameInstance
+
+	// Capabilities describes which operations this source supports
+	Capabilities() models.SourceCapabilities
+}
+
+// ArtPrefetcher is implemented by sources that can warm their art cache in the
+// background after a refresh
+type ArtPrefetcher interface {
+	PrefetchArt(ctx context.Context, instances []models.GameInstance)
+}
+
+// ArtFileSource is implemented by sources that keep art on disk. GameArtPath returns
+// the art file's path, or an error wrapping models.ErrArtNotFound, so it can be
+// streamed rather than read into memory. GetGameArt is still used for art that
+// only exists in memory.
+type ArtFileSource interface {
+	GameArtPath(ctx context.Context, instance models.GameInstance, artType string) (string, error)
+}
+
+// ArtAvailability is implemented by sources that download art on demand. HasGameArt
+// reports whether an art type is cached or known to be downloadable, without
+// downloading it, so listing an instance's art stays cheap.
+type ArtAvailability interface {
+	HasGameArt(instance models.GameInstance, artType string) bool
+}
+
+// ManualAdder is implemented by sources that report SupportsManualAdd. An empty
+// platform asks the source to detect it.
+type ManualAdder interface {
+	AddManual(ctx context.Context, path, platform string) (models.GameInstance, error)
+}
+
+// FileHasher is implemented by sources that record a FileHash for their
+// instances. HashFile computes the hash the source records for a file.
+type FileHasher interface {
+	HashFile(path string) (string, error)
+}
+
+// HeroVideoSource is implemented by sources that can look up a trailer for an
+// instance. HeroVideoURL returns "" if the instance has none; it's called when the
+// video is first requested, so implementations should cache their lookups.
+type HeroVideoSource interface {
+	HeroVideoURL(ctx context.Context, instance models.GameInstance) (string, error)
+}
+
+// SourceDeps holds the shared services passed to source factories
+type SourceDeps struct {
+	Logger *slog.Logger
+	// ArtCache is the root art cache; sources keep their art in a subdirectory named after themselves
+	ArtCache        string
+	EmulatorService *emulator.Service
+	// Config may be nil if the config manager failed to load
+	Config *config.Manager
+	// Events is shared with the service so all status updates go through one emitter
+	Events *events.Events
+}
+
+// SourceFactory constructs an uninitialized source. SourceRegistry calls Init on the result.
+type SourceFactory func(deps SourceDeps) GameSource
+
+var (
+	sourceFactoriesMu sync.RWMutex
+	sourceFactories   = make(map[string]SourceFactory)
+)
+
+// RegisterSource makes a source type available to ServiceStartup. Source packages
+// call it from init, so a source is enabled by importing its package.
+// It panics if factory is nil or name is already registered.
+func RegisterSource(name string, factory SourceFactory) {
+	sourceFactoriesMu.Lock()
+	defer sourceFactoriesMu.Unlock()
+
+	if factory == nil {
+		panic("games: RegisterSource factory is nil")
+	}
+	if _, dup := sourceFactories[name]; dup {
+		panic(fmt.Sprintf("games: RegisterSource called twice for source %q", name))
+	}
+	sourceFactories[name] = factory
+}
+
+// RegisteredSources returns the names of all registered source factories, sorted
+func RegisteredSources() []string {
+	sourceFactoriesMu.RLock()
+	defer sourceFactoriesMu.RUnlock()
+
+	return slices.Sorted(maps.Keys(sourceFactories))
+}
+
+// newRegisteredSource builds a source from its registered factory
+func newRegisteredSource(name string, deps SourceDeps) (GameSource, bool) {
+	sourceFactoriesMu.RLock()
+	factory, ok := sourceFactories[name]
+	sourceFactoriesMu.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
+	return factory(deps), true
 }
 
 // SourceRegistry manages multiple game sources
@@ -57,19 +165,26 @@ func NewSourceRegistry() *SourceRegistry {
 }
 
 // Register adds a source to the registry
-func (r *SourceRegistry) Register(source GameSource) error {
-	if err := source.Init(nil); err != nil {
-		return err
-	}
-	r.sources[source.Name()] = source
-	return nil
+func (r *SourceRegistry) Register(ctx context.Context, source GameSource) error {
+	return r.RegisterWithConfig(ctx, source, nil)
 }
 
-// RegisterWithConfig adds a source with configuration
-func (r *SourceRegistry) RegisterWithConfig(source GameSource, config map[string]any) error {
-	if err := source.Init(config); err != nil {
-		return err
+// RegisterWithConfig adds a source with configuration. If ctx ends before
+// Init returns, the source is not registered.
+func (r *SourceRegistry) RegisterWithConfig(ctx context.Context, source GameSource, config map[string]any) error {
+	// Init runs in its own goroutine so a source that ignores ctx still can't block startup
+	done := make(chan error, 1)
+	go func() { done <- source.Init(ctx, config) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return err
+		}
+	case <-ctx.Done():
+		return fmt.Errorf("timed out initializing source %s: %w", source.Name(), ctx.Err())
 	}
+
 	r.sources[source.Name()] = source
 	return nil
 }
@@ -0,0 +1,90 @@
+package games
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestRegisterSource(t *testing.T) {
+	var gotDeps SourceDeps
+	RegisterSource("test-factory", func(deps SourceDeps) GameSource {
+		gotDeps = deps
+		return &MockSource{name: "test-factory"}
+	})
+
+	if !slices.Contains(RegisteredSources(), "test-factory") {
+		t.Fatalf("expected test-factory in %v", RegisteredSources())
+	}
+
+	source, ok := newRegisteredSource("test-factory", SourceDeps{ArtCache: "/tmp/art"})
+	if !ok || source.Name() != "test-factory" {
+		t.Fatalf("expected factory to build test-factory source, got %v", source)
+	}
+	if gotDeps.ArtCache != "/tmp/art" {
+		t.Errorf("expected deps to be passed to factory, got %+v", gotDeps)
+	}
+
+	if _, ok := newRegisteredSource("missing", SourceDeps{}); ok {
+		t.Error("expected unknown source to be reported missing")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected duplicate registration to panic")
+		}
+	}()
+	RegisterSource("test-factory", func(deps SourceDeps) GameSource { return nil })
+}
+
+// capableSource is a MockSource advertising launch support
+type capableSource struct{ MockSource }
+
+func (c *capableSource) Capabilities() models.SourceCapabilities {
+	return models.SourceCapabilities{CanLaunch: true}
+}
+
+func TestGetSourceCapabilities(t *testing.T) {
+	service := &GamesService{registry: NewSourceRegistry()}
+	service.registry.Register(context.Background(), &MockSource{name: "mock"})
+	service.registry.Register(context.Background(), &capableSource{MockSource{name: "capable"}})
+
+	capabilities := service.GetSourceCapabilities()
+	if len(capabilities) != 2 {
+		t.Fatalf("expected 2 sources, got %v", capabilities)
+	}
+	if capabilities["mock"].CanLaunch || !capabilities["capable"].CanLaunch {
+		t.Errorf("unexpected capabilities: %+v", capabilities)
+	}
+}
+
+// slowSource blocks in Init until its release channel is closed
+type slowSource struct {
+	MockSource
+	release chan struct{}
+}
+
+func (s *slowSource) Init(ctx context.Context, config map[string]any) error {
+	<-s.release
+	return nil
+}
+
+func TestRegister_InitTimeout(t *testing.T) {
+	registry := NewSourceRegistry()
+	source := &slowSource{MockSource: MockSource{name: "slow"}, release: make(chan struct{})}
+	defer close(source.release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := registry.Register(ctx, source); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if _, ok := registry.Get("slow"); ok {
+		t.Error("expected timed out source not to be registered")
+	}
+}
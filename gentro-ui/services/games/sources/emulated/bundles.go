@@ -0,0 +1,140 @@
+package emulated
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// diskTagRegex matches the disk tag of a multi-disk game, e.g. "(Disk 1 of 3)" or "(Disc 2)"
+var diskTagRegex = regexp.MustCompile(`(?i)\s*\(dis[ck]\s*(\d+)(?:\s*of\s*\d+)?\)`)
+
+// biosTagRegex matches the "[BIOS]" prefix No-Intro puts on BIOS images
+var biosTagRegex = regexp.MustCompile(`(?i)^\[bios\]`)
+
+// romFile is a ROM found while scanning a directory
+type romFile struct {
+	path string
+	info os.FileInfo
+}
+
+// romBundle is the files of one instance: the file launched and any it uses
+type romBundle struct {
+	primary romFile
+	files   []models.InstanceFile
+}
+
+// bundleROMs groups the ROMs of one directory into instances. On platforms with
+// disk sets, the disks of a game become one bundle launched from its first disk;
+// on romset platforms, clones reference their parent. BIOS images aren't games,
+// so they're returned separately for the caller to attach.
+func bundleROMs(roms []romFile, cfg PlatformConfig) ([]romBundle, []models.InstanceFile) {
+	var groups [][]romFile
+	var bios []models.InstanceFile
+	diskSets := make(map[string]int)
+
+	for _, rom := range roms {
+		name := rom.info.Name()
+		if biosTagRegex.MatchString(name) {
+			bios = append(bios, models.InstanceFile{Path: rom.path, Role: models.FileRoleBIOS})
+			continue
+		}
+
+		if cfg.DiskSets {
+			if key, ok := diskSetKey(name); ok {
+				if i, found := diskSets[key]; found {
+					groups[i] = append(groups[i], rom)
+					continue
+				}
+				diskSets[key] = len(groups)
+			}
+		}
+		groups = append(groups, []romFile{rom})
+	}
+
+	bundles := make([]romBundle, 0, len(groups))
+	for _, group := range groups {
+		// The first disk is launched, the rest passed along in order
+		slices.SortStableFunc(group, func(a, b romFile) int {
+			return diskNumber(a.info.Name()) - diskNumber(b.info.Name())
+		})
+		bundle := romBundle{primary: group[0]}
+		for _, disk := range group[1:] {
+			bundle.files = append(bundle.files, models.InstanceFile{Path: disk.path, Role: models.FileRoleDisk})
+		}
+		bundles = append(bundles, bundle)
+	}
+
+	if cfg.Romsets {
+		linkRomsetParents(bundles)
+	}
+
+	return bundles, bios
+}
+
+// diskSetKey identifies the set a disk belongs to: its filename without the
+// disk tag. Files without a disk tag aren't part of a set.
+func diskSetKey(filename string) (string, bool) {
+	if !diskTagRegex.MatchString(filename) {
+		return "", false
+	}
+	return strings.ToLower(diskTagRegex.ReplaceAllString(filename, "")), true
+}
+
+// diskNumber returns the number in a filename's disk tag, or 0 without one
+func diskNumber(filename string) int {
+	match := diskTagRegex.FindStringSubmatch(filename)
+	if match == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(match[1])
+	return n
+}
+
+// linkRomsetParents adds the parent romset to each clone. MAME clone names
+// extend their parent's, e.g. "sf2ua" is a clone of "sf2", so a romset's
+// parent is the longest other romset name it starts with.
+func linkRomsetParents(bundles []romBundle) {
+	stems := make([]string, len(bundles))
+	for i, bundle := range bundles {
+		stems[i] = romsetName(bundle.primary.info.Name())
+	}
+
+	for i := range bundles {
+		parent := -1
+		for j, stem := range stems {
+			if j == i || len(stem) >= len(stems[i]) || !strings.HasPrefix(stems[i], stem) {
+				continue
+			}
+			if parent < 0 || len(stem) > len(stems[parent]) {
+				parent = j
+			}
+		}
+		if parent >= 0 {
+			bundles[i].files = append(bundles[i].files, models.InstanceFile{Path: bundles[parent].primary.path, Role: models.FileRoleParent})
+		}
+	}
+}
+
+// romsetName is a romset's name: its filename without the extension, lowercased
+func romsetName(filename string) string {
+	return strings.ToLower(strings.TrimSuffix(filename, filepath.Ext(filename)))
+}
+
+// launchFiles returns the files passed to the emulator alongside the instance's
+// Path: the further disks of a multi-disk game. BIOS images and parent romsets
+// sit next to the ROM, where emulators look for them.
+func launchFiles(instance models.GameInstance) []string {
+	var files []string
+	for _, file := range instance.Files {
+		if file.Role == models.FileRoleDisk {
+			files = append(files, file.Path)
+		}
+	}
+	return files
+}
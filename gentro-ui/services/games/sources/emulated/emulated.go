@@ -11,10 +11,14 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
-	"syscall"
-	"time"
+	"sync"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/rhythmerc/gentro-ui/services/config"
+	"github.com/rhythmerc/gentro-ui/services/games"
 	"github.com/rhythmerc/gentro-ui/services/games/emulator"
 	"github.com/rhythmerc/gentro-ui/services/games/events"
 	"github.com/rhythmerc/gentro-ui/services/games/models"
@@ -22,13 +26,21 @@ import (
 
 // Source implements GameSource for emulated games (ROMs)
 type Source struct {
-	config                    Config
-	basePath                  string
-	platforms                 map[string]PlatformConfig
-	ArtCache                  string
-	emuService                *emulator.Service
-	Logger                    *slog.Logger
-	emulatorAvailabilityCache map[string]bool
+	config     Config
+	basePath   string
+	ArtCache   string
+	emuService *emulator.Service
+	Logger     *slog.Logger
+	Events     *events.Events
+	appConfig  *config.Manager
+
+	// exits hands each launched process's Wait result to MonitorProcess
+	exits processExits
+
+	// platforms is replaced, never modified, on each scan; readers take one
+	// snapshot through currentPlatforms and use it throughout
+	platforms   map[string]PlatformConfig
+	platformsMu sync.RWMutex
 }
 
 // Config holds emulated source configuration
@@ -42,6 +54,12 @@ type PlatformConfig struct {
 	Extensions  []string
 	DisplayName string
 	ArtTypes    []string
+	// MaxDepth limits scan recursion; see config.PlatformConfigOverride.MaxDepth
+	MaxDepth int
+	// DiskSets bundles the disks of multi-disk games into one instance
+	DiskSets bool
+	// Romsets links clone romsets to their parent, MAME-style
+	Romsets bool
 }
 
 // Common ROM extensions by platform
@@ -81,6 +99,24 @@ var defaultPlatformConfigs = map[string]PlatformConfig{
 		DisplayName: "PlayStation",
 		ArtTypes:    []string{"boxart", "screenshot"},
 	},
+	"msx": {
+		Extensions:  []string{".rom", ".mx1", ".mx2", ".dsk", ".cas"},
+		DisplayName: "MSX",
+		ArtTypes:    []string{"boxart", "screenshot"},
+		DiskSets:    true,
+	},
+	"amiga": {
+		Extensions:  []string{".adf", ".ipf"},
+		DisplayName: "Commodore Amiga",
+		ArtTypes:    []string{"boxart", "screenshot"},
+		DiskSets:    true,
+	},
+	"arcade": {
+		Extensions:  []string{".zip", ".7z"},
+		DisplayName: "Arcade",
+		ArtTypes:    []string{"boxart", "screenshot"},
+		Romsets:     true,
+	},
 }
 
 // romTagPatterns defines regex patterns to clean ROM filenames
@@ -92,8 +128,8 @@ var romTagPatterns = []string{
 	`\s+\((?:(?:En|Fr|De|Es|It|Ja|Ko|Nl|Pt|Ru|Zh)\s*,?\s*)+\)`,
 	// Revision/Version variants
 	`\s+\((?:Rev\s*[\d\.]+|v[\d\.]+|Version\s*[\d\.]+)\)`,
-	// Beta/Proto/Demo/Preview/Sample/Disc/Track
-	`\s+\((?:Beta|Proto|Demo|Preview|Sample|Kiosk|Debug|Disc\s*\d+|Track\s*\d+)\)`,
+	// Beta/Proto/Demo/Preview/Sample/Disc/Disk/Track
+	`\s+\((?:Beta|Proto|Demo|Preview|Sample|Kiosk|Debug|Dis[ck]\s*\d+(?:\s+of\s+\d+)?|Track\s*\d+)\)`,
 	// SGB/Enhancement codes
 	`\s+\((?:SGB\s+Enhanced|SGB|Enhanced)\)`,
 	// GoodTools codes in brackets (including variants like [!], [b], [T+Eng], etc.)
@@ -110,13 +146,25 @@ func init() {
 	romTagRegex = regexp.MustCompile(pattern)
 }
 
+func init() {
+	games.RegisterSource("emulated", func(deps games.SourceDeps) games.GameSource {
+		return &Source{
+			Logger:     deps.Logger,
+			Events:     deps.Events,
+			ArtCache:   filepath.Join(deps.ArtCache, "emulated"),
+			emuService: deps.EmulatorService,
+			appConfig:  deps.Config,
+		}
+	})
+}
+
 // Name returns the source identifier
 func (s *Source) Name() string {
 	return "emulated"
 }
 
 // Init initializes the emulated source
-func (s *Source) Init(config map[string]any) error {
+func (s *Source) Init(ctx context.Context, config map[string]any) error {
 	// Set default base path
 	s.basePath = filepath.Join(os.Getenv("HOME"), ".local", "share", "gentro", "roms")
 
@@ -137,18 +185,36 @@ func (s *Source) Init(config map[string]any) error {
 		return fmt.Errorf("failed to create art cache path: %w", err)
 	}
 
-	// Use default platform configs
-	s.platforms = defaultPlatformConfigs
+	// Built-in platforms plus user overrides from the config file
+	s.setPlatforms(s.loadPlatforms())
 
 	return nil
 }
 
+// currentPlatforms returns the platform configs of the latest scan. The map must not be modified.
+func (s *Source) currentPlatforms() map[string]PlatformConfig {
+	s.platformsMu.RLock()
+	defer s.platformsMu.RUnlock()
+	return s.platforms
+}
+
+// setPlatforms replaces the platform configs
+func (s *Source) setPlatforms(platforms map[string]PlatformConfig) {
+	s.platformsMu.Lock()
+	defer s.platformsMu.Unlock()
+	s.platforms = platforms
+}
+
 // GetInstances returns all discovered ROM instances
 func (s *Source) GetInstances(ctx context.Context) ([]models.GameInstance, error) {
+	// Pick up extensions added since Init
+	platforms := s.loadPlatforms()
+	s.setPlatforms(platforms)
+
 	var instances []models.GameInstance
 
 	// Walk each platform directory
-	for platform := range s.platforms {
+	for platform, config := range platforms {
 		platformPath := filepath.Join(s.basePath, platform)
 
 		// Skip if directory doesn't exist
@@ -156,56 +222,108 @@ func (s *Source) GetInstances(ctx context.Context) ([]models.GameInstance, error
 			continue
 		}
 
-		// Walk the platform directory recursively
-		err := filepath.Walk(platformPath, func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
+		// Walk the platform directory, down to its configured depth
+		found, err := s.scanDir(ctx, platformPath, platform, config, 1, nil)
+		instances = append(instances, found...)
 
-			// Skip directories
-			if info.IsDir() {
-				return nil
-			}
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan platform %s: %w", platform, err)
+		}
+	}
 
-			// Check if this is a ROM file
-			if !s.isROMFile(path, platform) {
-				return nil
-			}
+	return instances, nil
+}
+
+// scanDir collects the ROMs in dir, which is depth levels below the platform
+// root counting the root as 1, recursing until the platform's MaxDepth. BIOS
+// images found in a directory are attached to every game in and below it.
+func (s *Source) scanDir(ctx context.Context, dir, platform string, config PlatformConfig, depth int, bios []models.InstanceFile) ([]models.GameInstance, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	maxDepth := config.MaxDepth
+	var roms []romFile
+	var subdirs []string
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
 
-			// Create instance
-			instance, err := s.createInstance(path, info, platform)
-			if err != nil {
-				return err
+		if entry.IsDir() {
+			if maxDepth == 0 || depth < maxDepth {
+				subdirs = append(subdirs, path)
 			}
+			continue
+		}
 
-			instances = append(instances, instance)
-			return nil
-		})
+		// Check if this is a ROM file
+		if !config.isROMFile(path) {
+			continue
+		}
 
+		info, err := entry.Info()
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan platform %s: %w", platform, err)
+			return nil, err
+		}
+		roms = append(roms, romFile{path: path, info: info})
+	}
+
+	bundles, dirBIOS := bundleROMs(roms, config)
+	bios = append(slices.Clone(bios), dirBIOS...)
+
+	var instances []models.GameInstance
+	for _, bundle := range bundles {
+		instance, err := s.createInstance(bundle.primary.path, bundle.primary.info, platform, config)
+		if err != nil {
+			return nil, err
+		}
+		if files := append(bundle.files, bios...); len(files) > 0 {
+			instance.Files = files
 		}
+		instances = append(instances, instance)
+	}
+
+	for _, subdir := range subdirs {
+		found, err := s.scanDir(ctx, subdir, platform, config, depth+1, bios)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, found...)
 	}
 
 	return instances, nil
 }
 
-// Refresh rescans the ROM directories and refreshes emulator availability cache
+// Capabilities reports that ROMs are launched through configured emulators,
+// get art from metadata resolvers, and can be added from any path
+func (s *Source) Capabilities() models.SourceCapabilities {
+	return models.SourceCapabilities{
+		CanLaunch:         true,
+		CanScanArt:        true,
+		SupportsManualAdd: true,
+		RequiresEmulator:  true,
+	}
+}
+
+// Refresh is a no-op; ROM directories are scanned by GetInstances
 func (s *Source) Refresh(ctx context.Context) error {
-	s.populateEmulatorAvailabilityCache()
 	return nil
 }
 
 // GetGameArt returns art data for a game
-func (s *Source) GetGameArt(ctx context.Context, instanceID string, artType string) ([]byte, string, error) {
+func (s *Source) GetGameArt(ctx context.Context, instance models.GameInstance, artType string) ([]byte, string, error) {
 	// Look for cached art file
-	artPath := filepath.Join(s.ArtCache, instanceID, artType+".png")
+	artPath := s.artPath(instance, artType)
 
 	// Check if art exists
 	data, err := os.ReadFile(artPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil, "", fmt.Errorf("art not found: %s/%s", instanceID, artType)
+			return nil, "", fmt.Errorf("%w: %s/%s", models.ErrArtNotFound, instance.ID, artType)
 		}
 		return nil, "", fmt.Errorf("failed to read art: %w", err)
 	}
@@ -213,14 +331,26 @@ func (s *Source) GetGameArt(ctx context.Context, instanceID string, artType stri
 	return data, "image/png", nil
 }
 
-// isROMFile checks if a file is a ROM for the given platform
-func (s *Source) isROMFile(path string, platform string) bool {
-	ext := strings.ToLower(filepath.Ext(path))
-	config, ok := s.platforms[platform]
-	if !ok {
-		return false
+// GameArtPath returns the cached art file for streaming
+func (s *Source) GameArtPath(ctx context.Context, instance models.GameInstance, artType string) (string, error) {
+	artPath := s.artPath(instance, artType)
+	if _, err := os.Stat(artPath); err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%w: %s/%s", models.ErrArtNotFound, instance.ID, artType)
+		}
+		return "", fmt.Errorf("failed to stat art: %w", err)
 	}
+	return artPath, nil
+}
 
+// artPath is where an instance's art of the given type is cached
+func (s *Source) artPath(instance models.GameInstance, artType string) string {
+	return filepath.Join(s.ArtCache, instance.ID, artType+".png")
+}
+
+// isROMFile checks if a file has one of the platform's ROM extensions
+func (config PlatformConfig) isROMFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
 	for _, validExt := range config.Extensions {
 		if ext == strings.ToLower(validExt) {
 			return true
@@ -229,80 +359,55 @@ func (s *Source) isROMFile(path string, platform string) bool {
 	return false
 }
 
-// createInstance creates a GameInstance from a ROM file
-func (s *Source) createInstance(path string, info os.FileInfo, platform string) (models.GameInstance, error) {
+// createInstance creates a GameInstance from a ROM file of the given platform
+func (s *Source) createInstance(path string, info os.FileInfo, platform string, config PlatformConfig) (models.GameInstance, error) {
 	// Calculate file hash (first 1MB)
 	hash, err := hashFirstMB(path)
 	if err != nil {
 		return models.GameInstance{}, fmt.Errorf("failed to hash file: %w", err)
 	}
 
-	// Generate instance ID from file hash
-	instanceID := generateInstanceID(hash)
+	// Instances are identified by path, so copies of a ROM stay separate
+	// instances; a moved file is relinked to its old instance by its hash
+	instanceID := generateInstanceID(hashString(path))
+	sourceID := hash
 
 	// Parse game name from filename
 	gameName := parseGameName(info.Name())
 
+	// A romset is identified by its name, which the emulator is launched with;
+	// its zip is rebuilt whenever the set is updated, so the hash isn't stable
+	if config.Romsets {
+		sourceID = romsetName(info.Name())
+		instanceID = generateInstanceID(hashString(platform + "/" + sourceID))
+		gameName = sourceID
+	}
+
 	// Generate game ID from name and platform
 	gameID := generateGameID(gameName, platform)
 
-	// Check emulator availability from cache or compute on-demand
-	hasEmulator := s.getEmulatorAvailabilityForPlatform(platform)
-
 	return models.GameInstance{
 		ID:          instanceID,
 		GameID:      gameID,
 		Source:      "emulated",
 		Platform:    platform,
-		SourceID:    hash,
+		SourceID:    sourceID,
 		Path:        path,
 		Filename:    info.Name(),
 		FileSize:    info.Size(),
 		FileHash:    hash,
 		Installed:   true,
 		InstallPath: path,
-		CustomMetadata: map[string]any{
-			"emulator.available": hasEmulator,
-		},
 		SourceData: map[string]any{
 			"displayName": gameName,
+			"region":      parseRegion(info.Name()),
 		},
 	}, nil
 }
 
-// getEmulatorAvailabilityForPlatform returns emulator availability from cache or checks on-demand
-func (s *Source) getEmulatorAvailabilityForPlatform(platform string) bool {
-	// Return cached value if available
-	if s.emulatorAvailabilityCache != nil {
-		if available, ok := s.emulatorAvailabilityCache[platform]; ok {
-			if s.Logger != nil {
-				s.Logger.Debug("emulator availability from cache",
-					"platform", platform,
-					"available", available,
-				)
-			}
-			return available
-		}
-	}
-
-	// Check on-demand if not in cache
-	if s.emuService != nil {
-		pairs, err := s.emuService.GetAvailableEmulatorsForPlatform(platform)
-		available := err == nil && len(pairs) > 0
-		if s.Logger != nil {
-			s.Logger.Debug("emulator availability on-demand check",
-				"platform", platform,
-				"available", available,
-				"error", err,
-				"pairsCount", len(pairs),
-			)
-		}
-		if available {
-			return true
-		}
-	}
-
-	return false
+// HashFile returns the hash recorded as an instance's FileHash
+func (s *Source) HashFile(path string) (string, error) {
+	return hashFirstMB(path)
 }
 
 // hashFirstMB calculates SHA256 hash of the first 1MB of a file
@@ -326,16 +431,21 @@ func hashFirstMB(path string) (string, error) {
 	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
-// generateInstanceID creates a UUID from file hash
-func generateInstanceID(fileHash string) string {
-	// Use the file hash directly as ID
-	return fmt.Sprintf("file_%s", fileHash[:16])
+// hashString returns the hex SHA256 of s
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateInstanceID creates an instance ID from a hex hash
+func generateInstanceID(hash string) string {
+	return fmt.Sprintf("file_%s", hash[:16])
 }
 
 // generateGameID creates a UUID from game name and platform
 func generateGameID(name string, platform string) string {
 	// Simple hash for now - could use proper UUID generation
-	return fmt.Sprintf("game_%s_%s", platform, sanitizeString(name))
+	return fmt.Sprintf("game_%s_%s", sanitizeString(platform), sanitizeString(name))
 }
 
 // parseGameName extracts a clean game name from filename for display and IGDB search
@@ -356,59 +466,88 @@ func parseGameName(filename string) string {
 	return name
 }
 
-// sanitizeString makes a string safe for use in IDs
-func sanitizeString(s string) string {
-	// Replace spaces and special chars
-	s = strings.ToLower(s)
-	s = strings.ReplaceAll(s, " ", "_")
-	s = strings.ReplaceAll(s, "/", "_")
-	s = strings.ReplaceAll(s, "\\", "_")
-	return s
-}
-
-// SetEmulatorService injects the emulator service and populates availability cache
-func (s *Source) SetEmulatorService(svc *emulator.Service) {
-	s.emuService = svc
-	s.populateEmulatorAvailabilityCache()
+// regionTagRegex matches the region tag in a ROM filename, e.g. "(USA)" or "(USA, Europe)"
+var regionTagRegex = regexp.MustCompile(`\(((?:[A-Za-z ]+)(?:\s*,\s*[A-Za-z ]+)*)\)`)
+
+// regionCodes maps ROM filename region names to the region codes used in
+// models.FetchRequest
+var regionCodes = map[string]string{
+	"usa":            "us",
+	"us":             "us",
+	"u":              "us",
+	"canada":         "us",
+	"europe":         "eu",
+	"eur":            "eu",
+	"eu":             "eu",
+	"e":              "eu",
+	"uk":             "eu",
+	"united kingdom": "eu",
+	"france":         "eu",
+	"germany":        "eu",
+	"italy":          "eu",
+	"spain":          "eu",
+	"netherlands":    "eu",
+	"sweden":         "eu",
+	"japan":          "jp",
+	"jpn":            "jp",
+	"jp":             "jp",
+	"j":              "jp",
+	"world":          "world",
+	"asia":           "asia",
+	"hong kong":      "asia",
+	"taiwan":         "asia",
+	"korea":          "kr",
+	"china":          "cn",
+	"brazil":         "br",
+	"australia":      "au",
 }
 
-// populateEmulatorAvailabilityCache pre-computes emulator availability for all platforms
-func (s *Source) populateEmulatorAvailabilityCache() {
-	if s.emuService == nil {
-		if s.Logger != nil {
-			s.Logger.Warn("emuService is nil, skipping cache population")
+// parseRegion returns the region code of the first region named in a ROM
+// filename's tags, or "" when there is none. "(USA, Europe)" is "us".
+func parseRegion(filename string) string {
+	for _, match := range regionTagRegex.FindAllStringSubmatch(filename, -1) {
+		first, _, _ := strings.Cut(match[1], ",")
+		if code, ok := regionCodes[strings.ToLower(strings.TrimSpace(first))]; ok {
+			return code
 		}
-		return
 	}
+	return ""
+}
 
-	if s.emulatorAvailabilityCache == nil {
-		s.emulatorAvailabilityCache = make(map[string]bool)
-	}
+// maxIDSegmentBytes bounds a sanitized ID segment, well under filesystem name limits
+const maxIDSegmentBytes = 100
 
-	if s.Logger != nil {
-		s.Logger.Debug("Populating emulator availability cache")
+// sanitizeString makes a string safe for use in IDs and as a directory name.
+// Letters and digits are kept, lowercased; runs of anything else become one '_'.
+// Names with nothing usable get a stable hash so the ID is still deterministic.
+func sanitizeString(s string) string {
+	var b strings.Builder
+	pendingSep := false
+	for _, r := range strings.ToLower(s) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			pendingSep = b.Len() > 0
+			continue
+		}
+		if pendingSep {
+			b.WriteByte('_')
+			pendingSep = false
+		}
+		// Stop at a rune boundary so multi-byte characters are never split
+		if b.Len()+utf8.RuneLen(r) > maxIDSegmentBytes {
+			break
+		}
+		b.WriteRune(r)
 	}
 
-	for platform := range s.platforms {
-		pairs, err := s.emuService.GetAvailableEmulatorsForPlatform(platform)
-		platformHasEmu := err == nil && len(pairs) > 0
-		s.emulatorAvailabilityCache[platform] = platformHasEmu
-
-		if s.Logger != nil {
-			if platformHasEmu {
-				s.Logger.Info("found emulator for platform",
-					"platform", platform,
-					"pairsCount", len(pairs),
-					"firstEmulator", pairs[0].Emulator.ID,
-				)
-			} else {
-				s.Logger.Warn("no emulator available for platform",
-					"platform", platform,
-					"error", err,
-				)
-			}
-		}
+	if b.Len() == 0 {
+		return "x" + hashString(s)[:12]
 	}
+	return b.String()
+}
+
+// SetEmulatorService injects the emulator service
+func (s *Source) SetEmulatorService(svc *emulator.Service) {
+	s.emuService = svc
 }
 
 // Launch initiates the game using the configured emulator
@@ -420,15 +559,15 @@ func (s *Source) Launch(ctx context.Context, instance models.GameInstance) (*exe
 	// Resolve emulator (platform default or instance override)
 	emu, core, err := s.emuService.ResolveEmulator(instance)
 	if err != nil {
-		return nil, fmt.Errorf("no emulator available for %s: %w", instance.Platform, err)
+		return nil, fmt.Errorf("%w: no emulator available for %s: %w", models.ErrEmulatorNotConfigured, instance.Platform, err)
 	}
 
 	if emu == nil {
-		return nil, fmt.Errorf("no emulator configured for platform %s", instance.Platform)
+		return nil, fmt.Errorf("%w for platform %s", models.ErrEmulatorNotConfigured, instance.Platform)
 	}
 
 	if !emu.IsAvailable {
-		return nil, fmt.Errorf("emulator %s is not installed", emu.DisplayName)
+		return nil, fmt.Errorf("%w: %s", models.ErrEmulatorNotInstalled, emu.DisplayName)
 	}
 
 	// Log resolved emulator
@@ -449,13 +588,9 @@ func (s *Source) Launch(ctx context.Context, instance models.GameInstance) (*exe
 
 	// Get instance-specific settings
 	settings, _ := s.emuService.GetInstanceEmulatorSettings(instance.ID)
-	customArgs := ""
-	if settings != nil {
-		customArgs = settings.CustomArgs
-	}
 
 	// Build command
-	cmd, err := s.emuService.BuildCommand(emu, core, instance.Path, customArgs)
+	cmd, err := s.emuService.BuildCommand(emu, core, instance.Path, launchFiles(instance), settings)
 	if err != nil {
 		if s.Logger != nil {
 			s.Logger.Error("failed to build emulator command",
@@ -490,14 +625,9 @@ func (s *Source) Launch(ctx context.Context, instance models.GameInstance) (*exe
 		)
 	}
 
-	// Execute
+	// Execute, treating an exit within the grace period as a failed start
 	execCmd := exec.Command(cmd[0], cmd[1:]...)
-
-	// Capture stderr for error reporting
-	var stderrBuf strings.Builder
-	execCmd.Stderr = &stderrBuf
-
-	err = execCmd.Start()
+	exited, err := startProcess(execCmd, s.launchGrace())
 	if err != nil {
 		if s.Logger != nil {
 			s.Logger.Error("failed to start emulator",
@@ -507,27 +637,9 @@ func (s *Source) Launch(ctx context.Context, instance models.GameInstance) (*exe
 				"command", cmdStr,
 			)
 		}
-		return nil, fmt.Errorf("failed to start emulator: %w", err)
-	}
-
-	// Wait a moment and check if process is still running
-	time.Sleep(500 * time.Millisecond)
-	if execCmd.Process == nil {
-		return nil, fmt.Errorf("emulator process failed to start")
-	}
-
-	// Check if process has already exited
-	if err := execCmd.Process.Signal(syscall.Signal(0)); err != nil {
-		stderr := stderrBuf.String()
-		if s.Logger != nil {
-			s.Logger.Error("emulator process exited immediately",
-				"instanceId", instance.ID,
-				"emulator", emu.DisplayName,
-				"error", stderr,
-			)
-		}
-		return nil, fmt.Errorf("emulator failed to start: %s", stderr)
+		return nil, err
 	}
+	s.exits.track(execCmd, exited)
 
 	if s.Logger != nil {
 		s.Logger.Info("emulator started successfully",
@@ -545,22 +657,24 @@ func (s *Source) Launch(ctx context.Context, instance models.GameInstance) (*exe
 func (s *Source) MonitorProcess(ctx context.Context, instance models.GameInstance, cmd *exec.Cmd) {
 	// Spawn goroutine that blocks on Wait()
 	go func() {
-		emit := events.NewEvents(s.Logger)
-
 		s.Logger.Info("starting process monitor",
 			"instanceId", instance.ID,
 			"pid", cmd.Process.Pid,
 		)
 
 		// Emit running immediately - we know process started successfully
-		emit.EmitGameInstanceRunning(instance)
+		s.Events.EmitGameInstanceRunning(instance)
 
 		// Wait for process to exit (blocking)
-		err := cmd.Wait()
+		err := s.exits.wait(cmd)
+		exitCode, crashed := exitStatus(err)
 
-		if err != nil {
+		errMsg := ""
+		if crashed {
+			errMsg = err.Error()
 			s.Logger.Error("emulator process exited with error",
 				"instanceId", instance.ID,
+				"exitCode", exitCode,
 				"error", err,
 			)
 		} else {
@@ -570,7 +684,7 @@ func (s *Source) MonitorProcess(ctx context.Context, instance models.GameInstanc
 		}
 
 		// Emit stopped immediately when Wait() returns
-		emit.EmitGameInstanceStopped(instance)
+		s.Events.EmitGameInstanceExited(instance, exitCode, crashed, errMsg)
 	}()
 }
 
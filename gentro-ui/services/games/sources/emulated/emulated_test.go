@@ -0,0 +1,260 @@
+package emulated
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/config"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestParseRegion(t *testing.T) {
+	tests := map[string]string{
+		"Super Mario Bros. (USA).nes":                "us",
+		"Sonic the Hedgehog (USA, Europe).md":        "us",
+		"Mother (Japan).nes":                         "jp",
+		"Tetris (World) (Rev 1).gb":                  "world",
+		"Zelda (En,Fr,De) (Europe).sfc":              "eu",
+		"Street Fighter II (Beta) [!].sfc":           "",
+		"homebrew.nes":                               "",
+		"Pokemon Red (U) [S][!].gb":                  "us",
+		"Final Fantasy (Rev 1) (Hong Kong) [T+Eng].": "asia",
+	}
+
+	for filename, want := range tests {
+		if got := parseRegion(filename); got != want {
+			t.Errorf("parseRegion(%q) = %q, want %q", filename, got, want)
+		}
+	}
+}
+
+func TestGetInstances_MaxDepth(t *testing.T) {
+	base := t.TempDir()
+	for _, rel := range []string{"nes/top.nes", "nes/a/one.nes", "nes/a/b/two.nes", "nes/a/b/c/three.nes"} {
+		path := filepath.Join(base, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scan := func(maxDepth int) []string {
+		t.Helper()
+		manager, err := config.NewManager(filepath.Join(t.TempDir(), "gentro.toml"))
+		if err != nil {
+			t.Fatalf("failed to create config manager: %v", err)
+		}
+		if err := manager.SetPlatforms(map[string]config.PlatformConfigOverride{"nes": {MaxDepth: maxDepth}}); err != nil {
+			t.Fatalf("SetPlatforms failed: %v", err)
+		}
+
+		source := &Source{basePath: base, appConfig: manager}
+		instances, err := source.GetInstances(context.Background())
+		if err != nil {
+			t.Fatalf("GetInstances failed: %v", err)
+		}
+		var names []string
+		for _, instance := range instances {
+			names = append(names, instance.Filename)
+		}
+		slices.Sort(names)
+		return names
+	}
+
+	if got := scan(0); !slices.Equal(got, []string{"one.nes", "three.nes", "top.nes", "two.nes"}) {
+		t.Errorf("expected unlimited depth by default, got %v", got)
+	}
+	if got := scan(1); !slices.Equal(got, []string{"top.nes"}) {
+		t.Errorf("expected only the platform folder at depth 1, got %v", got)
+	}
+	if got := scan(2); !slices.Equal(got, []string{"one.nes", "top.nes"}) {
+		t.Errorf("expected one level of subfolders at depth 2, got %v", got)
+	}
+}
+
+func TestGetInstances_Cancelled(t *testing.T) {
+	base := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(base, "nes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	source := &Source{basePath: base}
+	if _, err := source.GetInstances(ctx); err == nil {
+		t.Error("expected a cancelled scan to fail")
+	}
+}
+
+func TestGetInstances_KeepsCopiesApart(t *testing.T) {
+	base := t.TempDir()
+	for _, rel := range []string{"nes/Zelda.nes", "nes/backup/Zelda (Copy).nes"} {
+		path := filepath.Join(base, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("NES\x1a same rom"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	source := &Source{basePath: base}
+	instances, err := source.GetInstances(context.Background())
+	if err != nil {
+		t.Fatalf("GetInstances failed: %v", err)
+	}
+	if len(instances) != 2 {
+		t.Fatalf("expected both copies to be found, got %d instances", len(instances))
+	}
+	if instances[0].ID == instances[1].ID {
+		t.Errorf("expected copies at different paths to get different IDs, both are %s", instances[0].ID)
+	}
+	if instances[0].FileHash == "" || instances[0].FileHash != instances[1].FileHash {
+		t.Errorf("expected copies to share a file hash, got %q and %q", instances[0].FileHash, instances[1].FileHash)
+	}
+}
+
+func TestGetInstances_MovedFileKeepsHash(t *testing.T) {
+	base := t.TempDir()
+	oldPath := filepath.Join(base, "nes", "Zelda.nes")
+	newPath := filepath.Join(base, "nes", "zelda", "Zelda.nes")
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(oldPath, []byte("NES\x1a rom"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	source := &Source{basePath: base}
+	scan := func() models.GameInstance {
+		t.Helper()
+		instances, err := source.GetInstances(context.Background())
+		if err != nil {
+			t.Fatalf("GetInstances failed: %v", err)
+		}
+		if len(instances) != 1 {
+			t.Fatalf("expected 1 instance, got %d", len(instances))
+		}
+		return instances[0]
+	}
+
+	before := scan()
+	if err := os.Rename(oldPath, newPath); err != nil {
+		t.Fatal(err)
+	}
+	after := scan()
+
+	// The service relinks the moved file to its old instance by hash
+	if before.ID == after.ID {
+		t.Errorf("expected a moved file to be scanned under a new ID, both are %s", before.ID)
+	}
+	if before.FileHash != after.FileHash || before.SourceID != after.SourceID {
+		t.Errorf("expected a moved file to keep its hash, got %+v and %+v", before, after)
+	}
+}
+
+func TestGetInstances_BundlesFiles(t *testing.T) {
+	base := t.TempDir()
+	for _, rel := range []string{
+		"msx/[BIOS] MSX2 (Japan).rom",
+		"msx/Aleste (Disk 2 of 2).dsk",
+		"msx/Aleste (Disk 1 of 2).dsk",
+		"msx/more/Penguin Adventure.rom",
+		"arcade/sf2.zip",
+		"arcade/sf2ua.zip",
+	} {
+		path := filepath.Join(base, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(rel), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manager, err := config.NewManager(filepath.Join(t.TempDir(), "gentro.toml"))
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	romsets := true
+	if err := manager.SetPlatforms(map[string]config.PlatformConfigOverride{"arcade": {Extensions: []string{".zip"}, Romsets: &romsets}}); err != nil {
+		t.Fatalf("SetPlatforms failed: %v", err)
+	}
+
+	source := &Source{basePath: base, appConfig: manager}
+	instances, err := source.GetInstances(context.Background())
+	if err != nil {
+		t.Fatalf("GetInstances failed: %v", err)
+	}
+
+	bios := models.InstanceFile{Path: filepath.Join(base, "msx/[BIOS] MSX2 (Japan).rom"), Role: models.FileRoleBIOS}
+	want := map[string][]models.InstanceFile{
+		"Aleste (Disk 1 of 2).dsk": {
+			{Path: filepath.Join(base, "msx/Aleste (Disk 2 of 2).dsk"), Role: models.FileRoleDisk},
+			bios,
+		},
+		"Penguin Adventure.rom": {bios},
+		"sf2.zip":               nil,
+		"sf2ua.zip":             {{Path: filepath.Join(base, "arcade/sf2.zip"), Role: models.FileRoleParent}},
+	}
+	if len(instances) != len(want) {
+		t.Fatalf("expected %d instances, got %d: %+v", len(want), len(instances), instances)
+	}
+	for _, instance := range instances {
+		files, ok := want[instance.Filename]
+		if !ok {
+			t.Errorf("unexpected instance %q", instance.Filename)
+			continue
+		}
+		if !slices.Equal(instance.Files, files) {
+			t.Errorf("%s: expected files %v, got %v", instance.Filename, files, instance.Files)
+		}
+	}
+
+	for _, instance := range instances {
+		if instance.Filename == "Aleste (Disk 1 of 2).dsk" && instance.SourceData["displayName"] != "Aleste" {
+			t.Errorf("expected the disk tag to be dropped from the name, got %v", instance.SourceData["displayName"])
+		}
+	}
+}
+
+func TestCreateInstance_Romset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sf2.zip")
+	source := &Source{platforms: mergePlatformConfigs(defaultPlatformConfigs, nil)}
+
+	create := func(content string) models.GameInstance {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		instance, err := source.createInstance(path, info, "arcade", source.currentPlatforms()["arcade"])
+		if err != nil {
+			t.Fatalf("createInstance failed: %v", err)
+		}
+		return instance
+	}
+
+	first := create("romset v1")
+	if first.SourceID != "sf2" || first.Path != path {
+		t.Errorf("expected the romset name as source ID and the zip as path, got %+v", first)
+	}
+
+	// A rebuilt zip is still the same romset
+	second := create("romset v2")
+	if second.ID != first.ID || second.GameID != first.GameID {
+		t.Errorf("expected a stable identity across zip rebuilds, got %s and %s", first.ID, second.ID)
+	}
+	if second.FileHash == first.FileHash {
+		t.Error("expected the file hash to follow the content")
+	}
+}
@@ -0,0 +1,95 @@
+package emulated
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// maxPlatformDirDepth is how many parent directories are checked for a platform name
+const maxPlatformDirDepth = 3
+
+// DetectPlatform picks the platform for a ROM from its extension. Extensions shared
+// by several platforms (e.g. .iso) are resolved by a parent directory named after one.
+func (s *Source) DetectPlatform(path string) (string, error) {
+	return detectPlatform(s.currentPlatforms(), path)
+}
+
+// detectPlatform implements DetectPlatform against one snapshot of the platform configs
+func detectPlatform(platforms map[string]PlatformConfig, path string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == "" {
+		return "", fmt.Errorf("cannot detect platform for %s: file has no extension", filepath.Base(path))
+	}
+
+	var candidates []string
+	for _, platform := range slices.Sorted(maps.Keys(platforms)) {
+		if platforms[platform].isROMFile(path) {
+			candidates = append(candidates, platform)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("unsupported ROM extension %s", ext)
+	case 1:
+		return candidates[0], nil
+	}
+
+	dir := filepath.Dir(path)
+	for i := 0; i < maxPlatformDirDepth; i++ {
+		name := strings.ToLower(filepath.Base(dir))
+		if slices.Contains(candidates, name) {
+			return name, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return "", fmt.Errorf("%w: %s could be %s; specify the platform or move the file into a folder named after it",
+		models.ErrAmbiguousPlatform, ext, strings.Join(candidates, ", "))
+}
+
+// AddManual builds an instance for a ROM outside the scanned directories.
+// An empty platform is detected from the file.
+func (s *Source) AddManual(ctx context.Context, path, platform string) (models.GameInstance, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return models.GameInstance{}, fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return models.GameInstance{}, fmt.Errorf("failed to read ROM: %w", err)
+	}
+	if info.IsDir() {
+		return models.GameInstance{}, fmt.Errorf("%s is a directory, not a ROM file", path)
+	}
+
+	platforms := s.currentPlatforms()
+	if platform == "" {
+		platform, err = detectPlatform(platforms, path)
+		if err != nil {
+			return models.GameInstance{}, err
+		}
+	}
+	config, ok := platforms[platform]
+	if !ok || !config.isROMFile(path) {
+		return models.GameInstance{}, fmt.Errorf("%s is not a recognized ROM extension for %s", filepath.Ext(path), platform)
+	}
+
+	if err := s.checkROMHeader(path, platform); err != nil {
+		return models.GameInstance{}, err
+	}
+
+	return s.createInstance(path, info, platform, config)
+}
@@ -0,0 +1,161 @@
+package emulated
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/rhythmerc/gentro-ui/services/config"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestDetectPlatform(t *testing.T) {
+	source := &Source{platforms: defaultPlatformConfigs}
+
+	tests := []struct {
+		path      string
+		want      string
+		ambiguous bool
+		wantErr   bool
+	}{
+		{path: "/games/Super Mario 64.z64", want: "n64"},
+		{path: "/games/Chrono Trigger.SFC", want: "snes"},
+		{path: "/roms/ps2/Okami.iso", want: "ps2"},
+		{path: "/roms/gamecube/disc1/Metroid Prime.iso", want: "gamecube"},
+		{path: "/downloads/Okami.iso", ambiguous: true},
+		{path: "/downloads/readme.txt", wantErr: true},
+		{path: "/downloads/noext", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(filepath.Base(tt.path), func(t *testing.T) {
+			got, err := source.DetectPlatform(tt.path)
+			switch {
+			case tt.ambiguous:
+				if !errors.Is(err, models.ErrAmbiguousPlatform) {
+					t.Errorf("expected ErrAmbiguousPlatform, got %q, %v", got, err)
+				}
+			case tt.wantErr:
+				if err == nil || errors.Is(err, models.ErrAmbiguousPlatform) {
+					t.Errorf("expected unsupported extension error, got %q, %v", got, err)
+				}
+			case err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case got != tt.want:
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDetectPlatform_ConcurrentWithScan(t *testing.T) {
+	base := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(base, "n64"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(base, "n64", "Mario.z64"), []byte("rom"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	source := &Source{basePath: base}
+
+	// Run with -race: scans replace the platform configs while detection reads them
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			if _, err := source.GetInstances(context.Background()); err != nil {
+				t.Errorf("GetInstances failed: %v", err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		source.DetectPlatform("/games/Super Mario 64.z64")
+	}
+	<-done
+}
+
+func TestCheckROMHeader(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("failed to write ROM: %v", err)
+		}
+		return path
+	}
+
+	validNES := write("valid.nes", append([]byte("NES\x1a"), make([]byte, 12)...))
+	corruptNES := write("corrupt.nes", []byte("<html>not found</html>"))
+	shortISO := write("short.iso", []byte("tiny"))
+	unchecked := write("game.sfc", []byte("anything"))
+
+	if err := validateROMHeader(validNES, "nes"); err != nil {
+		t.Errorf("expected valid iNES header to pass, got %v", err)
+	}
+	if err := validateROMHeader(corruptNES, "nes"); !errors.Is(err, ErrInvalidROMHeader) {
+		t.Errorf("expected ErrInvalidROMHeader, got %v", err)
+	}
+	if err := validateROMHeader(shortISO, "ps2"); !errors.Is(err, ErrInvalidROMHeader) {
+		t.Errorf("expected truncated ISO to fail, got %v", err)
+	}
+	if err := validateROMHeader(unchecked, "snes"); err != nil {
+		t.Errorf("expected formats without a signature to pass, got %v", err)
+	}
+
+	manager, err := config.NewManager(filepath.Join(t.TempDir(), "gentro.toml"))
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	source := &Source{appConfig: manager}
+
+	for mode, wantErr := range map[string]bool{
+		config.HeaderValidationOff:    false,
+		config.HeaderValidationWarn:   false,
+		config.HeaderValidationStrict: true,
+	} {
+		if err := manager.SetEmulated(config.EmulatedConfig{HeaderValidation: mode}); err != nil {
+			t.Fatalf("SetEmulated failed: %v", err)
+		}
+		if err := source.checkROMHeader(corruptNES, "nes"); (err != nil) != wantErr {
+			t.Errorf("mode %s: expected error %v, got %v", mode, wantErr, err)
+		}
+	}
+}
+
+func TestSanitizeString(t *testing.T) {
+	tests := map[string]string{
+		"Super Mario World":                  "super_mario_world",
+		"Zelda: A Link to the Past":          "zelda_a_link_to_the_past",
+		"../../etc/passwd":                   "etc_passwd",
+		".hidden":                            "hidden",
+		"nul\x00byte\ttab\nline":             "nul_byte_tab_line",
+		`C:\Games\Doom`:                      "c_games_doom",
+		"ポケットモンスター 赤":                        "ポケットモンスター_赤",
+		"Pokémon Édition Rouge":              "pokémon_édition_rouge",
+		"  --Trailing & leading--  ":         "trailing_leading",
+		"Final Fantasy VI (USA) [Rev 1] !!!": "final_fantasy_vi_usa_rev_1",
+	}
+	for input, want := range tests {
+		if got := sanitizeString(input); got != want {
+			t.Errorf("sanitizeString(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	// Names without letters or digits still get a stable, safe ID
+	if got := sanitizeString("!!!"); got != sanitizeString("!!!") || got == "" || strings.ContainsAny(got, "!/.") {
+		t.Errorf("expected stable hashed ID, got %q", got)
+	}
+	if sanitizeString("!!!") == sanitizeString("???") {
+		t.Error("expected different punctuation-only names to get different IDs")
+	}
+
+	long := sanitizeString(strings.Repeat("あ", 200))
+	if len(long) > maxIDSegmentBytes || !utf8.ValidString(long) {
+		t.Errorf("expected truncation at a rune boundary, got %d bytes", len(long))
+	}
+}
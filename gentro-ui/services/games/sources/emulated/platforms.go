@@ -0,0 +1,57 @@
+package emulated
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/rhythmerc/gentro-ui/services/config"
+)
+
+// mergePlatformConfigs applies user overrides on top of the built-in platforms.
+// The result shares no slices with either input.
+func mergePlatformConfigs(defaults map[string]PlatformConfig, overrides map[string]config.PlatformConfigOverride) map[string]PlatformConfig {
+	merged := make(map[string]PlatformConfig, len(defaults)+len(overrides))
+	for platform, cfg := range defaults {
+		cfg.Extensions = slices.Clone(cfg.Extensions)
+		cfg.ArtTypes = slices.Clone(cfg.ArtTypes)
+		merged[platform] = cfg
+	}
+
+	for _, platform := range slices.Sorted(maps.Keys(overrides)) {
+		override := overrides[platform]
+		cfg, ok := merged[platform]
+		if !ok {
+			cfg = PlatformConfig{DisplayName: platform}
+		}
+		if override.DisplayName != "" {
+			cfg.DisplayName = override.DisplayName
+		}
+		if override.MaxDepth > 0 {
+			cfg.MaxDepth = override.MaxDepth
+		}
+		if override.DiskSets != nil {
+			cfg.DiskSets = *override.DiskSets
+		}
+		if override.Romsets != nil {
+			cfg.Romsets = *override.Romsets
+		}
+		for _, ext := range override.Extensions {
+			ext = config.NormalizeExtension(ext)
+			if ext != "" && !slices.Contains(cfg.Extensions, ext) {
+				cfg.Extensions = append(cfg.Extensions, ext)
+			}
+		}
+		merged[platform] = cfg
+	}
+
+	return merged
+}
+
+// loadPlatforms returns the built-in platforms merged with any configured overrides
+func (s *Source) loadPlatforms() map[string]PlatformConfig {
+	var overrides map[string]config.PlatformConfigOverride
+	if s.appConfig != nil {
+		overrides = s.appConfig.Get().Platforms
+	}
+	return mergePlatformConfigs(defaultPlatformConfigs, overrides)
+}
@@ -0,0 +1,40 @@
+package emulated
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/config"
+)
+
+func TestMergePlatformConfigs(t *testing.T) {
+	defaults := map[string]PlatformConfig{
+		"nes": {Extensions: []string{".nes"}, DisplayName: "Nintendo Entertainment System"},
+	}
+	overrides := map[string]config.PlatformConfigOverride{
+		"nes":    {Extensions: []string{"UNF", ".nes"}},
+		"pico8":  {Extensions: []string{".p8"}, DisplayName: "PICO-8"},
+		"nohome": {},
+	}
+
+	merged := mergePlatformConfigs(defaults, overrides)
+
+	nes := merged["nes"]
+	if !slices.Equal(nes.Extensions, []string{".nes", ".unf"}) {
+		t.Errorf("expected normalized, deduplicated extensions, got %v", nes.Extensions)
+	}
+	if nes.DisplayName != "Nintendo Entertainment System" {
+		t.Errorf("expected built-in display name to be kept, got %q", nes.DisplayName)
+	}
+	if merged["pico8"].DisplayName != "PICO-8" || !slices.Equal(merged["pico8"].Extensions, []string{".p8"}) {
+		t.Errorf("expected new platform to be added, got %+v", merged["pico8"])
+	}
+	if merged["nohome"].DisplayName != "nohome" {
+		t.Errorf("expected platform ID as fallback display name, got %q", merged["nohome"].DisplayName)
+	}
+
+	// Defaults must not be modified
+	if len(defaults["nes"].Extensions) != 1 {
+		t.Errorf("expected defaults to be untouched, got %v", defaults["nes"].Extensions)
+	}
+}
@@ -0,0 +1,106 @@
+package emulated
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/config"
+)
+
+// startProcess starts cmd and waits up to grace for it to exit. A process that
+// exits within the grace period failed to start, and its stderr is returned in the
+// error. One still running is reported as started; the returned channel receives
+// its Wait result when it exits, since Wait can only be called once.
+func startProcess(cmd *exec.Cmd, grace time.Duration) (<-chan error, error) {
+	// Capture stderr for error reporting
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start emulator: %w", err)
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+
+	select {
+	case err := <-exited:
+		// Wait has returned, so stderr is fully written
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" && err != nil {
+			msg = err.Error()
+		}
+		if msg == "" {
+			msg = "exited immediately"
+		}
+		return nil, fmt.Errorf("emulator failed to start: %s", msg)
+	case <-timer.C:
+		return exited, nil
+	}
+}
+
+// exitStatus extracts a process's exit code from its Wait error and reports
+// whether it crashed, meaning it exited non-zero or was killed by a signal.
+// Signals are reported shell-style as 128 plus the signal number.
+func exitStatus(err error) (code int, crashed bool) {
+	if err == nil {
+		return 0, false
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return -1, true
+	}
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal()), true
+	}
+	return exitErr.ExitCode(), true
+}
+
+// launchGrace returns how long a launched emulator must stay running to count as started
+func (s *Source) launchGrace() time.Duration {
+	ms := config.DefaultLaunchGraceMs
+	if s.appConfig != nil && s.appConfig.Get().Emulated.LaunchGraceMs > 0 {
+		ms = s.appConfig.Get().Emulated.LaunchGraceMs
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// processExits holds the Wait results of launched processes until MonitorProcess collects them
+type processExits struct {
+	mu     sync.Mutex
+	byProc map[*exec.Cmd]<-chan error
+}
+
+// track records the exit channel of a launched process
+func (p *processExits) track(cmd *exec.Cmd, exited <-chan error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.byProc == nil {
+		p.byProc = make(map[*exec.Cmd]<-chan error)
+	}
+	p.byProc[cmd] = exited
+}
+
+// wait blocks until a process exits and returns its Wait result. Processes not
+// started through startProcess are waited on directly.
+func (p *processExits) wait(cmd *exec.Cmd) error {
+	p.mu.Lock()
+	exited, ok := p.byProc[cmd]
+	delete(p.byProc, cmd)
+	p.mu.Unlock()
+
+	if !ok {
+		return cmd.Wait()
+	}
+	return <-exited
+}
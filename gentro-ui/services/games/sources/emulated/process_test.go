@@ -0,0 +1,96 @@
+package emulated
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/games/events"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestStartProcess_ExitWithinGraceFails(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "echo 'missing bios' >&2; exit 3")
+	if _, err := startProcess(cmd, 2*time.Second); err == nil || !strings.Contains(err.Error(), "missing bios") {
+		t.Errorf("expected a failed start with stderr, got %v", err)
+	}
+}
+
+func TestStartProcess_RunningAfterGraceStarts(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "sleep 0.3")
+	started := time.Now()
+	exited, err := startProcess(cmd, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("expected a started process, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 250*time.Millisecond {
+		t.Errorf("expected to return after the grace period, took %v", elapsed)
+	}
+
+	var exits processExits
+	exits.track(cmd, exited)
+	if err := exits.wait(cmd); err != nil {
+		t.Errorf("expected a clean exit, got %v", err)
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		script      string
+		wantCode    int
+		wantCrashed bool
+	}{
+		{"clean exit", "exit 0", 0, false},
+		{"non-zero exit", "exit 3", 3, true},
+		{"killed by signal", "kill -SEGV $$", 139, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, crashed := exitStatus(exec.Command("sh", "-c", tt.script).Run())
+			if code != tt.wantCode || crashed != tt.wantCrashed {
+				t.Errorf("expected exit %d crashed=%v, got %d crashed=%v", tt.wantCode, tt.wantCrashed, code, crashed)
+			}
+		})
+	}
+}
+
+func TestMonitorProcess_ReportsExitCode(t *testing.T) {
+	updates := make(chan models.LaunchStatusUpdate, 4)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	source := &Source{
+		Logger: logger,
+		Events: events.NewEventsWithSink(logger, func(name string, data any) {
+			if update, ok := data.(models.LaunchStatusUpdate); ok {
+				updates <- update
+			}
+		}),
+	}
+
+	cmd := exec.Command("sh", "-c", "sleep 0.1; exit 42")
+	exited, err := startProcess(cmd, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("startProcess failed: %v", err)
+	}
+	source.exits.track(cmd, exited)
+
+	instance := models.GameInstance{ID: "inst1", GameID: "game1"}
+	source.MonitorProcess(context.Background(), instance, cmd)
+
+	if running := <-updates; running.Status != models.LaunchStatusRunning {
+		t.Fatalf("expected running first, got %+v", running)
+	}
+	select {
+	case stopped := <-updates:
+		if stopped.Status != models.LaunchStatusStopped || stopped.ExitCode != 42 || !stopped.Crashed {
+			t.Errorf("expected a crashed stop with exit 42, got %+v", stopped)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a stopped update")
+	}
+}
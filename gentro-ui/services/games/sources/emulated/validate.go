@@ -0,0 +1,131 @@
+package emulated
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rhythmerc/gentro-ui/services/config"
+)
+
+// ErrInvalidROMHeader is returned when a ROM doesn't start with its platform's header
+var ErrInvalidROMHeader = errors.New("invalid ROM header")
+
+// romSignature is a magic byte sequence expected at an offset in a ROM.
+// Any one of magics matching is enough.
+type romSignature struct {
+	offset int64
+	magics [][]byte
+	desc   string
+}
+
+var (
+	zipSignature    = romSignature{0, [][]byte{[]byte("PK\x03\x04")}, "ZIP archive"}
+	sevenZSignature = romSignature{0, [][]byte{[]byte("7z\xbc\xaf\x27\x1c")}, "7z archive"}
+	iso9660         = romSignature{0x8001, [][]byte{[]byte("CD001")}, "ISO 9660 disc image"}
+)
+
+// romSignatures lists the headers checked per platform and extension. Formats
+// without a reliable header (e.g. SNES, raw .bin) aren't checked.
+var romSignatures = map[string]map[string]romSignature{
+	"nes": {
+		".nes": {0, [][]byte{[]byte("NES\x1a")}, "iNES header"},
+		".zip": zipSignature,
+		".7z":  sevenZSignature,
+	},
+	"snes": {
+		".zip": zipSignature,
+	},
+	"n64": {
+		".z64": {0, [][]byte{{0x80, 0x37, 0x12, 0x40}}, "N64 big-endian header"},
+		".v64": {0, [][]byte{{0x37, 0x80, 0x40, 0x12}}, "N64 byte-swapped header"},
+		".n64": {0, [][]byte{{0x40, 0x12, 0x37, 0x80}}, "N64 little-endian header"},
+	},
+	"genesis": {
+		".md":  {0x100, [][]byte{[]byte("SEGA")}, "SEGA header"},
+		".gen": {0x100, [][]byte{[]byte("SEGA")}, "SEGA header"},
+		".bin": {0x100, [][]byte{[]byte("SEGA")}, "SEGA header"},
+	},
+	"gamecube": {
+		".iso": {0x1c, [][]byte{{0xc2, 0x33, 0x9f, 0x3d}}, "GameCube disc header"},
+	},
+	"wii": {
+		".iso":  {0x18, [][]byte{{0x5d, 0x1c, 0x9e, 0xa3}}, "Wii disc header"},
+		".wbfs": {0, [][]byte{[]byte("WBFS")}, "WBFS header"},
+	},
+	"ps1": {
+		".iso": iso9660,
+	},
+	"ps2": {
+		".iso": iso9660,
+	},
+}
+
+// validateROMHeader checks a ROM against its platform's expected header.
+// Files without a known signature pass.
+func validateROMHeader(path, platform string) error {
+	sig, ok := romSignatures[platform][strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open ROM: %w", err)
+	}
+	defer file.Close()
+
+	size := 0
+	for _, magic := range sig.magics {
+		size = max(size, len(magic))
+	}
+	buf := make([]byte, size)
+	n, err := file.ReadAt(buf, sig.offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to read ROM header: %w", err)
+	}
+
+	for _, magic := range sig.magics {
+		if n >= len(magic) && bytes.Equal(buf[:len(magic)], magic) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s has no %s; the file may be corrupt or not a %s ROM",
+		ErrInvalidROMHeader, filepath.Base(path), sig.desc, platform)
+}
+
+// headerValidationMode returns the configured validation strictness
+func (s *Source) headerValidationMode() string {
+	if s.appConfig == nil {
+		return config.HeaderValidationWarn
+	}
+	mode := s.appConfig.Get().Emulated.HeaderValidation
+	if mode == "" {
+		return config.HeaderValidationWarn
+	}
+	return mode
+}
+
+// checkROMHeader validates a ROM according to the configured strictness.
+// Only strict mode returns an error; warn mode logs and accepts the file.
+func (s *Source) checkROMHeader(path, platform string) error {
+	mode := s.headerValidationMode()
+	if mode == config.HeaderValidationOff {
+		return nil
+	}
+
+	err := validateROMHeader(path, platform)
+	if err == nil || mode == config.HeaderValidationStrict {
+		return err
+	}
+
+	if s.Logger != nil {
+		s.Logger.Warn("ROM header validation failed", "path", path, "platform", platform, "error", err)
+	}
+	return nil
+}
@@ -0,0 +1,364 @@
+package gog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/rhythmerc/gentro-ui/services/games"
+	"github.com/rhythmerc/gentro-ui/services/games/events"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// Source implements GameSource for games installed from GOG
+type Source struct {
+	// basePath is scanned for installs in addition to the default folders
+	basePath string
+	Logger   *slog.Logger
+	Events   *events.Events
+}
+
+// GameInfo is the goggame-<id>.info file GOG installs alongside a game
+type GameInfo struct {
+	GameID     string     `json:"gameId"`
+	RootGameID string     `json:"rootGameId"`
+	Name       string     `json:"name"`
+	PlayTasks  []PlayTask `json:"playTasks"`
+}
+
+// PlayTask is one way of starting a game, such as the game itself or a settings tool
+type PlayTask struct {
+	Name       string `json:"name"`
+	Category   string `json:"category"`
+	IsPrimary  bool   `json:"isPrimary"`
+	Type       string `json:"type"`
+	Path       string `json:"path"`
+	Arguments  string `json:"arguments"`
+	WorkingDir string `json:"workingDir"`
+}
+
+func init() {
+	games.RegisterSource("gog", func(deps games.SourceDeps) games.GameSource {
+		source := &Source{
+			Logger: deps.Logger,
+			Events: deps.Events,
+		}
+		if deps.Config != nil {
+			source.basePath = deps.Config.Get().GOG.BasePath
+		}
+		return source
+	})
+}
+
+// Name returns the source identifier
+func (s *Source) Name() string {
+	return "gog"
+}
+
+// Init initializes the GOG source. A "basePath" in config replaces the
+// configured folder of installs.
+func (s *Source) Init(ctx context.Context, config map[string]any) error {
+	if config != nil {
+		if path, ok := config["basePath"].(string); ok && path != "" {
+			s.basePath = path
+		}
+	}
+	return nil
+}
+
+// installDirs returns the folders GOG games are installed into: the default
+// folder of GOG's Linux installers, Heroic's default folder and the configured one
+func (s *Source) installDirs() []string {
+	home := os.Getenv("HOME")
+	dirs := []string{
+		filepath.Join(home, "GOG Games"),
+		filepath.Join(home, "Games", "Heroic"),
+	}
+	if s.basePath != "" {
+		dirs = append(dirs, s.basePath)
+	}
+	return dirs
+}
+
+// GetInstances returns a game for each GOG install found in the install folders
+func (s *Source) GetInstances(ctx context.Context) ([]models.GameInstance, error) {
+	var instances []models.GameInstance
+	seen := make(map[string]bool)
+	for _, dir := range s.installDirs() {
+		entries, err := os.ReadDir(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GOG folder %s: %w", dir, err)
+		}
+
+		for _, entry := range entries {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if !entry.IsDir() {
+				continue
+			}
+
+			installPath := filepath.Join(dir, entry.Name())
+			info, err := findGameInfo(installPath)
+			if err != nil {
+				s.Logger.Debug("skipping GOG folder", "path", installPath, "error", err)
+				continue
+			}
+			instance := newInstance(installPath, info)
+			if seen[instance.ID] {
+				continue
+			}
+			seen[instance.ID] = true
+			instances = append(instances, instance)
+		}
+	}
+
+	return instances, nil
+}
+
+// newInstance builds the instance for a GOG install
+func newInstance(installPath string, info *GameInfo) models.GameInstance {
+	return models.GameInstance{
+		ID:             "gog_" + info.GameID,
+		GameID:         "gog_" + info.GameID,
+		Source:         "gog",
+		Platform:       "gog",
+		SourceID:       info.GameID,
+		Filename:       filepath.Base(installPath),
+		Installed:      true,
+		InstallPath:    installPath,
+		SourceData:     map[string]any{"displayName": info.Name},
+		CustomMetadata: map[string]any{},
+		UpdatedAt:      time.Now(),
+	}
+}
+
+// infoDirs are where an install keeps its goggame-<id>.info file: the install
+// itself for GOG Galaxy and Heroic, or its game folder for the Linux installers
+var infoDirs = []string{".", "game"}
+
+// findGameInfo reads the .info file of the base game in an install. DLCs ship
+// their own .info files, which name the base game as their root. Files that
+// can't be parsed are skipped, so a broken DLC file doesn't hide the game.
+func findGameInfo(installPath string) (*GameInfo, error) {
+	var parseErr error
+	for _, dir := range infoDirs {
+		matches, err := filepath.Glob(filepath.Join(installPath, dir, "goggame-*.info"))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range matches {
+			info, err := ParseGameInfo(path)
+			if err != nil {
+				parseErr = err
+				continue
+			}
+			if info.RootGameID == "" || info.RootGameID == info.GameID {
+				return info, nil
+			}
+		}
+	}
+	if parseErr != nil {
+		return nil, fmt.Errorf("no readable goggame info file found: %w", parseErr)
+	}
+	return nil, fmt.Errorf("no goggame info file found")
+}
+
+// ParseGameInfo parses a goggame-<id>.info file
+func ParseGameInfo(path string) (*GameInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read game info: %w", err)
+	}
+
+	var info GameInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse game info %s: %w", path, err)
+	}
+	if info.GameID == "" {
+		return nil, fmt.Errorf("no gameId in %s", path)
+	}
+	return &info, nil
+}
+
+// PrimaryTask returns the task that starts the game: the one marked primary,
+// or else the first game file task
+func (info *GameInfo) PrimaryTask() (*PlayTask, bool) {
+	for i, task := range info.PlayTasks {
+		if task.IsPrimary && task.Path != "" {
+			return &info.PlayTasks[i], true
+		}
+	}
+	for i, task := range info.PlayTasks {
+		if task.Type == "FileTask" && task.Category == "game" && task.Path != "" {
+			return &info.PlayTasks[i], true
+		}
+	}
+	return nil, false
+}
+
+// infoFile finds an instance's .info file. The stored instance doesn't keep its
+// source data, so it's looked up from the install path and GOG ID.
+func infoFile(instance models.GameInstance) (string, error) {
+	for _, dir := range infoDirs {
+		path := filepath.Join(instance.InstallPath, dir, "goggame-"+instance.SourceID+".info")
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no game info for %s in %s", instance.SourceID, instance.InstallPath)
+}
+
+// Capabilities reports that GOG games launch directly; installs go through GOG's installers
+func (s *Source) Capabilities() models.SourceCapabilities {
+	return models.SourceCapabilities{
+		CanLaunch:  true,
+		CanScanArt: true,
+	}
+}
+
+// Refresh is a no-op; install folders are scanned by GetInstances
+func (s *Source) Refresh(ctx context.Context) error {
+	return nil
+}
+
+// GetGameArt returns the icon GOG bundles with a game. Other art comes from
+// metadata providers.
+func (s *Source) GetGameArt(ctx context.Context, instance models.GameInstance, artType string) ([]byte, string, error) {
+	if artType != "icon" {
+		return nil, "", fmt.Errorf("%w: %s/%s", models.ErrArtNotFound, instance.ID, artType)
+	}
+
+	infoPath, err := infoFile(instance)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %v", models.ErrArtNotFound, err)
+	}
+	data, err := os.ReadFile(strings.TrimSuffix(infoPath, ".info") + ".png")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", fmt.Errorf("%w: %s/%s", models.ErrArtNotFound, instance.ID, artType)
+		}
+		return nil, "", fmt.Errorf("failed to read icon: %w", err)
+	}
+	return data, "image/png", nil
+}
+
+// Launch runs the game's primary play task from its working directory
+func (s *Source) Launch(ctx context.Context, instance models.GameInstance) (*exec.Cmd, error) {
+	infoPath, err := infoFile(instance)
+	if err != nil {
+		return nil, err
+	}
+	info, err := ParseGameInfo(infoPath)
+	if err != nil {
+		return nil, err
+	}
+	task, ok := info.PrimaryTask()
+	if !ok {
+		return nil, fmt.Errorf("no play task for %s", info.Name)
+	}
+
+	// Task paths are relative to the folder holding the .info file
+	args, err := splitArguments(task.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("invalid arguments for %s: %w", info.Name, err)
+	}
+	infoDir := filepath.Dir(infoPath)
+	cmd := exec.Command(filepath.Join(infoDir, task.Path), args...)
+	cmd.Dir = filepath.Join(infoDir, task.WorkingDir)
+	if task.WorkingDir == "" {
+		cmd.Dir = filepath.Dir(cmd.Path)
+	}
+
+	s.Logger.Info("launching game", "instanceId", instance.ID, "task", task.Name, "path", cmd.Path)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start game: %w", err)
+	}
+	return cmd, nil
+}
+
+// splitArguments splits a play task's argument string the way a shell would:
+// whitespace separates arguments, and single or double quotes group text that
+// contains spaces. A backslash only escapes a quote, so Windows paths pass
+// through unchanged.
+func splitArguments(arguments string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+
+	runes := []rune(arguments)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && quote != '\'' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\''):
+			i++
+			current.WriteRune(runes[i])
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, arguments)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// MonitorProcess waits for the game process to exit and emits status events
+func (s *Source) MonitorProcess(ctx context.Context, instance models.GameInstance, cmd *exec.Cmd) {
+	go func() {
+		s.Events.EmitGameInstanceRunning(instance)
+
+		err := cmd.Wait()
+		exitCode, crashed := 0, false
+		errMsg := ""
+		if err != nil {
+			crashed = true
+			errMsg = err.Error()
+			exitCode = -1
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				exitCode = exitErr.ExitCode()
+			}
+			s.Logger.Error("game process exited with error", "instanceId", instance.ID, "exitCode", exitCode, "error", err)
+		}
+
+		s.Events.EmitGameInstanceExited(instance, exitCode, crashed, errMsg)
+	}()
+}
+
+// FilterInstances applies GOG-specific filters; there are none yet
+func (s *Source) FilterInstances(instances []models.GameInstance, filter models.GameFilter) []models.GameInstance {
+	return instances
+}
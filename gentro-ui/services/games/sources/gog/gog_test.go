@@ -0,0 +1,177 @@
+package gog
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// writeFile creates a file and its parent folders
+func writeFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetInstances(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	base := t.TempDir()
+
+	// A Linux installer keeps the .info file in its game folder
+	linuxInstall := filepath.Join(home, "GOG Games", "Stardew Valley")
+	writeFile(t, filepath.Join(linuxInstall, "game", "goggame-1453375253.info"), `{
+		"gameId": "1453375253", "rootGameId": "1453375253", "name": "Stardew Valley",
+		"playTasks": [{"category": "game", "isPrimary": true, "name": "Stardew Valley", "path": "StardewValley", "type": "FileTask"}]
+	}`, 0644)
+	// A Heroic install keeps it at the top, next to its DLCs'
+	heroicInstall := filepath.Join(base, "Witcher 3")
+	writeFile(t, filepath.Join(heroicInstall, "goggame-1495134320.info"),
+		`{"gameId": "1495134320", "rootGameId": "1207664643", "name": "Hearts of Stone"}`, 0644)
+	writeFile(t, filepath.Join(heroicInstall, "goggame-1207664643.info"),
+		`{"gameId": "1207664643", "rootGameId": "1207664643", "name": "The Witcher 3"}`, 0644)
+	writeFile(t, filepath.Join(base, "Not A Game", "readme.txt"), "", 0644)
+
+	source := &Source{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	if err := source.Init(context.Background(), map[string]any{"basePath": base}); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	instances, err := source.GetInstances(context.Background())
+	if err != nil {
+		t.Fatalf("GetInstances failed: %v", err)
+	}
+
+	got := make(map[string]models.GameInstance)
+	for _, instance := range instances {
+		got[instance.ID] = instance
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 instances, got %+v", instances)
+	}
+	stardew := got["gog_1453375253"]
+	if stardew.Source != "gog" || stardew.SourceID != "1453375253" || stardew.InstallPath != linuxInstall ||
+		stardew.SourceData["displayName"] != "Stardew Valley" {
+		t.Errorf("unexpected instance: %+v", stardew)
+	}
+	if witcher := got["gog_1207664643"]; witcher.SourceData["displayName"] != "The Witcher 3" {
+		t.Errorf("expected the base game rather than its DLC, got %+v", witcher)
+	}
+}
+
+func TestPrimaryTask(t *testing.T) {
+	info := &GameInfo{PlayTasks: []PlayTask{
+		{Name: "Manual", Category: "document", Type: "URLTask", Path: "https://example.com"},
+		{Name: "Game", Category: "game", Type: "FileTask", Path: "bin/game"},
+		{Name: "Launcher", Category: "launcher", Type: "FileTask", Path: "launcher"},
+	}}
+	if task, ok := info.PrimaryTask(); !ok || task.Name != "Game" {
+		t.Errorf("expected the game task without a primary one, got %+v", task)
+	}
+
+	info.PlayTasks[2].IsPrimary = true
+	if task, ok := info.PrimaryTask(); !ok || task.Name != "Launcher" {
+		t.Errorf("expected the primary task, got %+v", task)
+	}
+
+	if _, ok := (&GameInfo{}).PrimaryTask(); ok {
+		t.Error("expected no task without play tasks")
+	}
+}
+
+func TestLaunchAndIcon(t *testing.T) {
+	install := t.TempDir()
+	writeFile(t, filepath.Join(install, "game", "goggame-1.info"), `{
+		"gameId": "1", "name": "Game",
+		"playTasks": [{"isPrimary": true, "path": "bin/start.sh", "arguments": "--windowed \"Save Slot 1\"", "workingDir": "data"}]
+	}`, 0644)
+	writeFile(t, filepath.Join(install, "game", "bin", "start.sh"), "#!/bin/sh\npwd > launched.txt\necho \"$1\" >> launched.txt\necho \"$2\" >> launched.txt\n", 0755)
+	writeFile(t, filepath.Join(install, "game", "goggame-1.png"), "icon", 0644)
+	if err := os.MkdirAll(filepath.Join(install, "game", "data"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	source := &Source{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	instance := models.GameInstance{ID: "gog_1", SourceID: "1", InstallPath: install}
+
+	cmd, err := source.Launch(context.Background(), instance)
+	if err != nil {
+		t.Fatalf("Launch failed: %v", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("game exited with error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(install, "game", "data", "launched.txt"))
+	if err != nil {
+		t.Fatalf("expected the task to run in its working directory: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 3 || lines[1] != "--windowed" || lines[2] != "Save Slot 1" {
+		t.Errorf("expected the task's arguments to be passed, got %q", data)
+	}
+
+	icon, contentType, err := source.GetGameArt(context.Background(), instance, "icon")
+	if err != nil || string(icon) != "icon" || contentType != "image/png" {
+		t.Errorf("expected the bundled icon, got %q %q (%v)", icon, contentType, err)
+	}
+	if _, _, err := source.GetGameArt(context.Background(), instance, "hero"); !errors.Is(err, models.ErrArtNotFound) {
+		t.Errorf("expected ErrArtNotFound for other art, got %v", err)
+	}
+}
+
+func TestSplitArguments(t *testing.T) {
+	tests := []struct {
+		arguments string
+		want      []string
+	}{
+		{"", nil},
+		{"  --windowed   -w 1280 ", []string{"--windowed", "-w", "1280"}},
+		{`-config "C:\Games\My Game\settings.cfg"`, []string{"-config", `C:\Games\My Game\settings.cfg`}},
+		{`--name='Player One' -x`, []string{"--name=Player One", "-x"}},
+		{`say "a \"quoted\" word" ""`, []string{"say", `a "quoted" word`, ""}},
+	}
+	for _, tt := range tests {
+		got, err := splitArguments(tt.arguments)
+		if err != nil {
+			t.Errorf("splitArguments(%q) failed: %v", tt.arguments, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("splitArguments(%q): expected %q, got %q", tt.arguments, tt.want, got)
+		}
+	}
+
+	if _, err := splitArguments(`-config "unterminated`); err == nil {
+		t.Error("expected an unterminated quote to be rejected")
+	}
+}
+
+func TestFindGameInfo_SkipsMalformed(t *testing.T) {
+	install := t.TempDir()
+	writeFile(t, filepath.Join(install, "goggame-1.info"), `{"gameId": "1", "rootGameId": "2"`, 0644)
+	writeFile(t, filepath.Join(install, "goggame-2.info"), `{"gameId": "2", "rootGameId": "2", "name": "Base Game"}`, 0644)
+
+	info, err := findGameInfo(install)
+	if err != nil {
+		t.Fatalf("findGameInfo failed: %v", err)
+	}
+	if info.GameID != "2" {
+		t.Errorf("expected the base game past the broken file, got %+v", info)
+	}
+
+	broken := t.TempDir()
+	writeFile(t, filepath.Join(broken, "goggame-1.info"), "not json", 0644)
+	if _, err := findGameInfo(broken); err == nil {
+		t.Error("expected an error when no file can be read")
+	}
+}
@@ -0,0 +1,112 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// defaultCDNBases are the Steam CDNs tried in order after any configured base
+var defaultCDNBases = []string{
+	"https://cdn.cloudflare.steamstatic.com",
+	"https://steamcdn-a.akamaihd.net",
+}
+
+// cdnBases returns the CDN base URLs to try, configured base first
+func (s *Source) cdnBases() []string {
+	configured := strings.TrimRight(s.CDNBase, "/")
+
+	bases := make([]string, 0, len(defaultCDNBases)+1)
+	if configured != "" {
+		bases = append(bases, configured)
+	}
+	for _, base := range defaultCDNBases {
+		if base != configured {
+			bases = append(bases, base)
+		}
+	}
+	return bases
+}
+
+// cdnArtFiles maps the art types the Steam CDN has to their file names
+var cdnArtFiles = map[string]string{
+	"header":  "header.jpg",
+	"library": "library_600x900.jpg",
+	"grid":    "library_600x900.jpg",
+	"hero":    "library_hero.jpg",
+	"logo":    "logo.png",
+	"icon":    "icon.jpg",
+}
+
+// cdnPath returns the CDN path of an art type for an app, or false if the CDN
+// doesn't have that type
+func cdnPath(appID, artType string) (string, bool) {
+	file, ok := cdnArtFiles[artType]
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf("/steam/apps/%s/%s", appID, file), true
+}
+
+// downloadFromCDN tries each CDN base in order and returns the first image found.
+// Returns ErrArtNotFound only if every CDN reported the image missing.
+func (s *Source) downloadFromCDN(ctx context.Context, path string) ([]byte, string, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	// The last failure other than a 404, reported if no CDN has the image
+	var lastErr error
+	for _, base := range s.cdnBases() {
+		data, contentType, err := fetchImage(ctx, client, base+path)
+		if err == nil {
+			return data, contentType, nil
+		}
+		if !errors.Is(err, models.ErrArtNotFound) {
+			lastErr = err
+		}
+	}
+
+	if lastErr != nil {
+		return nil, "", lastErr
+	}
+	return nil, "", fmt.Errorf("%w: %s", models.ErrArtNotFound, path)
+}
+
+// fetchImage downloads a URL and checks that the body is an image, since
+// mirrors and captive portals can answer 200 with an HTML page
+func fetchImage(ctx context.Context, client *http.Client, url string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, "", fmt.Errorf("%w: %s", models.ErrArtNotFound, url)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("Steam CDN returned status %d for %s", resp.StatusCode, url)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read art data: %w", err)
+	}
+
+	contentType := http.DetectContentType(data)
+	if !strings.HasPrefix(contentType, "image/") {
+		return nil, "", fmt.Errorf("%s returned %s, not an image", url, contentType)
+	}
+
+	return data, contentType, nil
+}
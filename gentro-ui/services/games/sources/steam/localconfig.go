@@ -0,0 +1,114 @@
+package steam
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	vdf "github.com/andygrunwald/vdf"
+)
+
+// appActivity is per-app play data recorded by the Steam client in localconfig.vdf
+type appActivity struct {
+	// LastPlayed is a unix timestamp in seconds
+	LastPlayed int64
+	// Playtime is total minutes played
+	Playtime int64
+}
+
+// loadAppActivity reads play data for every Steam user on this machine. When
+// several users have played the same app, the most recent entry wins.
+func (s *Source) loadAppActivity() map[string]appActivity {
+	activity := make(map[string]appActivity)
+
+	paths, _ := filepath.Glob(filepath.Join(s.installPath, "userdata", "*", "config", "localconfig.vdf"))
+	for _, path := range paths {
+		apps, err := parseLocalConfig(path)
+		if err != nil {
+			if s.Logger != nil {
+				s.Logger.Debug("failed to parse localconfig", "path", path, "error", err)
+			}
+			continue
+		}
+		for appID, a := range apps {
+			if existing, ok := activity[appID]; !ok || a.LastPlayed > existing.LastPlayed {
+				activity[appID] = a
+			}
+		}
+	}
+
+	return activity
+}
+
+// parseLocalConfig extracts per-app play data from a text localconfig.vdf
+func parseLocalConfig(path string) (map[string]appActivity, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open localconfig: %w", err)
+	}
+	defer f.Close()
+
+	root, err := vdf.NewParser(f).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse VDF: %w", err)
+	}
+
+	apps := lookupPath(root, "UserLocalConfigStore", "Software", "Valve", "Steam", "apps")
+	if apps == nil {
+		return map[string]appActivity{}, nil
+	}
+
+	activity := make(map[string]appActivity)
+	for appID, value := range apps {
+		app, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		a := appActivity{
+			LastPlayed: getInt64Fold(app, "LastPlayed"),
+			Playtime:   getInt64Fold(app, "Playtime"),
+		}
+		if a.LastPlayed > 0 || a.Playtime > 0 {
+			activity[appID] = a
+		}
+	}
+
+	return activity, nil
+}
+
+// lookupPath walks nested VDF sections. Steam isn't consistent about key case, so matching is case-insensitive.
+func lookupPath(m map[string]any, keys ...string) map[string]any {
+	for _, key := range keys {
+		next, ok := getFold(m, key).(map[string]any)
+		if !ok {
+			return nil
+		}
+		m = next
+	}
+	return m
+}
+
+// getFold returns the value for key, ignoring case
+func getFold(m map[string]any, key string) any {
+	if v, ok := m[key]; ok {
+		return v
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return nil
+}
+
+// getInt64Fold parses a numeric VDF value, ignoring key case
+func getInt64Fold(m map[string]any, key string) int64 {
+	if s, ok := getFold(m, key).(string); ok {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,81 @@
+package steam
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// prefetchArtTypes are the images the library grid and detail view request first
+var prefetchArtTypes = []string{"header", "library", "hero"}
+
+// prefetchConcurrency bounds simultaneous CDN downloads during a prefetch
+const prefetchConcurrency = 4
+
+// PrefetchArt downloads uncached art for all instances in the background so the
+// first scroll through the library doesn't wait on the CDN. It is a no-op unless
+// prefetching is enabled, and only one prefetch runs at a time.
+func (s *Source) PrefetchArt(ctx context.Context, instances []models.GameInstance) {
+	if !s.prefetchArt || !s.prefetching.CompareAndSwap(false, true) {
+		return
+	}
+	defer s.prefetching.Store(false)
+
+	type job struct {
+		instance models.GameInstance
+		appID    string
+		artType  string
+		artPath  string
+	}
+
+	var jobs []job
+	for _, instance := range instances {
+		appID, err := appIDFor(instance)
+		if err != nil {
+			continue
+		}
+		for _, artType := range prefetchArtTypes {
+			artPath := filepath.Join(s.ArtCache, instance.ID, artType+".jpg")
+			if _, err := os.Stat(artPath); err == nil {
+				continue
+			}
+			jobs = append(jobs, job{instance, appID, artType, artPath})
+		}
+	}
+	if len(jobs) == 0 {
+		return
+	}
+
+	s.Logger.Info("prefetching Steam art", "images", len(jobs))
+
+	var completed atomic.Int32
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, prefetchConcurrency)
+
+	for _, j := range jobs {
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if _, _, err := s.fetchAndCacheArt(ctx, j.appID, j.artType, j.artPath); err != nil {
+				s.Logger.Debug("failed to prefetch art", "appID", j.appID, "artType", j.artType, "error", err)
+			} else {
+				s.Events.EmitGameArtUpdated(j.instance.ID, j.instance.GameID, j.artType)
+			}
+			s.Events.EmitArtPrefetchProgress(s.Name(), int(completed.Add(1)), len(jobs))
+		}()
+	}
+
+	wg.Wait()
+	s.Logger.Info("Steam art prefetch complete", "images", len(jobs))
+}
@@ -2,22 +2,24 @@ package steam
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"io"
 	"log/slog"
 	"maps"
-	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	vdf "github.com/andygrunwald/vdf"
 	"github.com/shirou/gopsutil/v4/process"
 
+	"github.com/rhythmerc/gentro-ui/services/games"
 	"github.com/rhythmerc/gentro-ui/services/games/events"
 	"github.com/rhythmerc/gentro-ui/services/games/models"
 )
@@ -28,6 +30,22 @@ type Source struct {
 	ArtCache    string
 	config      Config
 	Logger      *slog.Logger
+	Events      *events.Events
+
+	// CDNBase is tried before the default Steam CDNs, e.g. a regional mirror
+	CDNBase string
+
+	// videoURLs caches store trailer lookups by app ID
+	videoURLs map[string]string
+	videoMu   sync.Mutex
+
+	// artMisses holds the CDN paths that every CDN reported missing
+	artMisses map[string]bool
+	artMu     sync.Mutex
+
+	// prefetchArt downloads art for all games after a refresh instead of on first view
+	prefetchArt bool
+	prefetching atomic.Bool
 }
 
 // Config holds Steam source configuration
@@ -36,13 +54,29 @@ type Config struct {
 	APIKey      string // Steam Web API key
 }
 
+func init() {
+	games.RegisterSource("steam", func(deps games.SourceDeps) games.GameSource {
+		source := &Source{
+			Logger:   deps.Logger,
+			Events:   deps.Events,
+			ArtCache: filepath.Join(deps.ArtCache, "steam"),
+		}
+		if deps.Config != nil {
+			cfg := deps.Config.Get()
+			source.CDNBase = cfg.Steam.CDNBase
+			source.prefetchArt = cfg.Steam.PrefetchArt
+		}
+		return source
+	})
+}
+
 // Name returns the source identifier
 func (s *Source) Name() string {
 	return "steam"
 }
 
 // Init initializes the Steam source
-func (s *Source) Init(config map[string]any) error {
+func (s *Source) Init(ctx context.Context, config map[string]any) error {
 	// Try to auto-detect Steam installation
 	if config != nil {
 		if path, ok := config["installPath"].(string); ok && path != "" {
@@ -55,6 +89,9 @@ func (s *Source) Init(config map[string]any) error {
 
 	// Auto-detect if not configured
 	if s.installPath == "" {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		path, err := s.detectSteamPath()
 		if err != nil {
 			return fmt.Errorf("failed to detect Steam installation: %w", err)
@@ -91,8 +128,14 @@ func (s *Source) GetInstances(ctx context.Context) ([]models.GameInstance, error
 		return nil, fmt.Errorf("failed to read steamapps directory: %w", err)
 	}
 
+	// Play data comes from the Steam client, so it's available without a Web API key
+	activity := s.loadAppActivity()
+
 	var instances []models.GameInstance
 	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		if entry.IsDir() {
 			continue
 		}
@@ -111,6 +154,15 @@ func (s *Source) GetInstances(ctx context.Context) ([]models.GameInstance, error
 
 		// Update instance timestamps
 		instance.UpdatedAt = time.Now()
+
+		if a, ok := activity[instance.SourceID]; ok {
+			if a.LastPlayed > 0 {
+				instance.CustomMetadata["steam.lastPlayed"] = strconv.FormatInt(a.LastPlayed, 10)
+			}
+			if a.Playtime > 0 {
+				instance.CustomMetadata["steam.playtime"] = strconv.FormatInt(a.Playtime, 10)
+			}
+		}
 		instances = append(instances, *instance)
 	}
 
@@ -122,6 +174,15 @@ func isAppManifest(filename string) bool {
 	return filepath.Ext(filename) == ".acf" && len(filename) > 12 && filename[:12] == "appmanifest_"
 }
 
+// Capabilities reports that Steam games are launched and given art through
+// the Steam client and CDN; installs and manual additions go through Steam itself
+func (s *Source) Capabilities() models.SourceCapabilities {
+	return models.SourceCapabilities{
+		CanLaunch:  true,
+		CanScanArt: true,
+	}
+}
+
 // Refresh updates Steam game data
 func (s *Source) Refresh(ctx context.Context) error {
 	// TODO: Re-fetch from Steam
@@ -129,16 +190,14 @@ func (s *Source) Refresh(ctx context.Context) error {
 }
 
 // GetGameArt returns Steam game art, fetching from CDN if not cached
-func (s *Source) GetGameArt(ctx context.Context, instanceID string, artType string) ([]byte, string, error) {
-	// Parse instance ID to get Steam App ID
-	// instanceID format: "steam_{appid}"
-	appID := strings.TrimPrefix(instanceID, "steam_")
-	if appID == "" {
-		return nil, "", fmt.Errorf("invalid instance ID format: %s", instanceID)
+func (s *Source) GetGameArt(ctx context.Context, instance models.GameInstance, artType string) ([]byte, string, error) {
+	appID, err := appIDFor(instance)
+	if err != nil {
+		return nil, "", err
 	}
 
 	// Look for cached art
-	artPath := filepath.Join(s.ArtCache, instanceID, artType+".jpg")
+	artPath := s.artPath(instance, artType)
 
 	// Check if art exists in cache
 	data, err := os.ReadFile(artPath)
@@ -155,50 +214,94 @@ func (s *Source) GetGameArt(ctx context.Context, instanceID string, artType stri
 	return nil, "", fmt.Errorf("failed to read art: %w", err)
 }
 
-// fetchAndCacheArt downloads art from Steam CDN and caches it
-func (s *Source) fetchAndCacheArt(ctx context.Context, appID, artType, artPath string) ([]byte, string, error) {
-	// Build Steam CDN URL based on art type
-	var cdnURL string
-	switch artType {
-	case "header":
-		cdnURL = fmt.Sprintf("https://cdn.cloudflare.steamstatic.com/steam/apps/%s/header.jpg", appID)
-	case "library":
-		cdnURL = fmt.Sprintf("https://cdn.cloudflare.steamstatic.com/steam/apps/%s/library_600x900.jpg", appID)
-	case "hero":
-		cdnURL = fmt.Sprintf("https://cdn.cloudflare.steamstatic.com/steam/apps/%s/library_hero.jpg", appID)
-	case "logo":
-		cdnURL = fmt.Sprintf("https://cdn.cloudflare.steamstatic.com/steam/apps/%s/logo.png", appID)
-	case "icon":
-		cdnURL = fmt.Sprintf("https://cdn.cloudflare.steamstatic.com/steam/apps/%s/icon.jpg", appID)
-	default:
-		// Default to header
-		cdnURL = fmt.Sprintf("https://cdn.cloudflare.steamstatic.com/steam/apps/%s/header.jpg", appID)
+// GameArtPath returns the cached art file, downloading it from the CDN first if needed
+func (s *Source) GameArtPath(ctx context.Context, instance models.GameInstance, artType string) (string, error) {
+	artPath := s.artPath(instance, artType)
+	if _, err := os.Stat(artPath); err == nil {
+		return artPath, nil
 	}
 
-	// Create HTTP client with timeout
-	client := &http.Client{Timeout: 30 * time.Second}
+	if _, _, err := s.GetGameArt(ctx, instance, artType); err != nil {
+		return "", err
+	}
 
-	// Make the request
-	req, err := http.NewRequestWithContext(ctx, "GET", cdnURL, nil)
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	// Caching can fail without failing the download; callers fall back to GetGameArt
+	if _, err := os.Stat(artPath); err != nil {
+		return "", fmt.Errorf("failed to cache art: %w", err)
+	}
+	return artPath, nil
+}
+
+// HasGameArt reports whether an art type is cached, or could be downloaded because
+// the CDN has that type and hasn't reported it missing for the app. It never
+// downloads anything. It implements games.ArtAvailability.
+func (s *Source) HasGameArt(instance models.GameInstance, artType string) bool {
+	if _, err := os.Stat(s.artPath(instance, artType)); err == nil {
+		return true
 	}
 
-	resp, err := client.Do(req)
+	appID, err := appIDFor(instance)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to fetch art from Steam: %w", err)
+		return false
+	}
+	path, ok := cdnPath(appID, artType)
+	if !ok {
+		return false
+	}
+	s.artMu.Lock()
+	defer s.artMu.Unlock()
+	return !s.artMisses[path]
+}
+
+// setArtMissing records whether every CDN reported a path missing
+func (s *Source) setArtMissing(path string, missing bool) {
+	s.artMu.Lock()
+	defer s.artMu.Unlock()
+
+	if !missing {
+		delete(s.artMisses, path)
+		return
 	}
-	defer resp.Body.Close()
+	if s.artMisses == nil {
+		s.artMisses = make(map[string]bool)
+	}
+	s.artMisses[path] = true
+}
+
+// artPath is where an instance's art of the given type is cached
+func (s *Source) artPath(instance models.GameInstance, artType string) string {
+	return filepath.Join(s.ArtCache, instance.ID, artType+".jpg")
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, "", fmt.Errorf("Steam CDN returned status %d for %s", resp.StatusCode, artType)
+// appIDFor returns the Steam app ID for an instance. The game ID can change when
+// instances are merged, so the app ID is read from SourceID, falling back to the
+// "steam_{appid}" instance ID format for rows scanned before SourceID was set.
+func appIDFor(instance models.GameInstance) (string, error) {
+	if instance.SourceID != "" {
+		return instance.SourceID, nil
+	}
+	if appID, ok := strings.CutPrefix(instance.ID, "steam_"); ok && appID != "" {
+		return appID, nil
 	}
+	return "", fmt.Errorf("no Steam app ID for instance %s", instance.ID)
+}
 
-	// Read the image data
-	data, err := io.ReadAll(resp.Body)
+// fetchAndCacheArt downloads art from the Steam CDN and caches it. Types the CDN
+// doesn't have, and images it reported missing, are ErrArtNotFound.
+func (s *Source) fetchAndCacheArt(ctx context.Context, appID, artType, artPath string) ([]byte, string, error) {
+	path, ok := cdnPath(appID, artType)
+	if !ok {
+		return nil, "", fmt.Errorf("%w: no Steam CDN art of type %s", models.ErrArtNotFound, artType)
+	}
+
+	data, contentType, err := s.downloadFromCDN(ctx, path)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to read art data: %w", err)
+		if errors.Is(err, models.ErrArtNotFound) {
+			s.setArtMissing(path, true)
+		}
+		return nil, "", fmt.Errorf("failed to fetch %s art for %s: %w", artType, appID, err)
 	}
+	s.setArtMissing(path, false)
 
 	// Create cache directory
 	artDir := filepath.Dir(artPath)
@@ -212,7 +315,7 @@ func (s *Source) fetchAndCacheArt(ctx context.Context, appID, artType, artPath s
 		fmt.Printf("Warning: failed to cache art to %s: %v\n", artPath, err)
 	}
 
-	return data, "image/jpeg", nil
+	return data, contentType, nil
 }
 
 // detectSteamPath auto-detects Steam installation path
@@ -434,10 +537,7 @@ func (s *Source) Launch(ctx context.Context, instance models.GameInstance) (*exe
 
 // MonitorProcess watches the Steam game process and emits status events
 // For Steam, we use activity-based polling since Steam manages the actual game process
-// The GamesService.monitorGameProcess handles the actual monitoring via isProcessRunningInPath
 func (s *Source) MonitorProcess(ctx context.Context, instance models.GameInstance, cmd *exec.Cmd) {
-	emit := events.NewEvents(s.Logger)
-
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 
@@ -455,13 +555,13 @@ func (s *Source) MonitorProcess(ctx context.Context, instance models.GameInstanc
 		if running {
 			// Emit running on first detection
 			if !hasBeenRunning {
-				emit.EmitGameInstanceRunning(instance)
+				s.Events.EmitGameInstanceRunning(instance)
 				hasBeenRunning = true
 			}
 			lastSeenRunning = time.Now()
 		} else if hasBeenRunning && time.Since(lastSeenRunning) > stopThreshold {
 			// Emit stopped after threshold
-			emit.EmitGameInstanceStopped(instance)
+			s.Events.EmitGameInstanceStopped(instance)
 			return
 		}
 	}
@@ -1,9 +1,24 @@
 package steam
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/games/events"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
 )
 
 func TestIsTool(t *testing.T) {
@@ -177,3 +192,320 @@ func BenchmarkIsTool(b *testing.B) {
 		}
 	})
 }
+
+func TestHeroVideoURL(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Query().Get("appids") {
+		case "10":
+			w.Write([]byte(`{"10":{"success":true,"data":{"movies":[{"webm":{"480":"https://cdn/480.webm","max":"https://cdn/max.webm"},"mp4":{"max":"https://cdn/max.mp4"}}]}}}`))
+		default:
+			w.Write([]byte(`{"20":{"success":true,"data":{}}}`))
+		}
+	}))
+	defer server.Close()
+
+	originalURL := storeAPIURL
+	storeAPIURL = server.URL
+	defer func() { storeAPIURL = originalURL }()
+
+	source := &Source{}
+
+	url, err := source.heroVideoURL(context.Background(), "10")
+	if err != nil {
+		t.Fatalf("heroVideoURL failed: %v", err)
+	}
+	if url != "https://cdn/max.webm" {
+		t.Errorf("expected max webm, got %q", url)
+	}
+
+	url, err = source.heroVideoURL(context.Background(), "20")
+	if err != nil {
+		t.Fatalf("heroVideoURL failed: %v", err)
+	}
+	if url != "" {
+		t.Errorf("expected no video, got %q", url)
+	}
+
+	// Both hits and misses are cached
+	source.heroVideoURL(context.Background(), "10")
+	source.heroVideoURL(context.Background(), "20")
+	if requests != 2 {
+		t.Errorf("expected 2 store requests, got %d", requests)
+	}
+}
+
+func TestAppIDFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance models.GameInstance
+		want     string
+		wantErr  bool
+	}{
+		{"source ID survives a merged game ID", models.GameInstance{ID: "steam_10", GameID: "merged", SourceID: "10"}, "10", false},
+		{"source ID wins over instance ID", models.GameInstance{ID: "steam_10", SourceID: "20"}, "20", false},
+		{"legacy row falls back to instance ID", models.GameInstance{ID: "steam_30"}, "30", false},
+		{"no app ID", models.GameInstance{ID: "rom_1"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := appIDFor(tt.instance)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("appIDFor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("appIDFor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrefetchArt(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write(testJPEG(t))
+	}))
+	defer server.Close()
+
+	overrideCDNBases(t, server.URL)
+
+	artCache := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(artCache, "steam_10"), 0755); err != nil {
+		t.Fatalf("failed to create art dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(artCache, "steam_10", "header.jpg"), []byte("cached"), 0644); err != nil {
+		t.Fatalf("failed to seed cached art: %v", err)
+	}
+
+	var progress []models.ArtPrefetchProgress
+	var mu sync.Mutex
+	source := &Source{
+		ArtCache: artCache,
+		Logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Events: events.NewEventsWithSink(nil, func(name string, data any) {
+			if p, ok := data.(models.ArtPrefetchProgress); ok {
+				mu.Lock()
+				progress = append(progress, p)
+				mu.Unlock()
+			}
+		}),
+	}
+	instances := []models.GameInstance{
+		{ID: "steam_10", GameID: "10", SourceID: "10"},
+		{ID: "steam_20", GameID: "20", SourceID: "20"},
+	}
+
+	// Disabled by default
+	source.PrefetchArt(context.Background(), instances)
+	if requests.Load() != 0 {
+		t.Fatalf("expected no requests with prefetch disabled, got %d", requests.Load())
+	}
+
+	source.prefetchArt = true
+	source.PrefetchArt(context.Background(), instances)
+	if got := requests.Load(); got != 5 {
+		t.Errorf("expected 5 downloads skipping the cached header, got %d", got)
+	}
+	if len(progress) != 5 || progress[len(progress)-1].Total != 5 {
+		t.Errorf("unexpected progress events: %+v", progress)
+	}
+	if _, err := os.Stat(filepath.Join(artCache, "steam_20", "hero.jpg")); err != nil {
+		t.Errorf("expected hero art to be cached: %v", err)
+	}
+
+	// Everything is cached now
+	source.PrefetchArt(context.Background(), instances)
+	if got := requests.Load(); got != 5 {
+		t.Errorf("expected cached art to be skipped, got %d requests", got)
+	}
+}
+
+// testJPEG returns a small valid JPEG
+func testJPEG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4)), nil); err != nil {
+		t.Fatalf("failed to encode jpeg: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// overrideCDNBases replaces the default CDNs for the duration of a test
+func overrideCDNBases(t *testing.T, bases ...string) {
+	t.Helper()
+	original := defaultCDNBases
+	defaultCDNBases = bases
+	t.Cleanup(func() { defaultCDNBases = original })
+}
+
+func TestHasGameArt(t *testing.T) {
+	var requests int
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if strings.HasSuffix(r.URL.Path, "/header.jpg") {
+			w.Write(testJPEG(t))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(cdn.Close)
+	overrideCDNBases(t, cdn.URL)
+
+	source := &Source{ArtCache: t.TempDir()}
+	instance := models.GameInstance{ID: "steam_10", SourceID: "10"}
+
+	// Types the CDN doesn't have are never downloaded or reported
+	for _, artType := range []string{"cover", "screenshot", "artwork"} {
+		if source.HasGameArt(instance, artType) {
+			t.Errorf("expected no %s art", artType)
+		}
+		if _, _, err := source.GetGameArt(context.Background(), instance, artType); !errors.Is(err, models.ErrArtNotFound) {
+			t.Errorf("expected ErrArtNotFound for %s, got %v", artType, err)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("expected no CDN requests for unsupported types, got %d", requests)
+	}
+
+	// CDN types are available until the CDN reports them missing
+	if !source.HasGameArt(instance, "hero") {
+		t.Error("expected hero to be available before it's fetched")
+	}
+	if _, _, err := source.GetGameArt(context.Background(), instance, "hero"); !errors.Is(err, models.ErrArtNotFound) {
+		t.Fatalf("expected ErrArtNotFound for hero, got %v", err)
+	}
+	if source.HasGameArt(instance, "hero") {
+		t.Error("expected hero to be unavailable once the CDN reported it missing")
+	}
+
+	if _, _, err := source.GetGameArt(context.Background(), instance, "header"); err != nil {
+		t.Fatalf("GetGameArt failed: %v", err)
+	}
+	requests = 0
+	if !source.HasGameArt(instance, "header") || requests != 0 {
+		t.Errorf("expected cached header to be available without a request, got %d requests", requests)
+	}
+}
+
+func TestDownloadFromCDN(t *testing.T) {
+	var hits []string
+	var mu sync.Mutex
+	handler := func(name string, respond func(w http.ResponseWriter)) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			hits = append(hits, name)
+			mu.Unlock()
+			respond(w)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	portal := handler("portal", func(w http.ResponseWriter) { w.Write([]byte("<html>sign in</html>")) })
+	missing := handler("missing", func(w http.ResponseWriter) { w.WriteHeader(http.StatusNotFound) })
+	mirror := handler("mirror", func(w http.ResponseWriter) { w.Write(testJPEG(t)) })
+
+	t.Run("configured base is tried first and non-images are skipped", func(t *testing.T) {
+		hits = nil
+		overrideCDNBases(t, missing.URL, mirror.URL)
+		source := &Source{CDNBase: portal.URL + "/"}
+
+		data, contentType, err := source.downloadFromCDN(context.Background(), "/steam/apps/10/header.jpg")
+		if err != nil {
+			t.Fatalf("downloadFromCDN failed: %v", err)
+		}
+		if contentType != "image/jpeg" || len(data) == 0 {
+			t.Errorf("unexpected result %q (%d bytes)", contentType, len(data))
+		}
+		if strings.Join(hits, ",") != "portal,missing,mirror" {
+			t.Errorf("unexpected CDN order: %v", hits)
+		}
+	})
+
+	t.Run("missing everywhere is not found", func(t *testing.T) {
+		overrideCDNBases(t, missing.URL)
+		source := &Source{}
+
+		_, _, err := source.downloadFromCDN(context.Background(), "/steam/apps/10/library_hero.jpg")
+		if !errors.Is(err, models.ErrArtNotFound) {
+			t.Errorf("expected ErrArtNotFound, got %v", err)
+		}
+	})
+
+	t.Run("bad responses are real errors", func(t *testing.T) {
+		overrideCDNBases(t, missing.URL)
+		source := &Source{CDNBase: portal.URL}
+
+		_, _, err := source.downloadFromCDN(context.Background(), "/steam/apps/10/library_hero.jpg")
+		if err == nil || errors.Is(err, models.ErrArtNotFound) {
+			t.Errorf("expected a non-not-found error, got %v", err)
+		}
+	})
+}
+
+func TestGetInstances_ReadsLocalConfigActivity(t *testing.T) {
+	steamDir := t.TempDir()
+	steamappsDir := filepath.Join(steamDir, "steamapps")
+	if err := os.MkdirAll(filepath.Join(steamappsDir, "common", "TestGame"), 0755); err != nil {
+		t.Fatalf("failed to create install dir: %v", err)
+	}
+	manifest := `"AppState"
+{
+	"appid"		"12345"
+	"name"		"Test Game"
+	"installdir"		"TestGame"
+}`
+	if err := os.WriteFile(filepath.Join(steamappsDir, "appmanifest_12345.acf"), []byte(manifest), 0644); err != nil {
+		t.Fatalf("failed to create appmanifest: %v", err)
+	}
+
+	// Two users played the same game; the most recent session wins
+	writeLocalConfig := func(userID, lastPlayed, playtime string) {
+		dir := filepath.Join(steamDir, "userdata", userID, "config")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("failed to create userdata dir: %v", err)
+		}
+		content := `"UserLocalConfigStore"
+{
+	"Software"
+	{
+		"Valve"
+		{
+			"Steam"
+			{
+				"Apps"
+				{
+					"12345"
+					{
+						"LastPlayed"		"` + lastPlayed + `"
+						"Playtime"		"` + playtime + `"
+					}
+				}
+			}
+		}
+	}
+}`
+		if err := os.WriteFile(filepath.Join(dir, "localconfig.vdf"), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write localconfig: %v", err)
+		}
+	}
+	writeLocalConfig("100", "1700000000", "30")
+	writeLocalConfig("200", "1710000000", "95")
+
+	source := &Source{installPath: steamDir}
+	instances, err := source.GetInstances(context.Background())
+	if err != nil {
+		t.Fatalf("GetInstances failed: %v", err)
+	}
+	if len(instances) != 1 {
+		t.Fatalf("expected 1 instance, got %d", len(instances))
+	}
+
+	metadata := instances[0].CustomMetadata
+	if metadata["steam.lastPlayed"] != "1710000000" || metadata["steam.playtime"] != "95" {
+		t.Errorf("unexpected play data: %v", metadata)
+	}
+}
@@ -0,0 +1,86 @@
+package steam
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// storeAPIURL is the Steam store endpoint used to look up trailers
+var storeAPIURL = "https://store.steampowered.com/api/appdetails"
+
+// storeMovie is a trailer entry from the store appdetails response
+type storeMovie struct {
+	Webm map[string]string `json:"webm"`
+	Mp4  map[string]string `json:"mp4"`
+}
+
+// HeroVideoURL looks up the instance's store trailer. It implements games.HeroVideoSource.
+func (s *Source) HeroVideoURL(ctx context.Context, instance models.GameInstance) (string, error) {
+	return s.heroVideoURL(ctx, instance.SourceID)
+}
+
+// heroVideoURL returns the URL of an app's first store trailer, or "" if it has none.
+// Lookups, including misses, are cached for the lifetime of the source.
+func (s *Source) heroVideoURL(ctx context.Context, appID string) (string, error) {
+	s.videoMu.Lock()
+	if url, ok := s.videoURLs[appID]; ok {
+		s.videoMu.Unlock()
+		return url, nil
+	}
+	s.videoMu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s?appids=%s&filters=movies", storeAPIURL, appID), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch app details: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Steam store returned status %d", resp.StatusCode)
+	}
+
+	var details map[string]struct {
+		Success bool `json:"success"`
+		Data    struct {
+			Movies []storeMovie `json:"movies"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&details); err != nil {
+		return "", fmt.Errorf("failed to decode app details: %w", err)
+	}
+
+	var url string
+	if app, ok := details[appID]; ok && app.Success && len(app.Data.Movies) > 0 {
+		url = pickMovieURL(app.Data.Movies[0])
+	}
+
+	s.videoMu.Lock()
+	if s.videoURLs == nil {
+		s.videoURLs = make(map[string]string)
+	}
+	s.videoURLs[appID] = url
+	s.videoMu.Unlock()
+
+	return url, nil
+}
+
+// pickMovieURL prefers WebM over MP4 and the highest available quality
+func pickMovieURL(movie storeMovie) string {
+	for _, formats := range []map[string]string{movie.Webm, movie.Mp4} {
+		for _, quality := range []string{"max", "480"} {
+			if url := formats[quality]; url != "" {
+				return url
+			}
+		}
+	}
+	return ""
+}
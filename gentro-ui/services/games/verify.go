@@ -0,0 +1,89 @@
+package games
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// verifyProgressInterval is how many instances are checked between progress events
+const verifyProgressInterval = 25
+
+// VerifyLibrary checks that each instance's files still exist and match the size
+// and hash recorded when they were scanned, flagging files that were moved,
+// deleted or modified. It only reports; nothing in the database is changed.
+func (s *GamesService) VerifyLibrary() (models.VerifyReport, error) {
+	instances, err := s.db.GetInstances(models.GameFilter{})
+	if err != nil {
+		return models.VerifyReport{}, fmt.Errorf("failed to get instances: %w", err)
+	}
+
+	report := models.VerifyReport{Issues: []models.VerifyIssue{}}
+	for i, instance := range instances {
+		if instance.Path == "" {
+			report.Skipped++
+		} else {
+			report.Checked++
+			report.Issues = append(report.Issues, s.verifyInstance(instance)...)
+		}
+
+		if checked := i + 1; checked%verifyProgressInterval == 0 || checked == len(instances) {
+			s.events.EmitVerifyProgress(checked, len(instances))
+		}
+	}
+
+	s.logger.Info("verified library", "checked", report.Checked, "skipped", report.Skipped, "issues", len(report.Issues))
+	return report, nil
+}
+
+// verifyInstance checks an instance's launched file against its recorded size
+// and hash, and that the other files it's made of still exist
+func (s *GamesService) verifyInstance(instance models.GameInstance) []models.VerifyIssue {
+	issue := func(path string, kind models.VerifyIssueKind, message string) models.VerifyIssue {
+		return models.VerifyIssue{InstanceID: instance.ID, GameID: instance.GameID, Path: path, Kind: kind, Message: message}
+	}
+
+	var issues []models.VerifyIssue
+	for _, file := range instance.Files {
+		if _, err := os.Stat(file.Path); err != nil {
+			issues = append(issues, issue(file.Path, verifyStatKind(err), fmt.Sprintf("%s file: %v", file.Role, err)))
+		}
+	}
+
+	info, err := os.Stat(instance.Path)
+	if err != nil {
+		return append(issues, issue(instance.Path, verifyStatKind(err), err.Error()))
+	}
+	if instance.FileSize > 0 && info.Size() != instance.FileSize {
+		return append(issues, issue(instance.Path, models.VerifySizeChanged,
+			fmt.Sprintf("size is %d bytes, expected %d", info.Size(), instance.FileSize)))
+	}
+
+	source, ok := s.registry.Get(instance.Source)
+	if !ok || instance.FileHash == "" {
+		return issues
+	}
+	hasher, ok := source.(FileHasher)
+	if !ok {
+		return issues
+	}
+	hash, err := hasher.HashFile(instance.Path)
+	if err != nil {
+		return append(issues, issue(instance.Path, models.VerifyUnreadable, err.Error()))
+	}
+	if hash != instance.FileHash {
+		issues = append(issues, issue(instance.Path, models.VerifyModified, "content differs from when it was scanned"))
+	}
+	return issues
+}
+
+// verifyStatKind classifies a failure to stat a file
+func verifyStatKind(err error) models.VerifyIssueKind {
+	if errors.Is(err, fs.ErrNotExist) {
+		return models.VerifyMissing
+	}
+	return models.VerifyUnreadable
+}
@@ -0,0 +1,99 @@
+package games
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rhythmerc/gentro-ui/services/games/events"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// hashingSource is a mock source that hashes files by their content
+type hashingSource struct {
+	MockSource
+}
+
+func (h *hashingSource) HashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	return string(data), err
+}
+
+func TestVerifyLibrary(t *testing.T) {
+	service := newTestService(t)
+	var progress []models.VerifyProgress
+	service.events = events.NewEventsWithSink(service.logger, func(name string, data any) {
+		if update, ok := data.(models.VerifyProgress); ok {
+			progress = append(progress, update)
+		}
+	})
+	service.registry.Register(context.Background(), &hashingSource{MockSource{name: "roms"}})
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	intact := write("intact.nes", "abcd")
+	resized := write("resized.nes", "abcd")
+	modified := write("modified.nes", "abcd")
+	disk := write("disk2.adf", "disk")
+
+	if _, err := service.syncSourceInstances("roms", []models.GameInstance{
+		{ID: "intact", GameID: "g1", Source: "roms", Platform: "nes", Path: intact, FileSize: 4, FileHash: "abcd",
+			Files: []models.InstanceFile{{Path: disk, Role: models.FileRoleDisk}}},
+		{ID: "resized", GameID: "g2", Source: "roms", Platform: "nes", Path: resized, FileSize: 4, FileHash: "abcd"},
+		{ID: "modified", GameID: "g3", Source: "roms", Platform: "nes", Path: modified, FileSize: 4, FileHash: "abcd"},
+		{ID: "missing", GameID: "g4", Source: "roms", Platform: "nes", Path: filepath.Join(dir, "gone.nes"), FileSize: 4},
+		{ID: "installed", GameID: "g5", Source: "roms", Platform: "pc", InstallPath: dir},
+	}); err != nil {
+		t.Fatalf("failed to seed instances: %v", err)
+	}
+	write("resized.nes", "abcdef")
+	write("modified.nes", "wxyz")
+	if err := os.Remove(disk); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := service.VerifyLibrary()
+	if err != nil {
+		t.Fatalf("VerifyLibrary failed: %v", err)
+	}
+	if report.Checked != 4 || report.Skipped != 1 {
+		t.Errorf("expected 4 checked and 1 skipped, got %d and %d", report.Checked, report.Skipped)
+	}
+
+	got := make(map[string]models.VerifyIssueKind)
+	for _, issue := range report.Issues {
+		got[issue.InstanceID+":"+filepath.Base(issue.Path)] = issue.Kind
+	}
+	want := map[string]models.VerifyIssueKind{
+		"intact:disk2.adf":      models.VerifyMissing,
+		"resized:resized.nes":   models.VerifySizeChanged,
+		"modified:modified.nes": models.VerifyModified,
+		"missing:gone.nes":      models.VerifyMissing,
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected issues %v, got %v", want, got)
+	}
+	for key, kind := range want {
+		if got[key] != kind {
+			t.Errorf("expected %s to be %s, got %q", key, kind, got[key])
+		}
+	}
+
+	if len(progress) == 0 || progress[len(progress)-1] != (models.VerifyProgress{Checked: 5, Total: 5}) {
+		t.Errorf("expected a final progress event, got %v", progress)
+	}
+
+	// Nothing is changed in the database
+	instance, err := service.db.GetInstance("modified")
+	if err != nil || instance == nil || instance.FileHash != "abcd" {
+		t.Errorf("expected the instance to be left alone, got %+v (%v)", instance, err)
+	}
+}
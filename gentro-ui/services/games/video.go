@@ -0,0 +1,241 @@
+package games
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/games/apppaths"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+// heroVideoKey is the custom metadata key holding a hero video URL
+const heroVideoKey = "art.heroVideo"
+
+// heroVideoLookupTimeout bounds a source's hero video lookup
+const heroVideoLookupTimeout = 10 * time.Second
+
+// heroVideoRetryAfter is how long a failed hero video lookup is remembered before
+// the source is asked again
+const heroVideoRetryAfter = 15 * time.Minute
+
+// videoDownload is a hero video download in progress, which other requests for
+// the same file wait on instead of starting their own
+type videoDownload struct {
+	done chan struct{}
+	err  error
+}
+
+// heroVideosEnabled reports whether hero video downloads are turned on in config
+func (s *GamesService) heroVideosEnabled() bool {
+	return s.config != nil && s.config.Get().Art.HeroVideos
+}
+
+// GetHeroVideoURL returns the URL serving an instance's hero video, or "" if it has none
+func (s *GamesService) GetHeroVideoURL(instanceID string) (string, error) {
+	if !s.heroVideosEnabled() {
+		return "", nil
+	}
+
+	instance, err := s.db.GetInstance(instanceID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get instance: %w", err)
+	}
+	if instance == nil {
+		return "", fmt.Errorf("instance not found: %s", instanceID)
+	}
+	videoURL, err := s.resolveHeroVideo(context.Background(), *instance)
+	if err != nil {
+		return "", err
+	}
+	if videoURL == "" {
+		return "", nil
+	}
+
+	if s.route == "" {
+		return "", fmt.Errorf("service route not configured")
+	}
+	return fmt.Sprintf("%s/video/%s/hero", s.route, instanceID), nil
+}
+
+// serveHeroVideo streams an instance's hero video, downloading it to the art cache on first use
+func (s *GamesService) serveHeroVideo(w http.ResponseWriter, r *http.Request, instanceID string) {
+	if !s.heroVideosEnabled() {
+		http.Error(w, "Hero videos disabled", http.StatusNotFound)
+		return
+	}
+
+	instance, err := s.db.GetInstance(instanceID)
+	if err != nil {
+		http.Error(w, "Failed to get instance", http.StatusInternalServerError)
+		return
+	}
+	if instance == nil {
+		http.Error(w, "Instance not found", http.StatusNotFound)
+		return
+	}
+
+	videoURL, err := s.resolveHeroVideo(r.Context(), *instance)
+	if err != nil {
+		s.logger.Warn("failed to look up hero video", "error", err, "instanceID", instanceID)
+		http.Error(w, "Video unavailable", http.StatusBadGateway)
+		return
+	}
+	if videoURL == "" {
+		http.Error(w, "Video not found", http.StatusNotFound)
+		return
+	}
+
+	ext := path.Ext(videoURL)
+	if ext != ".webm" && ext != ".mp4" {
+		ext = ".webm"
+	}
+	videoPath := filepath.Join(apppaths.ArtCache, instance.Source, instanceID, "heroVideo"+ext)
+
+	if _, err := os.Stat(videoPath); os.IsNotExist(err) {
+		if err := s.downloadVideoOnce(r.Context(), videoURL, videoPath); err != nil {
+			s.logger.Warn("failed to download hero video", "error", err, "instanceID", instanceID)
+			http.Error(w, "Video unavailable", http.StatusBadGateway)
+			return
+		}
+	}
+
+	// ServeFile streams from disk and handles range requests for seeking
+	http.ServeFile(w, r, videoPath)
+}
+
+// resolveHeroVideo returns the upstream URL of an instance's hero video, or "" if it
+// has none. A URL in the instance's custom metadata wins; otherwise the source is
+// asked, so lookups only happen for videos that are actually requested. A URL the
+// source finds is saved to the custom metadata, and a failed lookup isn't retried
+// for heroVideoRetryAfter.
+func (s *GamesService) resolveHeroVideo(ctx context.Context, instance models.GameInstance) (string, error) {
+	if url, _ := instance.CustomMetadata[heroVideoKey].(string); url != "" {
+		return url, nil
+	}
+
+	source, ok := s.registry.Get(instance.Source)
+	if !ok {
+		return "", nil
+	}
+	videoSource, ok := source.(HeroVideoSource)
+	if !ok {
+		return "", nil
+	}
+
+	s.heroVideoMu.Lock()
+	failedAt, failed := s.heroVideoFailures[instance.ID]
+	s.heroVideoMu.Unlock()
+	if failed && time.Since(failedAt) < heroVideoRetryAfter {
+		return "", errors.New("hero video lookup failed recently")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, heroVideoLookupTimeout)
+	defer cancel()
+	url, err := videoSource.HeroVideoURL(ctx, instance)
+
+	s.heroVideoMu.Lock()
+	if err != nil {
+		if s.heroVideoFailures == nil {
+			s.heroVideoFailures = make(map[string]time.Time)
+		}
+		s.heroVideoFailures[instance.ID] = time.Now()
+	} else {
+		delete(s.heroVideoFailures, instance.ID)
+	}
+	s.heroVideoMu.Unlock()
+
+	if err != nil {
+		return "", fmt.Errorf("failed to look up hero video: %w", err)
+	}
+	if url != "" {
+		if err := s.db.BulkSetInstanceCustomMetadata([]string{instance.ID}, heroVideoKey, url); err != nil {
+			s.logger.Warn("failed to save hero video URL", "error", err, "instanceID", instance.ID)
+		}
+	}
+	return url, nil
+}
+
+// downloadVideoOnce downloads a video to dest, sharing one download between
+// concurrent requests for the same file. The download outlives the request that
+// started it, since others may be waiting on it.
+func (s *GamesService) downloadVideoOnce(ctx context.Context, url, dest string) error {
+	s.heroVideoMu.Lock()
+	if download, ok := s.videoDownloads[dest]; ok {
+		s.heroVideoMu.Unlock()
+		select {
+		case <-download.done:
+			return download.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	// A download may have finished since the caller checked
+	if _, err := os.Stat(dest); err == nil {
+		s.heroVideoMu.Unlock()
+		return nil
+	}
+	download := &videoDownload{done: make(chan struct{})}
+	if s.videoDownloads == nil {
+		s.videoDownloads = make(map[string]*videoDownload)
+	}
+	s.videoDownloads[dest] = download
+	s.heroVideoMu.Unlock()
+
+	download.err = s.downloadVideo(context.WithoutCancel(ctx), url, dest)
+
+	s.heroVideoMu.Lock()
+	delete(s.videoDownloads, dest)
+	s.heroVideoMu.Unlock()
+	close(download.done)
+	return download.err
+}
+
+// downloadVideo streams a video to dest, writing to a temp file first so a
+// failed download never leaves a truncated cache entry
+func (s *GamesService) downloadVideo(ctx context.Context, url, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create video cache directory: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download video: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("video download returned status %d", resp.StatusCode)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), "heroVideo-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write video: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write video: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return fmt.Errorf("failed to cache video: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package games
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rhythmerc/gentro-ui/services/config"
+	"github.com/rhythmerc/gentro-ui/services/games/apppaths"
+	"github.com/rhythmerc/gentro-ui/services/games/models"
+)
+
+func TestServeHeroVideo(t *testing.T) {
+	service := newTestService(t)
+
+	originalArtCache := apppaths.ArtCache
+	apppaths.ArtCache = t.TempDir()
+	t.Cleanup(func() { apppaths.ArtCache = originalArtCache })
+
+	var downloads int
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		w.Write([]byte("webm-data"))
+	}))
+	t.Cleanup(upstream.Close)
+
+	instance := models.GameInstance{
+		ID: "steam_10", GameID: "10", Source: "steam", Platform: "steam",
+		CustomMetadata: map[string]any{heroVideoKey: upstream.URL + "/movie_max.webm"},
+	}
+	if _, err := service.syncSourceInstances("steam", []models.GameInstance{instance}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		service.ServeHTTP(rec, httptest.NewRequest("GET", "/video/steam_10/hero", nil))
+		return rec
+	}
+
+	// Disabled without config
+	if rec := get(); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 with hero videos disabled, got %d", rec.Code)
+	}
+
+	manager, err := config.NewManager(filepath.Join(t.TempDir(), "gentro.toml"))
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	artConfig := manager.Get().Art
+	enabled := artConfig
+	enabled.HeroVideos = true
+	if err := manager.SetArt(enabled); err != nil {
+		t.Fatalf("SetArt failed: %v", err)
+	}
+	service.config = manager
+
+	for i := 0; i < 2; i++ {
+		rec := get()
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if rec.Body.String() != "webm-data" {
+			t.Errorf("unexpected body %q", rec.Body.String())
+		}
+	}
+	if downloads != 1 {
+		t.Errorf("expected video to be downloaded once and served from cache, got %d downloads", downloads)
+	}
+}
+
+// videoSource is a MockSource that looks up hero videos on request
+type videoSource struct {
+	MockSource
+	url     string
+	lookups int
+}
+
+func (v *videoSource) HeroVideoURL(ctx context.Context, instance models.GameInstance) (string, error) {
+	v.lookups++
+	return v.url, nil
+}
+
+func TestGetHeroVideoURL_LooksUpSource(t *testing.T) {
+	service := newTestService(t)
+	service.route = "/games"
+
+	source := &videoSource{MockSource: MockSource{name: "videos"}, url: "https://cdn/movie_max.webm"}
+	service.registry.Register(context.Background(), source)
+
+	if _, err := service.syncSourceInstances("videos", []models.GameInstance{
+		{ID: "v1", GameID: "game1", Source: "videos", Platform: "pc"},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+	if source.lookups != 0 {
+		t.Fatalf("expected no lookups before the video is requested, got %d", source.lookups)
+	}
+
+	// Disabled without config
+	if url, err := service.GetHeroVideoURL("v1"); err != nil || url != "" {
+		t.Errorf("expected no URL with hero videos disabled, got %q, %v", url, err)
+	}
+
+	manager, err := config.NewManager(filepath.Join(t.TempDir(), "gentro.toml"))
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	artConfig := manager.Get().Art
+	artConfig.HeroVideos = true
+	if err := manager.SetArt(artConfig); err != nil {
+		t.Fatalf("SetArt failed: %v", err)
+	}
+	service.config = manager
+
+	url, err := service.GetHeroVideoURL("v1")
+	if err != nil {
+		t.Fatalf("GetHeroVideoURL failed: %v", err)
+	}
+	if url != "/games/video/v1/hero" {
+		t.Errorf("unexpected URL %q", url)
+	}
+	if source.lookups != 1 {
+		t.Errorf("expected one lookup, got %d", source.lookups)
+	}
+
+	// The URL found is kept on the instance, so the source isn't asked again
+	instance, err := service.db.GetInstance("v1")
+	if err != nil {
+		t.Fatalf("GetInstance failed: %v", err)
+	}
+	if instance.CustomMetadata[heroVideoKey] != "https://cdn/movie_max.webm" {
+		t.Errorf("expected the URL to be saved, got %v", instance.CustomMetadata[heroVideoKey])
+	}
+	if _, err := service.GetHeroVideoURL("v1"); err != nil || source.lookups != 1 {
+		t.Errorf("expected the saved URL to be used, got %d lookups, %v", source.lookups, err)
+	}
+
+	source.url = ""
+	if _, err := service.syncSourceInstances("videos", []models.GameInstance{
+		{ID: "v2", GameID: "game2", Source: "videos", Platform: "pc"},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+	if url, err := service.GetHeroVideoURL("v2"); err != nil || url != "" {
+		t.Errorf("expected no URL without a trailer, got %q, %v", url, err)
+	}
+}
+
+// failingVideoSource is a MockSource whose hero video lookups fail
+type failingVideoSource struct {
+	MockSource
+	lookups int
+}
+
+func (f *failingVideoSource) HeroVideoURL(ctx context.Context, instance models.GameInstance) (string, error) {
+	f.lookups++
+	return "", errors.New("store unavailable")
+}
+
+func TestGetHeroVideoURL_RemembersFailures(t *testing.T) {
+	service := newTestService(t)
+	service.route = "/games"
+	enableHeroVideos(t, service)
+
+	source := &failingVideoSource{MockSource: MockSource{name: "videos"}}
+	service.registry.Register(context.Background(), source)
+	if _, err := service.syncSourceInstances("videos", []models.GameInstance{
+		{ID: "v1", GameID: "game1", Source: "videos", Platform: "pc"},
+	}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.GetHeroVideoURL("v1"); err == nil {
+			t.Error("expected the lookup to fail")
+		}
+	}
+	if source.lookups != 1 {
+		t.Errorf("expected a failed lookup not to be retried right away, got %d lookups", source.lookups)
+	}
+
+	// Once the cooldown passes the source is asked again
+	service.heroVideoFailures["v1"] = time.Now().Add(-heroVideoRetryAfter)
+	service.GetHeroVideoURL("v1")
+	if source.lookups != 2 {
+		t.Errorf("expected a retry after the cooldown, got %d lookups", source.lookups)
+	}
+}
+
+func TestServeHeroVideo_SharesDownload(t *testing.T) {
+	service := newTestService(t)
+	enableHeroVideos(t, service)
+
+	originalArtCache := apppaths.ArtCache
+	apppaths.ArtCache = t.TempDir()
+	t.Cleanup(func() { apppaths.ArtCache = originalArtCache })
+
+	var downloads atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads.Add(1)
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("webm-data"))
+	}))
+	t.Cleanup(upstream.Close)
+
+	if _, err := service.syncSourceInstances("steam", []models.GameInstance{{
+		ID: "steam_10", GameID: "10", Source: "steam", Platform: "steam",
+		CustomMetadata: map[string]any{heroVideoKey: upstream.URL + "/movie_max.webm"},
+	}}); err != nil {
+		t.Fatalf("failed to seed instance: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	codes := make([]int, 4)
+	for i := range codes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			service.ServeHTTP(rec, httptest.NewRequest("GET", "/video/steam_10/hero", nil))
+			codes[i] = rec.Code
+		}()
+	}
+	wg.Wait()
+
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("request %d: expected 200, got %d", i, code)
+		}
+	}
+	if n := downloads.Load(); n != 1 {
+		t.Errorf("expected concurrent requests to share one download, got %d", n)
+	}
+}
+
+// enableHeroVideos turns hero videos on in a fresh config for service
+func enableHeroVideos(t *testing.T, service *GamesService) {
+	t.Helper()
+	manager, err := config.NewManager(filepath.Join(t.TempDir(), "gentro.toml"))
+	if err != nil {
+		t.Fatalf("failed to create config manager: %v", err)
+	}
+	artConfig := manager.Get().Art
+	artConfig.HeroVideos = true
+	if err := manager.SetArt(artConfig); err != nil {
+		t.Fatalf("SetArt failed: %v", err)
+	}
+	service.config = manager
+}